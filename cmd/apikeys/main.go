@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeyscli"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+func main() {
+	name := flag.String("name", "", "name of the API key to create")
+	scopes := flag.String("scopes", "", "scopes granted to the API key separated by commas, all of them when empty")
+	holder := flag.String("holder", "", "id of the holder the API key is restricted to")
+	tenant := flag.String("tenant", "", "id of the tenant the API key is restricted to, any tenant when empty")
+	flag.Parse()
+
+	if *name == "" {
+		log.Fatal("the name of the API key is required")
+	}
+
+	opts := apikeyscli.Options{Name: *name, TenantID: *tenant, KeyOut: os.Stdout}
+
+	if *scopes == "" {
+		for _, scope := range authz.Scopes {
+			opts.Scopes = append(opts.Scopes, string(scope))
+		}
+	} else {
+		opts.Scopes = strings.Split(*scopes, ",")
+	}
+
+	if *holder != "" {
+		id, err := uuid.Parse(*holder)
+		if err != nil {
+			log.Fatal("invalid holder id")
+		}
+		opts.HolderID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	if err := zapctx.StartZapCtx(); err != nil {
+		log.Fatal(err)
+	}
+
+	app := fx.New(
+		apikeyscli.Module,
+		fx.Supply(opts),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	signal := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(signal.ExitCode)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/dalmarcogd/ledger-exp/internal/dayclosejob"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+func main() {
+	err := zapctx.StartZapCtx()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app := fx.New(dayclosejob.Module, fx.NopLogger)
+	err = app.Err()
+	if err != nil {
+		zap.L().Fatal("fx", zap.Error(err))
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		zap.L().Fatal("fx_start", zap.Error(err))
+	}
+
+	signal := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		zap.L().Error("fx_stop", zap.Error(err))
+	}
+
+	os.Exit(signal.ExitCode)
+}
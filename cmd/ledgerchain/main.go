@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchaincli"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+func main() {
+	account := flag.String("account", "", "id of the account to verify its chain, the global chain when empty")
+	backfill := flag.Bool("backfill", false, "link the transactions not linked to the chains before verifying")
+	checkpoint := flag.Bool("checkpoint", false, "sign the head of the global chain at the end of the day when valid")
+	date := flag.String("date", "", "day (yyyy-mm-dd) of the checkpoint, the previous day when empty")
+	flag.Parse()
+
+	opts := ledgerchaincli.Options{
+		Chain:      ledgerchain.GlobalChain,
+		Backfill:   *backfill,
+		Checkpoint: *checkpoint,
+		Date:       businessdays.PreviousDay(time.Now()),
+		ReportOut:  os.Stdout,
+	}
+
+	if *account != "" {
+		id, err := uuid.Parse(*account)
+		if err != nil {
+			log.Fatal("invalid account id")
+		}
+		opts.Chain = id.String()
+	}
+
+	if *checkpoint && opts.Chain != ledgerchain.GlobalChain {
+		log.Fatal("checkpoints are only created for the global chain")
+	}
+
+	if *date != "" {
+		d, err := businessdays.ParseDate(*date)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Date = d
+	}
+
+	if err := zapctx.StartZapCtx(); err != nil {
+		log.Fatal(err)
+	}
+
+	app := fx.New(
+		ledgerchaincli.Module,
+		fx.Supply(opts),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	signal := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(signal.ExitCode)
+}
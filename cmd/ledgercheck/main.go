@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dalmarcogd/ledger-exp/internal/ledgercheckcli"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/fx"
+)
+
+func main() {
+	interval := flag.Duration("interval", 0, "check the ledger again at every interval (e.g. 1h), only once when zero")
+	output := flag.String("output", "", "file to append the JSON reports, the standard output when empty")
+	flag.Parse()
+
+	if err := zapctx.StartZapCtx(); err != nil {
+		log.Fatal(err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		f, err := os.OpenFile(*output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	app := fx.New(
+		ledgercheckcli.Module,
+		fx.Supply(ledgercheckcli.Options{Interval: *interval, ReportOut: out}),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	signal := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(signal.ExitCode)
+}
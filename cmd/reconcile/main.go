@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliationscli"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+func main() {
+	filePath := flag.String("file", "", "path of the settlement file")
+	format := flag.String("format", string(reconciliations.CSVFormat), "format of the file: csv or cnab240")
+	account := flag.String("account", "", "id of the account to reconcile")
+	amountTolerance := flag.Float64("amount-tolerance", 0, "maximum difference of amount to match")
+	daysTolerance := flag.Int("days-tolerance", 0, "maximum difference of days to match")
+	flag.Parse()
+
+	accountID, err := uuid.Parse(*account)
+	if *filePath == "" || err != nil {
+		flag.Usage()
+		os.Exit(reconciliationscli.ExitError)
+	}
+
+	if err := zapctx.StartZapCtx(); err != nil {
+		log.Fatal(err)
+	}
+
+	app := fx.New(
+		reconciliationscli.Module,
+		fx.Supply(reconciliationscli.Options{
+			FilePath: *filePath,
+			Request: reconciliations.Request{
+				AccountID: accountID,
+				Format:    reconciliations.Format(*format),
+				Tolerance: reconciliations.Tolerance{Amount: *amountTolerance, Days: *daysTolerance},
+			},
+			ReportOut: os.Stdout,
+		}),
+		fx.NopLogger,
+	)
+	if err := app.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	startCtx, cancel := context.WithTimeout(context.Background(), app.StartTimeout())
+	defer cancel()
+	if err := app.Start(startCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	signal := <-app.Wait()
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), app.StopTimeout())
+	defer cancel()
+	if err := app.Stop(stopCtx); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+
+	os.Exit(signal.ExitCode)
+}
@@ -1,6 +1,11 @@
 package accounts
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/google/uuid"
+)
 
 type Account struct {
 	ID             uuid.UUID
@@ -9,7 +14,9 @@ type Account struct {
 	Number         string
 	DocumentNumber string
 	HolderID       uuid.UUID
+	TenantID       string
 	Status         Status
+	CreatedAt      time.Time
 }
 
 func newAccount(model accountModel) Account {
@@ -19,8 +26,10 @@ func newAccount(model accountModel) Account {
 		Agency:         model.Agency,
 		Number:         model.Number,
 		HolderID:       model.HolderID,
+		TenantID:       model.TenantID,
 		DocumentNumber: model.HolderDocumentNumber,
 		Status:         model.Status,
+		CreatedAt:      model.CreatedAt,
 	}
 }
 
@@ -29,4 +38,14 @@ type ListFilter struct {
 	Page           int
 	Size           int
 	DocumentNumber string
+	// Status keeps only the accounts in this status, all of them when empty.
+	Status Status
+	// HolderID keeps only the accounts of the holder, the listings of the principals bound to a holder.
+	HolderID uuid.NullUUID
+	// TenantID keeps only the accounts of the tenant, the accounts of all tenants when empty.
+	TenantID string
+	// Cursor selects the page by keyset instead of by Page, it has precedence over Page when not nil.
+	Cursor *cursor.Cursor
+	// WithTotal counts all items matched by the filter, what is expensive for big listings.
+	WithTotal bool
 }
@@ -29,6 +29,7 @@ type accountModel struct {
 	Agency               string    `bun:"agency"`
 	Number               string    `bun:"number"`
 	HolderID             uuid.UUID `bun:"holder_id"`
+	TenantID             string    `bun:"tenant_id,nullzero"`
 	HolderDocumentNumber string    `bun:"holder_document_number,scanonly"`
 	Status               Status    `bun:"status"`
 	CreatedAt            time.Time `bun:"created_at,notnull"`
@@ -42,6 +43,7 @@ func newAccountModel(acc Account) accountModel {
 		Agency:   acc.Agency,
 		Number:   acc.Number,
 		HolderID: acc.HolderID,
+		TenantID: acc.TenantID,
 		Status:   acc.Status,
 	}
 }
@@ -50,6 +52,7 @@ type accountFilter struct {
 	ID             uuid.NullUUID
 	Name           string
 	DocumentNumber string
+	TenantID       string
 	CreatedAtBegin database.NullTime
 	CreatedAtEnd   database.NullTime
 	UpdatedAtBegin database.NullTime
@@ -4,11 +4,21 @@ import (
 	"context"
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/google/uuid"
 )
 
+// queryTimeout is the default deadline of the queries of the accounts.
+const queryTimeout = 2 * time.Second
+
+// constraints are the errors of the constraints of the accounts.
+var constraints = database.Constraints{
+	"accounts_holder_id_fkey": ErrAccountHolderNotFound,
+	"accounts_holder_number":  ErrAccountAlreadyExists,
+}
+
 type Repository interface {
 	Create(ctx context.Context, model accountModel) (accountModel, error)
 	Update(ctx context.Context, model accountModel) (accountModel, error)
@@ -32,15 +42,19 @@ func (r repository) Create(ctx context.Context, model accountModel) (accountMode
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	model.ID = uuid.New()
 	model.CreatedAt = time.Now().UTC()
 
-	_, err := r.db.Master().
+	_, err := r.db.Master(ctx).
 		NewInsert().
 		Model(&model).
 		Returning("*").
 		Exec(ctx)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return accountModel{}, err
 	}
@@ -52,9 +66,12 @@ func (r repository) Update(ctx context.Context, model accountModel) (accountMode
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	model.UpdatedAt = time.Now().UTC()
 
-	_, err := r.db.Master().
+	_, err := r.db.Master(ctx).
 		NewUpdate().
 		Model(&model).
 		WherePK().
@@ -62,6 +79,7 @@ func (r repository) Update(ctx context.Context, model accountModel) (accountMode
 		OmitZero().
 		Exec(ctx)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return accountModel{}, err
 	}
@@ -73,7 +91,10 @@ func (r repository) GetByFilter(ctx context.Context, filter accountFilter) ([]ac
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
-	selectQuery := r.db.Replica().
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	selectQuery := r.db.Replica(ctx).
 		NewSelect().
 		ModelTableExpr("accounts AS a").
 		Join("JOIN holders AS h ON h.id = a.holder_id").
@@ -91,6 +112,10 @@ func (r repository) GetByFilter(ctx context.Context, filter accountFilter) ([]ac
 		selectQuery.Where("h.document_number = ?", filter.DocumentNumber)
 	}
 
+	if filter.TenantID != "" {
+		selectQuery.Where("a.tenant_id = ?", filter.TenantID)
+	}
+
 	if filter.CreatedAtBegin.Valid {
 		selectQuery.Where("a.created_at >= ?", filter.CreatedAtBegin.Time)
 	}
@@ -110,6 +135,7 @@ func (r repository) GetByFilter(ctx context.Context, filter accountFilter) ([]ac
 	var accs []accountModel
 	_, err := selectQuery.Exec(ctx, &accs)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return nil, err
 	}
@@ -121,6 +147,9 @@ func (r repository) ListByFilter(ctx context.Context, filter ListFilter) (int, [
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	page := filter.Page
 	if page == 0 {
 		page = 1
@@ -131,30 +160,65 @@ func (r repository) ListByFilter(ctx context.Context, filter ListFilter) (int, [
 		size = 20
 	}
 
-	selectQuery := r.db.Replica().
+	selectQuery := r.db.Replica(ctx).
 		NewSelect().
 		ModelTableExpr("accounts AS a").
 		ColumnExpr("a.*, h.document_number AS holder_document_number").
 		Join("JOIN holders AS h ON h.id = a.holder_id").
-		Limit(size).
-		Offset((page - 1) * size)
+		Limit(size)
 
 	if filter.DocumentNumber != "" {
 		selectQuery.Where("h.document_number = ?", filter.DocumentNumber)
 	}
 
-	if filter.Sort == 0 {
-		selectQuery.Order("created_at ASC")
-	} else if filter.Sort > 0 {
-		selectQuery.Order("created_at DESC")
+	if filter.Status != "" {
+		selectQuery.Where("a.status = ?", filter.Status)
+	}
+
+	if filter.HolderID.Valid {
+		selectQuery.Where("a.holder_id = ?", filter.HolderID.UUID)
+	}
+
+	if filter.TenantID != "" {
+		selectQuery.Where("a.tenant_id = ?", filter.TenantID)
+	}
+
+	var total int
+	var err error
+	if filter.WithTotal {
+		// Counted before the keyset restriction, the total is about the whole listing and not what is after the cursor.
+		total, err = selectQuery.Count(ctx)
+		if err != nil {
+			err = database.TranslateError(err, constraints)
+			span.RecordError(err)
+			return 0, nil, err
+		}
+	}
+
+	var reversed bool
+	if filter.Cursor != nil {
+		reversed = cursor.Keyset(selectQuery, *filter.Cursor, filter.Sort > 0, "a.created_at", "a.id")
+	} else {
+		selectQuery.Offset((page - 1) * size)
+
+		if filter.Sort == 0 {
+			selectQuery.Order("created_at ASC")
+		} else if filter.Sort > 0 {
+			selectQuery.Order("created_at DESC")
+		}
 	}
 
 	var accs []accountModel
-	total, err := selectQuery.ScanAndCount(ctx, &accs)
+	err = selectQuery.Scan(ctx, &accs)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return 0, nil, err
 	}
 
+	if reversed {
+		cursor.Reverse(accs)
+	}
+
 	return total, accs, nil
 }
@@ -4,6 +4,7 @@ package accounts
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"runtime"
@@ -13,8 +14,10 @@ import (
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,7 +36,7 @@ func TestRepository(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	db, err := database.New(tracer.NewNoop(), url, url)
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 	assert.NoError(t, err)
 
 	repo := NewRepository(tracer.NewNoop(), db)
@@ -176,3 +179,62 @@ func TestRepository(t *testing.T) {
 		assert.Empty(t, rst)
 	})
 }
+
+func TestService_CreateWithHolder(t *testing.T) {
+	ctx := context.Background()
+
+	url, closeFunc, err := testingcontainers.NewPostgresContainer()
+	assert.NoError(t, err)
+	defer closeFunc(ctx) //nolint:errcheck
+
+	_, callerPath, _, _ := runtime.Caller(0) //nolint:dogsled
+	err = testingcontainers.RunMigrateDatabase(
+		url,
+		fmt.Sprintf("file://%s/../../migrations/", filepath.Dir(callerPath)),
+	)
+	assert.NoError(t, err)
+
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
+	assert.NoError(t, err)
+
+	holdersRepo := holders.NewRepository(tracer.NewNoop(), db)
+	uow := database.NewUnitOfWork(tracer.NewNoop(), db)
+
+	findHolders := func(documentNumber string) []holders.HolderModel {
+		hds, err := holdersRepo.GetByFilter(ctx, holders.HolderFilter{DocumentNumber: documentNumber})
+		assert.NoError(t, err)
+		return hds
+	}
+
+	t.Run("create the holder with its account", func(t *testing.T) {
+		svc := NewService(tracer.NewNoop(), NewRepository(tracer.NewNoop(), db), holdersRepo, uow)
+
+		holder, account, err := svc.CreateWithHolder(
+			ctx,
+			holders.Holder{Name: gofakeit.Name(), DocumentNumber: gofakeit.SSN()},
+			Account{Name: gofakeit.Name()},
+		)
+		assert.NoError(t, err)
+		assert.Len(t, findHolders(holder.DocumentNumber), 1)
+
+		created, err := svc.GetByID(ctx, account.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, holder.ID, created.HolderID)
+	})
+
+	t.Run("rollback the holder when the account fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		failure := errors.New("failure")
+		repoMock := NewMockRepository(ctrl)
+		repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(accountModel{}, failure)
+
+		svc := NewService(tracer.NewNoop(), repoMock, holdersRepo, uow)
+
+		holder := holders.Holder{Name: gofakeit.Name(), DocumentNumber: gofakeit.SSN()}
+		_, _, err := svc.CreateWithHolder(ctx, holder, Account{Name: gofakeit.Name()})
+		assert.ErrorIs(t, err, failure)
+		assert.Empty(t, findHolders(holder.DocumentNumber))
+	})
+}
@@ -5,7 +5,10 @@ import (
 	"errors"
 
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/stringer"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/google/uuid"
@@ -18,25 +21,39 @@ var (
 	ErrMultpleAccountsFound  = errors.New("multiple accounts found with these filters")
 	ErrAccountInactive       = errors.New("account must be active for this operation")
 	ErrAccountUnblcked       = errors.New("account must be blocked for this operation")
+	ErrAccountAlreadyExists  = errors.New("an account with this number already exists for the holder")
 )
 
 type Service interface {
 	Create(ctx context.Context, account Account) (Account, error)
+	// CreateWithHolder creates the holder with its first account in a unit of work, neither is created when one of
+	// them fails. The principal needs the scopes to write both.
+	CreateWithHolder(ctx context.Context, holder holders.Holder, account Account) (holders.Holder, Account, error)
 	BlockByID(ctx context.Context, id uuid.UUID) (Account, error)
 	UnblockByID(ctx context.Context, id uuid.UUID) (Account, error)
 	CloseByID(ctx context.Context, id uuid.UUID) (Account, error)
 	GetByID(ctx context.Context, id uuid.UUID) (Account, error)
 	List(ctx context.Context, filter ListFilter) (int, []Account, error)
+	// Authorize returns authz.ErrForbidden when the principal is bound to a holder other than the one of the account,
+	// and ErrAccountNotFound when the account is of another tenant. The account is only looked up for the principals
+	// bound to a holder and the calls in a tenant.
+	Authorize(ctx context.Context, id uuid.UUID) error
 }
 
 type service struct {
 	tracer           tracer.Tracer
 	repository       Repository
 	holderRepository holders.Repository
+	unitOfWork       database.UnitOfWork
 }
 
-func NewService(t tracer.Tracer, r Repository, holderRepository holders.Repository) Service {
-	return service{tracer: t, repository: r, holderRepository: holderRepository}
+func NewService(
+	t tracer.Tracer,
+	r Repository,
+	holderRepository holders.Repository,
+	unitOfWork database.UnitOfWork,
+) Service {
+	return service{tracer: t, repository: r, holderRepository: holderRepository, unitOfWork: unitOfWork}
 }
 
 func (s service) Create(ctx context.Context, account Account) (Account, error) {
@@ -49,7 +66,10 @@ func (s service) Create(ctx context.Context, account Account) (Account, error) {
 		return Account{}, ErrAccountHolderNotFound
 	}
 
-	hds, err := s.holderRepository.GetByFilter(ctx, holders.HolderFilter{DocumentNumber: account.DocumentNumber})
+	hds, err := s.holderRepository.GetByFilter(ctx, holders.HolderFilter{
+		DocumentNumber: account.DocumentNumber,
+		TenantID:       tenancy.FilterID(ctx),
+	})
 	if err != nil {
 		zapctx.L(ctx).Error("account_service_holder_repository_error", zap.Error(err))
 		span.RecordError(err)
@@ -62,18 +82,84 @@ func (s service) Create(ctx context.Context, account Account) (Account, error) {
 		return Account{}, ErrAccountHolderNotFound
 	}
 
-	account.Agency = "0001"
-	account.Number = stringer.GenerateCode([]rune(AccountNumberVariants), AccountNumberSize)
+	if err := authz.AuthorizeHolder(ctx, hds[0].ID); err != nil {
+		span.RecordError(err)
+		return Account{}, err
+	}
+
 	account.HolderID = hds[0].ID
+
+	account, err = s.create(ctx, account)
+	if err != nil {
+		span.RecordError(err)
+		return Account{}, err
+	}
+
+	return account, nil
+}
+
+func (s service) CreateWithHolder(
+	ctx context.Context,
+	holder holders.Holder,
+	account Account,
+) (holders.Holder, Account, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	// The principals bound to a holder can not create other holders.
+	if err := authz.AuthorizeHolder(ctx); err != nil {
+		span.RecordError(err)
+		return holders.Holder{}, Account{}, err
+	}
+	if err := authz.Authorize(ctx, authz.ScopeHoldersWrite, authz.ScopeAccountsWrite); err != nil {
+		span.RecordError(err)
+		return holders.Holder{}, Account{}, err
+	}
+
+	holder.TenantID = tenancy.Current(ctx).ID
+
+	var created Account
+	err := s.unitOfWork.Do(ctx, nil, func(ctx context.Context) error {
+		model, err := s.holderRepository.Create(ctx, holders.HolderModel{
+			Name:           holder.Name,
+			DocumentNumber: holder.DocumentNumber,
+			TenantID:       holder.TenantID,
+		})
+		if err != nil {
+			zapctx.L(ctx).Error("account_service_create_holder_repository_error", zap.Error(err))
+			return err
+		}
+		holder.ID = model.ID
+		holder.CreatedAt = model.CreatedAt
+
+		created, err = s.create(ctx, Account{
+			Name:           account.Name,
+			DocumentNumber: holder.DocumentNumber,
+			HolderID:       holder.ID,
+		})
+		return err
+	})
+	if err != nil {
+		span.RecordError(err)
+		return holders.Holder{}, Account{}, err
+	}
+
+	return holder, created, nil
+}
+
+// create creates the account of the holder of account.HolderID in the tenant of ctx.
+func (s service) create(ctx context.Context, account Account) (Account, error) {
+	account.Agency = tenancy.Current(ctx).Agency
+	account.Number = stringer.GenerateCode([]rune(AccountNumberVariants), AccountNumberSize)
 	account.Status = ActiveStatus
 
 	model, err := s.repository.Create(ctx, newAccountModel(account))
 	if err != nil {
 		zapctx.L(ctx).Error("account_service_create_repository_error", zap.Error(err))
-		span.RecordError(err)
 		return Account{}, err
 	}
 	account.ID = model.ID
+	account.TenantID = model.TenantID
 
 	return account, nil
 }
@@ -192,7 +278,10 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Account, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
-	models, err := s.repository.GetByFilter(ctx, accountFilter{ID: uuid.NullUUID{UUID: id, Valid: true}})
+	models, err := s.repository.GetByFilter(ctx, accountFilter{
+		ID:       uuid.NullUUID{UUID: id, Valid: true},
+		TenantID: tenancy.FilterID(ctx),
+	})
 	if err != nil {
 		zapctx.L(ctx).Error(
 			"account_service_get_repository_error",
@@ -211,13 +300,26 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Account, error) {
 		return Account{}, ErrMultpleAccountsFound
 	}
 
+	if err := authz.AuthorizeHolder(ctx, models[0].HolderID); err != nil {
+		span.RecordError(err)
+		return Account{}, err
+	}
+
 	return newAccount(models[0]), nil
 }
 
+// List lists the accounts of the filter in the tenant, only the accounts of the holder of the principal when it is
+// bound to a holder.
 func (s service) List(ctx context.Context, filter ListFilter) (int, []Account, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	filter.TenantID = tenancy.FilterID(ctx)
+
+	if holderID, bound := authz.Holder(ctx); bound {
+		filter.HolderID = uuid.NullUUID{UUID: holderID, Valid: true}
+	}
+
 	total, models, err := s.repository.ListByFilter(ctx, filter)
 	if err != nil {
 		zapctx.L(ctx).Error(
@@ -235,3 +337,20 @@ func (s service) List(ctx context.Context, filter ListFilter) (int, []Account, e
 
 	return total, hdrs, nil
 }
+
+func (s service) Authorize(ctx context.Context, id uuid.UUID) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if _, bound := authz.Holder(ctx); !bound && tenancy.FilterID(ctx) == "" {
+		return nil
+	}
+
+	_, err := s.GetByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
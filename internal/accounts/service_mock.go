@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	holders "github.com/dalmarcogd/ledger-exp/internal/holders"
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
 )
@@ -35,6 +36,20 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Authorize mocks base method.
+func (m *MockService) Authorize(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockServiceMockRecorder) Authorize(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockService)(nil).Authorize), ctx, id)
+}
+
 // BlockByID mocks base method.
 func (m *MockService) BlockByID(ctx context.Context, id uuid.UUID) (Account, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +95,22 @@ func (mr *MockServiceMockRecorder) Create(ctx, account interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, account)
 }
 
+// CreateWithHolder mocks base method.
+func (m *MockService) CreateWithHolder(ctx context.Context, holder holders.Holder, account Account) (holders.Holder, Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithHolder", ctx, holder, account)
+	ret0, _ := ret[0].(holders.Holder)
+	ret1, _ := ret[1].(Account)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateWithHolder indicates an expected call of CreateWithHolder.
+func (mr *MockServiceMockRecorder) CreateWithHolder(ctx, holder, account interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithHolder", reflect.TypeOf((*MockService)(nil).CreateWithHolder), ctx, holder, account)
+}
+
 // GetByID mocks base method.
 func (m *MockService) GetByID(ctx context.Context, id uuid.UUID) (Account, error) {
 	m.ctrl.T.Helper()
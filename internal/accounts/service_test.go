@@ -5,10 +5,15 @@ package accounts
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -23,7 +28,7 @@ func TestService_Create(t *testing.T) {
 	repoMock := NewMockRepository(ctrl)
 	holderRepoMock := holders.NewMockRepository(ctrl)
 
-	svc := NewService(tracer.NewNoop(), repoMock, holderRepoMock)
+	svc := NewService(tracer.NewNoop(), repoMock, holderRepoMock, database.NewMockUnitOfWork(ctrl))
 
 	t.Run("fail create, holder not found", func(t *testing.T) {
 		account := Account{
@@ -76,6 +81,94 @@ func TestService_Create(t *testing.T) {
 	})
 }
 
+func TestService_CreateWithHolder(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	holderRepoMock := holders.NewMockRepository(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, holderRepoMock, uowMock)
+
+	holder := holders.Holder{Name: gofakeit.Name(), DocumentNumber: gofakeit.SSN()}
+	account := Account{Name: gofakeit.Name()}
+
+	runUnitOfWork := func() {
+		uowMock.EXPECT().
+			Do(ctx, nil, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *sql.TxOptions, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	t.Run("success create with holder", func(t *testing.T) {
+		holderID := uuid.New()
+		runUnitOfWork()
+		holderRepoMock.EXPECT().
+			Create(ctx, holders.HolderModel{
+				Name:           holder.Name,
+				DocumentNumber: holder.DocumentNumber,
+				TenantID:       tenancy.Default.ID,
+			}).
+			Return(holders.HolderModel{ID: holderID, Name: holder.Name, DocumentNumber: holder.DocumentNumber}, nil)
+		repoMock.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(_ context.Context, model accountModel) (accountModel, error) {
+				assert.Equal(t, holderID, model.HolderID)
+				model.ID = uuid.New()
+				return model, nil
+			})
+
+		createdHolder, createdAccount, err := svc.CreateWithHolder(ctx, holder, account)
+		assert.NoError(t, err)
+		assert.Equal(t, holderID, createdHolder.ID)
+		assert.Equal(t, holderID, createdAccount.HolderID)
+		assert.Equal(t, holder.DocumentNumber, createdAccount.DocumentNumber)
+		assert.Equal(t, ActiveStatus, createdAccount.Status)
+	})
+
+	t.Run("fail create with holder, account not created", func(t *testing.T) {
+		failure := errors.New("failure")
+		runUnitOfWork()
+		holderRepoMock.EXPECT().
+			Create(ctx, gomock.Any()).
+			Return(holders.HolderModel{ID: uuid.New()}, nil)
+		repoMock.EXPECT().
+			Create(ctx, gomock.Any()).
+			Return(accountModel{}, failure)
+
+		createdHolder, createdAccount, err := svc.CreateWithHolder(ctx, holder, account)
+		assert.ErrorIs(t, err, failure)
+		assert.Empty(t, createdHolder)
+		assert.Empty(t, createdAccount)
+	})
+
+	t.Run("fail create with holder, principal bound to a holder", func(t *testing.T) {
+		ctx := auth.WithPrincipal(ctx, auth.Principal{
+			ID:       "user",
+			Type:     auth.JWTPrincipal,
+			Scopes:   []string{string(authz.ScopeHoldersWrite), string(authz.ScopeAccountsWrite)},
+			HolderID: uuid.NullUUID{UUID: uuid.New(), Valid: true},
+		})
+
+		_, _, err := svc.CreateWithHolder(ctx, holder, account)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+
+	t.Run("fail create with holder, principal without the scope of the accounts", func(t *testing.T) {
+		ctx := auth.WithPrincipal(ctx, auth.Principal{
+			ID:     "key",
+			Type:   auth.APIKeyPrincipal,
+			Scopes: []string{string(authz.ScopeHoldersWrite)},
+		})
+
+		_, _, err := svc.CreateWithHolder(ctx, holder, account)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+}
+
 func TestService_BlockByID(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -83,7 +176,7 @@ func TestService_BlockByID(t *testing.T) {
 
 	repoMock := NewMockRepository(ctrl)
 
-	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl))
+	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl), database.NewMockUnitOfWork(ctrl))
 
 	accountID := uuid.New()
 
@@ -133,7 +226,7 @@ func TestService_UnblockByID(t *testing.T) {
 
 	repoMock := NewMockRepository(ctrl)
 
-	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl))
+	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl), database.NewMockUnitOfWork(ctrl))
 
 	accountID := uuid.New()
 
@@ -183,7 +276,7 @@ func TestService_CloseByID(t *testing.T) {
 
 	repoMock := NewMockRepository(ctrl)
 
-	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl))
+	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl), database.NewMockUnitOfWork(ctrl))
 
 	accountID := uuid.New()
 
@@ -223,3 +316,53 @@ func TestService_CloseByID(t *testing.T) {
 		assert.Equal(t, ClosedStatus, acc.Status)
 	})
 }
+
+func TestService_HolderBoundPrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, holders.NewMockRepository(ctrl), database.NewMockUnitOfWork(ctrl))
+
+	holderID := uuid.New()
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:       "user",
+		Type:     auth.JWTPrincipal,
+		HolderID: uuid.NullUUID{UUID: holderID, Valid: true},
+	})
+
+	t.Run("list only the accounts of the holder", func(t *testing.T) {
+		repoMock.EXPECT().
+			ListByFilter(ctx, ListFilter{Size: 10, HolderID: uuid.NullUUID{UUID: holderID, Valid: true}}).
+			Return(0, []accountModel{{HolderID: holderID}}, nil)
+
+		_, accs, err := svc.List(ctx, ListFilter{Size: 10})
+		assert.NoError(t, err)
+		assert.Len(t, accs, 1)
+	})
+
+	t.Run("fail get, account of another holder", func(t *testing.T) {
+		accountID := uuid.New()
+		repoMock.EXPECT().
+			GetByFilter(ctx, accountFilter{ID: uuid.NullUUID{UUID: accountID, Valid: true}}).
+			Return([]accountModel{{ID: accountID, HolderID: uuid.New()}}, nil)
+
+		acc, err := svc.GetByID(ctx, accountID)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, acc)
+	})
+
+	t.Run("authorize the account of the holder", func(t *testing.T) {
+		accountID := uuid.New()
+		repoMock.EXPECT().
+			GetByFilter(ctx, accountFilter{ID: uuid.NullUUID{UUID: accountID, Valid: true}}).
+			Return([]accountModel{{ID: accountID, HolderID: holderID}}, nil)
+
+		assert.NoError(t, svc.Authorize(ctx, accountID))
+	})
+
+	t.Run("authorize without lookup when not bound to a holder", func(t *testing.T) {
+		assert.NoError(t, svc.Authorize(context.Background(), uuid.New()))
+	})
+}
@@ -4,73 +4,202 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"strings"
+	"time"
 
 	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/apierrors"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/grpcservers"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/accountsh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/adminh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/apikeysh"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/balancesh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/businessdaysh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/graphqlh"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/holdersh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/ledgerchainh"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/openapih"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/reconciliationsh"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/statementsh"
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/transactionsh"
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
 	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
+	"github.com/dalmarcogd/ledger-exp/internal/monthlystatements"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
 	"github.com/dalmarcogd/ledger-exp/internal/statements"
 	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/distlock"
+	"github.com/dalmarcogd/ledger-exp/pkg/grpc/interceptors"
 	"github.com/dalmarcogd/ledger-exp/pkg/healthcheck"
 	"github.com/dalmarcogd/ledger-exp/pkg/http/middlewares"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
 	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// healthCheckCacheTTL is the time the reports of the health checks are reused, to not overload the dependencies with
+// the probes.
+const healthCheckCacheTTL = time.Second
+
+// testEnvironment is the environment of the tests, the responses of the HTTP API are validated against the OpenAPI
+// document in it.
+const testEnvironment = "test"
+
 var Module = fx.Options(
 	// Infra
 	fx.Provide(
 		environment.NewEnvironment,
-		func(lc fx.Lifecycle, e environment.Environment, t tracer.Tracer) (database.Database, error) {
-			return database.Setup(lc, t, e.DatabaseURL, e.DatabaseURL)
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			config, err := newDatabaseConfig(e)
+			if err != nil {
+				return nil, err
+			}
+			return database.Setup(lc, t, m, config)
 		},
 		func(env environment.Environment) (redis.Client, error) {
 			return redis.NewClient(env.RedisURL, env.RedisCACert)
 		},
-		func(env environment.Environment, db database.Database, redisClient redis.Client) healthcheck.HealthCheck {
-			return healthcheck.NewChain(
-				healthcheck.NewDatabaseConnectivity(db.Master()),
-				healthcheck.NewDatabaseMigration(db.Master(), "schema_migrations"),
-				healthcheck.NewDatabaseConnectivity(db.Replica()),
-				healthcheck.NewDatabaseMigration(db.Replica(), "schema_migrations"),
-				redis.NewHealthCheck(redisClient),
+		func(env environment.Environment, db database.Database, redisClient redis.Client) (healthcheck.Reporter, error) {
+			config, err := newDatabaseConfig(env)
+			if err != nil {
+				return nil, err
+			}
+
+			master := db.Master(context.Background())
+			components := []healthcheck.Component{
+				{
+					Name:     "database.master",
+					Check:    healthcheck.NewDatabaseConnectivity(master),
+					Critical: true,
+				},
+				{
+					Name:     "database.master.migrations",
+					Check:    healthcheck.NewDatabaseMigration(master, "schema_migrations"),
+					Critical: true,
+				},
+			}
+			// The reads fall back to the master while the replicas lag or fail, when their lag is checked, so the API is
+			// only degraded without them.
+			critical := config.MaxReplicaLag == 0
+			for i, replica := range db.Replicas() {
+				name := fmt.Sprintf("database.replica-%d", i)
+				components = append(components,
+					healthcheck.Component{
+						Name:     name,
+						Check:    healthcheck.NewDatabaseConnectivity(replica),
+						Critical: critical,
+					},
+					healthcheck.Component{
+						Name:     name + ".migrations",
+						Check:    healthcheck.NewDatabaseMigration(replica, "schema_migrations"),
+						Critical: critical,
+					},
+				)
+				if !critical {
+					components = append(components, healthcheck.Component{
+						Name:  name + ".lag",
+						Check: healthcheck.NewReplicaLag(replica, config.MaxReplicaLag),
+					})
+				}
+			}
+			components = append(components,
+				healthcheck.Component{
+					Name:     "redis",
+					Check:    redis.NewHealthCheck(redisClient),
+					Critical: true,
+				},
+				healthcheck.Component{
+					Name:  "otel_collector",
+					Check: healthcheck.NewOtelCollector(env.OtelCollectorHost),
+				},
 			)
+
+			return healthcheck.NewChecker(healthCheckCacheTTL, components...), nil
+		},
+
+		func(r healthcheck.Reporter) healthcheck.HealthCheck {
+			return r
 		},
 		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
-			return tracer.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
 		},
 		distlock.NewDistock,
+		ratelimit.NewLimiter,
+		newAuthenticators,
+		func(env environment.Environment) (tenancy.Tenants, error) {
+			return tenancy.Load(env.TenantsFile)
+		},
+		// The API only reads the checkpoints, they are signed by cmd/ledgerchain.
+		func() (ledgerchain.Signer, error) {
+			return ledgerchain.NewSigner("")
+		},
 	),
 	// Domains
 	fx.Provide(
+		database.NewUnitOfWork,
 		holders.NewRepository,
 		holders.NewService,
 		accounts.NewRepository,
 		accounts.NewService,
+		businessdays.NewRepository,
+		businessdays.NewService,
 		transactions.NewRepository,
 		transactions.NewService,
 		statements.NewRepository,
 		statements.NewService,
+		monthlystatements.NewRepository,
+		monthlystatements.NewService,
 		balances.NewRepository,
 		balances.NewService,
+		reconciliations.NewRepository,
+		reconciliations.NewService,
+		ledgerchain.NewRepository,
+		ledgerchain.NewService,
+		apikeys.NewRepository,
+		apikeys.NewService,
 	),
 	// Endpoints
 	fx.Provide(
 		handlers.NewLivenessFunc,
 		handlers.NewReadinessFunc,
+		openapih.Load,
+		openapih.NewOpenAPIFunc,
+		openapih.NewDocsFunc,
 		holdersh.NewCreateHolderFunc,
 		holdersh.NewGetByIDHolderFunc,
 		holdersh.NewListHoldersFunc,
@@ -81,11 +210,31 @@ var Module = fx.Options(
 		accountsh.NewGetByIDFunc,
 		accountsh.NewListAccountsFunc,
 		statementsh.NewListAccountStatementFunc,
+		statementsh.NewExportAccountStatementFunc,
+		statementsh.NewListMonthlyStatementsFunc,
+		statementsh.NewGetMonthlyStatementFunc,
 		balancesh.NewGetBalanceByAccountIDFunc,
 		transactionsh.NewCreateCreditTransactionFunc,
 		transactionsh.NewCreateDebitTransactionFunc,
 		transactionsh.NewCreateP2PTransactionFunc,
 		transactionsh.NewGetByIDTransactionFunc,
+		reconciliationsh.NewCreateReconciliationFunc,
+		reconciliationsh.NewGetReconciliationFunc,
+		businessdaysh.NewCloseBusinessDayFunc,
+		businessdaysh.NewOpenAdjustmentFunc,
+		businessdaysh.NewGetTrialBalanceFunc,
+		ledgerchainh.NewGetCheckpointFunc,
+		graphqlh.NewGraphQLFunc,
+		apikeysh.NewCreateAPIKeyFunc,
+		apikeysh.NewRotateAPIKeyFunc,
+		apikeysh.NewRevokeAPIKeyFunc,
+		adminh.NewGetDatabaseStatsFunc,
+		grpcservers.NewHolderServer,
+		grpcservers.NewAccountServer,
+		grpcservers.NewTransactionServer,
+		grpcservers.NewBalanceServer,
+		grpcservers.NewStatementServer,
+		grpcservers.NewHealthServer,
 	),
 	// Startup applications
 	fx.Invoke(func(
@@ -98,6 +247,7 @@ var Module = fx.Options(
 		)
 	}),
 	fx.Invoke(runHTTPServer),
+	fx.Invoke(runGRPCServer),
 )
 
 func setupLogger(service, version, env string) (*zap.Logger, error) {
@@ -111,49 +261,307 @@ func setupLogger(service, version, env string) (*zap.Logger, error) {
 	return logger, nil
 }
 
-//nolint:funlen
+// httpHandlers are the handlers of the routes of the HTTP API.
+type httpHandlers struct {
+	fx.In
+
+	Readiness              handlers.ReadinessFunc
+	Liveness               handlers.LivenessFunc
+	OpenAPI                openapih.OpenAPIFunc
+	Docs                   openapih.DocsFunc
+	CreateHolder           holdersh.CreateHolderFunc
+	GetByIDHolder          holdersh.GetByIDHolderFunc
+	ListHolders            holdersh.ListHoldersFunc
+	CreateAccount          accountsh.CreateAccountFunc
+	CloseByID              accountsh.CloseByIDFunc
+	BlockByID              accountsh.BlockByIDFunc
+	UnblockByID            accountsh.UnblockByIDFunc
+	GetByIDAccount         accountsh.GetByIDFunc
+	ListAccounts           accountsh.ListAccountsFunc
+	CreateCredit           transactionsh.CreateCreditTransactionFunc
+	CreateDebit            transactionsh.CreateDebitTransactionFunc
+	CreateP2P              transactionsh.CreateP2PTransactionFunc
+	GetByIDTransaction     transactionsh.GetByIDTransactionFunc
+	ListAccountStatement   statementsh.ListAccountStatementFunc
+	ExportAccountStatement statementsh.ExportAccountStatementFunc
+	ListMonthlyStatements  statementsh.ListMonthlyStatementsFunc
+	GetMonthlyStatement    statementsh.GetMonthlyStatementFunc
+	GetBalanceByAccountID  balancesh.GetBalanceByAccountIDFunc
+	CreateReconciliation   reconciliationsh.CreateReconciliationFunc
+	GetReconciliation      reconciliationsh.GetReconciliationFunc
+	CloseBusinessDay       businessdaysh.CloseBusinessDayFunc
+	OpenAdjustment         businessdaysh.OpenAdjustmentFunc
+	GetTrialBalance        businessdaysh.GetTrialBalanceFunc
+	GetCheckpoint          ledgerchainh.GetCheckpointFunc
+	GraphQL                graphqlh.GraphQLFunc
+	CreateAPIKey           apikeysh.CreateAPIKeyFunc
+	RotateAPIKey           apikeysh.RotateAPIKeyFunc
+	RevokeAPIKey           apikeysh.RevokeAPIKeyFunc
+	GetDatabaseStats       adminh.GetDatabaseStatsFunc
+}
+
+// newDatabaseConfig returns the config of the master, the replicas and their pools of the environment.
+func newDatabaseConfig(env environment.Environment) (database.Config, error) {
+	config := database.Config{
+		MasterURL: env.DatabaseURL,
+		Pool: database.PoolConfig{
+			MaxOpenConns: env.DatabaseMaxOpenConns,
+			MaxIdleConns: env.DatabaseMaxIdleConns,
+		},
+	}
+	for _, d := range []struct {
+		name     string
+		value    string
+		duration *time.Duration
+	}{
+		{"DATABASE_REPLICA_MAX_LAG", env.DatabaseReplicaMaxLag, &config.MaxReplicaLag},
+		{"DATABASE_CONN_MAX_LIFETIME", env.DatabaseConnMaxLifetime, &config.Pool.ConnMaxLifetime},
+		{"DATABASE_CONN_MAX_IDLE_TIME", env.DatabaseConnMaxIdleTime, &config.Pool.ConnMaxIdleTime},
+		{"DATABASE_STATEMENT_TIMEOUT", env.DatabaseStatementTimeout, &config.Pool.StatementTimeout},
+		{"DATABASE_SLOW_QUERY_THRESHOLD", env.DatabaseSlowQueryThreshold, &config.SlowQueryThreshold},
+	} {
+		var err error
+		if *d.duration, err = time.ParseDuration(d.value); err != nil {
+			return database.Config{}, fmt.Errorf("%s: %w", d.name, err)
+		}
+	}
+
+	for _, url := range strings.Split(env.DatabaseReplicaURLs, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			config.ReplicaURLs = append(config.ReplicaURLs, url)
+		}
+	}
+
+	return config, nil
+}
+
+// authenticators authenticate the API keys and the bearer tokens, tokens is nil when no JWKS is configured.
+type authenticators struct {
+	apiKeys auth.Authenticator
+	tokens  auth.Authenticator
+}
+
+func newAuthenticators(env environment.Environment, svc apikeys.Service) (authenticators, error) {
+	if env.JWKSFiles == "" {
+		return authenticators{apiKeys: svc}, nil
+	}
+
+	tokens, err := auth.NewJWTAuthenticator(env.JWTIssuer, env.JWTAudience, strings.Split(env.JWKSFiles, ",")...)
+	if err != nil {
+		return authenticators{}, err
+	}
+
+	return authenticators{apiKeys: svc, tokens: tokens}, nil
+}
+
+// rateLimits are the rate limiting middlewares of the routes of the HTTP API.
+type rateLimits struct {
+	ip  middlewares.Middleware
+	v1  middlewares.Middleware
+	p2p echo.MiddlewareFunc
+}
+
+func newRateLimits(
+	env environment.Environment,
+	limiter ratelimit.Limiter,
+	route func(request *http.Request) string,
+) (rateLimits, error) {
+	rates, err := parseRateLimitRates(env)
+	if err != nil {
+		return rateLimits{}, err
+	}
+
+	return rateLimits{
+		ip: middlewares.NewRateLimitMiddleware(limiter, middlewares.RateLimitPolicy{
+			Name: "ip",
+			Rate: rates.ip,
+			Keys: []middlewares.RateLimitKey{middlewares.RateLimitByIP},
+		}, "/readiness", "/liveness"),
+		v1: middlewares.NewRateLimitMiddleware(limiter, middlewares.RateLimitPolicy{
+			Name:  "v1",
+			Rate:  rates.v1,
+			Keys:  []middlewares.RateLimitKey{middlewares.RateLimitByPrincipal, middlewares.RateLimitByRoute},
+			Route: route,
+		}, "/readiness", "/liveness", "/openapi.json", "/docs"),
+		p2p: echo.WrapMiddleware(middlewares.NewRateLimitMiddleware(limiter, middlewares.RateLimitPolicy{
+			Name:      "p2p",
+			Rate:      rates.p2p,
+			Keys:      []middlewares.RateLimitKey{middlewares.RateLimitByPrincipal, middlewares.RateLimitByAccount},
+			AccountID: middlewares.AccountFromJSONBody("from_account_id"),
+		})),
+	}, nil
+}
+
+// grpcRateLimits are the rate limiting interceptors of the gRPC API, with the rates of the HTTP API. The transfers
+// share their windows with the ones of the HTTP API, so a principal has the same rate from an account in both.
+type grpcRateLimits struct {
+	ip  grpc.UnaryServerInterceptor
+	v1  grpc.UnaryServerInterceptor
+	p2p grpc.UnaryServerInterceptor
+}
+
+func newGRPCRateLimits(env environment.Environment, limiter ratelimit.Limiter) (grpcRateLimits, error) {
+	rates, err := parseRateLimitRates(env)
+	if err != nil {
+		return grpcRateLimits{}, err
+	}
+
+	return grpcRateLimits{
+		ip: interceptors.NewRateLimitUnaryServerInterceptor(limiter, interceptors.RateLimitPolicy{
+			Name: "ip",
+			Rate: rates.ip,
+			Keys: []interceptors.RateLimitKey{interceptors.RateLimitByIP},
+		}, healthpb.Health_Check_FullMethodName),
+		v1: interceptors.NewRateLimitUnaryServerInterceptor(limiter, interceptors.RateLimitPolicy{
+			Name: "v1",
+			Rate: rates.v1,
+			Keys: []interceptors.RateLimitKey{interceptors.RateLimitByPrincipal, interceptors.RateLimitByMethod},
+		}, healthpb.Health_Check_FullMethodName),
+		p2p: interceptors.NewRateLimitUnaryServerInterceptor(limiter, interceptors.RateLimitPolicy{
+			Name:    "p2p",
+			Rate:    rates.p2p,
+			Keys:    []interceptors.RateLimitKey{interceptors.RateLimitByPrincipal, interceptors.RateLimitByAccount},
+			Methods: []string{ledgerv1.TransactionService_CreateP2P_FullMethodName},
+			AccountID: func(req interface{}) string {
+				if r, ok := req.(*ledgerv1.CreateP2PRequest); ok {
+					return r.GetFromAccountId()
+				}
+				return ""
+			},
+		}),
+	}, nil
+}
+
+// rateLimitRates are the rates of the policies of the APIs.
+type rateLimitRates struct {
+	ip  ratelimit.Rate
+	v1  ratelimit.Rate
+	p2p ratelimit.Rate
+}
+
+func parseRateLimitRates(env environment.Environment) (rateLimitRates, error) {
+	ip, err := ratelimit.ParseRate(env.RateLimitIP)
+	if err != nil {
+		return rateLimitRates{}, fmt.Errorf("RATE_LIMIT_IP: %w", err)
+	}
+	v1, err := ratelimit.ParseRate(env.RateLimitDefault)
+	if err != nil {
+		return rateLimitRates{}, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+	p2p, err := ratelimit.ParseRate(env.RateLimitP2P)
+	if err != nil {
+		return rateLimitRates{}, fmt.Errorf("RATE_LIMIT_P2P: %w", err)
+	}
+
+	return rateLimitRates{ip: ip, v1: v1, p2p: p2p}, nil
+}
+
+// echoRoute returns the route template the router of e matches the request to, e.g. /v1/accounts/:id, or empty when
+// no route matches it.
+func echoRoute(e *echo.Echo) func(request *http.Request) string {
+	return func(request *http.Request) string {
+		c := e.NewContext(request, nil)
+		e.Router().Find(request.Method, echo.GetPath(request), c)
+		return c.Path()
+	}
+}
+
+// registerRoutes registers the routes of the HTTP API, each one must be described in the OpenAPI document. The
+// routes of v1 are authorized by the scopes of the principal, and the services restrict the principals bound to a
+// holder to the holder and its accounts.
+func registerRoutes(e *echo.Echo, h httpHandlers, limits rateLimits) {
+	e.GET("/readiness", echo.HandlerFunc(h.Readiness))
+	e.GET("/liveness", echo.HandlerFunc(h.Liveness))
+	e.GET("/openapi.json", echo.HandlerFunc(h.OpenAPI))
+	e.GET("/docs", echo.HandlerFunc(h.Docs))
+	v1 := e.Group("/v1")
+	v1.POST("/holders", echo.HandlerFunc(h.CreateHolder), scoped(authz.ScopeHoldersWrite))
+	v1.GET("/holders/:id", echo.HandlerFunc(h.GetByIDHolder), scoped(authz.ScopeHoldersRead))
+	v1.GET("/holders", echo.HandlerFunc(h.ListHolders), scoped(authz.ScopeHoldersRead))
+	v1.POST("/accounts", echo.HandlerFunc(h.CreateAccount), scoped(authz.ScopeAccountsWrite))
+	v1.GET("/accounts", echo.HandlerFunc(h.ListAccounts), scoped(authz.ScopeAccountsRead))
+	v1.GET("/accounts/:id", echo.HandlerFunc(h.GetByIDAccount), scoped(authz.ScopeAccountsRead))
+	v1.PUT("/accounts/:id/blocks", echo.HandlerFunc(h.BlockByID), scoped(authz.ScopeAccountsManage))
+	v1.PUT("/accounts/:id/unblocks", echo.HandlerFunc(h.UnblockByID), scoped(authz.ScopeAccountsManage))
+	v1.PUT("/accounts/:id/closes", echo.HandlerFunc(h.CloseByID), scoped(authz.ScopeAccountsManage))
+	v1.GET("/accounts/:id/statements", echo.HandlerFunc(h.ListAccountStatement), scoped(authz.ScopeStatementsRead))
+	v1.GET(
+		"/accounts/:id/statements/export",
+		echo.HandlerFunc(h.ExportAccountStatement),
+		scoped(authz.ScopeStatementsRead),
+	)
+	v1.GET(
+		"/accounts/:id/statements/monthly",
+		echo.HandlerFunc(h.ListMonthlyStatements),
+		scoped(authz.ScopeStatementsRead),
+	)
+	v1.GET(
+		"/accounts/:id/statements/monthly/:month",
+		echo.HandlerFunc(h.GetMonthlyStatement),
+		scoped(authz.ScopeStatementsRead),
+	)
+	v1.GET("/accounts/:id/balances", echo.HandlerFunc(h.GetBalanceByAccountID), scoped(authz.ScopeBalancesRead))
+	v1.POST("/transactions/credits", echo.HandlerFunc(h.CreateCredit), scoped(authz.ScopeTransactionsCredit))
+	v1.POST("/transactions/debits", echo.HandlerFunc(h.CreateDebit), scoped(authz.ScopeTransactionsDebit))
+	v1.POST("/transactions/p2p", echo.HandlerFunc(h.CreateP2P), limits.p2p, scoped(authz.ScopeTransactionsP2P))
+	v1.GET("/transactions/:id", echo.HandlerFunc(h.GetByIDTransaction), scoped(authz.ScopeTransactionsRead))
+	v1.POST("/reconciliations", echo.HandlerFunc(h.CreateReconciliation), scoped(authz.ScopeReconciliations))
+	v1.GET("/reconciliations/:id", echo.HandlerFunc(h.GetReconciliation), scoped(authz.ScopeReconciliations))
+	v1.PUT("/business-days/:date/closes", echo.HandlerFunc(h.CloseBusinessDay), scoped(authz.ScopeBusinessDays))
+	v1.PUT("/business-days/:date/adjustments", echo.HandlerFunc(h.OpenAdjustment), scoped(authz.ScopeBusinessDays))
+	v1.GET("/business-days/:date/trial-balance", echo.HandlerFunc(h.GetTrialBalance), scoped(authz.ScopeBusinessDays))
+	v1.GET("/ledger-chain/checkpoints/:date", echo.HandlerFunc(h.GetCheckpoint), scoped(authz.ScopeLedgerChain))
+	// The fields of the GraphQL API are authorized by its resolvers.
+	v1.POST("/graphql", echo.HandlerFunc(h.GraphQL))
+	v1.POST("/api-keys", echo.HandlerFunc(h.CreateAPIKey), scoped(authz.ScopeAPIKeys))
+	v1.PUT("/api-keys/:id/rotations", echo.HandlerFunc(h.RotateAPIKey), scoped(authz.ScopeAPIKeys))
+	v1.PUT("/api-keys/:id/revokes", echo.HandlerFunc(h.RevokeAPIKey), scoped(authz.ScopeAPIKeys))
+	v1.GET("/admin/database", echo.HandlerFunc(h.GetDatabaseStats), scoped(authz.ScopeAdmin))
+}
+
+// scoped authorizes the principals with the scopes on a route.
+func scoped(scopes ...authz.Scope) echo.MiddlewareFunc {
+	return echo.WrapMiddleware(middlewares.NewAuthorizationMiddleware(scopes...))
+}
+
+// grpcScopes are the scopes of the methods of the gRPC API, the methods missing here are denied.
+var grpcScopes = map[string]authz.Scope{
+	ledgerv1.HolderService_CreateHolder_FullMethodName:        authz.ScopeHoldersWrite,
+	ledgerv1.HolderService_GetHolder_FullMethodName:           authz.ScopeHoldersRead,
+	ledgerv1.HolderService_ListHolders_FullMethodName:         authz.ScopeHoldersRead,
+	ledgerv1.AccountService_CreateAccount_FullMethodName:      authz.ScopeAccountsWrite,
+	ledgerv1.AccountService_GetAccount_FullMethodName:         authz.ScopeAccountsRead,
+	ledgerv1.AccountService_ListAccounts_FullMethodName:       authz.ScopeAccountsRead,
+	ledgerv1.AccountService_BlockAccount_FullMethodName:       authz.ScopeAccountsManage,
+	ledgerv1.AccountService_UnblockAccount_FullMethodName:     authz.ScopeAccountsManage,
+	ledgerv1.AccountService_CloseAccount_FullMethodName:       authz.ScopeAccountsManage,
+	ledgerv1.TransactionService_CreateCredit_FullMethodName:   authz.ScopeTransactionsCredit,
+	ledgerv1.TransactionService_CreateDebit_FullMethodName:    authz.ScopeTransactionsDebit,
+	ledgerv1.TransactionService_CreateP2P_FullMethodName:      authz.ScopeTransactionsP2P,
+	ledgerv1.TransactionService_GetTransaction_FullMethodName: authz.ScopeTransactionsRead,
+	ledgerv1.BalanceService_GetBalance_FullMethodName:         authz.ScopeBalancesRead,
+	ledgerv1.StatementService_ListStatements_FullMethodName:   authz.ScopeStatementsRead,
+}
+
 func runHTTPServer(
 	lc fx.Lifecycle,
 	env environment.Environment,
 	t tracer.Tracer,
-	readinessFunc handlers.ReadinessFunc,
-	livenessFunc handlers.LivenessFunc,
-	createHolderFunc holdersh.CreateHolderFunc,
-	getByIDHolderFunc holdersh.GetByIDHolderFunc,
-	listHoldersFunc holdersh.ListHoldersFunc,
-	createAccountFunc accountsh.CreateAccountFunc,
-	closeByIDFunc accountsh.CloseByIDFunc,
-	blockByIDFunc accountsh.BlockByIDFunc,
-	unblockByIDFunc accountsh.UnblockByIDFunc,
-	getByIDAccountFunc accountsh.GetByIDFunc,
-	listAccountsFunc accountsh.ListAccountsFunc,
-	createCreditTransactionFunc transactionsh.CreateCreditTransactionFunc,
-	createDebitTransactionFunc transactionsh.CreateDebitTransactionFunc,
-	createP2PTransactionFunc transactionsh.CreateP2PTransactionFunc,
-	getByIDTransactionFunc transactionsh.GetByIDTransactionFunc,
-	listAccountStatementFunc statementsh.ListAccountStatementFunc,
-	getBalanceByIDAccountFunc balancesh.GetBalanceByAccountIDFunc,
+	m metrics.Metrics,
+	doc *openapi3.T,
+	authn authenticators,
+	tenants tenancy.Tenants,
+	limiter ratelimit.Limiter,
+	h httpHandlers,
 ) error {
 	e := echo.New()
+	limits, err := newRateLimits(env, limiter, echoRoute(e))
+	if err != nil {
+		return err
+	}
 
-	e.GET("/readiness", echo.HandlerFunc(readinessFunc))
-	e.GET("/liveness", echo.HandlerFunc(livenessFunc))
-	v1 := e.Group("/v1")
-	v1.POST("/holders", echo.HandlerFunc(createHolderFunc))
-	v1.GET("/holders/:id", echo.HandlerFunc(getByIDHolderFunc))
-	v1.GET("/holders", echo.HandlerFunc(listHoldersFunc))
-	v1.POST("/accounts", echo.HandlerFunc(createAccountFunc))
-	v1.GET("/accounts", echo.HandlerFunc(listAccountsFunc))
-	v1.GET("/accounts/:id", echo.HandlerFunc(getByIDAccountFunc))
-	v1.PUT("/accounts/:id/blocks", echo.HandlerFunc(blockByIDFunc))
-	v1.PUT("/accounts/:id/unblocks", echo.HandlerFunc(unblockByIDFunc))
-	v1.PUT("/accounts/:id/closes", echo.HandlerFunc(closeByIDFunc))
-	v1.GET("/accounts/:id/statements", echo.HandlerFunc(listAccountStatementFunc))
-	v1.GET("/accounts/:id/balances", echo.HandlerFunc(getBalanceByIDAccountFunc))
-	v1.POST("/transactions/credits", echo.HandlerFunc(createCreditTransactionFunc))
-	v1.POST("/transactions/debits", echo.HandlerFunc(createDebitTransactionFunc))
-	v1.POST("/transactions/p2p", echo.HandlerFunc(createP2PTransactionFunc))
-	v1.GET("/transactions/:id", echo.HandlerFunc(getByIDTransactionFunc))
+	e.HTTPErrorHandler = apierrors.NewHTTPErrorHandler()
+	registerRoutes(e, h, limits)
 
 	hmux := http.NewServeMux()
 	hmux.Handle("/", e)
@@ -165,10 +573,34 @@ func runHTTPServer(
 		hmux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	}
 
-	apiMiddlewares := make([]middlewares.Middleware, 0, 3)
+	// The responses are only validated in the tests, they are buffered to be validated.
+	openAPIValidator, err := middlewares.NewOpenAPIValidationMiddleware(doc, env.Environment == testEnvironment)
+	if err != nil {
+		return err
+	}
+
+	metricsMiddleware, err := middlewares.NewMetricsHTTPMiddleware(m, "/readiness", "/liveness")
+	if err != nil {
+		return err
+	}
+
+	apiMiddlewares := make([]middlewares.Middleware, 0, 9)
 	apiMiddlewares = append(apiMiddlewares, middlewares.NewTracerHTTPMiddleware(t, "/", "/readiness", "/liveness"))
+	apiMiddlewares = append(apiMiddlewares, metricsMiddleware)
 	apiMiddlewares = append(apiMiddlewares, middlewares.NewRecoveryHTTPMiddleware())
+	apiMiddlewares = append(apiMiddlewares, limits.ip)
+	apiMiddlewares = append(apiMiddlewares, middlewares.NewAuthenticationMiddleware(
+		authn.apiKeys,
+		authn.tokens,
+		"/readiness", "/liveness", "/openapi.json", "/docs",
+	))
+	apiMiddlewares = append(apiMiddlewares, middlewares.NewTenancyMiddleware(
+		tenants,
+		"/readiness", "/liveness", "/openapi.json", "/docs",
+	))
+	apiMiddlewares = append(apiMiddlewares, limits.v1)
 	apiMiddlewares = append(apiMiddlewares, middlewares.NewDefaultContentTypeValidator())
+	apiMiddlewares = append(apiMiddlewares, openAPIValidator)
 
 	httpServer := &http.Server{
 		Addr:    fmt.Sprintf(":%s", env.HTTPPort),
@@ -196,3 +628,86 @@ func runHTTPServer(
 
 	return nil
 }
+
+func runGRPCServer(
+	lc fx.Lifecycle,
+	env environment.Environment,
+	t tracer.Tracer,
+	authn authenticators,
+	tenants tenancy.Tenants,
+	limiter ratelimit.Limiter,
+	healthServer healthpb.HealthServer,
+	holderServer ledgerv1.HolderServiceServer,
+	accountServer ledgerv1.AccountServiceServer,
+	transactionServer ledgerv1.TransactionServiceServer,
+	balanceServer ledgerv1.BalanceServiceServer,
+	statementServer ledgerv1.StatementServiceServer,
+) error {
+	limits, err := newGRPCRateLimits(env, limiter)
+	if err != nil {
+		return err
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			interceptors.NewTracerUnaryServerInterceptor(t, healthpb.Health_Check_FullMethodName),
+			interceptors.NewRecoveryUnaryServerInterceptor(),
+			limits.ip,
+			interceptors.NewAuthenticationUnaryServerInterceptor(
+				authn.apiKeys,
+				authn.tokens,
+				healthpb.Health_Check_FullMethodName,
+			),
+			interceptors.NewTenancyUnaryServerInterceptor(tenants, healthpb.Health_Check_FullMethodName),
+			limits.v1,
+			limits.p2p,
+			interceptors.NewAuthorizationUnaryServerInterceptor(grpcScopes, healthpb.Health_Check_FullMethodName),
+		),
+	)
+
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	ledgerv1.RegisterHolderServiceServer(grpcServer, holderServer)
+	ledgerv1.RegisterAccountServiceServer(grpcServer, accountServer)
+	ledgerv1.RegisterTransactionServiceServer(grpcServer, transactionServer)
+	ledgerv1.RegisterBalanceServiceServer(grpcServer, balanceServer)
+	ledgerv1.RegisterStatementServiceServer(grpcServer, statementServer)
+
+	addr := fmt.Sprintf(":%s", env.GRPCPort)
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				zap.L().Info(
+					"grpc_server_up",
+					zap.String("description", "up and running grpc server"),
+					zap.String("address", addr),
+				)
+				if err := grpcServer.Serve(listener); !errors.Is(err, grpc.ErrServerStopped) {
+					zap.L().Error("grpc_server_down", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+
+			select {
+			case <-stopped:
+			case <-ctx.Done():
+				grpcServer.Stop()
+			}
+			return nil
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/openapih"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pathParam = regexp.MustCompile(`:([^/]+)`)
+
+func TestRoutesInOpenAPIDocument(t *testing.T) {
+	doc, err := openapih.Load()
+	require.NoError(t, err)
+
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	e := echo.New()
+	limits, err := newRateLimits(environment.Environment{}, ratelimit.NewMemoryLimiter(), echoRoute(e))
+	require.NoError(t, err)
+	registerRoutes(e, httpHandlers{}, limits)
+	require.NotEmpty(t, e.Routes())
+
+	for _, route := range e.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+
+		pathItem := doc.Paths.Find(path)
+		if !assert.NotNil(t, pathItem, "route %s %s is missing in the openapi document", route.Method, path) {
+			continue
+		}
+		assert.NotNil(
+			t,
+			pathItem.GetOperation(route.Method),
+			"route %s %s is missing in the openapi document",
+			route.Method,
+			path,
+		)
+
+		// The document must route the requests of the route, the path parameters are filled with dummy values.
+		request, err := http.NewRequest(route.Method, pathParam.ReplaceAllString(route.Path, "x"), http.NoBody)
+		require.NoError(t, err)
+		_, _, err = router.FindRoute(request)
+		assert.NoError(t, err, "route %s %s is not routed by the openapi document", route.Method, path)
+	}
+}
+
+func TestEchoRoute(t *testing.T) {
+	e := echo.New()
+	limits, err := newRateLimits(environment.Environment{}, ratelimit.NewMemoryLimiter(), echoRoute(e))
+	require.NoError(t, err)
+	registerRoutes(e, httpHandlers{}, limits)
+
+	route := echoRoute(e)
+	for _, id := range []string{"5b0b9f1c-58a4-4f6e-9a3c-2b0c9b7d1e01", "c1d2e3f4-0a1b-4c2d-8e3f-405162738495"} {
+		request, err := http.NewRequest(http.MethodPut, "/v1/accounts/"+id+"/blocks", http.NoBody)
+		require.NoError(t, err)
+		assert.Equal(t, "/v1/accounts/:id/blocks", route(request))
+	}
+
+	request, err := http.NewRequest(http.MethodGet, "/v1/unknown", http.NoBody)
+	require.NoError(t, err)
+	assert.Empty(t, route(request))
+}
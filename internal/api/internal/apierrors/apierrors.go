@@ -0,0 +1,305 @@
+package apierrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
+	"github.com/dalmarcogd/ledger-exp/internal/monthlystatements"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// The codes of the domain errors, they are stable: a code is never renamed nor reused for another error.
+const (
+	CodeHolderNotFound           problem.Code = "holder_not_found"
+	CodeHolderAlreadyExists      problem.Code = "holder_already_exists"
+	CodeAccountNotFound          problem.Code = "account_not_found"
+	CodeAccountAlreadyExists     problem.Code = "account_already_exists"
+	CodeAccountInactive          problem.Code = "account_inactive"
+	CodeAccountNotBlocked        problem.Code = "account_not_blocked"
+	CodeAccountLocked            problem.Code = "account_locked"
+	CodeSameAccount              problem.Code = "same_account"
+	CodeInsufficientFunds        problem.Code = "insufficient_funds"
+	CodeDailyLimitExceeded       problem.Code = "daily_limit_exceeded"
+	CodeTransactionNotFound      problem.Code = "transaction_not_found"
+	CodeTransactionInFuture      problem.Code = "transaction_in_future"
+	CodePeriodClosed             problem.Code = "period_closed"
+	CodeNotAdjustmentPeriod      problem.Code = "not_adjustment_period"
+	CodeInvalidDirection         problem.Code = "invalid_direction"
+	CodeInvalidAmountRange       problem.Code = "invalid_amount_range"
+	CodeExportPeriodRequired     problem.Code = "export_period_required"
+	CodeInvalidExportPeriod      problem.Code = "invalid_export_period"
+	CodeUnsupportedExportFormat  problem.Code = "unsupported_export_format"
+	CodeMonthlyStatementNotFound problem.Code = "monthly_statement_not_found"
+	CodeMonthNotClosed           problem.Code = "month_not_closed"
+	CodeInvalidMonth             problem.Code = "invalid_month"
+	CodeReconciliationNotFound   problem.Code = "reconciliation_not_found"
+	CodeUnsupportedFileFormat    problem.Code = "unsupported_file_format"
+	CodeInvalidFile              problem.Code = "invalid_file"
+	CodeEmptyFile                problem.Code = "empty_file"
+	CodeInvalidTolerance         problem.Code = "invalid_tolerance"
+	CodeBusinessDayNotFound      problem.Code = "business_day_not_found"
+	CodeBusinessDayNotOver       problem.Code = "business_day_not_over"
+	CodePreviousBusinessDayOpen  problem.Code = "previous_business_day_open"
+	CodeBusinessDayClosed        problem.Code = "business_day_closed"
+	CodeInvalidDate              problem.Code = "invalid_date"
+	CodeCheckpointNotFound       problem.Code = "checkpoint_not_found"
+	CodeDayNotOver               problem.Code = "day_not_over"
+	CodeAPIKeyNotFound           problem.Code = "api_key_not_found"
+	CodeAPIKeyRevoked            problem.Code = "api_key_revoked"
+	CodeUnknownScope             problem.Code = "unknown_scope"
+	CodeInvalidCursor            problem.Code = "invalid_cursor"
+	CodeReferenceViolated        problem.Code = "reference_violated"
+	CodeConstraintViolated       problem.Code = "constraint_violated"
+	CodeValueRequired            problem.Code = "value_required"
+)
+
+// Entry is a domain error of the catalog with the status and the code of its responses.
+type Entry struct {
+	Err    error
+	Status int
+	Code   problem.Code
+	// GRPCCode is the code of the gRPC status, the one of the Status when it is OK, see GRPCStatusCode.
+	GRPCCode codes.Code
+}
+
+// grpcCodes are the codes of the gRPC status of the statuses of the Catalog.
+var grpcCodes = map[int]codes.Code{
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusNotAcceptable:       codes.InvalidArgument,
+	http.StatusConflict:            codes.FailedPrecondition,
+	http.StatusUnprocessableEntity: codes.InvalidArgument,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// GRPCStatusCode returns the code of the gRPC status of the calls failed with the error of the entry.
+func (e Entry) GRPCStatusCode() codes.Code {
+	if e.GRPCCode != codes.OK {
+		return e.GRPCCode
+	}
+	if code, ok := grpcCodes[e.Status]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// Catalog are the domain errors replied by the API, the first entry matching an error wins. The errors missing here
+// are internal.
+var Catalog = []Entry{
+	{Err: holders.ErrHolderNotFound, Status: http.StatusNotFound, Code: CodeHolderNotFound},
+	{
+		Err:      holders.ErrHolderAlreadyExists,
+		Status:   http.StatusConflict,
+		Code:     CodeHolderAlreadyExists,
+		GRPCCode: codes.AlreadyExists,
+	},
+	{Err: accounts.ErrAccountHolderNotFound, Status: http.StatusNotFound, Code: CodeHolderNotFound},
+	{Err: accounts.ErrAccountNotFound, Status: http.StatusNotFound, Code: CodeAccountNotFound},
+	{
+		Err:      accounts.ErrAccountAlreadyExists,
+		Status:   http.StatusConflict,
+		Code:     CodeAccountAlreadyExists,
+		GRPCCode: codes.AlreadyExists,
+	},
+	{Err: accounts.ErrAccountInactive, Status: http.StatusConflict, Code: CodeAccountInactive},
+	{Err: accounts.ErrAccountUnblcked, Status: http.StatusConflict, Code: CodeAccountNotBlocked},
+	{Err: transactions.ErrAccountNotfound, Status: http.StatusNotFound, Code: CodeAccountNotFound},
+	{Err: transactions.ErrAccountInactive, Status: http.StatusConflict, Code: CodeAccountInactive},
+	// The lock of the account is held by another operation, the request can be retried.
+	{
+		Err:      transactions.ErrFailLockAccount,
+		Status:   http.StatusConflict,
+		Code:     CodeAccountLocked,
+		GRPCCode: codes.Aborted,
+	},
+	{
+		Err:    transactions.ErrFromAccountToAccountShouldBeDifferent,
+		Status: http.StatusUnprocessableEntity,
+		Code:   CodeSameAccount,
+	},
+	{
+		Err:      transactions.ErrBalanceInsufficientFunds,
+		Status:   http.StatusUnprocessableEntity,
+		Code:     CodeInsufficientFunds,
+		GRPCCode: codes.FailedPrecondition,
+	},
+	{
+		Err:      transactions.ErrInsufficientDailyLimit,
+		Status:   http.StatusUnprocessableEntity,
+		Code:     CodeDailyLimitExceeded,
+		GRPCCode: codes.FailedPrecondition,
+	},
+	{Err: transactions.ErrTransactionNotFound, Status: http.StatusNotFound, Code: CodeTransactionNotFound},
+	{Err: transactions.ErrTransactionInFuture, Status: http.StatusUnprocessableEntity, Code: CodeTransactionInFuture},
+	{Err: transactions.ErrPeriodClosed, Status: http.StatusConflict, Code: CodePeriodClosed},
+	{Err: transactions.ErrNotAdjustmentPeriod, Status: http.StatusConflict, Code: CodeNotAdjustmentPeriod},
+	{Err: statements.ErrInvalidDirection, Status: http.StatusUnprocessableEntity, Code: CodeInvalidDirection},
+	{Err: statements.ErrInvalidAmountRange, Status: http.StatusUnprocessableEntity, Code: CodeInvalidAmountRange},
+	{Err: statements.ErrExportPeriodRequired, Status: http.StatusUnprocessableEntity, Code: CodeExportPeriodRequired},
+	{Err: statements.ErrInvalidExportPeriod, Status: http.StatusUnprocessableEntity, Code: CodeInvalidExportPeriod},
+	{Err: statements.ErrUnsupportedExportFormat, Status: http.StatusNotAcceptable, Code: CodeUnsupportedExportFormat},
+	{
+		Err:    monthlystatements.ErrMonthlyStatementNotFound,
+		Status: http.StatusNotFound,
+		Code:   CodeMonthlyStatementNotFound,
+	},
+	{Err: monthlystatements.ErrMonthNotClosed, Status: http.StatusConflict, Code: CodeMonthNotClosed},
+	{Err: monthlystatements.ErrInvalidMonth, Status: http.StatusUnprocessableEntity, Code: CodeInvalidMonth},
+	{Err: reconciliations.ErrReconciliationNotFound, Status: http.StatusNotFound, Code: CodeReconciliationNotFound},
+	{Err: reconciliations.ErrUnsupportedFormat, Status: http.StatusUnprocessableEntity, Code: CodeUnsupportedFileFormat},
+	{Err: reconciliations.ErrInvalidFile, Status: http.StatusUnprocessableEntity, Code: CodeInvalidFile},
+	{Err: reconciliations.ErrEmptyFile, Status: http.StatusUnprocessableEntity, Code: CodeEmptyFile},
+	{Err: reconciliations.ErrInvalidTolerance, Status: http.StatusUnprocessableEntity, Code: CodeInvalidTolerance},
+	{Err: businessdays.ErrBusinessDayNotFound, Status: http.StatusNotFound, Code: CodeBusinessDayNotFound},
+	{Err: businessdays.ErrBusinessDayNotOver, Status: http.StatusConflict, Code: CodeBusinessDayNotOver},
+	{Err: businessdays.ErrPreviousBusinessDayOpen, Status: http.StatusConflict, Code: CodePreviousBusinessDayOpen},
+	{Err: businessdays.ErrBusinessDayClosed, Status: http.StatusConflict, Code: CodeBusinessDayClosed},
+	{Err: businessdays.ErrNotAdjustmentPeriod, Status: http.StatusConflict, Code: CodeNotAdjustmentPeriod},
+	{Err: businessdays.ErrInvalidDate, Status: http.StatusUnprocessableEntity, Code: CodeInvalidDate},
+	{Err: ledgerchain.ErrCheckpointNotFound, Status: http.StatusNotFound, Code: CodeCheckpointNotFound},
+	{Err: ledgerchain.ErrDayNotOver, Status: http.StatusConflict, Code: CodeDayNotOver},
+	{Err: apikeys.ErrAPIKeyNotFound, Status: http.StatusNotFound, Code: CodeAPIKeyNotFound},
+	{Err: apikeys.ErrAPIKeyRevoked, Status: http.StatusConflict, Code: CodeAPIKeyRevoked},
+	{Err: apikeys.ErrUnknownScope, Status: http.StatusUnprocessableEntity, Code: CodeUnknownScope},
+	{Err: cursor.ErrInvalidCursor, Status: http.StatusUnprocessableEntity, Code: CodeInvalidCursor},
+	{Err: authz.ErrForbidden, Status: http.StatusForbidden, Code: problem.CodePermissionDenied},
+	// The violations of the constraints without a domain error, and the transactions aborted by concurrent ones, which
+	// can be retried.
+	{
+		Err:      database.ErrUniqueViolation,
+		Status:   http.StatusConflict,
+		Code:     problem.CodeConflict,
+		GRPCCode: codes.AlreadyExists,
+	},
+	// A referenced row is missing, or a row is still referenced by others.
+	{Err: database.ErrForeignKeyViolation, Status: http.StatusConflict, Code: CodeReferenceViolated},
+	{Err: database.ErrCheckViolation, Status: http.StatusUnprocessableEntity, Code: CodeConstraintViolated},
+	{Err: database.ErrNotNullViolation, Status: http.StatusUnprocessableEntity, Code: CodeValueRequired},
+	{
+		Err:      database.ErrSerializationFailure,
+		Status:   http.StatusConflict,
+		Code:     problem.CodeConflict,
+		GRPCCode: codes.Aborted,
+	},
+	{Err: context.DeadlineExceeded, Status: http.StatusGatewayTimeout, Code: problem.CodeTimeout},
+}
+
+// statusCodes are the codes of the errors of echo and of the handlers built by echo.NewHTTPError.
+var statusCodes = map[int]problem.Code{
+	http.StatusBadRequest:           problem.CodeInvalidRequest,
+	http.StatusUnauthorized:         problem.CodeUnauthenticated,
+	http.StatusForbidden:            problem.CodePermissionDenied,
+	http.StatusNotFound:             problem.CodeNotFound,
+	http.StatusMethodNotAllowed:     problem.CodeMethodNotAllowed,
+	http.StatusNotAcceptable:        problem.CodeNotAcceptable,
+	http.StatusConflict:             problem.CodeConflict,
+	http.StatusUnsupportedMediaType: problem.CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:  problem.CodeValidationFailed,
+	http.StatusTooManyRequests:      problem.CodeRateLimited,
+	http.StatusServiceUnavailable:   problem.CodeUnavailable,
+	http.StatusGatewayTimeout:       problem.CodeTimeout,
+}
+
+// FromError converts the errors of the handlers to problems: the problems as they are, the domain errors of the
+// Catalog with their message, the validation errors with the invalid fields, the errors of echo by their status and
+// any other error as internal without leaking its details.
+func FromError(err error) problem.Problem {
+	var p problem.Problem
+	if errors.As(err, &p) {
+		return p
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return fromHTTPError(he)
+	}
+
+	var validationErrors validation.Errors
+	if errors.As(err, &validationErrors) {
+		p := problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed, "invalid request")
+		fields := make([]string, 0, len(validationErrors))
+		for field := range validationErrors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			p.Errors = append(p.Errors, problem.FieldError{
+				Field:    field,
+				Location: "body",
+				Message:  validationErrors[field].Error(),
+			})
+		}
+		return p
+	}
+
+	for _, entry := range Catalog {
+		if errors.Is(err, entry.Err) {
+			return problem.New(entry.Status, entry.Code, entry.Err.Error())
+		}
+	}
+
+	return problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error")
+}
+
+func fromHTTPError(he *echo.HTTPError) problem.Problem {
+	if he.Code >= http.StatusInternalServerError && he.Code != http.StatusServiceUnavailable {
+		return problem.New(he.Code, problem.CodeInternal, "internal error")
+	}
+
+	code, ok := statusCodes[he.Code]
+	if !ok {
+		code = problem.CodeInvalidRequest
+	}
+
+	detail := http.StatusText(he.Code)
+	if he.Message != nil {
+		detail = fmt.Sprint(he.Message)
+	}
+
+	return problem.New(he.Code, code, detail)
+}
+
+// NewHTTPErrorHandler returns the error handler of echo replying the errors of the handlers as problems, see
+// FromError. The internal errors are logged with their details, and the code is set in the span of the request.
+func NewHTTPErrorHandler() echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		ctx := c.Request().Context()
+		p := FromError(err)
+		if p.Status >= http.StatusInternalServerError {
+			zapctx.L(ctx).Error("http_error_handler_internal_error", zap.Error(err))
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("error.code", string(p.Code)))
+
+		if c.Request().Method == http.MethodHead {
+			_ = c.NoContent(p.Status)
+			return
+		}
+
+		problem.Write(c.Response(), c.Request(), p)
+	}
+}
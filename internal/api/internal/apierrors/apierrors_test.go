@@ -0,0 +1,162 @@
+//go:build unit
+
+package apierrors
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var codeFormat = regexp.MustCompile(`^[a-z]+(_[a-z]+)*$`)
+
+func TestCatalog(t *testing.T) {
+	for _, entry := range Catalog {
+		assert.Regexp(t, codeFormat, entry.Code, entry.Err.Error())
+		assert.Equal(t, entry.Code, FromError(entry.Err).Code, entry.Err.Error())
+		assert.NotEqual(t, codes.Unknown, entry.GRPCStatusCode(), entry.Err.Error())
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		problem problem.Problem
+	}{
+		{
+			name: "Domain error",
+			err:  fmt.Errorf("debit: %w", transactions.ErrBalanceInsufficientFunds),
+			problem: problem.New(
+				http.StatusUnprocessableEntity,
+				CodeInsufficientFunds,
+				transactions.ErrBalanceInsufficientFunds.Error(),
+			),
+		},
+		{
+			name:    "Forbidden",
+			err:     authz.ErrForbidden,
+			problem: problem.New(http.StatusForbidden, problem.CodePermissionDenied, authz.ErrForbidden.Error()),
+		},
+		{
+			name:    "Problem",
+			err:     problem.New(http.StatusServiceUnavailable, problem.CodeUnavailable, "not ready"),
+			problem: problem.New(http.StatusServiceUnavailable, problem.CodeUnavailable, "not ready"),
+		},
+		{
+			name: "Constraint violation",
+			err:  &database.Error{Err: database.ErrUniqueViolation, Domain: holders.ErrHolderAlreadyExists},
+			problem: problem.New(
+				http.StatusConflict,
+				CodeHolderAlreadyExists,
+				holders.ErrHolderAlreadyExists.Error(),
+			),
+		},
+		{
+			name:    "Constraint violation without domain error",
+			err:     &database.Error{Err: database.ErrUniqueViolation, Constraint: "api_keys_key_hash"},
+			problem: problem.New(http.StatusConflict, problem.CodeConflict, database.ErrUniqueViolation.Error()),
+		},
+		{
+			name: "Foreign key violation without domain error",
+			err:  &database.Error{Err: database.ErrForeignKeyViolation, Constraint: "accounts_holder_id_fkey"},
+			problem: problem.New(
+				http.StatusConflict,
+				CodeReferenceViolated,
+				database.ErrForeignKeyViolation.Error(),
+			),
+		},
+		{
+			name: "Check violation without domain error",
+			err:  &database.Error{Err: database.ErrCheckViolation, Constraint: "accounts_status_check"},
+			problem: problem.New(
+				http.StatusUnprocessableEntity,
+				CodeConstraintViolated,
+				database.ErrCheckViolation.Error(),
+			),
+		},
+		{
+			name: "Not null violation without domain error",
+			err:  fmt.Errorf("insert holder: %w", &database.Error{Err: database.ErrNotNullViolation}),
+			problem: problem.New(
+				http.StatusUnprocessableEntity,
+				CodeValueRequired,
+				database.ErrNotNullViolation.Error(),
+			),
+		},
+		{
+			name:    "Error of echo",
+			err:     echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id"),
+			problem: problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed, "invalid id"),
+		},
+		{
+			name:    "Route not found",
+			err:     echo.ErrNotFound,
+			problem: problem.New(http.StatusNotFound, problem.CodeNotFound, "Not Found"),
+		},
+		{
+			name:    "Internal error of echo",
+			err:     echo.NewHTTPError(http.StatusInternalServerError, "connection refused"),
+			problem: problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error"),
+		},
+		{
+			name:    "Unknown error",
+			err:     errors.New("connection refused"),
+			problem: problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.problem, FromError(tt.err))
+		})
+	}
+
+	t.Run("Validation errors", func(t *testing.T) {
+		p := FromError(validation.Errors{
+			"name":            errors.New("cannot be blank"),
+			"document_number": errors.New("the length must be between 11 and 14"),
+		})
+		assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+		assert.Equal(t, problem.CodeValidationFailed, p.Code)
+		assert.Equal(t, []problem.FieldError{
+			{Field: "document_number", Location: "body", Message: "the length must be between 11 and 14"},
+			{Field: "name", Location: "body", Message: "cannot be blank"},
+		}, p.Errors)
+	})
+}
+
+func TestNewHTTPErrorHandler(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = NewHTTPErrorHandler()
+	e.GET("/v1/accounts/:id", func(c echo.Context) error {
+		return transactions.ErrAccountNotfound
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/accounts/1", http.NoBody)
+	response := httptest.NewRecorder()
+	e.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, problem.ContentType, response.Header().Get(echo.HeaderContentType))
+
+	var body problem.Problem
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, CodeAccountNotFound, body.Code)
+	assert.Equal(t, "/v1/accounts/1", body.Instance)
+}
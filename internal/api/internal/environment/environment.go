@@ -1,23 +1,60 @@
 package environment
 
-import "github.com/gosidekick/goconfig"
+import (
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/gosidekick/goconfig"
+)
 
 // Environment this object keep the all environment variables.
 type Environment struct {
 	// Database
 	DatabaseURL string `cfg:"DATABASE_URL" cfgRequired:"true"`
+	// DatabaseReplicaURLs are the comma separated URLs of the read replicas, the reads are balanced between them. The
+	// master is read when empty.
+	DatabaseReplicaURLs string `cfg:"DATABASE_REPLICA_URLS"`
+	// DatabaseReplicaMaxLag is the lag over which a replica is not read until it catches up, and the API is degraded.
+	DatabaseReplicaMaxLag string `cfg:"DATABASE_REPLICA_MAX_LAG" cfgDefault:"5s"`
+	// The pools of connections of the master and of each replica, the lifetimes and the statement timeout are
+	// durations, e.g. 30m, zero keeps the connections forever and disables the timeout.
+	DatabaseMaxOpenConns     int    `cfg:"DATABASE_MAX_OPEN_CONNS" cfgDefault:"20"`
+	DatabaseMaxIdleConns     int    `cfg:"DATABASE_MAX_IDLE_CONNS" cfgDefault:"10"`
+	DatabaseConnMaxLifetime  string `cfg:"DATABASE_CONN_MAX_LIFETIME" cfgDefault:"30m"`
+	DatabaseConnMaxIdleTime  string `cfg:"DATABASE_CONN_MAX_IDLE_TIME" cfgDefault:"5m"`
+	DatabaseStatementTimeout string `cfg:"DATABASE_STATEMENT_TIMEOUT" cfgDefault:"10s"`
+	// DatabaseSlowQueryThreshold is the duration from which the queries are logged, redacted, zero disables the log.
+	DatabaseSlowQueryThreshold string `cfg:"DATABASE_SLOW_QUERY_THRESHOLD" cfgDefault:"500ms"`
 	// Redis
 	RedisURL    string `cfg:"REDIS_URL" cfgRequired:"true"`
 	RedisCACert string `cfg:"REDIS_CA_CERT"`
 	// Open Telemetry
 	OtelCollectorHost string `cfg:"OTEL_COLLECTOR_HOST" cfgRequired:"true"`
+	// Tracing is the sampling and the exporter of the spans (OTEL_EXPORTER, OTEL_SAMPLE_RATIO, ...).
+	Tracing tracer.Config `cfg:"OTEL"`
 	// Application
 	Environment string `cfg:"ENVIRONMENT" cfgRequired:"true"`
 	Service     string `cfg:"SERVICE" cfgRequired:"true"`
 	Version     string `cfg:"VERSION" cfgRequired:"true"`
 	HTTPHost    string `cfg:"HTTP_HOST" cfgRequired:"true"`
 	HTTPPort    string `cfg:"PORT" cfgRequired:"true"`
+	GRPCPort    string `cfg:"GRPC_PORT" cfgDefault:"50051"`
 	DebugPprof  bool   `cfg:"DEBUG_PPROF"`
+	// Authentication
+	// JWKSFiles are the comma separated JSON Web Key Set files with the public keys of the issuers of the bearer
+	// tokens, the bearer tokens are refused when empty.
+	JWKSFiles   string `cfg:"JWKS_FILES"`
+	JWTIssuer   string `cfg:"JWT_ISSUER"`
+	JWTAudience string `cfg:"JWT_AUDIENCE"`
+	// Tenancy
+	// TenantsFile is the JSON file with the tenants and their configuration, only the default tenant exists when
+	// empty.
+	TenantsFile string `cfg:"TENANTS_FILE"`
+	// Rate limiting
+	// The rates are <limit>/<window>, e.g. 30/1m, the requests are not limited by the empty ones. RateLimitIP limits
+	// the requests of each remote address, RateLimitDefault the requests of each principal to each route of v1 and
+	// RateLimitP2P the transfers of each principal from each account.
+	RateLimitIP      string `cfg:"RATE_LIMIT_IP"`
+	RateLimitDefault string `cfg:"RATE_LIMIT_DEFAULT" cfgDefault:"600/1m"`
+	RateLimitP2P     string `cfg:"RATE_LIMIT_P2P" cfgDefault:"30/1m"`
 }
 
 func NewEnvironment() (Environment, error) {
@@ -0,0 +1,188 @@
+package grpcservers
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	accountStatuses = map[accounts.Status]ledgerv1.AccountStatus{
+		accounts.ActiveStatus:  ledgerv1.AccountStatus_ACCOUNT_STATUS_ACTIVE,
+		accounts.BlockedStatus: ledgerv1.AccountStatus_ACCOUNT_STATUS_BLOCKED,
+		accounts.ClosedStatus:  ledgerv1.AccountStatus_ACCOUNT_STATUS_CLOSED,
+	}
+	statusesOfAccount = map[ledgerv1.AccountStatus]accounts.Status{
+		ledgerv1.AccountStatus_ACCOUNT_STATUS_UNSPECIFIED: "",
+		ledgerv1.AccountStatus_ACCOUNT_STATUS_ACTIVE:      accounts.ActiveStatus,
+		ledgerv1.AccountStatus_ACCOUNT_STATUS_BLOCKED:     accounts.BlockedStatus,
+		ledgerv1.AccountStatus_ACCOUNT_STATUS_CLOSED:      accounts.ClosedStatus,
+	}
+)
+
+type accountServer struct {
+	ledgerv1.UnimplementedAccountServiceServer
+	svc accounts.Service
+}
+
+func NewAccountServer(svc accounts.Service) ledgerv1.AccountServiceServer {
+	return accountServer{svc: svc}
+}
+
+func (s accountServer) CreateAccount(
+	ctx context.Context,
+	req *ledgerv1.CreateAccountRequest,
+) (*ledgerv1.CreateAccountResponse, error) {
+	err := validation.ValidateStruct(req,
+		validation.Field(&req.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&req.DocumentNumber, validation.Required, validation.Length(11, 14)),
+	)
+	if err != nil {
+		zapctx.L(ctx).Error("create_account_grpc_validation_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	account, err := s.svc.Create(ctx, accounts.Account{
+		Name:           req.Name,
+		DocumentNumber: req.DocumentNumber,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("create_account_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.CreateAccountResponse{Account: newAccount(account)}, nil
+}
+
+func (s accountServer) GetAccount(
+	ctx context.Context,
+	req *ledgerv1.GetAccountRequest,
+) (*ledgerv1.GetAccountResponse, error) {
+	account, err := s.byID(ctx, "get_account", req.Id, s.svc.GetByID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerv1.GetAccountResponse{Account: account}, nil
+}
+
+func (s accountServer) ListAccounts(
+	ctx context.Context,
+	req *ledgerv1.ListAccountsRequest,
+) (*ledgerv1.ListAccountsResponse, error) {
+	l, err := parseListing(req.Sort, req.Page, req.Size, req.Cursor, req.WithTotal)
+	if err != nil {
+		zapctx.L(ctx).Error("list_accounts_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	status, ok := statusesOfAccount[req.Status]
+	if !ok {
+		zapctx.L(ctx).Error("list_accounts_grpc_parse_error", zap.Stringer("status", req.Status))
+		return nil, invalidArgument("status")
+	}
+
+	total, accs, err := s.svc.List(ctx, accounts.ListFilter{
+		Sort:           l.sort,
+		Page:           l.page,
+		Size:           l.size,
+		DocumentNumber: req.DocumentNumber,
+		Status:         status,
+		Cursor:         l.cursor,
+		WithTotal:      l.withTotal,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("list_accounts_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	listed := make([]*ledgerv1.Account, len(accs))
+	keys := make([]cursor.Key, len(accs))
+	for i, account := range accs {
+		listed[i] = newAccount(account)
+		keys[i] = cursor.Key{CreatedAt: account.CreatedAt, ID: account.ID.String()}
+	}
+
+	return &ledgerv1.ListAccountsResponse{
+		Pagination: l.pagination(total, keys),
+		Accounts:   listed,
+	}, nil
+}
+
+func (s accountServer) BlockAccount(
+	ctx context.Context,
+	req *ledgerv1.BlockAccountRequest,
+) (*ledgerv1.BlockAccountResponse, error) {
+	account, err := s.byID(ctx, "block_account", req.Id, s.svc.BlockByID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerv1.BlockAccountResponse{Account: account}, nil
+}
+
+func (s accountServer) UnblockAccount(
+	ctx context.Context,
+	req *ledgerv1.UnblockAccountRequest,
+) (*ledgerv1.UnblockAccountResponse, error) {
+	account, err := s.byID(ctx, "unblock_account", req.Id, s.svc.UnblockByID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerv1.UnblockAccountResponse{Account: account}, nil
+}
+
+func (s accountServer) CloseAccount(
+	ctx context.Context,
+	req *ledgerv1.CloseAccountRequest,
+) (*ledgerv1.CloseAccountResponse, error) {
+	account, err := s.byID(ctx, "close_account", req.Id, s.svc.CloseByID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledgerv1.CloseAccountResponse{Account: account}, nil
+}
+
+// byID runs the operation of the service over the account of the id, logging the errors prefixed by the event.
+func (s accountServer) byID(
+	ctx context.Context,
+	event string,
+	rawID string,
+	operation func(context.Context, uuid.UUID) (accounts.Account, error),
+) (*ledgerv1.Account, error) {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		zapctx.L(ctx).Error(event+"_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("id")
+	}
+
+	account, err := operation(ctx, id)
+	if err != nil {
+		zapctx.L(ctx).Error(event+"_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return newAccount(account), nil
+}
+
+func newAccount(account accounts.Account) *ledgerv1.Account {
+	return &ledgerv1.Account{
+		Id:             account.ID.String(),
+		Name:           account.Name,
+		Agency:         account.Agency,
+		Number:         account.Number,
+		DocumentNumber: account.DocumentNumber,
+		HolderId:       account.HolderID.String(),
+		Status:         accountStatuses[account.Status],
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+	}
+}
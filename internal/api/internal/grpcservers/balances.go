@@ -0,0 +1,44 @@
+package grpcservers
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type balanceServer struct {
+	ledgerv1.UnimplementedBalanceServiceServer
+	svc balances.Service
+}
+
+func NewBalanceServer(svc balances.Service) ledgerv1.BalanceServiceServer {
+	return balanceServer{svc: svc}
+}
+
+func (s balanceServer) GetBalance(
+	ctx context.Context,
+	req *ledgerv1.GetBalanceRequest,
+) (*ledgerv1.GetBalanceResponse, error) {
+	id, err := uuid.Parse(req.AccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("get_balance_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("account_id")
+	}
+
+	accb, err := s.svc.GetByAccountID(ctx, id)
+	if err != nil {
+		zapctx.L(ctx).Error("get_balance_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.GetBalanceResponse{
+		Balance: &ledgerv1.Balance{
+			AccountId:      accb.AccountID.String(),
+			CurrentBalance: accb.CurrentBalance,
+		},
+	}, nil
+}
@@ -0,0 +1,84 @@
+package grpcservers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/healthcheck"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// watchInterval is the interval between the checks of the Watch calls.
+const watchInterval = 5 * time.Second
+
+// healthServices are the services known by the health server, the empty name is the server as a whole.
+var healthServices = map[string]struct{}{
+	"": {},
+	ledgerv1.HolderService_ServiceDesc.ServiceName:      {},
+	ledgerv1.AccountService_ServiceDesc.ServiceName:     {},
+	ledgerv1.TransactionService_ServiceDesc.ServiceName: {},
+	ledgerv1.BalanceService_ServiceDesc.ServiceName:     {},
+	ledgerv1.StatementService_ServiceDesc.ServiceName:   {},
+}
+
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+	hc healthcheck.HealthCheck
+}
+
+// NewHealthServer implements the gRPC health checking protocol with the readiness of the healthcheck.HealthCheck,
+// all services of the server share the same dependencies, so all of them have the same status.
+func NewHealthServer(hc healthcheck.HealthCheck) healthpb.HealthServer {
+	return healthServer{hc: hc}
+}
+
+func (s healthServer) Check(
+	ctx context.Context,
+	req *healthpb.HealthCheckRequest,
+) (*healthpb.HealthCheckResponse, error) {
+	if _, ok := healthServices[req.Service]; !ok {
+		return nil, status.Error(codes.NotFound, "unknown service")
+	}
+
+	return &healthpb.HealthCheckResponse{Status: s.status(ctx)}, nil
+}
+
+func (s healthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	if _, ok := healthServices[req.Service]; !ok {
+		return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN})
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		if current := s.status(ctx); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s healthServer) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if err := s.hc.Readiness(ctx); err != nil {
+		zapctx.L(ctx).Error("health_grpc_readiness_error", zap.Error(err))
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
@@ -0,0 +1,109 @@
+package grpcservers
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type holderServer struct {
+	ledgerv1.UnimplementedHolderServiceServer
+	svc holders.Service
+}
+
+func NewHolderServer(svc holders.Service) ledgerv1.HolderServiceServer {
+	return holderServer{svc: svc}
+}
+
+func (s holderServer) CreateHolder(
+	ctx context.Context,
+	req *ledgerv1.CreateHolderRequest,
+) (*ledgerv1.CreateHolderResponse, error) {
+	err := validation.ValidateStruct(req,
+		validation.Field(&req.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&req.DocumentNumber, validation.Required, validation.Length(11, 14)),
+	)
+	if err != nil {
+		zapctx.L(ctx).Error("create_holder_grpc_validation_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	holder, err := s.svc.Create(ctx, holders.Holder{
+		Name:           req.Name,
+		DocumentNumber: req.DocumentNumber,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("create_holder_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.CreateHolderResponse{Holder: newHolder(holder)}, nil
+}
+
+func (s holderServer) GetHolder(ctx context.Context, req *ledgerv1.GetHolderRequest) (*ledgerv1.GetHolderResponse, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		zapctx.L(ctx).Error("get_holder_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("id")
+	}
+
+	holder, err := s.svc.GetByID(ctx, id)
+	if err != nil {
+		zapctx.L(ctx).Error("get_holder_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.GetHolderResponse{Holder: newHolder(holder)}, nil
+}
+
+func (s holderServer) ListHolders(
+	ctx context.Context,
+	req *ledgerv1.ListHoldersRequest,
+) (*ledgerv1.ListHoldersResponse, error) {
+	l, err := parseListing(req.Sort, req.Page, req.Size, req.Cursor, req.WithTotal)
+	if err != nil {
+		zapctx.L(ctx).Error("list_holders_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	total, hdlrs, err := s.svc.List(ctx, holders.ListFilter{
+		Sort:           l.sort,
+		Page:           l.page,
+		Size:           l.size,
+		DocumentNumber: req.DocumentNumber,
+		Cursor:         l.cursor,
+		WithTotal:      l.withTotal,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("list_holders_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	listed := make([]*ledgerv1.Holder, len(hdlrs))
+	keys := make([]cursor.Key, len(hdlrs))
+	for i, holder := range hdlrs {
+		listed[i] = newHolder(holder)
+		keys[i] = cursor.Key{CreatedAt: holder.CreatedAt, ID: holder.ID.String()}
+	}
+
+	return &ledgerv1.ListHoldersResponse{
+		Pagination: l.pagination(total, keys),
+		Holders:    listed,
+	}, nil
+}
+
+func newHolder(holder holders.Holder) *ledgerv1.Holder {
+	return &ledgerv1.Holder{
+		Id:             holder.ID.String(),
+		Name:           holder.Name,
+		DocumentNumber: holder.DocumentNumber,
+		CreatedAt:      timestamppb.New(holder.CreatedAt),
+	}
+}
@@ -0,0 +1,68 @@
+package grpcservers
+
+import (
+	"strconv"
+
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/paging"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+)
+
+// listing is the pagination requested by the listings, with the same defaults of the HTTP API.
+type listing struct {
+	sort      int
+	page      int
+	size      int
+	cursor    *cursor.Cursor
+	withTotal bool
+}
+
+func parseListing(sort, page, size int32, token string, withTotal *bool) (listing, error) {
+	l := listing{
+		sort: int(sort),
+		page: int(page),
+		size: int(size),
+	}
+
+	if l.page == 0 {
+		l.page = 1
+	}
+
+	if l.size == 0 {
+		l.size = 20
+	}
+
+	var count string
+	if withTotal != nil {
+		count = strconv.FormatBool(*withTotal)
+	}
+
+	var err error
+	l.cursor, l.withTotal, err = paging.Parse(token, count)
+	if err != nil {
+		return listing{}, invalidArgument("cursor")
+	}
+
+	return l, nil
+}
+
+func (l listing) pagination(total int, keys []cursor.Key) *ledgerv1.Pagination {
+	links := cursor.NewLinks(l.cursor, l.page > 1, l.size, keys)
+
+	pgn := &ledgerv1.Pagination{
+		Sort:        int32(l.sort),
+		Page:        int32(l.page),
+		Size:        int32(l.size),
+		TotalInPage: int32(len(keys)),
+		NextCursor:  links.Next,
+		PrevCursor:  links.Prev,
+	}
+
+	totalItems, totalPages := paging.Totals(l.withTotal, total, l.size)
+	if totalItems != nil {
+		items, pages := int32(*totalItems), int32(*totalPages)
+		pgn.TotalItems, pgn.TotalPages = &items, &pages
+	}
+
+	return pgn
+}
@@ -0,0 +1,179 @@
+package grpcservers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var directions = map[ledgerv1.Direction]statements.Direction{
+	ledgerv1.Direction_DIRECTION_UNSPECIFIED: "",
+	ledgerv1.Direction_DIRECTION_IN:          statements.DirectionIn,
+	ledgerv1.Direction_DIRECTION_OUT:         statements.DirectionOut,
+}
+
+type statementServer struct {
+	ledgerv1.UnimplementedStatementServiceServer
+	svc statements.Service
+}
+
+func NewStatementServer(svc statements.Service) ledgerv1.StatementServiceServer {
+	return statementServer{svc: svc}
+}
+
+//nolint:funlen
+func (s statementServer) ListStatements(
+	ctx context.Context,
+	req *ledgerv1.ListStatementsRequest,
+) (*ledgerv1.ListStatementsResponse, error) {
+	id, err := uuid.Parse(req.AccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("account_id")
+	}
+
+	l, err := parseListing(req.Sort, req.Page, req.Size, req.Cursor, req.WithTotal)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	createdAtBegin, err := parseOptionalTime(req.CreatedAtBegin)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("created_at_begin")
+	}
+
+	createdAtEnd, err := parseOptionalTime(req.CreatedAtEnd)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("created_at_end")
+	}
+
+	types := make([]string, len(req.Types))
+	for i, tp := range req.Types {
+		trxType, ok := typesOfTransaction[tp]
+		if !ok {
+			zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Stringer("type", tp))
+			return nil, invalidArgument("types")
+		}
+		types[i] = string(trxType)
+	}
+
+	direction, ok := directions[req.Direction]
+	if !ok {
+		zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Stringer("direction", req.Direction))
+		return nil, invalidArgument("direction")
+	}
+
+	var counterpartyID uuid.NullUUID
+	if req.CounterpartyAccountId != "" {
+		counterpartyID.UUID, err = uuid.Parse(req.CounterpartyAccountId)
+		if err != nil {
+			zapctx.L(ctx).Error("list_statements_grpc_parse_error", zap.Error(err))
+			return nil, invalidArgument("counterparty_account_id")
+		}
+		counterpartyID.Valid = true
+	}
+
+	filter := statements.ListFilter{
+		Sort:                  l.sort,
+		Page:                  l.page,
+		Size:                  l.size,
+		AccountID:             id,
+		CreatedAtBegin:        createdAtBegin,
+		CreatedAtEnd:          createdAtEnd,
+		Types:                 types,
+		Direction:             direction,
+		MinAmount:             nullFloat64(req.MinAmount),
+		MaxAmount:             nullFloat64(req.MaxAmount),
+		CounterpartyAccountID: counterpartyID,
+		Description:           req.Description,
+		Cursor:                l.cursor,
+		WithTotal:             l.withTotal,
+	}
+
+	total, stms, err := s.svc.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	smr, err := s.svc.Summarize(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("list_statements_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	listed := make([]*ledgerv1.Statement, len(stms))
+	keys := make([]cursor.Key, len(stms))
+	for i, stm := range stms {
+		listed[i] = newStatement(stm)
+		keys[i] = cursor.Key{CreatedAt: stm.CreatedAt, ID: stm.ID.String()}
+	}
+
+	return &ledgerv1.ListStatementsResponse{
+		Pagination: l.pagination(total, keys),
+		AccountId:  id.String(),
+		Summary: &ledgerv1.StatementSummary{
+			OpeningBalance: smr.OpeningBalance,
+			ClosingBalance: smr.ClosingBalance,
+			TotalCredits:   smr.TotalCredits,
+			TotalDebits:    smr.TotalDebits,
+		},
+		Statements: listed,
+	}, nil
+}
+
+func parseOptionalTime(ts *timestamppb.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, err
+	}
+
+	return ts.AsTime(), nil
+}
+
+func nullFloat64(value *float64) database.NullFloat64 {
+	if value == nil {
+		return database.NullFloat64{}
+	}
+	return database.NullFloat64{Float64: *value, Valid: true}
+}
+
+func newStatement(stm statements.Statement) *ledgerv1.Statement {
+	return &ledgerv1.Statement{
+		Id:          stm.ID.String(),
+		FromAccount: newStatementAccount(stm.FromAccount),
+		ToAccount:   newStatementAccount(stm.ToAccount),
+		Type:        transactionTypes[transactions.TransactionType(stm.Type)],
+		Amount:      stm.Amount,
+		Description: stm.Description,
+		Balance:     stm.Balance,
+		Adjustment:  stm.Adjustment,
+		CreatedAt:   timestamppb.New(stm.CreatedAt),
+	}
+}
+
+func newStatementAccount(account accounts.Account) *ledgerv1.StatementAccount {
+	if account.ID == uuid.Nil {
+		return nil
+	}
+	return &ledgerv1.StatementAccount{
+		Id:   account.ID.String(),
+		Name: account.Name,
+	}
+}
@@ -0,0 +1,45 @@
+package grpcservers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/apierrors"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// statusFromError converts the errors of the services to the status of the gRPC calls, the domain errors of the
+// catalog of the API with their message and any other error as internal without leaking its details.
+func statusFromError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErrors validation.Errors
+	if errors.As(err, &validationErrors) {
+		return status.Error(codes.InvalidArgument, validationErrors.Error())
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, context.Canceled.Error())
+	}
+
+	for _, entry := range apierrors.Catalog {
+		if errors.Is(err, entry.Err) {
+			return status.Error(entry.GRPCStatusCode(), entry.Err.Error())
+		}
+	}
+
+	return status.Error(codes.Internal, codes.Internal.String())
+}
+
+// invalidArgument returns the status of the requests with an invalid field.
+func invalidArgument(field string) error {
+	return status.Errorf(codes.InvalidArgument, "invalid %s", field)
+}
@@ -0,0 +1,145 @@
+//go:build unit
+
+package grpcservers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestStatusFromError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: holders.ErrHolderNotFound, code: codes.NotFound},
+		{err: accounts.ErrAccountNotFound, code: codes.NotFound},
+		{err: fmt.Errorf("wrapped: %w", transactions.ErrTransactionNotFound), code: codes.NotFound},
+		{err: transactions.ErrBalanceInsufficientFunds, code: codes.FailedPrecondition},
+		{err: transactions.ErrInsufficientDailyLimit, code: codes.FailedPrecondition},
+		{err: accounts.ErrAccountInactive, code: codes.FailedPrecondition},
+		{err: transactions.ErrPeriodClosed, code: codes.FailedPrecondition},
+		{err: transactions.ErrTransactionInFuture, code: codes.InvalidArgument},
+		{err: transactions.ErrFailLockAccount, code: codes.Aborted},
+		{err: authz.ErrForbidden, code: codes.PermissionDenied},
+		{err: &database.Error{Err: database.ErrForeignKeyViolation}, code: codes.FailedPrecondition},
+		{err: &database.Error{Err: database.ErrCheckViolation}, code: codes.InvalidArgument},
+		{err: &database.Error{Err: database.ErrNotNullViolation}, code: codes.InvalidArgument},
+		{err: context.DeadlineExceeded, code: codes.DeadlineExceeded},
+		{err: context.Canceled, code: codes.Canceled},
+		{err: holders.ErrHolderAlreadyExists, code: codes.AlreadyExists},
+		{err: &database.Error{Err: database.ErrSerializationFailure}, code: codes.Aborted},
+		{err: businessdays.ErrBusinessDayClosed, code: codes.FailedPrecondition},
+		{err: status.Error(codes.Unavailable, "unavailable"), code: codes.Unavailable},
+		{err: errors.New("connection refused"), code: codes.Internal},
+	}
+
+	for _, c := range cases {
+		t.Run(c.err.Error(), func(t *testing.T) {
+			assert.Equal(t, c.code, status.Code(statusFromError(c.err)))
+		})
+	}
+
+	assert.NoError(t, statusFromError(nil))
+	assert.Equal(t, codes.Internal.String(), status.Convert(statusFromError(errors.New("secret"))).Message())
+}
+
+func TestTransactionServer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := transactions.NewMockService(ctrl)
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	ledgerv1.RegisterTransactionServiceServer(server, NewTransactionServer(svc))
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(
+		context.Background(),
+		"bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := ledgerv1.NewTransactionServiceClient(conn)
+	fromID := uuid.New()
+
+	t.Run("Insufficient funds", func(t *testing.T) {
+		svc.EXPECT().
+			CreateDebit(gomock.Any(), transactions.Transaction{From: fromID, Amount: 10}).
+			Return(transactions.Transaction{}, transactions.ErrBalanceInsufficientFunds)
+
+		_, err := client.CreateDebit(context.Background(), &ledgerv1.CreateDebitRequest{
+			FromAccountId: fromID.String(),
+			Amount:        10,
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Equal(t, transactions.ErrBalanceInsufficientFunds.Error(), status.Convert(err).Message())
+	})
+
+	t.Run("Invalid amount", func(t *testing.T) {
+		_, err := client.CreateDebit(context.Background(), &ledgerv1.CreateDebitRequest{
+			FromAccountId: fromID.String(),
+			Amount:        -10,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Invalid account", func(t *testing.T) {
+		_, err := client.CreateDebit(context.Background(), &ledgerv1.CreateDebitRequest{
+			FromAccountId: "invalid",
+			Amount:        10,
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Created", func(t *testing.T) {
+		created := transactions.Transaction{
+			ID:     uuid.New(),
+			From:   fromID,
+			Type:   transactions.DebitTransaction,
+			Amount: 10,
+		}
+		svc.EXPECT().
+			CreateDebit(gomock.Any(), transactions.Transaction{From: fromID, Amount: 10}).
+			Return(created, nil)
+
+		resp, err := client.CreateDebit(context.Background(), &ledgerv1.CreateDebitRequest{
+			FromAccountId: fromID.String(),
+			Amount:        10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, created.ID.String(), resp.Transaction.Id)
+		assert.Equal(t, fromID.String(), resp.Transaction.FromAccountId)
+		assert.Empty(t, resp.Transaction.ToAccountId)
+		assert.Equal(t, ledgerv1.TransactionType_TRANSACTION_TYPE_DEBIT, resp.Transaction.Type)
+	})
+}
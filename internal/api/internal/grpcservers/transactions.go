@@ -0,0 +1,188 @@
+package grpcservers
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	ledgerv1 "github.com/dalmarcogd/ledger-exp/pkg/proto/ledger/v1"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	transactionTypes = map[transactions.TransactionType]ledgerv1.TransactionType{
+		transactions.CreditTransaction: ledgerv1.TransactionType_TRANSACTION_TYPE_CREDIT,
+		transactions.DebitTransaction:  ledgerv1.TransactionType_TRANSACTION_TYPE_DEBIT,
+		transactions.P2PTransaction:    ledgerv1.TransactionType_TRANSACTION_TYPE_P2P,
+	}
+	typesOfTransaction = map[ledgerv1.TransactionType]transactions.TransactionType{
+		ledgerv1.TransactionType_TRANSACTION_TYPE_CREDIT: transactions.CreditTransaction,
+		ledgerv1.TransactionType_TRANSACTION_TYPE_DEBIT:  transactions.DebitTransaction,
+		ledgerv1.TransactionType_TRANSACTION_TYPE_P2P:    transactions.P2PTransaction,
+	}
+)
+
+type transactionServer struct {
+	ledgerv1.UnimplementedTransactionServiceServer
+	svc transactions.Service
+}
+
+func NewTransactionServer(svc transactions.Service) ledgerv1.TransactionServiceServer {
+	return transactionServer{svc: svc}
+}
+
+func (s transactionServer) CreateCredit(
+	ctx context.Context,
+	req *ledgerv1.CreateCreditRequest,
+) (*ledgerv1.CreateCreditResponse, error) {
+	toID, err := uuid.Parse(req.ToAccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("create_credit_transaction_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("to_account_id")
+	}
+
+	createdAt, err := parseNewTransaction(req.Amount, req.CreatedAt)
+	if err != nil {
+		zapctx.L(ctx).Error("create_credit_transaction_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	transaction, err := s.svc.CreateCredit(ctx, transactions.Transaction{
+		To:          toID,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Adjustment:  req.Adjustment,
+		CreatedAt:   createdAt,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("create_credit_transaction_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.CreateCreditResponse{Transaction: newTransaction(transaction)}, nil
+}
+
+func (s transactionServer) CreateDebit(
+	ctx context.Context,
+	req *ledgerv1.CreateDebitRequest,
+) (*ledgerv1.CreateDebitResponse, error) {
+	fromID, err := uuid.Parse(req.FromAccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("create_debit_transaction_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("from_account_id")
+	}
+
+	createdAt, err := parseNewTransaction(req.Amount, req.CreatedAt)
+	if err != nil {
+		zapctx.L(ctx).Error("create_debit_transaction_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	transaction, err := s.svc.CreateDebit(ctx, transactions.Transaction{
+		From:        fromID,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Adjustment:  req.Adjustment,
+		CreatedAt:   createdAt,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("create_debit_transaction_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.CreateDebitResponse{Transaction: newTransaction(transaction)}, nil
+}
+
+func (s transactionServer) CreateP2P(
+	ctx context.Context,
+	req *ledgerv1.CreateP2PRequest,
+) (*ledgerv1.CreateP2PResponse, error) {
+	fromID, err := uuid.Parse(req.FromAccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("create_p2p_transaction_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("from_account_id")
+	}
+
+	toID, err := uuid.Parse(req.ToAccountId)
+	if err != nil {
+		zapctx.L(ctx).Error("create_p2p_transaction_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("to_account_id")
+	}
+
+	createdAt, err := parseNewTransaction(req.Amount, req.CreatedAt)
+	if err != nil {
+		zapctx.L(ctx).Error("create_p2p_transaction_grpc_parse_error", zap.Error(err))
+		return nil, err
+	}
+
+	transaction, err := s.svc.CreateP2P(ctx, transactions.Transaction{
+		From:        fromID,
+		To:          toID,
+		Amount:      req.Amount,
+		Description: req.Description,
+		Adjustment:  req.Adjustment,
+		CreatedAt:   createdAt,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("create_p2p_transaction_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.CreateP2PResponse{Transaction: newTransaction(transaction)}, nil
+}
+
+func (s transactionServer) GetTransaction(
+	ctx context.Context,
+	req *ledgerv1.GetTransactionRequest,
+) (*ledgerv1.GetTransactionResponse, error) {
+	id, err := uuid.Parse(req.Id)
+	if err != nil {
+		zapctx.L(ctx).Error("get_transaction_grpc_parse_error", zap.Error(err))
+		return nil, invalidArgument("id")
+	}
+
+	transaction, err := s.svc.GetByID(ctx, id)
+	if err != nil {
+		zapctx.L(ctx).Error("get_transaction_grpc_service_error", zap.Error(err))
+		return nil, statusFromError(err)
+	}
+
+	return &ledgerv1.GetTransactionResponse{Transaction: newTransaction(transaction)}, nil
+}
+
+// parseNewTransaction validates the amount of the new transaction and parses its optional date, empty when not given.
+func parseNewTransaction(amount float64, createdAt *timestamppb.Timestamp) (time.Time, error) {
+	err := validation.Validate(amount, validation.Required, validation.Min(0.0).Exclusive())
+	if err != nil {
+		return time.Time{}, statusFromError(validation.Errors{"amount": err})
+	}
+
+	date, err := parseOptionalTime(createdAt)
+	if err != nil {
+		return time.Time{}, invalidArgument("created_at")
+	}
+
+	return date, nil
+}
+
+func newTransaction(transaction transactions.Transaction) *ledgerv1.Transaction {
+	trx := &ledgerv1.Transaction{
+		Id:          transaction.ID.String(),
+		Type:        transactionTypes[transaction.Type],
+		Amount:      transaction.Amount,
+		Description: transaction.Description,
+		Adjustment:  transaction.Adjustment,
+		CreatedAt:   timestamppb.New(transaction.CreatedAt),
+	}
+	if transaction.From != uuid.Nil {
+		trx.FromAccountId = transaction.From.String()
+	}
+	if transaction.To != uuid.Nil {
+		trx.ToAccountId = transaction.To.String()
+	}
+	return trx
+}
@@ -37,7 +37,7 @@ func NewBlockByIDFunc(svc accounts.Service) BlockByIDFunc {
 		account, err := svc.BlockByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("block_by_account_id_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
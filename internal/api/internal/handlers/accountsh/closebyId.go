@@ -37,7 +37,7 @@ func NewCloseByIDFunc(svc accounts.Service) CloseByIDFunc {
 		account, err := svc.CloseByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("blocse_by_account_id_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
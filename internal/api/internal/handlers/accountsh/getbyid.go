@@ -37,7 +37,7 @@ func NewGetByIDFunc(svc accounts.Service) GetByIDFunc {
 		account, err := svc.GetByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("get_by_account_id_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
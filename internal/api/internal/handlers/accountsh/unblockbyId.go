@@ -37,7 +37,7 @@ func NewUnblockByIDFunc(svc accounts.Service) UnblockByIDFunc {
 		account, err := svc.UnblockByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("unblock_by_account_id_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
@@ -0,0 +1,86 @@
+package adminh
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/labstack/echo/v4"
+)
+
+// maxQueryStats are the fingerprints replied, the ones with the largest total duration.
+const maxQueryStats = 100
+
+type (
+	GetDatabaseStatsFunc echo.HandlerFunc
+
+	databaseStats struct {
+		Pools   []poolStats  `json:"pools"`
+		Queries []queryStats `json:"queries"`
+	}
+	poolStats struct {
+		Name               string  `json:"name"`
+		MaxOpenConnections int     `json:"max_open_connections"`
+		OpenConnections    int     `json:"open_connections"`
+		InUse              int     `json:"in_use"`
+		Idle               int     `json:"idle"`
+		WaitCount          int64   `json:"wait_count"`
+		WaitDurationMs     float64 `json:"wait_duration_ms"`
+	}
+	queryStats struct {
+		Fingerprint string  `json:"fingerprint"`
+		Count       int64   `json:"count"`
+		Errors      int64   `json:"errors"`
+		TotalMs     float64 `json:"total_ms"`
+		MeanMs      float64 `json:"mean_ms"`
+		P50Ms       float64 `json:"p50_ms"`
+		P95Ms       float64 `json:"p95_ms"`
+		P99Ms       float64 `json:"p99_ms"`
+		MaxMs       float64 `json:"max_ms"`
+	}
+)
+
+// NewGetDatabaseStatsFunc returns the statistics of the pools of connections and of the queries of the instance by
+// fingerprint, with their values redacted, to spot the regressions without logging all the statements.
+func NewGetDatabaseStatsFunc(db database.Database) GetDatabaseStatsFunc {
+	return func(c echo.Context) error {
+		stats := databaseStats{Pools: []poolStats{}, Queries: []queryStats{}}
+
+		for name, pool := range db.Stats() {
+			stats.Pools = append(stats.Pools, poolStats{
+				Name:               name,
+				MaxOpenConnections: pool.MaxOpenConnections,
+				OpenConnections:    pool.OpenConnections,
+				InUse:              pool.InUse,
+				Idle:               pool.Idle,
+				WaitCount:          pool.WaitCount,
+				WaitDurationMs:     milliseconds(pool.WaitDuration),
+			})
+		}
+		sort.Slice(stats.Pools, func(i, j int) bool { return stats.Pools[i].Name < stats.Pools[j].Name })
+
+		for i, query := range db.QueryStats() {
+			if i == maxQueryStats {
+				break
+			}
+			stats.Queries = append(stats.Queries, queryStats{
+				Fingerprint: query.Fingerprint,
+				Count:       query.Count,
+				Errors:      query.Errors,
+				TotalMs:     milliseconds(query.Total),
+				MeanMs:      milliseconds(query.Mean()),
+				P50Ms:       milliseconds(query.P50),
+				P95Ms:       milliseconds(query.P95),
+				P99Ms:       milliseconds(query.P99),
+				MaxMs:       milliseconds(query.Max),
+			})
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,57 @@
+package apikeysh
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+)
+
+type (
+	apiKeyID struct {
+		ID string `param:"id"`
+	}
+
+	apiKey struct {
+		ID        string     `json:"id"`
+		Name      string     `json:"name"`
+		Prefix    string     `json:"prefix"`
+		Scopes    []string   `json:"scopes"`
+		HolderID  *string    `json:"holder_id,omitempty"`
+		TenantID  string     `json:"tenant_id,omitempty"`
+		CreatedAt time.Time  `json:"created_at"`
+		RotatedAt *time.Time `json:"rotated_at,omitempty"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	}
+
+	// issuedAPIKey is the only response with the key, it can not be retrieved later.
+	issuedAPIKey struct {
+		apiKey
+		Key string `json:"key"`
+	}
+)
+
+func newAPIKey(key apikeys.APIKey) apiKey {
+	ak := apiKey{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		TenantID:  key.TenantID,
+		CreatedAt: key.CreatedAt,
+	}
+	if key.HolderID.Valid {
+		holderID := key.HolderID.UUID.String()
+		ak.HolderID = &holderID
+	}
+	if !key.RotatedAt.IsZero() {
+		ak.RotatedAt = &key.RotatedAt
+	}
+	if !key.RevokedAt.IsZero() {
+		ak.RevokedAt = &key.RevokedAt
+	}
+	return ak
+}
+
+func newIssuedAPIKey(key apikeys.IssuedAPIKey) issuedAPIKey {
+	return issuedAPIKey{apiKey: newAPIKey(key.APIKey), Key: key.Key}
+}
@@ -0,0 +1,63 @@
+package apikeysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	CreateAPIKeyFunc echo.HandlerFunc
+
+	createAPIKey struct {
+		Name     string   `json:"name"`
+		Scopes   []string `json:"scopes"`
+		HolderID string   `json:"holder_id"`
+	}
+)
+
+func (c createAPIKey) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&c.Scopes, validation.Required),
+		validation.Field(&c.HolderID, is.UUID),
+	)
+}
+
+// NewCreateAPIKeyFunc issues a new API key, the key is only returned in this response. The key is granted the scopes
+// and restricted to the holder of the request.
+func NewCreateAPIKeyFunc(svc apikeys.Service) CreateAPIKeyFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var cak createAPIKey
+		if err := c.Bind(&cak); err != nil {
+			zapctx.L(ctx).Error("create_api_key_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		if err := cak.Validate(); err != nil {
+			zapctx.L(ctx).Error("create_api_key_handler_validation_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		key := apikeys.APIKey{Name: cak.Name, Scopes: cak.Scopes}
+		if cak.HolderID != "" {
+			key.HolderID = uuid.NullUUID{UUID: uuid.MustParse(cak.HolderID), Valid: true}
+		}
+
+		issued, err := svc.Create(ctx, key)
+		if err != nil {
+			zapctx.L(ctx).Error("create_api_key_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, newIssuedAPIKey(issued))
+	}
+}
@@ -0,0 +1,40 @@
+package apikeysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type RevokeAPIKeyFunc echo.HandlerFunc
+
+// NewRevokeAPIKeyFunc revokes the API key, it stops authenticating at once.
+func NewRevokeAPIKeyFunc(svc apikeys.Service) RevokeAPIKeyFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var aki apiKeyID
+		if err := c.Bind(&aki); err != nil {
+			zapctx.L(ctx).Error("revoke_api_key_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(aki.ID)
+		if err != nil {
+			zapctx.L(ctx).Error("revoke_api_key_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		key, err := svc.Revoke(ctx, id)
+		if err != nil {
+			zapctx.L(ctx).Error("revoke_api_key_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusOK, newAPIKey(key))
+	}
+}
@@ -0,0 +1,41 @@
+package apikeysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type RotateAPIKeyFunc echo.HandlerFunc
+
+// NewRotateAPIKeyFunc replaces the key of the API key, the previous key stops authenticating at once and the new one
+// is only returned in this response.
+func NewRotateAPIKeyFunc(svc apikeys.Service) RotateAPIKeyFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var aki apiKeyID
+		if err := c.Bind(&aki); err != nil {
+			zapctx.L(ctx).Error("rotate_api_key_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(aki.ID)
+		if err != nil {
+			zapctx.L(ctx).Error("rotate_api_key_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		key, err := svc.Rotate(ctx, id)
+		if err != nil {
+			zapctx.L(ctx).Error("rotate_api_key_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusOK, newIssuedAPIKey(key))
+	}
+}
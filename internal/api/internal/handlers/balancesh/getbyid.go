@@ -41,7 +41,7 @@ func NewGetBalanceByAccountIDFunc(svc balances.Service) GetBalanceByAccountIDFun
 		accb, err := svc.GetByAccountID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("get_balance_by_account_id_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
@@ -0,0 +1,39 @@
+package businessdaysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type OpenAdjustmentFunc echo.HandlerFunc
+
+// NewOpenAdjustmentFunc opens the closed business day for adjusting entries, until it is closed again.
+func NewOpenAdjustmentFunc(svc businessdays.Service) OpenAdjustmentFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var bdd businessDayDate
+		if err := c.Bind(&bdd); err != nil {
+			zapctx.L(ctx).Error("open_adjustment_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		date, err := businessdays.ParseDate(bdd.Date)
+		if err != nil {
+			zapctx.L(ctx).Error("open_adjustment_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		day, err := svc.OpenAdjustment(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error("open_adjustment_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusOK, newBusinessDay(day))
+	}
+}
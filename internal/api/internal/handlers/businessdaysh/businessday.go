@@ -0,0 +1,29 @@
+package businessdaysh
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+)
+
+type (
+	businessDayDate struct {
+		Date string `param:"date"`
+	}
+
+	businessDay struct {
+		Date      string    `json:"date"`
+		Status    string    `json:"status"`
+		ClosedAt  time.Time `json:"closed_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func newBusinessDay(day businessdays.BusinessDay) businessDay {
+	return businessDay{
+		Date:      businessdays.FormatDate(day.Date),
+		Status:    string(day.Status),
+		ClosedAt:  day.ClosedAt,
+		UpdatedAt: day.UpdatedAt,
+	}
+}
@@ -0,0 +1,39 @@
+package businessdaysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CloseBusinessDayFunc echo.HandlerFunc
+
+// NewCloseBusinessDayFunc closes the business day, or ends its adjustment period when it is open for adjustments.
+func NewCloseBusinessDayFunc(svc businessdays.Service) CloseBusinessDayFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var bdd businessDayDate
+		if err := c.Bind(&bdd); err != nil {
+			zapctx.L(ctx).Error("close_business_day_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		date, err := businessdays.ParseDate(bdd.Date)
+		if err != nil {
+			zapctx.L(ctx).Error("close_business_day_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		day, err := svc.Close(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error("close_business_day_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusOK, newBusinessDay(day))
+	}
+}
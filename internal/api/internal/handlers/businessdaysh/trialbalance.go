@@ -0,0 +1,102 @@
+package businessdaysh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	GetTrialBalanceFunc echo.HandlerFunc
+
+	closingBalance struct {
+		AccountID      string  `json:"account_id"`
+		OpeningBalance float64 `json:"opening_balance"`
+		TotalCredits   float64 `json:"total_credits"`
+		TotalDebits    float64 `json:"total_debits"`
+		ClosingBalance float64 `json:"closing_balance"`
+	}
+
+	discrepancy struct {
+		AccountID string  `json:"account_id,omitempty"`
+		Reason    string  `json:"reason"`
+		Recorded  float64 `json:"recorded"`
+		Expected  float64 `json:"expected"`
+	}
+
+	trialBalance struct {
+		BusinessDay    businessDay      `json:"business_day"`
+		Balances       []closingBalance `json:"balances"`
+		OpeningBalance float64          `json:"opening_balance"`
+		TotalCredits   float64          `json:"total_credits"`
+		TotalDebits    float64          `json:"total_debits"`
+		ClosingBalance float64          `json:"closing_balance"`
+		Balanced       bool             `json:"balanced"`
+		Discrepancies  []discrepancy    `json:"discrepancies"`
+	}
+)
+
+func NewGetTrialBalanceFunc(svc businessdays.Service) GetTrialBalanceFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var bdd businessDayDate
+		if err := c.Bind(&bdd); err != nil {
+			zapctx.L(ctx).Error("get_trial_balance_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		date, err := businessdays.ParseDate(bdd.Date)
+		if err != nil {
+			zapctx.L(ctx).Error("get_trial_balance_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		tb, err := svc.GetTrialBalance(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error("get_trial_balance_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		balances := make([]closingBalance, len(tb.Balances))
+		for i, balance := range tb.Balances {
+			balances[i] = closingBalance{
+				AccountID:      balance.AccountID.String(),
+				OpeningBalance: balance.OpeningBalance,
+				TotalCredits:   balance.TotalCredits,
+				TotalDebits:    balance.TotalDebits,
+				ClosingBalance: balance.ClosingBalance,
+			}
+		}
+
+		discrepancies := make([]discrepancy, len(tb.Discrepancies))
+		for i, d := range tb.Discrepancies {
+			discrepancies[i] = discrepancy{
+				Reason:   string(d.Reason),
+				Recorded: d.Recorded,
+				Expected: d.Expected,
+			}
+			if d.AccountID != uuid.Nil {
+				discrepancies[i].AccountID = d.AccountID.String()
+			}
+		}
+
+		return c.JSON(
+			http.StatusOK,
+			trialBalance{
+				BusinessDay:    newBusinessDay(tb.BusinessDay),
+				Balances:       balances,
+				OpeningBalance: tb.OpeningBalance,
+				TotalCredits:   tb.TotalCredits,
+				TotalDebits:    tb.TotalDebits,
+				ClosingBalance: tb.ClosingBalance,
+				Balanced:       tb.Balanced,
+				Discrepancies:  discrepancies,
+			},
+		)
+	}
+}
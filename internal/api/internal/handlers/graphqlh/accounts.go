@@ -0,0 +1,226 @@
+package graphqlh
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+type accountResolver struct {
+	root    *resolver
+	account accounts.Account
+}
+
+func (a *accountResolver) ID() graphql.ID {
+	return graphql.ID(a.account.ID.String())
+}
+
+func (a *accountResolver) Name() string {
+	return a.account.Name
+}
+
+func (a *accountResolver) Agency() string {
+	return a.account.Agency
+}
+
+func (a *accountResolver) Number() string {
+	return a.account.Number
+}
+
+func (a *accountResolver) DocumentNumber() string {
+	return a.account.DocumentNumber
+}
+
+func (a *accountResolver) Status() string {
+	return string(a.account.Status)
+}
+
+func (a *accountResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: a.account.CreatedAt}
+}
+
+func (a *accountResolver) Holder(ctx context.Context) (*holderResolver, error) {
+	if err := authorize(ctx, authz.ScopeHoldersRead); err != nil {
+		return nil, err
+	}
+
+	holder, err := loadHolder(ctx, a.account.HolderID)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_account_holder_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &holderResolver{root: a.root, holder: holder}, nil
+}
+
+func (a *accountResolver) Balance(ctx context.Context) (*balanceResolver, error) {
+	if err := authorize(ctx, authz.ScopeBalancesRead); err != nil {
+		return nil, err
+	}
+
+	accb, err := loadBalance(ctx, a.account.ID)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_account_balance_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &balanceResolver{balance: accb}, nil
+}
+
+func (a *accountResolver) Statements(ctx context.Context, args struct {
+	connectionArgs
+	CreatedAtBegin *graphql.Time
+	CreatedAtEnd   *graphql.Time
+	Types          *[]string
+	Direction      *string
+	Description    *string
+}) (*statementConnectionResolver, error) {
+	if err := authorize(ctx, authz.ScopeStatementsRead); err != nil {
+		return nil, err
+	}
+
+	p, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := statements.ListFilter{AccountID: a.account.ID}
+	if args.CreatedAtBegin != nil {
+		filter.CreatedAtBegin = args.CreatedAtBegin.Time
+	}
+	if args.CreatedAtEnd != nil {
+		filter.CreatedAtEnd = args.CreatedAtEnd.Time
+	}
+	if args.Types != nil {
+		filter.Types = *args.Types
+	}
+	if args.Direction != nil {
+		filter.Direction = statements.Direction(strings.ToLower(*args.Direction))
+	}
+	if args.Description != nil {
+		filter.Description = *args.Description
+	}
+
+	return a.root.statementConnection(ctx, p, filter)
+}
+
+func (a *accountResolver) Transactions(ctx context.Context, args struct {
+	CreatedAtBegin graphql.Time
+	CreatedAtEnd   graphql.Time
+}) ([]*transactionResolver, error) {
+	if err := authorize(ctx, authz.ScopeTransactionsRead); err != nil {
+		return nil, err
+	}
+
+	trxs, err := a.root.transactions.ListByAccount(ctx, a.account.ID, args.CreatedAtBegin.Time, args.CreatedAtEnd.Time)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_account_transactions_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	resolvers := make([]*transactionResolver, len(trxs))
+	for i, transaction := range trxs {
+		resolvers[i] = &transactionResolver{transaction: transaction}
+	}
+
+	return resolvers, nil
+}
+
+type accountEdgeResolver struct {
+	cursor string
+	node   *accountResolver
+}
+
+func (e accountEdgeResolver) Cursor() string {
+	return e.cursor
+}
+
+func (e accountEdgeResolver) Node() *accountResolver {
+	return e.node
+}
+
+type accountConnectionResolver struct {
+	root     *resolver
+	filter   accounts.ListFilter
+	edges    []accountEdgeResolver
+	pageInfo pageInfoResolver
+}
+
+// accountConnection fetches the page of the accounts matched by the filter.
+func (r *resolver) accountConnection(
+	ctx context.Context,
+	p page,
+	filter accounts.ListFilter,
+) (*accountConnectionResolver, error) {
+	if err := authorize(ctx, authz.ScopeAccountsRead); err != nil {
+		return nil, err
+	}
+
+	filter.Sort = p.sort
+	filter.Page = 1
+	filter.Size = p.size()
+	filter.Cursor = p.after
+
+	_, accs, err := r.accounts.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_accounts_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	keys := make([]cursor.Key, len(accs))
+	for i, account := range accs {
+		keys[i] = cursor.Key{CreatedAt: account.CreatedAt, ID: account.ID.String()}
+	}
+	pageInfo := newPageInfo(p, keys)
+
+	edges := make([]accountEdgeResolver, len(pageInfo.keys))
+	for i := range edges {
+		edges[i] = accountEdgeResolver{
+			cursor: edgeCursor(pageInfo.keys[i]),
+			node:   &accountResolver{root: r, account: accs[i]},
+		}
+	}
+
+	return &accountConnectionResolver{root: r, filter: filter, edges: edges, pageInfo: pageInfo}, nil
+}
+
+func (c *accountConnectionResolver) Edges() []accountEdgeResolver {
+	return c.edges
+}
+
+func (c *accountConnectionResolver) PageInfo() pageInfoResolver {
+	return c.pageInfo
+}
+
+func (c *accountConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	filter := c.filter
+	filter.Page, filter.Size, filter.Cursor, filter.WithTotal = 1, 1, nil, true
+
+	total, _, err := c.root.accounts.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_accounts_service_error", zap.Error(err))
+		return 0, newResolverError(err)
+	}
+
+	return int32(total), nil
+}
+
+type balanceResolver struct {
+	balance balances.AccountBalance
+}
+
+func (b *balanceResolver) AccountID() graphql.ID {
+	return graphql.ID(b.balance.AccountID.String())
+}
+
+func (b *balanceResolver) CurrentBalance() float64 {
+	return b.balance.CurrentBalance
+}
@@ -0,0 +1,101 @@
+package graphqlh
+
+import (
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+)
+
+const (
+	maxFirst = 100
+
+	descOrder = "DESC"
+)
+
+// connectionArgs are the arguments of the connections, paged forward by keyset. First and Order have defaults in
+// the schema.
+type connectionArgs struct {
+	First int32
+	After *string
+	Order string
+}
+
+// page is the page of the connection to fetch from the services.
+type page struct {
+	first int
+	after *cursor.Cursor
+	sort  int
+}
+
+func (a connectionArgs) page() (page, error) {
+	p := page{first: int(a.First)}
+	if p.first < 1 || p.first > maxFirst {
+		return page{}, invalidArgument("first")
+	}
+
+	if a.After != nil && *a.After != "" {
+		after, err := cursor.Parse(*a.After)
+		if err != nil || after.Direction != cursor.Next {
+			return page{}, invalidArgument("after")
+		}
+		p.after = &after
+	}
+
+	if a.Order == descOrder {
+		p.sort = 1
+	}
+
+	return p, nil
+}
+
+// size is the size of the page fetched from the services, one item more than requested tells there is a next page.
+func (p page) size() int {
+	return p.first + 1
+}
+
+type pageInfoResolver struct {
+	keys        []cursor.Key
+	hasNextPage bool
+	hasPrevPage bool
+}
+
+// newPageInfo returns the page info of the fetched keys, trimming them to the requested page size.
+func newPageInfo(p page, keys []cursor.Key) pageInfoResolver {
+	hasNext := len(keys) > p.first
+	if hasNext {
+		keys = keys[:p.first]
+	}
+
+	return pageInfoResolver{
+		keys:        keys,
+		hasNextPage: hasNext,
+		hasPrevPage: p.after != nil,
+	}
+}
+
+func (p pageInfoResolver) HasNextPage() bool {
+	return p.hasNextPage
+}
+
+func (p pageInfoResolver) HasPreviousPage() bool {
+	return p.hasPrevPage
+}
+
+func (p pageInfoResolver) StartCursor() *string {
+	if len(p.keys) == 0 {
+		return nil
+	}
+	c := edgeCursor(p.keys[0])
+	return &c
+}
+
+func (p pageInfoResolver) EndCursor() *string {
+	if len(p.keys) == 0 {
+		return nil
+	}
+	c := edgeCursor(p.keys[len(p.keys)-1])
+	return &c
+}
+
+// edgeCursor is the cursor of an edge, given in after it returns the edges after this one.
+func edgeCursor(key cursor.Key) string {
+	return cursor.Cursor{Key: key, Direction: cursor.Next}.String()
+}
@@ -0,0 +1,106 @@
+package graphqlh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+)
+
+const (
+	notFoundCode           = "NOT_FOUND"
+	failedPreconditionCode = "FAILED_PRECONDITION"
+	invalidArgumentCode    = "INVALID_ARGUMENT"
+	conflictCode           = "CONFLICT"
+	permissionDeniedCode   = "PERMISSION_DENIED"
+	internalCode           = "INTERNAL"
+)
+
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{err: holders.ErrHolderNotFound, code: notFoundCode},
+	{err: holders.ErrHolderAlreadyExists, code: conflictCode},
+	{err: accounts.ErrAccountAlreadyExists, code: conflictCode},
+	{err: accounts.ErrAccountNotFound, code: notFoundCode},
+	{err: transactions.ErrTransactionNotFound, code: notFoundCode},
+	{err: transactions.ErrAccountNotfound, code: notFoundCode},
+	{err: accounts.ErrAccountInactive, code: failedPreconditionCode},
+	{err: accounts.ErrAccountUnblcked, code: failedPreconditionCode},
+	{err: transactions.ErrAccountInactive, code: failedPreconditionCode},
+	{err: transactions.ErrBalanceInsufficientFunds, code: failedPreconditionCode},
+	{err: transactions.ErrInsufficientDailyLimit, code: failedPreconditionCode},
+	{err: transactions.ErrPeriodClosed, code: failedPreconditionCode},
+	{err: transactions.ErrNotAdjustmentPeriod, code: failedPreconditionCode},
+	{err: transactions.ErrFromAccountToAccountShouldBeDifferent, code: invalidArgumentCode},
+	{err: transactions.ErrTransactionInFuture, code: invalidArgumentCode},
+	{err: statements.ErrInvalidDirection, code: invalidArgumentCode},
+	{err: statements.ErrInvalidAmountRange, code: invalidArgumentCode},
+	{err: cursor.ErrInvalidCursor, code: invalidArgumentCode},
+	{err: authz.ErrForbidden, code: permissionDeniedCode},
+	// The lock of the account is held by another operation, the mutation can be retried.
+	{err: transactions.ErrFailLockAccount, code: conflictCode},
+	{err: database.ErrUniqueViolation, code: conflictCode},
+	{err: database.ErrForeignKeyViolation, code: conflictCode},
+	{err: database.ErrCheckViolation, code: invalidArgumentCode},
+	{err: database.ErrNotNullViolation, code: invalidArgumentCode},
+	{err: database.ErrSerializationFailure, code: conflictCode},
+}
+
+// resolverError is an error of the resolvers with its code in the extensions of the GraphQL error.
+type resolverError struct {
+	message string
+	code    string
+}
+
+func (e resolverError) Error() string {
+	return e.message
+}
+
+func (e resolverError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code}
+}
+
+// newResolverError converts the errors of the services to the errors of the resolvers, the domain errors with their
+// message and any other error as internal without leaking its details.
+func newResolverError(err error) error {
+	var rerr resolverError
+	if errors.As(err, &rerr) {
+		return rerr
+	}
+
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return resolverError{message: ec.err.Error(), code: ec.code}
+		}
+	}
+
+	return resolverError{message: "internal error", code: internalCode}
+}
+
+// invalidArgument returns the error of the arguments with an invalid value.
+func invalidArgument(argument string) error {
+	return resolverError{message: fmt.Sprintf("invalid %s", argument), code: invalidArgumentCode}
+}
+
+// authorize returns the error of the resolvers when the principal lacks the scope of the field.
+func authorize(ctx context.Context, scope authz.Scope) error {
+	if err := authz.Authorize(ctx, scope); err != nil {
+		return newResolverError(err)
+	}
+	return nil
+}
+
+// isNotFound tells the error is about a missing entity, resolved as null by the nullable queries.
+func isNotFound(err error) bool {
+	rerr, _ := newResolverError(err).(resolverError)
+	return rerr.code == notFoundCode
+}
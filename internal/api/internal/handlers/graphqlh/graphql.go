@@ -0,0 +1,79 @@
+package graphqlh
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// maxDepth limits the nesting of the queries, deep enough for holder -> accounts -> statements.
+const maxDepth = 8
+
+//go:embed schema.graphql
+var schema string
+
+type (
+	GraphQLFunc echo.HandlerFunc
+
+	graphQLRequest struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+)
+
+// NewGraphQLFunc serves the GraphQL API over the services, resolving the balances and holders of each request
+// through loaders that batch their lookups.
+func NewGraphQLFunc(
+	hs holders.Service,
+	as accounts.Service,
+	ts transactions.Service,
+	bs balances.Service,
+	ss statements.Service,
+) (GraphQLFunc, error) {
+	s, err := graphql.ParseSchema(
+		schema,
+		&resolver{
+			holders:      hs,
+			accounts:     as,
+			transactions: ts,
+			statements:   ss,
+		},
+		graphql.UseStringDescriptions(),
+		graphql.MaxDepth(maxDepth),
+		graphql.Tracer(otel.DefaultTracer()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var req graphQLRequest
+		if err := c.Bind(&req); err != nil {
+			zapctx.L(ctx).Error("graphql_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		if req.Query == "" {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "query is required")
+		}
+
+		ctx = withLoaders(ctx, newLoaders(bs, hs))
+		response := s.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+		// As the GraphQL over HTTP spec, errors of the operations are reported in the body with status 200.
+		return c.JSON(http.StatusOK, response)
+	}, nil
+}
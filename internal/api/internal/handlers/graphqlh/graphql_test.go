@@ -0,0 +1,152 @@
+//go:build unit
+
+package graphqlh
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execGraphQL(t *testing.T, fn GraphQLFunc, query string) graphQLResponse {
+	body, err := json.Marshal(graphQLRequest{Query: query})
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(string(body)))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	recorder := httptest.NewRecorder()
+
+	require.NoError(t, fn(echo.New().NewContext(request, recorder)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response graphQLResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return response
+}
+
+//nolint:funlen
+func TestGraphQLFunc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hs := holders.NewMockService(ctrl)
+	as := accounts.NewMockService(ctrl)
+	ts := transactions.NewMockService(ctrl)
+	bs := balances.NewMockService(ctrl)
+	ss := statements.NewMockService(ctrl)
+
+	fn, err := NewGraphQLFunc(hs, as, ts, bs, ss)
+	require.NoError(t, err)
+
+	t.Run("Holder with accounts and balances", func(t *testing.T) {
+		holder := holders.Holder{ID: uuid.New(), Name: "Holder", DocumentNumber: "12345678901", CreatedAt: time.Now()}
+		accs := []accounts.Account{
+			{ID: uuid.New(), HolderID: holder.ID, Status: accounts.ActiveStatus, CreatedAt: time.Now()},
+			{ID: uuid.New(), HolderID: holder.ID, Status: accounts.BlockedStatus, CreatedAt: time.Now()},
+		}
+
+		hs.EXPECT().GetByID(gomock.Any(), holder.ID).Return(holder, nil)
+		as.EXPECT().
+			List(gomock.Any(), accounts.ListFilter{Page: 1, Size: 3, DocumentNumber: holder.DocumentNumber}).
+			Return(0, accs, nil)
+		// A single lookup for the balances of all accounts.
+		bs.EXPECT().
+			GetByAccountIDs(gomock.Any(), gomock.Len(2)).
+			DoAndReturn(func(_ interface{}, accountIDs []uuid.UUID) ([]balances.AccountBalance, error) {
+				accbs := make([]balances.AccountBalance, len(accountIDs))
+				for i, accountID := range accountIDs {
+					accbs[i] = balances.AccountBalance{AccountID: accountID, CurrentBalance: 10}
+				}
+				return accbs, nil
+			})
+
+		response := execGraphQL(t, fn, `{
+			holder(id: "`+holder.ID.String()+`") {
+				name
+				accounts(first: 2) {
+					edges { cursor node { id status balance { currentBalance } } }
+					pageInfo { hasNextPage hasPreviousPage }
+				}
+			}
+		}`)
+		require.Empty(t, response.Errors)
+
+		var data struct {
+			Holder struct {
+				Name     string
+				Accounts struct {
+					Edges []struct {
+						Cursor string
+						Node   struct {
+							ID      string
+							Status  string
+							Balance struct{ CurrentBalance float64 }
+						}
+					}
+					PageInfo struct{ HasNextPage, HasPreviousPage bool }
+				}
+			}
+		}
+		require.NoError(t, json.Unmarshal(response.Data, &data))
+		assert.Equal(t, holder.Name, data.Holder.Name)
+		assert.Len(t, data.Holder.Accounts.Edges, 2)
+		assert.False(t, data.Holder.Accounts.PageInfo.HasNextPage)
+		assert.False(t, data.Holder.Accounts.PageInfo.HasPreviousPage)
+		for i, edge := range data.Holder.Accounts.Edges {
+			assert.Equal(t, accs[i].ID.String(), edge.Node.ID)
+			assert.Equal(t, string(accs[i].Status), edge.Node.Status)
+			assert.Equal(t, 10.0, edge.Node.Balance.CurrentBalance)
+			assert.NotEmpty(t, edge.Cursor)
+		}
+	})
+
+	t.Run("Holder not found", func(t *testing.T) {
+		hs.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(holders.Holder{}, holders.ErrHolderNotFound)
+
+		response := execGraphQL(t, fn, `{ holder(id: "`+uuid.NewString()+`") { name } }`)
+		assert.Empty(t, response.Errors)
+		assert.JSONEq(t, `{"holder": null}`, string(response.Data))
+	})
+
+	t.Run("Debit with insufficient funds", func(t *testing.T) {
+		fromID := uuid.New()
+		ts.EXPECT().
+			CreateDebit(gomock.Any(), transactions.Transaction{From: fromID, Amount: 10}).
+			Return(transactions.Transaction{}, transactions.ErrBalanceInsufficientFunds)
+
+		response := execGraphQL(t, fn, `mutation {
+			createDebit(input: {fromAccountId: "`+fromID.String()+`", amount: 10}) { id }
+		}`)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, transactions.ErrBalanceInsufficientFunds.Error(), response.Errors[0].Message)
+		assert.Equal(t, failedPreconditionCode, response.Errors[0].Extensions["code"])
+	})
+
+	t.Run("Invalid first", func(t *testing.T) {
+		response := execGraphQL(t, fn, `{ holders(first: 1000) { edges { cursor } } }`)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, invalidArgumentCode, response.Errors[0].Extensions["code"])
+	})
+}
@@ -0,0 +1,125 @@
+package graphqlh
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+type holderResolver struct {
+	root   *resolver
+	holder holders.Holder
+}
+
+func (h *holderResolver) ID() graphql.ID {
+	return graphql.ID(h.holder.ID.String())
+}
+
+func (h *holderResolver) Name() string {
+	return h.holder.Name
+}
+
+func (h *holderResolver) DocumentNumber() string {
+	return h.holder.DocumentNumber
+}
+
+func (h *holderResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: h.holder.CreatedAt}
+}
+
+func (h *holderResolver) Accounts(ctx context.Context, args struct {
+	connectionArgs
+	Status *string
+}) (*accountConnectionResolver, error) {
+	p, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+
+	filter := accounts.ListFilter{DocumentNumber: h.holder.DocumentNumber}
+	if args.Status != nil {
+		filter.Status = accounts.Status(*args.Status)
+	}
+
+	return h.root.accountConnection(ctx, p, filter)
+}
+
+type holderEdgeResolver struct {
+	cursor string
+	node   *holderResolver
+}
+
+func (e holderEdgeResolver) Cursor() string {
+	return e.cursor
+}
+
+func (e holderEdgeResolver) Node() *holderResolver {
+	return e.node
+}
+
+type holderConnectionResolver struct {
+	root     *resolver
+	filter   holders.ListFilter
+	edges    []holderEdgeResolver
+	pageInfo pageInfoResolver
+}
+
+// holderConnection fetches the page of the holders matched by the filter.
+func (r *resolver) holderConnection(
+	ctx context.Context,
+	p page,
+	filter holders.ListFilter,
+) (*holderConnectionResolver, error) {
+	filter.Sort = p.sort
+	filter.Page = 1
+	filter.Size = p.size()
+	filter.Cursor = p.after
+
+	_, hdlrs, err := r.holders.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_holders_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	keys := make([]cursor.Key, len(hdlrs))
+	for i, holder := range hdlrs {
+		keys[i] = cursor.Key{CreatedAt: holder.CreatedAt, ID: holder.ID.String()}
+	}
+	pageInfo := newPageInfo(p, keys)
+
+	edges := make([]holderEdgeResolver, len(pageInfo.keys))
+	for i := range edges {
+		edges[i] = holderEdgeResolver{
+			cursor: edgeCursor(pageInfo.keys[i]),
+			node:   &holderResolver{root: r, holder: hdlrs[i]},
+		}
+	}
+
+	return &holderConnectionResolver{root: r, filter: filter, edges: edges, pageInfo: pageInfo}, nil
+}
+
+func (c *holderConnectionResolver) Edges() []holderEdgeResolver {
+	return c.edges
+}
+
+func (c *holderConnectionResolver) PageInfo() pageInfoResolver {
+	return c.pageInfo
+}
+
+func (c *holderConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	filter := c.filter
+	filter.Page, filter.Size, filter.Cursor, filter.WithTotal = 1, 1, nil, true
+
+	total, _, err := c.root.holders.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_holders_service_error", zap.Error(err))
+		return 0, newResolverError(err)
+	}
+
+	return int32(total), nil
+}
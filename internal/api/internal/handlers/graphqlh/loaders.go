@@ -0,0 +1,109 @@
+package graphqlh
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/google/uuid"
+	"github.com/graph-gophers/dataloader"
+)
+
+type loadersKey struct{}
+
+// loaders batch and cache the lookups of a single request, resolving the balances of all accounts of a query in a
+// single call of the balances.Service instead of one call per account (N+1).
+type loaders struct {
+	balances *dataloader.Loader
+	holders  *dataloader.Loader
+}
+
+func newLoaders(bs balances.Service, hs holders.Service) loaders {
+	return loaders{
+		balances: dataloader.NewBatchedLoader(
+			func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+				return loadBalances(ctx, bs, keys)
+			},
+			dataloader.WithBatchCapacity(maxFirst),
+		),
+		holders: dataloader.NewBatchedLoader(
+			func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+				return loadHolders(ctx, hs, keys)
+			},
+		),
+	}
+}
+
+func withLoaders(ctx context.Context, l loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFromContext(ctx context.Context) loaders {
+	l, _ := ctx.Value(loadersKey{}).(loaders)
+	return l
+}
+
+func loadBalance(ctx context.Context, accountID uuid.UUID) (balances.AccountBalance, error) {
+	result, err := loadersFromContext(ctx).balances.Load(ctx, dataloader.StringKey(accountID.String()))()
+	if err != nil {
+		return balances.AccountBalance{}, err
+	}
+	return result.(balances.AccountBalance), nil
+}
+
+func loadHolder(ctx context.Context, holderID uuid.UUID) (holders.Holder, error) {
+	result, err := loadersFromContext(ctx).holders.Load(ctx, dataloader.StringKey(holderID.String()))()
+	if err != nil {
+		return holders.Holder{}, err
+	}
+	return result.(holders.Holder), nil
+}
+
+func loadBalances(ctx context.Context, bs balances.Service, keys dataloader.Keys) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(keys))
+
+	accountIDs := make([]uuid.UUID, len(keys))
+	for i, key := range keys {
+		accountID, err := uuid.Parse(key.String())
+		if err != nil {
+			return failedResults(results, err)
+		}
+		accountIDs[i] = accountID
+	}
+
+	accountBalances, err := bs.GetByAccountIDs(ctx, accountIDs)
+	if err != nil {
+		return failedResults(results, err)
+	}
+
+	for i, accountBalance := range accountBalances {
+		results[i] = &dataloader.Result{Data: accountBalance}
+	}
+
+	return results
+}
+
+// loadHolders only deduplicates the holders of the request, the holders.Service has no batch lookup.
+func loadHolders(ctx context.Context, hs holders.Service, keys dataloader.Keys) []*dataloader.Result {
+	results := make([]*dataloader.Result, len(keys))
+
+	for i, key := range keys {
+		holderID, err := uuid.Parse(key.String())
+		if err != nil {
+			results[i] = &dataloader.Result{Error: err}
+			continue
+		}
+
+		holder, err := hs.GetByID(ctx, holderID)
+		results[i] = &dataloader.Result{Data: holder, Error: err}
+	}
+
+	return results
+}
+
+func failedResults(results []*dataloader.Result, err error) []*dataloader.Result {
+	for i := range results {
+		results[i] = &dataloader.Result{Error: err}
+	}
+	return results
+}
@@ -0,0 +1,131 @@
+package graphqlh
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+// resolver is the root resolver of the queries and mutations, its services are shared by the nested resolvers.
+type resolver struct {
+	holders      holders.Service
+	accounts     accounts.Service
+	transactions transactions.Service
+	statements   statements.Service
+}
+
+func (r *resolver) Holder(ctx context.Context, args struct{ ID graphql.ID }) (*holderResolver, error) {
+	if err := authorize(ctx, authz.ScopeHoldersRead); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, invalidArgument("id")
+	}
+
+	holder, err := r.holders.GetByID(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		zapctx.L(ctx).Error("graphql_holder_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &holderResolver{root: r, holder: holder}, nil
+}
+
+func (r *resolver) Holders(ctx context.Context, args struct {
+	connectionArgs
+	DocumentNumber *string
+}) (*holderConnectionResolver, error) {
+	if err := authorize(ctx, authz.ScopeHoldersRead); err != nil {
+		return nil, err
+	}
+
+	p, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+
+	var documentNumber string
+	if args.DocumentNumber != nil {
+		documentNumber = *args.DocumentNumber
+	}
+
+	return r.holderConnection(ctx, p, holders.ListFilter{DocumentNumber: documentNumber})
+}
+
+func (r *resolver) Account(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	if err := authorize(ctx, authz.ScopeAccountsRead); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, invalidArgument("id")
+	}
+
+	account, err := r.accounts.GetByID(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		zapctx.L(ctx).Error("graphql_account_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &accountResolver{root: r, account: account}, nil
+}
+
+func (r *resolver) Accounts(ctx context.Context, args struct {
+	connectionArgs
+	DocumentNumber *string
+	Status         *string
+}) (*accountConnectionResolver, error) {
+	p, err := args.page()
+	if err != nil {
+		return nil, err
+	}
+
+	var filter accounts.ListFilter
+	if args.DocumentNumber != nil {
+		filter.DocumentNumber = *args.DocumentNumber
+	}
+	if args.Status != nil {
+		filter.Status = accounts.Status(*args.Status)
+	}
+
+	return r.accountConnection(ctx, p, filter)
+}
+
+func (r *resolver) Transaction(ctx context.Context, args struct{ ID graphql.ID }) (*transactionResolver, error) {
+	if err := authorize(ctx, authz.ScopeTransactionsRead); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(args.ID))
+	if err != nil {
+		return nil, invalidArgument("id")
+	}
+
+	transaction, err := r.transactions.GetByID(ctx, id)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		zapctx.L(ctx).Error("graphql_transaction_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &transactionResolver{transaction: transaction}, nil
+}
@@ -0,0 +1,177 @@
+package graphqlh
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+type statementResolver struct {
+	statement statements.Statement
+}
+
+func (s *statementResolver) ID() graphql.ID {
+	return graphql.ID(s.statement.ID.String())
+}
+
+func (s *statementResolver) FromAccount() *statementAccountResolver {
+	return newStatementAccount(s.statement.FromAccount)
+}
+
+func (s *statementResolver) ToAccount() *statementAccountResolver {
+	return newStatementAccount(s.statement.ToAccount)
+}
+
+func (s *statementResolver) Type() string {
+	return s.statement.Type
+}
+
+func (s *statementResolver) Amount() float64 {
+	return s.statement.Amount
+}
+
+func (s *statementResolver) Description() string {
+	return s.statement.Description
+}
+
+func (s *statementResolver) Balance() float64 {
+	return s.statement.Balance
+}
+
+func (s *statementResolver) Adjustment() bool {
+	return s.statement.Adjustment
+}
+
+func (s *statementResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: s.statement.CreatedAt}
+}
+
+type statementAccountResolver struct {
+	account accounts.Account
+}
+
+func newStatementAccount(account accounts.Account) *statementAccountResolver {
+	if account.ID == uuid.Nil {
+		return nil
+	}
+	return &statementAccountResolver{account: account}
+}
+
+func (s *statementAccountResolver) ID() graphql.ID {
+	return graphql.ID(s.account.ID.String())
+}
+
+func (s *statementAccountResolver) Name() string {
+	return s.account.Name
+}
+
+type statementEdgeResolver struct {
+	cursor string
+	node   *statementResolver
+}
+
+func (e statementEdgeResolver) Cursor() string {
+	return e.cursor
+}
+
+func (e statementEdgeResolver) Node() *statementResolver {
+	return e.node
+}
+
+type statementConnectionResolver struct {
+	root     *resolver
+	filter   statements.ListFilter
+	edges    []statementEdgeResolver
+	pageInfo pageInfoResolver
+}
+
+// statementConnection fetches the page of the statements matched by the filter.
+func (r *resolver) statementConnection(
+	ctx context.Context,
+	p page,
+	filter statements.ListFilter,
+) (*statementConnectionResolver, error) {
+	filter.Sort = p.sort
+	filter.Page = 1
+	filter.Size = p.size()
+	filter.Cursor = p.after
+
+	_, stms, err := r.statements.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_statements_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	keys := make([]cursor.Key, len(stms))
+	for i, stm := range stms {
+		keys[i] = cursor.Key{CreatedAt: stm.CreatedAt, ID: stm.ID.String()}
+	}
+	pageInfo := newPageInfo(p, keys)
+
+	edges := make([]statementEdgeResolver, len(pageInfo.keys))
+	for i := range edges {
+		edges[i] = statementEdgeResolver{
+			cursor: edgeCursor(pageInfo.keys[i]),
+			node:   &statementResolver{statement: stms[i]},
+		}
+	}
+
+	return &statementConnectionResolver{root: r, filter: filter, edges: edges, pageInfo: pageInfo}, nil
+}
+
+func (c *statementConnectionResolver) Edges() []statementEdgeResolver {
+	return c.edges
+}
+
+func (c *statementConnectionResolver) PageInfo() pageInfoResolver {
+	return c.pageInfo
+}
+
+func (c *statementConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	filter := c.filter
+	filter.Page, filter.Size, filter.Cursor, filter.WithTotal = 1, 1, nil, true
+
+	total, _, err := c.root.statements.List(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_statements_service_error", zap.Error(err))
+		return 0, newResolverError(err)
+	}
+
+	return int32(total), nil
+}
+
+func (c *statementConnectionResolver) Summary(ctx context.Context) (*statementSummaryResolver, error) {
+	smr, err := c.root.statements.Summarize(ctx, c.filter)
+	if err != nil {
+		zapctx.L(ctx).Error("graphql_statements_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &statementSummaryResolver{summary: smr}, nil
+}
+
+type statementSummaryResolver struct {
+	summary statements.Summary
+}
+
+func (s *statementSummaryResolver) OpeningBalance() float64 {
+	return s.summary.OpeningBalance
+}
+
+func (s *statementSummaryResolver) ClosingBalance() float64 {
+	return s.summary.ClosingBalance
+}
+
+func (s *statementSummaryResolver) TotalCredits() float64 {
+	return s.summary.TotalCredits
+}
+
+func (s *statementSummaryResolver) TotalDebits() float64 {
+	return s.summary.TotalDebits
+}
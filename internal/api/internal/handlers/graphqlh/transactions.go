@@ -0,0 +1,225 @@
+package graphqlh
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	graphql "github.com/graph-gophers/graphql-go"
+	"go.uber.org/zap"
+)
+
+type transactionResolver struct {
+	transaction transactions.Transaction
+}
+
+func (t *transactionResolver) ID() graphql.ID {
+	return graphql.ID(t.transaction.ID.String())
+}
+
+func (t *transactionResolver) FromAccountID() *graphql.ID {
+	return optionalID(t.transaction.From)
+}
+
+func (t *transactionResolver) ToAccountID() *graphql.ID {
+	return optionalID(t.transaction.To)
+}
+
+func (t *transactionResolver) Type() string {
+	return string(t.transaction.Type)
+}
+
+func (t *transactionResolver) Amount() float64 {
+	return t.transaction.Amount
+}
+
+func (t *transactionResolver) Description() string {
+	return t.transaction.Description
+}
+
+func (t *transactionResolver) Adjustment() bool {
+	return t.transaction.Adjustment
+}
+
+func (t *transactionResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: t.transaction.CreatedAt}
+}
+
+func optionalID(id uuid.UUID) *graphql.ID {
+	if id == uuid.Nil {
+		return nil
+	}
+	gid := graphql.ID(id.String())
+	return &gid
+}
+
+type (
+	createCreditInput struct {
+		ToAccountID graphql.ID
+		newTransactionInput
+	}
+
+	createDebitInput struct {
+		FromAccountID graphql.ID
+		newTransactionInput
+	}
+
+	createP2PInput struct {
+		FromAccountID graphql.ID
+		ToAccountID   graphql.ID
+		newTransactionInput
+	}
+
+	// newTransactionInput are the fields shared by the inputs of the mutations that create transactions.
+	newTransactionInput struct {
+		Amount      float64
+		Description *string
+		Adjustment  *bool
+		CreatedAt   *graphql.Time
+	}
+)
+
+// transaction converts the input to the transaction between the accounts, empty ids are not parsed.
+func (i newTransactionInput) transaction(fromID, toID graphql.ID) (transactions.Transaction, error) {
+	var trx transactions.Transaction
+	var err error
+
+	if fromID != "" {
+		trx.From, err = uuid.Parse(string(fromID))
+		if err != nil {
+			return transactions.Transaction{}, invalidArgument("fromAccountId")
+		}
+	}
+
+	if toID != "" {
+		trx.To, err = uuid.Parse(string(toID))
+		if err != nil {
+			return transactions.Transaction{}, invalidArgument("toAccountId")
+		}
+	}
+
+	if i.Amount <= 0 {
+		return transactions.Transaction{}, invalidArgument("amount")
+	}
+	trx.Amount = i.Amount
+
+	if i.Description != nil {
+		trx.Description = *i.Description
+	}
+	if i.Adjustment != nil {
+		trx.Adjustment = *i.Adjustment
+	}
+	if i.CreatedAt != nil {
+		trx.CreatedAt = i.CreatedAt.Time
+	}
+
+	return trx, nil
+}
+
+func (r *resolver) BlockAccount(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	return r.accountMutation(ctx, "graphql_block_account", args.ID, r.accounts.BlockByID)
+}
+
+func (r *resolver) UnblockAccount(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	return r.accountMutation(ctx, "graphql_unblock_account", args.ID, r.accounts.UnblockByID)
+}
+
+func (r *resolver) CloseAccount(ctx context.Context, args struct{ ID graphql.ID }) (*accountResolver, error) {
+	return r.accountMutation(ctx, "graphql_close_account", args.ID, r.accounts.CloseByID)
+}
+
+func (r *resolver) CreateCredit(
+	ctx context.Context,
+	args struct{ Input createCreditInput },
+) (*transactionResolver, error) {
+	trx, err := args.Input.transaction("", args.Input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	return r.transactionMutation(
+		ctx,
+		"graphql_create_credit",
+		authz.ScopeTransactionsCredit,
+		trx,
+		r.transactions.CreateCredit,
+	)
+}
+
+func (r *resolver) CreateDebit(
+	ctx context.Context,
+	args struct{ Input createDebitInput },
+) (*transactionResolver, error) {
+	trx, err := args.Input.transaction(args.Input.FromAccountID, "")
+	if err != nil {
+		return nil, err
+	}
+	return r.transactionMutation(
+		ctx,
+		"graphql_create_debit",
+		authz.ScopeTransactionsDebit,
+		trx,
+		r.transactions.CreateDebit,
+	)
+}
+
+func (r *resolver) CreateP2P(
+	ctx context.Context,
+	args struct{ Input createP2PInput },
+) (*transactionResolver, error) {
+	trx, err := args.Input.transaction(args.Input.FromAccountID, args.Input.ToAccountID)
+	if err != nil {
+		return nil, err
+	}
+	return r.transactionMutation(ctx, "graphql_create_p2p", authz.ScopeTransactionsP2P, trx, r.transactions.CreateP2P)
+}
+
+// accountMutation runs the operation of the service over the account of the id, logging the errors prefixed by
+// the event.
+func (r *resolver) accountMutation(
+	ctx context.Context,
+	event string,
+	rawID graphql.ID,
+	operation func(context.Context, uuid.UUID) (accounts.Account, error),
+) (*accountResolver, error) {
+	if err := authorize(ctx, authz.ScopeAccountsManage); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.Parse(string(rawID))
+	if err != nil {
+		return nil, invalidArgument("id")
+	}
+
+	account, err := operation(ctx, id)
+	if err != nil {
+		zapctx.L(ctx).Error(event+"_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &accountResolver{root: r, account: account}, nil
+}
+
+// transactionMutation creates the transaction with the operation of the service when the principal has the scope,
+// logging the errors prefixed by the event.
+func (r *resolver) transactionMutation(
+	ctx context.Context,
+	event string,
+	scope authz.Scope,
+	trx transactions.Transaction,
+	operation func(context.Context, transactions.Transaction) (transactions.Transaction, error),
+) (*transactionResolver, error) {
+	if err := authorize(ctx, scope); err != nil {
+		return nil, err
+	}
+
+	transaction, err := operation(ctx, trx)
+	if err != nil {
+		zapctx.L(ctx).Error(event+"_service_error", zap.Error(err))
+		return nil, newResolverError(err)
+	}
+
+	return &transactionResolver{transaction: transaction}, nil
+}
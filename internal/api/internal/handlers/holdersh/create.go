@@ -3,6 +3,7 @@ package holdersh
 import (
 	"net/http"
 
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/go-ozzo/ozzo-validation/v4"
@@ -16,11 +17,25 @@ type (
 	createHolder struct {
 		Name           string `json:"name"`
 		DocumentNumber string `json:"document_number"`
+		// Account is the first account of the holder, created with it when given.
+		Account *createHolderAccount `json:"account"`
+	}
+	createHolderAccount struct {
+		Name string `json:"name"`
 	}
 	createdHolder struct {
+		ID             string                `json:"id"`
+		Name           string                `json:"name"`
+		DocumentNumber string                `json:"document_number"`
+		Account        *createdHolderAccount `json:"account,omitempty"`
+	}
+	createdHolderAccount struct {
 		ID             string `json:"id"`
 		Name           string `json:"name"`
+		Agency         string `json:"agency"`
+		Number         string `json:"number"`
 		DocumentNumber string `json:"document_number"`
+		Status         string `json:"status"`
 	}
 )
 
@@ -28,10 +43,18 @@ func (c createHolder) Validate() error {
 	return validation.ValidateStruct(&c,
 		validation.Field(&c.Name, validation.Required, validation.Length(1, 100)),
 		validation.Field(&c.DocumentNumber, validation.Required, validation.Length(11, 14)),
+		validation.Field(&c.Account),
 	)
 }
 
-func NewCreateHolderFunc(svc holders.Service) CreateHolderFunc {
+func (c createHolderAccount) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required, validation.Length(1, 100)),
+	)
+}
+
+// NewCreateHolderFunc creates the holder, with its first account in the same unit of work when the request has one.
+func NewCreateHolderFunc(svc holders.Service, accountsSvc accounts.Service) CreateHolderFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 
@@ -46,10 +69,15 @@ func NewCreateHolderFunc(svc holders.Service) CreateHolderFunc {
 			return err
 		}
 
-		holder, err := svc.Create(ctx, holders.Holder{
+		holder := holders.Holder{
 			Name:           acc.Name,
 			DocumentNumber: acc.DocumentNumber,
-		})
+		}
+		if acc.Account != nil {
+			return createHolderWithAccount(c, accountsSvc, holder, accounts.Account{Name: acc.Account.Name})
+		}
+
+		holder, err := svc.Create(ctx, holder)
 		if err != nil {
 			zapctx.L(ctx).Error("create_holder_handler_service_error", zap.Error(err))
 			return err
@@ -65,3 +93,35 @@ func NewCreateHolderFunc(svc holders.Service) CreateHolderFunc {
 		)
 	}
 }
+
+func createHolderWithAccount(
+	c echo.Context,
+	svc accounts.Service,
+	holder holders.Holder,
+	account accounts.Account,
+) error {
+	ctx := c.Request().Context()
+
+	holder, account, err := svc.CreateWithHolder(ctx, holder, account)
+	if err != nil {
+		zapctx.L(ctx).Error("create_holder_handler_with_account_service_error", zap.Error(err))
+		return err
+	}
+
+	return c.JSON(
+		http.StatusCreated,
+		createdHolder{
+			ID:             holder.ID.String(),
+			Name:           holder.Name,
+			DocumentNumber: holder.DocumentNumber,
+			Account: &createdHolderAccount{
+				ID:             account.ID.String(),
+				Name:           account.Name,
+				Agency:         account.Agency,
+				Number:         account.Number,
+				DocumentNumber: account.DocumentNumber,
+				Status:         string(account.Status),
+			},
+		},
+	)
+}
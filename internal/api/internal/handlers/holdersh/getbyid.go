@@ -37,7 +37,7 @@ func NewGetByIDHolderFunc(svc holders.Service) GetByIDHolderFunc {
 		holder, err := svc.GetByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("get_by_id_holder_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
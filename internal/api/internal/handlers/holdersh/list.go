@@ -3,7 +3,9 @@ package holdersh
 import (
 	"net/http"
 
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/paging"
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -17,15 +19,19 @@ type (
 		Sort          int    `query:"sort"`
 		Page          int    `query:"page"`
 		Size          int    `query:"size"`
+		Cursor        string `query:"cursor"`
+		WithTotal     string `query:"with_total"`
 	}
 
 	pagination struct {
-		Sort        int `json:"sort"`
-		Page        int `json:"page"`
-		Size        int `json:"size"`
-		TotalItems  int `json:"total_items"`
-		TotalPages  int `json:"total_pages"`
-		TotalInPage int `json:"total_in_page"`
+		Sort        int    `json:"sort"`
+		Page        int    `json:"page"`
+		Size        int    `json:"size"`
+		TotalItems  *int   `json:"total_items,omitempty"`
+		TotalPages  *int   `json:"total_pages,omitempty"`
+		TotalInPage int    `json:"total_in_page"`
+		NextCursor  string `json:"next_cursor,omitempty"`
+		PrevCursor  string `json:"prev_cursor,omitempty"`
 	}
 
 	listedHolder struct {
@@ -52,39 +58,49 @@ func NewListHoldersFunc(svc holders.Service) ListHoldersFunc {
 			lsa.Size = 20
 		}
 
+		crs, withTotal, err := paging.Parse(lsa.Cursor, lsa.WithTotal)
+		if err != nil {
+			zapctx.L(ctx).Error("list_holder_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
 		total, hdlrs, err := svc.List(ctx, holders.ListFilter{
 			Sort:           lsa.Sort,
 			Page:           lsa.Page,
 			Size:           lsa.Size,
 			DocumentNumber: lsa.DocumentNumer,
+			Cursor:         crs,
+			WithTotal:      withTotal,
 		})
 		if err != nil {
 			zapctx.L(ctx).Error("list_account_handler_service_error", zap.Error(err))
 			return err
 		}
 
-		totalPages := total / lsa.Size
-		if (total % lsa.Size) != 0 {
-			totalPages++
-		}
+		totalItems, totalPages := paging.Totals(withTotal, total, lsa.Size)
 
 		cholders := make([]createdHolder, len(hdlrs))
+		keys := make([]cursor.Key, len(hdlrs))
 		for i, holder := range hdlrs {
 			cholders[i] = createdHolder{
 				ID:             holder.ID.String(),
 				Name:           holder.Name,
 				DocumentNumber: holder.DocumentNumber,
 			}
+			keys[i] = cursor.Key{CreatedAt: holder.CreatedAt, ID: holder.ID.String()}
 		}
+		links := cursor.NewLinks(crs, lsa.Page > 1, lsa.Size, keys)
 
 		listed := listedHolder{
 			Pagination: pagination{
 				Sort:        lsa.Sort,
 				Page:        lsa.Page,
 				Size:        lsa.Size,
-				TotalItems:  total,
+				TotalItems:  totalItems,
 				TotalPages:  totalPages,
 				TotalInPage: len(cholders),
+				NextCursor:  links.Next,
+				PrevCursor:  links.Prev,
 			},
 			Holders: cholders,
 		}
@@ -0,0 +1,69 @@
+package ledgerchainh
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	GetCheckpointFunc echo.HandlerFunc
+
+	getCheckpoint struct {
+		Date string `param:"date"`
+	}
+
+	checkpoint struct {
+		Date      string    `json:"date"`
+		Sequence  int64     `json:"sequence"`
+		Hash      string    `json:"hash"`
+		Message   string    `json:"message"`
+		Signature string    `json:"signature"`
+		PublicKey string    `json:"public_key"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+// NewGetCheckpointFunc returns the signed checkpoint of the day with the signed message, so it can be published and
+// verified externally with the public key.
+func NewGetCheckpointFunc(svc ledgerchain.Service) GetCheckpointFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var gcp getCheckpoint
+		if err := c.Bind(&gcp); err != nil {
+			zapctx.L(ctx).Error("get_checkpoint_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		date, err := businessdays.ParseDate(gcp.Date)
+		if err != nil {
+			zapctx.L(ctx).Error("get_checkpoint_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		cp, err := svc.GetCheckpoint(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error("get_checkpoint_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(
+			http.StatusOK,
+			checkpoint{
+				Date:      businessdays.FormatDate(cp.Date),
+				Sequence:  cp.Sequence,
+				Hash:      cp.Hash,
+				Message:   string(cp.Message()),
+				Signature: cp.Signature,
+				PublicKey: cp.PublicKey,
+				CreatedAt: cp.CreatedAt,
+			},
+		)
+	}
+}
@@ -1,25 +1,18 @@
 package handlers
 
 import (
-	"net/http"
-
 	"github.com/dalmarcogd/ledger-exp/pkg/healthcheck"
-	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/labstack/echo/v4"
-	"go.uber.org/zap"
 )
 
 type LivenessFunc echo.HandlerFunc
 
-func NewLivenessFunc(check healthcheck.HealthCheck) LivenessFunc {
+// NewLivenessFunc replies the report of the components, with 503 when a critical one is down.
+func NewLivenessFunc(check healthcheck.Reporter) LivenessFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
-		err := check.Liveness(ctx)
-		if err != nil {
-			zapctx.L(ctx).Error("liveness_error", zap.Error(err))
-			c.Error(err)
-			return nil
-		}
-		return c.NoContent(http.StatusOK)
+		report := check.LivenessReport(ctx)
+		logHealthReport(ctx, "liveness_error", report)
+		return c.JSON(healthStatusCode(report), report)
 	}
 }
@@ -0,0 +1,74 @@
+package openapih
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// uuidFormat validates the ids of any version, the ones of the predefined format of the library are only up to v5.
+const uuidFormat = `^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`
+
+//go:embed openapi.yaml
+var spec []byte
+
+// docsPage renders the document with Swagger UI.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8" />
+  <title>ledger-exp API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>`
+
+type (
+	OpenAPIFunc echo.HandlerFunc
+	DocsFunc    echo.HandlerFunc
+)
+
+func init() {
+	openapi3.DefineStringFormat("uuid", uuidFormat)
+}
+
+// Load loads the OpenAPI document of the HTTP API, failing when it is not a valid document.
+func Load() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(spec)
+	if err != nil {
+		return nil, fmt.Errorf("load openapi document: %w", err)
+	}
+
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("validate openapi document: %w", err)
+	}
+
+	return doc, nil
+}
+
+// NewOpenAPIFunc serves the OpenAPI document as JSON.
+func NewOpenAPIFunc(doc *openapi3.T) OpenAPIFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, doc)
+	}
+}
+
+// NewDocsFunc serves the interactive documentation of the OpenAPI document.
+func NewDocsFunc() DocsFunc {
+	return func(c echo.Context) error {
+		return c.HTML(http.StatusOK, docsPage)
+	}
+}
@@ -0,0 +1,48 @@
+package paging
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+)
+
+var ErrInvalidWithTotal = errors.New("invalid with_total")
+
+// Parse reads the cursor and with_total parameters of listings. Without with_total the items are only counted when
+// paging by offset, to keep the total_items and total_pages of the responses for clients that don't use cursors.
+func Parse(token, withTotal string) (*cursor.Cursor, bool, error) {
+	var c *cursor.Cursor
+	if token != "" {
+		parsed, err := cursor.Parse(token)
+		if err != nil {
+			return nil, false, err
+		}
+		c = &parsed
+	}
+
+	if withTotal == "" {
+		return c, c == nil, nil
+	}
+
+	count, err := strconv.ParseBool(withTotal)
+	if err != nil {
+		return nil, false, ErrInvalidWithTotal
+	}
+
+	return c, count, nil
+}
+
+// Totals returns the total of items and pages of a listing, nil when the items were not counted.
+func Totals(counted bool, total, size int) (*int, *int) {
+	if !counted {
+		return nil, nil
+	}
+
+	totalPages := total / size
+	if (total % size) != 0 {
+		totalPages++
+	}
+
+	return &total, &totalPages
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/dalmarcogd/ledger-exp/pkg/healthcheck"
@@ -11,15 +12,36 @@ import (
 
 type ReadinessFunc echo.HandlerFunc
 
-func NewReadinessFunc(check healthcheck.HealthCheck) ReadinessFunc {
+// NewReadinessFunc replies the report of the components, with 503 when a critical one is down. A degraded API, with
+// only non-critical components down, is still ready.
+func NewReadinessFunc(check healthcheck.Reporter) ReadinessFunc {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
-		err := check.Readiness(ctx)
-		if err != nil {
-			zapctx.L(ctx).Error("readiness_error", zap.Error(err))
-			c.Error(err)
-			return nil
+		report := check.ReadinessReport(ctx)
+		logHealthReport(ctx, "readiness_error", report)
+		return c.JSON(healthStatusCode(report), report)
+	}
+}
+
+// logHealthReport logs the components down with the errors of their checks, which are not in the replied report.
+func logHealthReport(ctx context.Context, event string, report healthcheck.Report) {
+	for _, component := range report.Components {
+		if component.Status != healthcheck.StatusDown {
+			continue
 		}
-		return c.NoContent(http.StatusOK)
+		zapctx.L(ctx).Error(
+			event,
+			zap.String("status", string(report.Status)),
+			zap.String("component", component.Name),
+			zap.Bool("critical", component.Critical),
+			zap.String("error", component.Error),
+		)
+	}
+}
+
+func healthStatusCode(report healthcheck.Report) int {
+	if report.Status == healthcheck.StatusDown {
+		return http.StatusServiceUnavailable
 	}
+	return http.StatusOK
 }
@@ -0,0 +1,70 @@
+package reconciliationsh
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	CreateReconciliationFunc echo.HandlerFunc
+
+	createReconciliation struct {
+		AccountID         string `query:"account_id"`
+		Format            string `query:"format"`
+		FileName          string `query:"file_name"`
+		AmountTolerance   string `query:"amount_tolerance"`
+		DateToleranceDays int    `query:"date_tolerance_days"`
+	}
+)
+
+// NewCreateReconciliationFunc reconciles the settlement file sent as the body of the request (text/plain) with the
+// transactions of the account, the other parameters are sent in the query string.
+func NewCreateReconciliationFunc(svc reconciliations.Service) CreateReconciliationFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var cr createReconciliation
+		if err := (&echo.DefaultBinder{}).BindQueryParams(c, &cr); err != nil {
+			zapctx.L(ctx).Error("create_reconciliation_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		accountID, err := uuid.Parse(cr.AccountID)
+		if err != nil {
+			zapctx.L(ctx).Error("create_reconciliation_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid account_id")
+		}
+
+		var amountTolerance float64
+		if cr.AmountTolerance != "" {
+			amountTolerance, err = strconv.ParseFloat(cr.AmountTolerance, 64)
+			if err != nil {
+				zapctx.L(ctx).Error("create_reconciliation_handler_bind_error", zap.Error(err))
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid amount_tolerance")
+			}
+		}
+
+		rc, err := svc.Reconcile(
+			ctx,
+			reconciliations.Request{
+				AccountID: accountID,
+				Format:    reconciliations.Format(cr.Format),
+				FileName:  cr.FileName,
+				Tolerance: reconciliations.Tolerance{Amount: amountTolerance, Days: cr.DateToleranceDays},
+			},
+			c.Request().Body,
+		)
+		if err != nil {
+			zapctx.L(ctx).Error("create_reconciliation_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		return c.JSON(http.StatusCreated, newReconciliation(rc))
+	}
+}
@@ -0,0 +1,94 @@
+package reconciliationsh
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	GetReconciliationFunc echo.HandlerFunc
+
+	getReconciliation struct {
+		ID string `param:"id"`
+	}
+
+	reportedReconciliation struct {
+		reconciliation
+		// UnmatchedExternalLines are the lines of the file without a transaction in the ledger.
+		UnmatchedExternalLines []externalLine `json:"unmatched_external_lines"`
+		// UnmatchedLedgerTransactions are the transactions of the ledger missing in the file.
+		UnmatchedLedgerTransactions []ledgerTransaction `json:"unmatched_ledger_transactions"`
+	}
+)
+
+// NewGetReconciliationFunc reports the reconciliation with the unmatched items of both sides.
+func NewGetReconciliationFunc(svc reconciliations.Service) GetReconciliationFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var gr getReconciliation
+		if err := c.Bind(&gr); err != nil {
+			zapctx.L(ctx).Error("get_reconciliation_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(gr.ID)
+		if err != nil {
+			zapctx.L(ctx).Error("get_reconciliation_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		rc, err := svc.GetByID(ctx, id)
+		if err != nil {
+			zapctx.L(ctx).Error("get_reconciliation_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		report := reportedReconciliation{
+			reconciliation:              newReconciliation(rc),
+			UnmatchedExternalLines:      []externalLine{},
+			UnmatchedLedgerTransactions: []ledgerTransaction{},
+		}
+
+		if rc.UnmatchedExternal > 0 {
+			items, err := svc.ListItems(ctx, id, reconciliations.UnmatchedExternalStatus)
+			if err != nil {
+				zapctx.L(ctx).Error("get_reconciliation_handler_service_error", zap.Error(err))
+				return err
+			}
+
+			for _, item := range items {
+				report.UnmatchedExternalLines = append(report.UnmatchedExternalLines, externalLine{
+					LineNumber:  item.External.LineNumber,
+					Date:        item.External.Date,
+					Amount:      item.External.Amount,
+					Reference:   item.External.Reference,
+					Description: item.External.Description,
+				})
+			}
+		}
+
+		if rc.UnmatchedLedger > 0 {
+			items, err := svc.ListItems(ctx, id, reconciliations.UnmatchedLedgerStatus)
+			if err != nil {
+				zapctx.L(ctx).Error("get_reconciliation_handler_service_error", zap.Error(err))
+				return err
+			}
+
+			for _, item := range items {
+				report.UnmatchedLedgerTransactions = append(report.UnmatchedLedgerTransactions, ledgerTransaction{
+					TransactionID: item.TransactionID.UUID.String(),
+					Amount:        item.LedgerAmount,
+					CreatedAt:     item.LedgerDate,
+				})
+			}
+		}
+
+		return c.JSON(http.StatusOK, report)
+	}
+}
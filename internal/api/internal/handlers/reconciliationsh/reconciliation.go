@@ -0,0 +1,56 @@
+package reconciliationsh
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/stringers"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+)
+
+type (
+	reconciliation struct {
+		ID                string    `json:"id"`
+		AccountID         string    `json:"account_id"`
+		Format            string    `json:"format"`
+		FileName          string    `json:"file_name"`
+		PeriodBegin       time.Time `json:"period_begin"`
+		PeriodEnd         time.Time `json:"period_end"`
+		AmountTolerance   float64   `json:"amount_tolerance"`
+		DateToleranceDays int       `json:"date_tolerance_days"`
+		Matched           int       `json:"matched"`
+		UnmatchedExternal int       `json:"unmatched_external"`
+		UnmatchedLedger   int       `json:"unmatched_ledger"`
+		CreatedAt         time.Time `json:"created_at"`
+	}
+
+	externalLine struct {
+		LineNumber  int       `json:"line_number"`
+		Date        time.Time `json:"date"`
+		Amount      float64   `json:"amount"`
+		Reference   string    `json:"reference,omitempty"`
+		Description string    `json:"description,omitempty"`
+	}
+
+	ledgerTransaction struct {
+		TransactionID string    `json:"transaction_id"`
+		Amount        float64   `json:"amount"`
+		CreatedAt     time.Time `json:"created_at"`
+	}
+)
+
+func newReconciliation(rc reconciliations.Reconciliation) reconciliation {
+	return reconciliation{
+		ID:                stringers.UUIDEmpty(rc.ID),
+		AccountID:         stringers.UUIDEmpty(rc.AccountID),
+		Format:            string(rc.Format),
+		FileName:          rc.FileName,
+		PeriodBegin:       rc.PeriodBegin,
+		PeriodEnd:         rc.PeriodEnd,
+		AmountTolerance:   rc.Tolerance.Amount,
+		DateToleranceDays: rc.Tolerance.Days,
+		Matched:           rc.Matched,
+		UnmatchedExternal: rc.UnmatchedExternal,
+		UnmatchedLedger:   rc.UnmatchedLedger,
+		CreatedAt:         rc.CreatedAt,
+	}
+}
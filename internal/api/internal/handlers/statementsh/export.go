@@ -0,0 +1,150 @@
+package statementsh
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	ExportAccountStatementFunc echo.HandlerFunc
+
+	exportAccountStatement struct {
+		AccountID      string `param:"id"`
+		Format         string `query:"format"`
+		CreatedAtBegin string `query:"created_at_begin"`
+		CreatedAtEnd   string `query:"created_at_end"`
+	}
+)
+
+var exportFormats = []statements.ExportFormat{
+	statements.CSVExportFormat,
+	statements.OFXExportFormat,
+	statements.PDFExportFormat,
+}
+
+func NewExportAccountStatementFunc(svc statements.Service) ExportAccountStatementFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var esa exportAccountStatement
+		if err := c.Bind(&esa); err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(esa.AccountID)
+		if err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		format, ok := negotiateExportFormat(esa.Format, c.Request().Header.Get(echo.HeaderAccept))
+		if !ok {
+			zapctx.L(ctx).Error(
+				"export_account_statement_handler_format_error",
+				zap.String("format", esa.Format),
+				zap.String("accept", c.Request().Header.Get(echo.HeaderAccept)),
+			)
+			return statements.ErrUnsupportedExportFormat
+		}
+
+		createdAtBegin, err := parseTime(esa.CreatedAtBegin)
+		if err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at_begin")
+		}
+
+		createdAtEnd, err := parseTime(esa.CreatedAtEnd)
+		if err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at_end")
+		}
+
+		// The exporters write straight to the response, so the status is only committed after the service checked
+		// the account and the period. Errors before that still become a proper HTTP error.
+		exporter, err := statements.NewExporter(format, c.Response())
+		if err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_exporter_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusNotAcceptable, err.Error())
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, format.ContentType())
+		c.Response().Header().Set(
+			echo.HeaderContentDisposition,
+			fmt.Sprintf(
+				`attachment; filename="statement-%s-%s-%s.%s"`,
+				id.String(),
+				createdAtBegin.Format("2006-01-02"),
+				createdAtEnd.Format("2006-01-02"),
+				format,
+			),
+		)
+
+		err = svc.Export(
+			ctx,
+			statements.ExportFilter{
+				AccountID:      id,
+				CreatedAtBegin: createdAtBegin,
+				CreatedAtEnd:   createdAtEnd,
+			},
+			exporter,
+		)
+		if err != nil {
+			zapctx.L(ctx).Error("export_account_statement_handler_service_error", zap.Error(err))
+			if c.Response().Committed {
+				// Part of the document was already sent, the only thing left to do is to stop writing it.
+				return nil
+			}
+
+			c.Response().Header().Del(echo.HeaderContentType)
+			c.Response().Header().Del(echo.HeaderContentDisposition)
+			return err
+		}
+
+		return nil
+	}
+}
+
+// negotiateExportFormat picks the format of the export, giving precedence to the format parameter over the
+// Accept header. Without both of them the statement is exported as CSV.
+func negotiateExportFormat(format, accept string) (statements.ExportFormat, bool) {
+	if format != "" {
+		for _, f := range exportFormats {
+			if strings.EqualFold(format, string(f)) {
+				return f, true
+			}
+		}
+		return "", false
+	}
+
+	if accept == "" {
+		return statements.CSVExportFormat, true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		if mt == "*/*" || mt == "text/*" {
+			return statements.CSVExportFormat, true
+		}
+
+		for _, f := range exportFormats {
+			if mt == f.ContentType() {
+				return f, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -2,9 +2,12 @@ package statementsh
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/paging"
 	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -15,12 +18,20 @@ type (
 	ListAccountStatementFunc echo.HandlerFunc
 
 	listAccountStatement struct {
-		AccountID      string `param:"id"`
-		Sort           int    `query:"sort"`
-		Page           int    `query:"page"`
-		Size           int    `query:"size"`
-		CreatedAtBegin string `query:"created_at_begin"`
-		CreatedAtEnd   string `query:"created_at_end"`
+		AccountID      string   `param:"id"`
+		Sort           int      `query:"sort"`
+		Page           int      `query:"page"`
+		Size           int      `query:"size"`
+		CreatedAtBegin string   `query:"created_at_begin"`
+		CreatedAtEnd   string   `query:"created_at_end"`
+		Types          []string `query:"type"`
+		Direction      string   `query:"direction"`
+		MinAmount      string   `query:"min_amount"`
+		MaxAmount      string   `query:"max_amount"`
+		Counterparty   string   `query:"counterparty_account_id"`
+		Description    string   `query:"description"`
+		Cursor         string   `query:"cursor"`
+		WithTotal      string   `query:"with_total"`
 	}
 
 	account struct {
@@ -33,21 +44,33 @@ type (
 		ToAccount   *account  `json:"to_account,omitempty"`
 		Type        string    `json:"type"`
 		Amount      float64   `json:"amount"`
+		Balance     float64   `json:"balance"`
+		Adjustment  bool      `json:"adjustment,omitempty"`
 		CreatedAt   time.Time `json:"created_at"`
 	}
 
+	summary struct {
+		OpeningBalance float64 `json:"opening_balance"`
+		ClosingBalance float64 `json:"closing_balance"`
+		TotalCredits   float64 `json:"total_credits"`
+		TotalDebits    float64 `json:"total_debits"`
+	}
+
 	pagination struct {
-		Sort        int `json:"sort"`
-		Page        int `json:"page"`
-		Size        int `json:"size"`
-		TotalItems  int `json:"total_items"`
-		TotalPages  int `json:"total_pages"`
-		TotalInPage int `json:"total_in_page"`
+		Sort        int    `json:"sort"`
+		Page        int    `json:"page"`
+		Size        int    `json:"size"`
+		TotalItems  *int   `json:"total_items,omitempty"`
+		TotalPages  *int   `json:"total_pages,omitempty"`
+		TotalInPage int    `json:"total_in_page"`
+		NextCursor  string `json:"next_cursor,omitempty"`
+		PrevCursor  string `json:"prev_cursor,omitempty"`
 	}
 
 	listedAccountStatement struct {
 		Pagination pagination  `json:"pagination"`
 		AccountID  uuid.UUID   `json:"account_id"`
+		Summary    summary     `json:"summary"`
 		Statements []statement `json:"statements"`
 	}
 )
@@ -76,9 +99,15 @@ func NewListAccountStatementFunc(svc statements.Service) ListAccountStatementFun
 			lsa.Size = 20
 		}
 
+		crs, withTotal, err := paging.Parse(lsa.Cursor, lsa.WithTotal)
+		if err != nil {
+			zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
 		var createdAtBegin, createdAtEnd time.Time
 		if lsa.CreatedAtBegin != "" {
-			createdAtBegin, err = time.Parse("2006-01-02", lsa.CreatedAtBegin)
+			createdAtBegin, err = parseTime(lsa.CreatedAtBegin)
 			if err != nil {
 				zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
 				return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at_begin")
@@ -86,37 +115,82 @@ func NewListAccountStatementFunc(svc statements.Service) ListAccountStatementFun
 		}
 
 		if lsa.CreatedAtEnd != "" {
-			createdAtEnd, err = time.Parse("2006-01-02", lsa.CreatedAtEnd)
+			createdAtEnd, err = parseTime(lsa.CreatedAtEnd)
 			if err != nil {
 				zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
 				return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at_end")
 			}
 		}
 
-		total, stats, err := svc.List(ctx, statements.ListFilter{
-			Sort:           lsa.Sort,
-			Page:           lsa.Page,
-			Size:           lsa.Size,
-			AccountID:      id,
-			CreatedAtBegin: createdAtBegin,
-			CreatedAtEnd:   createdAtEnd,
-		})
+		types, err := parseTypes(lsa.Types)
+		if err != nil {
+			zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid type")
+		}
+
+		minAmount, err := parseAmount(lsa.MinAmount)
+		if err != nil {
+			zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid min_amount")
+		}
+
+		maxAmount, err := parseAmount(lsa.MaxAmount)
+		if err != nil {
+			zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid max_amount")
+		}
+
+		var counterpartyID uuid.NullUUID
+		if lsa.Counterparty != "" {
+			counterpartyID.UUID, err = uuid.Parse(lsa.Counterparty)
+			if err != nil {
+				zapctx.L(ctx).Error("list_account_handler_bind_error", zap.Error(err))
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid counterparty_account_id")
+			}
+			counterpartyID.Valid = true
+		}
+
+		filter := statements.ListFilter{
+			Sort:                  lsa.Sort,
+			Page:                  lsa.Page,
+			Size:                  lsa.Size,
+			AccountID:             id,
+			CreatedAtBegin:        createdAtBegin,
+			CreatedAtEnd:          createdAtEnd,
+			Types:                 types,
+			Direction:             statements.Direction(strings.ToLower(lsa.Direction)),
+			MinAmount:             minAmount,
+			MaxAmount:             maxAmount,
+			CounterpartyAccountID: counterpartyID,
+			Description:           lsa.Description,
+			Cursor:                crs,
+			WithTotal:             withTotal,
+		}
+
+		total, stats, err := svc.List(ctx, filter)
 		if err != nil {
 			zapctx.L(ctx).Error("list_account_handler_service_error", zap.Error(err))
 			return err
 		}
 
-		totalPages := total / lsa.Size
-		if (total % lsa.Size) != 0 {
-			totalPages++
+		smr, err := svc.Summarize(ctx, filter)
+		if err != nil {
+			zapctx.L(ctx).Error("list_account_handler_service_error", zap.Error(err))
+			return err
 		}
 
+		totalItems, totalPages := paging.Totals(withTotal, total, lsa.Size)
+
 		accountStatements := make([]statement, len(stats))
+		keys := make([]cursor.Key, len(stats))
 		for i, transaction := range stats {
+			keys[i] = cursor.Key{CreatedAt: transaction.CreatedAt, ID: transaction.ID.String()}
 			accountStatements[i] = statement{
-				Type:      transaction.Type,
-				Amount:    transaction.Amount,
-				CreatedAt: transaction.CreatedAt,
+				Type:       transaction.Type,
+				Amount:     transaction.Amount,
+				Balance:    transaction.Balance,
+				Adjustment: transaction.Adjustment,
+				CreatedAt:  transaction.CreatedAt,
 			}
 			if transaction.FromAccount.ID != uuid.Nil {
 				accountStatements[i].FromAccount = &account{
@@ -132,16 +206,26 @@ func NewListAccountStatementFunc(svc statements.Service) ListAccountStatementFun
 			}
 		}
 
+		links := cursor.NewLinks(crs, lsa.Page > 1, lsa.Size, keys)
+
 		listed := listedAccountStatement{
 			Pagination: pagination{
 				Sort:        lsa.Sort,
 				Page:        lsa.Page,
 				Size:        lsa.Size,
-				TotalItems:  total,
+				TotalItems:  totalItems,
 				TotalPages:  totalPages,
 				TotalInPage: len(accountStatements),
+				NextCursor:  links.Next,
+				PrevCursor:  links.Prev,
+			},
+			AccountID: id,
+			Summary: summary{
+				OpeningBalance: smr.OpeningBalance,
+				ClosingBalance: smr.ClosingBalance,
+				TotalCredits:   smr.TotalCredits,
+				TotalDebits:    smr.TotalDebits,
 			},
-			AccountID:  id,
 			Statements: accountStatements,
 		}
 
@@ -0,0 +1,166 @@
+package statementsh
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/monthlystatements"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type (
+	ListMonthlyStatementsFunc echo.HandlerFunc
+	GetMonthlyStatementFunc   echo.HandlerFunc
+
+	listMonthlyStatements struct {
+		AccountID string `param:"id"`
+	}
+
+	getMonthlyStatement struct {
+		AccountID string `param:"id"`
+		Month     string `param:"month"`
+		Format    string `query:"format"`
+	}
+
+	monthlyStatement struct {
+		Month        string    `json:"month"`
+		ContentHash  string    `json:"content_hash"`
+		DocumentHash string    `json:"document_hash"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	listedMonthlyStatements struct {
+		AccountID         uuid.UUID          `json:"account_id"`
+		MonthlyStatements []monthlyStatement `json:"monthly_statements"`
+	}
+)
+
+func NewListMonthlyStatementsFunc(svc monthlystatements.Service) ListMonthlyStatementsFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var lms listMonthlyStatements
+		if err := c.Bind(&lms); err != nil {
+			zapctx.L(ctx).Error("list_monthly_statements_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(lms.AccountID)
+		if err != nil {
+			zapctx.L(ctx).Error("list_monthly_statements_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		monthlies, err := svc.List(ctx, id)
+		if err != nil {
+			zapctx.L(ctx).Error("list_monthly_statements_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		listed := listedMonthlyStatements{
+			AccountID:         id,
+			MonthlyStatements: make([]monthlyStatement, len(monthlies)),
+		}
+		for i, monthly := range monthlies {
+			listed.MonthlyStatements[i] = monthlyStatement{
+				Month:        monthlystatements.FormatMonth(monthly.Month),
+				ContentHash:  monthly.ContentHash,
+				DocumentHash: monthly.DocumentHash,
+				CreatedAt:    monthly.CreatedAt,
+			}
+		}
+
+		return c.JSON(http.StatusOK, listed)
+	}
+}
+
+// NewGetMonthlyStatementFunc returns the stored snapshot of the monthly statement as JSON or its PDF document,
+// chosen by the format parameter (json, pdf) or the Accept header. The ETag is the hash of the returned bytes.
+func NewGetMonthlyStatementFunc(svc monthlystatements.Service) GetMonthlyStatementFunc {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var gms getMonthlyStatement
+		if err := c.Bind(&gms); err != nil {
+			zapctx.L(ctx).Error("get_monthly_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		id, err := uuid.Parse(gms.AccountID)
+		if err != nil {
+			zapctx.L(ctx).Error("get_monthly_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid id")
+		}
+
+		month, err := monthlystatements.ParseMonth(gms.Month)
+		if err != nil {
+			zapctx.L(ctx).Error("get_monthly_statement_handler_bind_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+
+		asPDF, ok := negotiateMonthlyFormat(gms.Format, c.Request().Header.Get(echo.HeaderAccept))
+		if !ok {
+			return statements.ErrUnsupportedExportFormat
+		}
+
+		monthly, err := svc.GetByMonth(ctx, id, month)
+		if err != nil {
+			zapctx.L(ctx).Error("get_monthly_statement_handler_service_error", zap.Error(err))
+			return err
+		}
+
+		if asPDF {
+			c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(
+				`attachment; filename="statement-%s-%s.pdf"`,
+				id.String(),
+				monthlystatements.FormatMonth(monthly.Month),
+			))
+			c.Response().Header().Set("ETag", fmt.Sprintf(`"%s"`, monthly.DocumentHash))
+			return c.Blob(http.StatusOK, statements.PDFExportFormat.ContentType(), monthly.Document)
+		}
+
+		c.Response().Header().Set("ETag", fmt.Sprintf(`"%s"`, monthly.ContentHash))
+		return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, monthly.Content)
+	}
+}
+
+// negotiateMonthlyFormat tells if the monthly statement must be returned as PDF instead of JSON, giving precedence
+// to the format parameter over the Accept header.
+func negotiateMonthlyFormat(format, accept string) (bool, bool) {
+	switch strings.ToLower(format) {
+	case "json":
+		return false, true
+	case string(statements.PDFExportFormat):
+		return true, true
+	case "":
+	default:
+		return false, false
+	}
+
+	if accept == "" {
+		return false, true
+	}
+
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mt, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+
+		switch mt {
+		case "*/*", "application/*", echo.MIMEApplicationJSON:
+			return false, true
+		case statements.PDFExportFormat.ContentType():
+			return true, true
+		}
+	}
+
+	return false, false
+}
@@ -0,0 +1,51 @@
+package statementsh
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+)
+
+var errInvalidType = errors.New("invalid transaction type")
+
+// parseTime accepts RFC3339 timestamps, with their time zone, and plain dates (2006-01-02), taken as midnight UTC.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseTypes accepts the types both as repeated parameters (type=CREDIT&type=DEBIT) and as a comma separated list
+// (type=CREDIT,DEBIT), case-insensitive.
+func parseTypes(values []string) ([]string, error) {
+	var types []string
+	for _, value := range values {
+		for _, tp := range strings.Split(value, ",") {
+			tp = strings.ToUpper(strings.TrimSpace(tp))
+			switch transactions.TransactionType(tp) {
+			case transactions.CreditTransaction, transactions.DebitTransaction, transactions.P2PTransaction:
+				types = append(types, tp)
+			default:
+				return nil, errInvalidType
+			}
+		}
+	}
+	return types, nil
+}
+
+func parseAmount(value string) (sql.NullFloat64, error) {
+	if value == "" {
+		return sql.NullFloat64{}, nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return sql.NullFloat64{}, err
+	}
+	return sql.NullFloat64{Float64: amount, Valid: true}, nil
+}
@@ -1,7 +1,6 @@
 package transactionsh
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/stringers"
@@ -19,6 +18,8 @@ type (
 		To          string  `json:"to_account_id"`
 		Amount      float64 `json:"amount"`
 		Description string  `json:"description"`
+		Adjustment  bool    `json:"adjustment"`
+		CreatedAt   string  `json:"created_at"`
 	}
 )
 
@@ -42,17 +43,22 @@ func NewCreateCreditTransactionFunc(svc transactions.Service) CreateCreditTransa
 			}
 		}
 
+		createdAt, err := parseCreatedAt(trx.CreatedAt)
+		if err != nil {
+			zapctx.L(ctx).Error("create_credit_transaction_handler_parse_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at")
+		}
+
 		transaction, err := svc.CreateCredit(ctx, transactions.Transaction{
 			To:          toID,
 			Amount:      trx.Amount,
 			Description: trx.Description,
+			Adjustment:  trx.Adjustment,
+			CreatedAt:   createdAt,
 		})
 		if err != nil {
 			zapctx.L(ctx).Error("create_credit_transaction_handler_service_error", zap.Error(err))
-			if errors.Is(err, transactions.ErrAccountNotfound) {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-			}
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
@@ -63,6 +69,8 @@ func NewCreateCreditTransactionFunc(svc transactions.Service) CreateCreditTransa
 				Type:        string(transaction.Type),
 				Amount:      transaction.Amount,
 				Description: transaction.Description,
+				Adjustment:  transaction.Adjustment,
+				CreatedAt:   transaction.CreatedAt,
 			},
 		)
 	}
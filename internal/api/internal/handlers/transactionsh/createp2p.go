@@ -1,7 +1,6 @@
 package transactionsh
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/dalmarcogd/ledger-exp/internal/api/internal/handlers/stringers"
@@ -20,6 +19,8 @@ type (
 		To          string  `json:"to_account_id"`
 		Amount      float64 `json:"amount"`
 		Description string  `json:"description"`
+		Adjustment  bool    `json:"adjustment"`
+		CreatedAt   string  `json:"created_at"`
 	}
 )
 
@@ -52,23 +53,23 @@ func NewCreateP2PTransactionFunc(svc transactions.Service) CreateP2PTransactionF
 			}
 		}
 
+		createdAt, err := parseCreatedAt(trx.CreatedAt)
+		if err != nil {
+			zapctx.L(ctx).Error("create_p2p_transaction_handler_parse_error", zap.Error(err))
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "invalid created_at")
+		}
+
 		transaction, err := svc.CreateP2P(ctx, transactions.Transaction{
 			From:        fromID,
 			To:          toID,
 			Amount:      trx.Amount,
 			Description: trx.Description,
+			Adjustment:  trx.Adjustment,
+			CreatedAt:   createdAt,
 		})
 		if err != nil {
 			zapctx.L(ctx).Error("create_p2p_transaction_handler_service_error", zap.Error(err))
-			if errors.Is(err, transactions.ErrBalanceInsufficientFunds) {
-				return echo.NewHTTPError(http.StatusPreconditionFailed, err.Error())
-			} else if errors.Is(err, transactions.ErrAccountNotfound) {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-			} else if errors.Is(err, transactions.ErrFromAccountToAccountShouldBeDifferent) {
-				return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
-			}
-
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
@@ -80,6 +81,8 @@ func NewCreateP2PTransactionFunc(svc transactions.Service) CreateP2PTransactionF
 				Type:        string(transaction.Type),
 				Amount:      transaction.Amount,
 				Description: transaction.Description,
+				Adjustment:  transaction.Adjustment,
+				CreatedAt:   transaction.CreatedAt,
 			},
 		)
 	}
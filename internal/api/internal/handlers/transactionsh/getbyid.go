@@ -38,7 +38,7 @@ func NewGetByIDTransactionFunc(svc transactions.Service) GetByIDTransactionFunc
 		transaction, err := svc.GetByID(ctx, id)
 		if err != nil {
 			zapctx.L(ctx).Error("get_by_id_transactions_handler_service_error", zap.Error(err))
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return err
 		}
 
 		return c.JSON(
@@ -50,6 +50,8 @@ func NewGetByIDTransactionFunc(svc transactions.Service) GetByIDTransactionFunc
 				Type:        string(transaction.Type),
 				Amount:      transaction.Amount,
 				Description: transaction.Description,
+				Adjustment:  transaction.Adjustment,
+				CreatedAt:   transaction.CreatedAt,
 			},
 		)
 	}
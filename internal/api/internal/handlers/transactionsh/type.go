@@ -1,10 +1,24 @@
 package transactionsh
 
+import (
+	"time"
+)
+
 type createdTransaction struct {
-	ID          string  `json:"id"`
-	From        string  `json:"from_account_id,omitempty"`
-	To          string  `json:"to_account_id,omitempty"`
-	Type        string  `json:"type"`
-	Amount      float64 `json:"amount"`
-	Description string  `json:"description"`
+	ID          string    `json:"id"`
+	From        string    `json:"from_account_id,omitempty"`
+	To          string    `json:"to_account_id,omitempty"`
+	Type        string    `json:"type"`
+	Amount      float64   `json:"amount"`
+	Description string    `json:"description"`
+	Adjustment  bool      `json:"adjustment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// parseCreatedAt parses the optional RFC3339 date of a backdated transaction.
+func parseCreatedAt(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
 }
@@ -0,0 +1,78 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// keyPrefix marks the API keys of the ledger, it makes them easy to find by secret scanners.
+	keyPrefix = "lk_"
+	// keyBytes is the entropy of the keys, high enough for a fast hash to be safe.
+	keyBytes = 32
+	// prefixLength is the length of the start of the key stored to identify it.
+	prefixLength = len(keyPrefix) + 8
+)
+
+type APIKey struct {
+	ID   uuid.UUID
+	Name string
+	// Prefix is the start of the key, it identifies the key without revealing it.
+	Prefix string
+	// Scopes are the operations allowed to the key, see the authz package.
+	Scopes []string
+	// HolderID restricts the key to the holder and its accounts when valid.
+	HolderID uuid.NullUUID
+	// TenantID restricts the key to the tenant when not empty, otherwise it picks the tenant of each request.
+	TenantID  string
+	CreatedAt time.Time
+	RotatedAt time.Time
+	RevokedAt time.Time
+}
+
+// Revoked tells if the key can no longer authenticate.
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// IssuedAPIKey is a created or rotated API key with its secret key, the only time the key is known.
+type IssuedAPIKey struct {
+	APIKey
+	Key string
+}
+
+func newAPIKey(model APIKeyModel) APIKey {
+	return APIKey{
+		ID:        model.ID,
+		Name:      model.Name,
+		Prefix:    model.Prefix,
+		Scopes:    model.Scopes,
+		HolderID:  model.HolderID,
+		TenantID:  model.TenantID,
+		CreatedAt: model.CreatedAt,
+		RotatedAt: model.RotatedAt,
+		RevokedAt: model.RevokedAt,
+	}
+}
+
+// generateKey returns a new random key and its prefix.
+func generateKey() (string, string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	key := keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:prefixLength], nil
+}
+
+// hashKey is the hash stored of the key, the keys are random so a plain SHA-256 is enough to protect them.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
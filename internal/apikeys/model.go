@@ -0,0 +1,31 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type APIKeyModel struct {
+	bun.BaseModel `bun:"table:api_keys"`
+
+	ID        uuid.UUID     `bun:"id,pk"`
+	Name      string        `bun:"name"`
+	Prefix    string        `bun:"prefix"`
+	KeyHash   string        `bun:"key_hash"`
+	Scopes    []string      `bun:"scopes,array"`
+	HolderID  uuid.NullUUID `bun:"holder_id"`
+	TenantID  string        `bun:"tenant_id,nullzero"`
+	CreatedAt time.Time     `bun:"created_at,notnull"`
+	UpdatedAt time.Time     `bun:"updated_at,nullzero"`
+	RotatedAt time.Time     `bun:"rotated_at,nullzero"`
+	RevokedAt time.Time     `bun:"revoked_at,nullzero"`
+}
+
+type APIKeyFilter struct {
+	ID      uuid.NullUUID
+	KeyHash string
+	// TenantID keeps only the keys of the tenant, the keys of all tenants when empty.
+	TenantID string
+}
@@ -0,0 +1,111 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/google/uuid"
+)
+
+// queryTimeout is the default deadline of the queries of the API keys, read by every authenticated request.
+const queryTimeout = 2 * time.Second
+
+type Repository interface {
+	Create(ctx context.Context, model APIKeyModel) (APIKeyModel, error)
+	Update(ctx context.Context, model APIKeyModel) (APIKeyModel, error)
+	GetByFilter(ctx context.Context, filter APIKeyFilter) ([]APIKeyModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+func (r repository) Create(ctx context.Context, model APIKeyModel) (APIKeyModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	model.ID = uuid.New()
+	model.CreatedAt = time.Now().UTC()
+
+	_, err := r.db.Master(ctx).
+		NewInsert().
+		Model(&model).
+		Returning("*").
+		Exec(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return APIKeyModel{}, err
+	}
+
+	return model, nil
+}
+
+func (r repository) Update(ctx context.Context, model APIKeyModel) (APIKeyModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	model.UpdatedAt = time.Now().UTC()
+
+	_, err := r.db.Master(ctx).
+		NewUpdate().
+		Model(&model).
+		WherePK().
+		Returning("*").
+		OmitZero().
+		Exec(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return APIKeyModel{}, err
+	}
+
+	return model, nil
+}
+
+// GetByFilter reads from the master, the rotations and revocations must take effect at once.
+func (r repository) GetByFilter(ctx context.Context, filter APIKeyFilter) ([]APIKeyModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	selectQuery := r.db.Master(ctx).NewSelect().Model(&APIKeyModel{})
+	if filter.ID.Valid {
+		selectQuery.Where("id = ?", filter.ID.UUID)
+	}
+
+	if filter.KeyHash != "" {
+		selectQuery.Where("key_hash = ?", filter.KeyHash)
+	}
+
+	if filter.TenantID != "" {
+		selectQuery.Where("tenant_id = ?", filter.TenantID)
+	}
+
+	var models []APIKeyModel
+	if err := selectQuery.Scan(ctx, &models); err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
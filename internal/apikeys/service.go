@@ -0,0 +1,254 @@
+package apikeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("no api key found with this id")
+	ErrAPIKeyRevoked  = errors.New("api key is revoked")
+	ErrUnknownScope   = errors.New("unknown scope")
+)
+
+type Service interface {
+	// Create issues a new API key with the name, scopes, holder and tenant of apiKey, the key is only returned here.
+	// The principal can only grant the scopes it has, and the keys created in a tenant are bound to it. The keys
+	// created by a principal bound to a holder are bound to its holder, and to no other.
+	Create(ctx context.Context, apiKey APIKey) (IssuedAPIKey, error)
+	// Rotate replaces the key of the API key, the previous one stops authenticating at once. The principals bound to
+	// a tenant only rotate the keys of the tenant, and the principals only rotate the keys they could create.
+	Rotate(ctx context.Context, id uuid.UUID) (IssuedAPIKey, error)
+	// Revoke stops the API key from authenticating, revoking a revoked key does nothing. The principals bound to a
+	// tenant only revoke the keys of the tenant, and the principals only revoke the keys they could create.
+	Revoke(ctx context.Context, id uuid.UUID) (APIKey, error)
+	// Authenticate returns the principal of the key, auth.ErrInvalidCredentials when it is unknown or revoked.
+	Authenticate(ctx context.Context, key string) (auth.Principal, error)
+}
+
+type service struct {
+	tracer     tracer.Tracer
+	repository Repository
+}
+
+func NewService(t tracer.Tracer, r Repository) Service {
+	return service{tracer: t, repository: r}
+}
+
+func (s service) Create(ctx context.Context, apiKey APIKey) (IssuedAPIKey, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	scopes := make([]authz.Scope, len(apiKey.Scopes))
+	for i, scope := range apiKey.Scopes {
+		if !knownScope(authz.Scope(scope)) {
+			span.RecordError(ErrUnknownScope)
+			return IssuedAPIKey{}, fmt.Errorf("%w: %s", ErrUnknownScope, scope)
+		}
+		scopes[i] = authz.Scope(scope)
+	}
+
+	if err := authz.Authorize(ctx, scopes...); err != nil {
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	if holderID, bound := authz.Holder(ctx); bound && !apiKey.HolderID.Valid {
+		apiKey.HolderID = uuid.NullUUID{UUID: holderID, Valid: true}
+	}
+	if err := authz.AuthorizeHolder(ctx, apiKey.HolderID.UUID); err != nil {
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	if tenant, ok := tenancy.FromContext(ctx); ok {
+		apiKey.TenantID = tenant.ID
+	}
+
+	key, prefix, err := generateKey()
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_create_generate_error", zap.Error(err))
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	model, err := s.repository.Create(ctx, APIKeyModel{
+		Name:     apiKey.Name,
+		Prefix:   prefix,
+		KeyHash:  hashKey(key),
+		Scopes:   apiKey.Scopes,
+		HolderID: apiKey.HolderID,
+		TenantID: apiKey.TenantID,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_create_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	zapctx.L(ctx).Info("apikey_service_created", zap.String("apikey_id", model.ID.String()))
+
+	return IssuedAPIKey{APIKey: newAPIKey(model), Key: key}, nil
+}
+
+func (s service) Rotate(ctx context.Context, id uuid.UUID) (IssuedAPIKey, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	model, err := s.getByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	if err := authorizeKey(ctx, model); err != nil {
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	if !model.RevokedAt.IsZero() {
+		return IssuedAPIKey{}, ErrAPIKeyRevoked
+	}
+
+	key, prefix, err := generateKey()
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_rotate_generate_error", zap.Error(err))
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	model.Prefix = prefix
+	model.KeyHash = hashKey(key)
+	model.RotatedAt = time.Now().UTC()
+
+	model, err = s.repository.Update(ctx, model)
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_rotate_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return IssuedAPIKey{}, err
+	}
+
+	zapctx.L(ctx).Info("apikey_service_rotated", zap.String("apikey_id", model.ID.String()))
+
+	return IssuedAPIKey{APIKey: newAPIKey(model), Key: key}, nil
+}
+
+func (s service) Revoke(ctx context.Context, id uuid.UUID) (APIKey, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	model, err := s.getByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return APIKey{}, err
+	}
+
+	if err := authorizeKey(ctx, model); err != nil {
+		span.RecordError(err)
+		return APIKey{}, err
+	}
+
+	if !model.RevokedAt.IsZero() {
+		return newAPIKey(model), nil
+	}
+
+	model.RevokedAt = time.Now().UTC()
+
+	model, err = s.repository.Update(ctx, model)
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_revoke_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return APIKey{}, err
+	}
+
+	zapctx.L(ctx).Info("apikey_service_revoked", zap.String("apikey_id", model.ID.String()))
+
+	return newAPIKey(model), nil
+}
+
+func (s service) Authenticate(ctx context.Context, key string) (auth.Principal, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	models, err := s.repository.GetByFilter(ctx, APIKeyFilter{KeyHash: hashKey(key)})
+	if err != nil {
+		zapctx.L(ctx).Error("apikey_service_authenticate_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return auth.Principal{}, err
+	}
+
+	if len(models) != 1 || !models[0].RevokedAt.IsZero() {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+
+	return auth.Principal{
+		ID:       models[0].ID.String(),
+		Type:     auth.APIKeyPrincipal,
+		Name:     models[0].Name,
+		Scopes:   models[0].Scopes,
+		HolderID: models[0].HolderID,
+		TenantID: models[0].TenantID,
+	}, nil
+}
+
+func (s service) getByID(ctx context.Context, id uuid.UUID) (APIKeyModel, error) {
+	filter := APIKeyFilter{ID: uuid.NullUUID{UUID: id, Valid: true}}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		filter.TenantID = principal.TenantID
+	}
+
+	models, err := s.repository.GetByFilter(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"apikey_service_get_repository_error",
+			zap.String("id", id.String()),
+			zap.Error(err),
+		)
+		return APIKeyModel{}, err
+	}
+
+	if len(models) == 0 {
+		return APIKeyModel{}, ErrAPIKeyNotFound
+	}
+
+	return models[0], nil
+}
+
+// authorizeKey returns authz.ErrForbidden when the principal of ctx could not create the key: when it lacks one of the
+// scopes of the key, or it is bound to a holder and the key is not bound to the same holder. Otherwise rotating a key
+// would hand the principal the secret of a key with more privileges.
+func authorizeKey(ctx context.Context, model APIKeyModel) error {
+	scopes := make([]authz.Scope, len(model.Scopes))
+	for i, scope := range model.Scopes {
+		scopes[i] = authz.Scope(scope)
+	}
+	if err := authz.Authorize(ctx, scopes...); err != nil {
+		return err
+	}
+
+	// The keys not bound to a holder are only managed by the principals not bound to a holder either.
+	var holderIDs []uuid.UUID
+	if model.HolderID.Valid {
+		holderIDs = append(holderIDs, model.HolderID.UUID)
+	}
+	return authz.AuthorizeHolder(ctx, holderIDs...)
+}
+
+func knownScope(scope authz.Scope) bool {
+	for _, s := range authz.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+//go:build unit
+
+package apikeys
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	var stored APIKeyModel
+	repoMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, model APIKeyModel) (APIKeyModel, error) {
+			model.ID = uuid.New()
+			model.CreatedAt = time.Now()
+			stored = model
+			return model, nil
+		})
+
+	issued, err := svc.Create(ctx, APIKey{Name: "backoffice", Scopes: []string{"accounts:read"}})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(issued.Key, keyPrefix))
+	assert.Equal(t, issued.Key[:prefixLength], issued.Prefix)
+	assert.Equal(t, hashKey(issued.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, issued.Key)
+
+	repoMock.EXPECT().
+		GetByFilter(gomock.Any(), APIKeyFilter{KeyHash: hashKey(issued.Key)}).
+		Return([]APIKeyModel{stored}, nil)
+
+	principal, err := svc.Authenticate(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, auth.Principal{
+		ID:     stored.ID.String(),
+		Type:   auth.APIKeyPrincipal,
+		Name:   "backoffice",
+		Scopes: []string{"accounts:read"},
+	}, principal)
+
+	repoMock.EXPECT().
+		GetByFilter(gomock.Any(), APIKeyFilter{KeyHash: hashKey("lk_unknown")}).
+		Return(nil, nil)
+
+	_, err = svc.Authenticate(ctx, "lk_unknown")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestService_Tenant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	repoMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, model APIKeyModel) (APIKeyModel, error) {
+			return model, nil
+		})
+
+	ctx := tenancy.WithTenant(context.Background(), tenancy.Tenant{ID: "brand-a"})
+	issued, err := svc.Create(ctx, APIKey{Name: "partner", Scopes: []string{"transactions:credit"}, TenantID: "other"})
+	require.NoError(t, err)
+	assert.Equal(t, "brand-a", issued.TenantID)
+
+	id := uuid.New()
+	repoMock.EXPECT().
+		GetByFilter(gomock.Any(), APIKeyFilter{ID: uuid.NullUUID{UUID: id, Valid: true}, TenantID: "brand-a"}).
+		Return(nil, nil)
+
+	ctx = auth.WithPrincipal(ctx, auth.Principal{ID: "partner", TenantID: "brand-a"})
+	_, err = svc.Revoke(ctx, id)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func TestService_Rotate(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	model := APIKeyModel{ID: uuid.New(), Name: "backoffice", Prefix: "lk_previous", KeyHash: hashKey("previous")}
+	byID := APIKeyFilter{ID: uuid.NullUUID{UUID: model.ID, Valid: true}}
+
+	repoMock.EXPECT().GetByFilter(gomock.Any(), byID).Return([]APIKeyModel{model}, nil)
+	repoMock.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, m APIKeyModel) (APIKeyModel, error) {
+			return m, nil
+		})
+
+	issued, err := svc.Rotate(ctx, model.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ID, issued.ID)
+	assert.NotEqual(t, model.Prefix, issued.Prefix)
+	assert.False(t, issued.RotatedAt.IsZero())
+
+	t.Run("Revoked", func(t *testing.T) {
+		revoked := model
+		revoked.RevokedAt = time.Now()
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byID).Return([]APIKeyModel{revoked}, nil)
+
+		_, err := svc.Rotate(ctx, model.ID)
+		assert.ErrorIs(t, err, ErrAPIKeyRevoked)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byID).Return(nil, nil)
+
+		_, err := svc.Rotate(ctx, model.ID)
+		assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+	})
+}
+
+func TestService_Revoke(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	model := APIKeyModel{ID: uuid.New(), Name: "backoffice", KeyHash: hashKey("key")}
+
+	repoMock.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]APIKeyModel{model}, nil)
+	repoMock.EXPECT().
+		Update(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, m APIKeyModel) (APIKeyModel, error) {
+			model = m
+			return m, nil
+		})
+
+	revoked, err := svc.Revoke(ctx, model.ID)
+	require.NoError(t, err)
+	assert.True(t, revoked.Revoked())
+
+	repoMock.EXPECT().GetByFilter(gomock.Any(), APIKeyFilter{KeyHash: hashKey("key")}).Return([]APIKeyModel{model}, nil)
+
+	_, err = svc.Authenticate(ctx, "key")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestService_Authorization(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	holderID := uuid.New()
+	otherHolderID := uuid.New()
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:     "operator",
+		Scopes: []string{"api-keys", "accounts:read"},
+	})
+	endUser := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:       "end-user",
+		Scopes:   []string{"api-keys", "accounts:read", "admin"},
+		HolderID: uuid.NullUUID{UUID: holderID, Valid: true},
+	})
+
+	admin := APIKeyModel{ID: uuid.New(), Name: "admin", Scopes: []string{"admin"}}
+	unbound := APIKeyModel{ID: uuid.New(), Name: "backoffice", Scopes: []string{"accounts:read"}}
+	otherHolder := APIKeyModel{
+		ID:       uuid.New(),
+		Name:     "other holder",
+		Scopes:   []string{"accounts:read"},
+		HolderID: uuid.NullUUID{UUID: otherHolderID, Valid: true},
+	}
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		model APIKeyModel
+	}{
+		{name: "Missing scope of the key", ctx: operator, model: admin},
+		{name: "Key not bound to a holder", ctx: endUser, model: unbound},
+		{name: "Key of another holder", ctx: endUser, model: otherHolder},
+	}
+	for _, tt := range tests {
+		t.Run("Rotate, "+tt.name, func(t *testing.T) {
+			repoMock.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]APIKeyModel{tt.model}, nil)
+
+			issued, err := svc.Rotate(tt.ctx, tt.model.ID)
+			assert.ErrorIs(t, err, authz.ErrForbidden)
+			assert.Empty(t, issued.Key)
+		})
+
+		t.Run("Revoke, "+tt.name, func(t *testing.T) {
+			repoMock.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]APIKeyModel{tt.model}, nil)
+
+			_, err := svc.Revoke(tt.ctx, tt.model.ID)
+			assert.ErrorIs(t, err, authz.ErrForbidden)
+		})
+	}
+
+	t.Run("Rotate, key of the holder", func(t *testing.T) {
+		own := APIKeyModel{
+			ID:       uuid.New(),
+			Name:     "own",
+			Scopes:   []string{"accounts:read"},
+			HolderID: uuid.NullUUID{UUID: holderID, Valid: true},
+		}
+		repoMock.EXPECT().GetByFilter(gomock.Any(), gomock.Any()).Return([]APIKeyModel{own}, nil)
+		repoMock.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, m APIKeyModel) (APIKeyModel, error) {
+				return m, nil
+			})
+
+		issued, err := svc.Rotate(endUser, own.ID)
+		require.NoError(t, err)
+		assert.NotEmpty(t, issued.Key)
+	})
+
+	t.Run("Create, key of another holder", func(t *testing.T) {
+		_, err := svc.Create(endUser, APIKey{
+			Name:     "other holder",
+			Scopes:   []string{"accounts:read"},
+			HolderID: uuid.NullUUID{UUID: otherHolderID, Valid: true},
+		})
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+	})
+
+	t.Run("Create, key bound to the holder of the principal", func(t *testing.T) {
+		repoMock.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, model APIKeyModel) (APIKeyModel, error) {
+				return model, nil
+			})
+
+		issued, err := svc.Create(endUser, APIKey{Name: "unbound", Scopes: []string{"accounts:read"}})
+		require.NoError(t, err)
+		assert.Equal(t, uuid.NullUUID{UUID: holderID, Valid: true}, issued.HolderID)
+	})
+}
@@ -0,0 +1,137 @@
+package apikeyscli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/dalmarcogd/ledger-exp/internal/apikeys"
+	"github.com/dalmarcogd/ledger-exp/internal/apikeyscli/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ExitError is the exit code when the API key could not be created.
+const ExitError = 1
+
+// Options are the arguments of the command line.
+type Options struct {
+	// Name of the API key to create.
+	Name string
+	// Scopes granted to the API key.
+	Scopes []string
+	// HolderID restricts the API key to the holder when valid.
+	HolderID uuid.NullUUID
+	// TenantID restricts the API key to the tenant when not empty.
+	TenantID string
+	// KeyOut receives the created API key, the only time its secret is shown.
+	KeyOut io.Writer
+}
+
+// Module creates an API key writing it as JSON, it bootstraps the first key of the API whose endpoints are
+// authenticated. It must be supplied with the Options.
+var Module = fx.Options(
+	// Infra
+	fx.Provide(
+		environment.NewEnvironment,
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			return database.Setup(lc, t, m, database.Config{MasterURL: e.DatabaseURL})
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+		},
+	),
+	// Domains
+	fx.Provide(
+		apikeys.NewRepository,
+		apikeys.NewService,
+	),
+	fx.Invoke(runAPIKeys),
+)
+
+// runAPIKeys creates the API key once the application started and shuts it down with the result.
+func runAPIKeys(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	opts Options,
+	svc apikeys.Service,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+
+				exitCode := run(ctx, opts, svc)
+				if ctx.Err() != nil {
+					return
+				}
+
+				if err := shutdowner.Shutdown(fx.ExitCode(exitCode)); err != nil {
+					zap.L().Error("apikeys_shutdown_error", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func run(ctx context.Context, opts Options, svc apikeys.Service) int {
+	key, err := svc.Create(ctx, apikeys.APIKey{
+		Name:     opts.Name,
+		Scopes:   opts.Scopes,
+		HolderID: opts.HolderID,
+		TenantID: opts.TenantID,
+	})
+	if err != nil {
+		zap.L().Error("apikeys_create_error", zap.Error(err))
+		return ExitError
+	}
+
+	output := struct {
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Prefix   string   `json:"prefix"`
+		Scopes   []string `json:"scopes"`
+		TenantID string   `json:"tenant_id,omitempty"`
+		Key      string   `json:"key"`
+	}{ID: key.ID.String(), Name: key.Name, Prefix: key.Prefix, Scopes: key.Scopes, TenantID: key.TenantID, Key: key.Key}
+
+	if err := json.NewEncoder(opts.KeyOut).Encode(output); err != nil {
+		zap.L().Error("apikeys_key_write_error", zap.Error(err))
+		return ExitError
+	}
+
+	zap.L().Info("apikeys_created", zap.String("api_key_id", key.ID.String()), zap.String("prefix", key.Prefix))
+	return 0
+}
@@ -2,14 +2,21 @@ package balances
 
 import (
 	"context"
+	"time"
 
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/google/uuid"
+	"github.com/uptrace/bun"
 )
 
+// queryTimeout is the default deadline of the balances, summed from the transactions of the accounts.
+const queryTimeout = 3 * time.Second
+
+// Repository reads the balances of the accounts, only of the accounts of tenantID when it is not empty.
 type Repository interface {
-	GetByAccountID(ctx context.Context, accountID uuid.UUID) (accountBalanceModel, error)
+	GetByAccountID(ctx context.Context, tenantID string, accountID uuid.UUID) (accountBalanceModel, error)
+	ListByAccountIDs(ctx context.Context, tenantID string, accountIDs []uuid.UUID) ([]accountBalanceModel, error)
 }
 
 type repository struct {
@@ -24,21 +31,75 @@ func NewRepository(t tracer.Tracer, db database.Database) Repository {
 	}
 }
 
-func (r repository) GetByAccountID(ctx context.Context, accountID uuid.UUID) (accountBalanceModel, error) {
+func (r repository) GetByAccountID(
+	ctx context.Context,
+	tenantID string,
+	accountID uuid.UUID,
+) (accountBalanceModel, error) {
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
-	selectQuery := r.db.Replica().
-		NewSelect().
-		ModelTableExpr("transactions_balances").
-		Where("account_id = ?", accountID.String())
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	selectQuery := r.selectBalances(ctx, tenantID).
+		Where("tb.account_id = ?", accountID.String())
 
 	var acb accountBalanceModel
 	err := selectQuery.Scan(ctx, &acb)
 	if err != nil {
+		err = database.TranslateError(err, nil)
 		span.RecordError(err)
 		return accountBalanceModel{}, err
 	}
 
 	return acb, nil
 }
+
+// ListByAccountIDs returns the balances of the accounts in a single query, the accounts without transactions are
+// missing from the result.
+func (r repository) ListByAccountIDs(
+	ctx context.Context,
+	tenantID string,
+	accountIDs []uuid.UUID,
+) ([]accountBalanceModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	ids := make([]string, len(accountIDs))
+	for i, accountID := range accountIDs {
+		ids[i] = accountID.String()
+	}
+
+	selectQuery := r.selectBalances(ctx, tenantID).
+		Where("tb.account_id IN (?)", bun.In(ids))
+
+	var acbs []accountBalanceModel
+	err := selectQuery.Scan(ctx, &acbs)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return acbs, nil
+}
+
+// selectBalances selects the balances of the view, restricted to the accounts of the tenant when it is not empty.
+func (r repository) selectBalances(ctx context.Context, tenantID string) *bun.SelectQuery {
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		ModelTableExpr("transactions_balances AS tb").
+		ColumnExpr("tb.*")
+
+	if tenantID != "" {
+		selectQuery.
+			Join("JOIN accounts AS a ON a.id = tb.account_id").
+			Where("a.tenant_id = ?", tenantID)
+	}
+
+	return selectQuery
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/google/uuid"
@@ -13,22 +15,29 @@ import (
 
 type Service interface {
 	GetByAccountID(ctx context.Context, accountID uuid.UUID) (AccountBalance, error)
+	GetByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]AccountBalance, error)
 }
 
 type service struct {
-	tracer     tracer.Tracer
-	repository Repository
+	tracer      tracer.Tracer
+	repository  Repository
+	accountsSvc accounts.Service
 }
 
-func NewService(t tracer.Tracer, r Repository) Service {
-	return service{tracer: t, repository: r}
+func NewService(t tracer.Tracer, r Repository, as accounts.Service) Service {
+	return service{tracer: t, repository: r, accountsSvc: as}
 }
 
 func (s service) GetByAccountID(ctx context.Context, accountID uuid.UUID) (AccountBalance, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
-	accountBalance, err := s.repository.GetByAccountID(ctx, accountID)
+	if err := s.accountsSvc.Authorize(ctx, accountID); err != nil {
+		span.RecordError(err)
+		return AccountBalance{}, err
+	}
+
+	accountBalance, err := s.repository.GetByAccountID(ctx, tenancy.FilterID(ctx), accountID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return AccountBalance{
@@ -46,3 +55,36 @@ func (s service) GetByAccountID(ctx context.Context, accountID uuid.UUID) (Accou
 		CurrentBalance: accountBalance.Balance,
 	}, nil
 }
+
+// GetByAccountIDs returns the balances of the accounts in the same order of accountIDs, batching the lookup of
+// many accounts in a single query. The accounts are not authorized, they must come from an authorized listing.
+func (s service) GetByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]AccountBalance, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if len(accountIDs) == 0 {
+		return []AccountBalance{}, nil
+	}
+
+	accountBalances, err := s.repository.ListByAccountIDs(ctx, tenancy.FilterID(ctx), accountIDs)
+	if err != nil {
+		zapctx.L(ctx).Error("balances_service_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return nil, err
+	}
+
+	byAccountID := make(map[uuid.UUID]float64, len(accountBalances))
+	for _, accountBalance := range accountBalances {
+		byAccountID[accountBalance.AccountID] = accountBalance.Balance
+	}
+
+	balances := make([]AccountBalance, len(accountIDs))
+	for i, accountID := range accountIDs {
+		balances[i] = AccountBalance{
+			AccountID:      accountID,
+			CurrentBalance: byAccountID[accountID],
+		}
+	}
+
+	return balances, nil
+}
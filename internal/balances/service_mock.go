@@ -49,3 +49,18 @@ func (mr *MockServiceMockRecorder) GetByAccountID(ctx, accountID interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountID", reflect.TypeOf((*MockService)(nil).GetByAccountID), ctx, accountID)
 }
+
+// GetByAccountIDs mocks base method.
+func (m *MockService) GetByAccountIDs(ctx context.Context, accountIDs []uuid.UUID) ([]AccountBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByAccountIDs", ctx, accountIDs)
+	ret0, _ := ret[0].([]AccountBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByAccountIDs indicates an expected call of GetByAccountIDs.
+func (mr *MockServiceMockRecorder) GetByAccountIDs(ctx, accountIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountIDs", reflect.TypeOf((*MockService)(nil).GetByAccountIDs), ctx, accountIDs)
+}
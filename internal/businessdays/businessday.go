@@ -0,0 +1,158 @@
+package businessdays
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const dateLayout = "2006-01-02"
+
+// balanceTolerance absorbs the float rounding of the sums when checking the trial balance.
+const balanceTolerance = 0.000001
+
+var ErrInvalidDate = errors.New("date must be in the format yyyy-mm-dd")
+
+type Status string
+
+const (
+	// ClosedStatus business day frozen, no transaction can be dated into it.
+	ClosedStatus Status = "CLOSED"
+	// AdjustingStatus closed business day flagged to receive adjusting entries until it is closed again.
+	AdjustingStatus Status = "ADJUSTING"
+)
+
+// BusinessDay is a closed UTC date of the ledger, it freezes the transactions dated into it and into the days before.
+type BusinessDay struct {
+	Date      time.Time
+	Status    Status
+	ClosedAt  time.Time
+	UpdatedAt time.Time
+}
+
+func newBusinessDay(model businessDayModel) BusinessDay {
+	return BusinessDay{
+		Date:      model.Date,
+		Status:    model.Status,
+		ClosedAt:  model.ClosedAt,
+		UpdatedAt: model.UpdatedAt,
+	}
+}
+
+// ClosingBalance is the movement of an account in a business day, recorded when the day is closed.
+type ClosingBalance struct {
+	AccountID      uuid.UUID
+	OpeningBalance float64
+	TotalCredits   float64
+	TotalDebits    float64
+	ClosingBalance float64
+}
+
+func newClosingBalance(model balanceModel) ClosingBalance {
+	return ClosingBalance{
+		AccountID:      model.AccountID,
+		OpeningBalance: model.OpeningBalance,
+		TotalCredits:   model.TotalCredits,
+		TotalDebits:    model.TotalDebits,
+		ClosingBalance: model.ClosingBalance,
+	}
+}
+
+// DiscrepancyReason tells which check of the trial balance a closing balance failed.
+type DiscrepancyReason string
+
+const (
+	// OpeningMismatchReason the opening balance is not the closing balance recorded for the previous day.
+	OpeningMismatchReason DiscrepancyReason = "opening_mismatch"
+	// CreditsMismatchReason the credits are not the ones of the transactions dated into the day.
+	CreditsMismatchReason DiscrepancyReason = "credits_mismatch"
+	// DebitsMismatchReason the debits are not the ones of the transactions dated into the day.
+	DebitsMismatchReason DiscrepancyReason = "debits_mismatch"
+	// ClosingMismatchReason the closing balance is not the balance of the ledger at the end of the day.
+	ClosingMismatchReason DiscrepancyReason = "closing_mismatch"
+	// P2PUnbalancedReason the P2P credits of the day are not its P2P debits, for all the accounts.
+	P2PUnbalancedReason DiscrepancyReason = "p2p_unbalanced"
+)
+
+// Discrepancy is a recorded figure of the trial balance different from the one computed apart from it. AccountID is
+// nil for P2PUnbalancedReason, which has the P2P credits as Recorded and the P2P debits as Expected.
+type Discrepancy struct {
+	AccountID uuid.UUID
+	Reason    DiscrepancyReason
+	Recorded  float64
+	Expected  float64
+}
+
+// TrialBalance lists the closing balances of all accounts in a business day with their totals. It is balanced when
+// its figures match the ones computed apart from them, see Discrepancies.
+type TrialBalance struct {
+	BusinessDay    BusinessDay
+	Balances       []ClosingBalance
+	OpeningBalance float64
+	TotalCredits   float64
+	TotalDebits    float64
+	ClosingBalance float64
+	Balanced       bool
+	Discrepancies  []Discrepancy
+}
+
+func newTrialBalance(day BusinessDay, models []balanceModel) TrialBalance {
+	tb := TrialBalance{
+		BusinessDay: day,
+		Balances:    make([]ClosingBalance, len(models)),
+		Balanced:    true,
+	}
+
+	for i, model := range models {
+		balance := newClosingBalance(model)
+		tb.Balances[i] = balance
+		tb.OpeningBalance += balance.OpeningBalance
+		tb.TotalCredits += balance.TotalCredits
+		tb.TotalDebits += balance.TotalDebits
+		tb.ClosingBalance += balance.ClosingBalance
+	}
+
+	return tb
+}
+
+// addDiscrepancy adds the discrepancy when the recorded figure is not the expected one.
+func (tb *TrialBalance) addDiscrepancy(accountID uuid.UUID, reason DiscrepancyReason, recorded, expected float64) {
+	if math.Abs(recorded-expected) <= balanceTolerance {
+		return
+	}
+
+	tb.Discrepancies = append(tb.Discrepancies, Discrepancy{
+		AccountID: accountID,
+		Reason:    reason,
+		Recorded:  recorded,
+		Expected:  expected,
+	})
+	tb.Balanced = false
+}
+
+// ParseDate parses a business date in the format yyyy-mm-dd.
+func ParseDate(value string) (time.Time, error) {
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return date, nil
+}
+
+// FormatDate formats the business date as yyyy-mm-dd.
+func FormatDate(date time.Time) string {
+	return date.Format(dateLayout)
+}
+
+// StartOfDay returns the business date of t, its first instant in UTC.
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PreviousDay returns the business date before the date of t, the last one that can be closed at t.
+func PreviousDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, -1)
+}
@@ -0,0 +1,51 @@
+package businessdays
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type businessDayModel struct {
+	bun.BaseModel `bun:"table:business_days,alias:bd"`
+
+	Date      time.Time `bun:"date,pk"`
+	Status    Status    `bun:"status"`
+	ClosedAt  time.Time `bun:"closed_at,notnull"`
+	UpdatedAt time.Time `bun:"updated_at,notnull"`
+}
+
+type balanceModel struct {
+	bun.BaseModel `bun:"table:business_day_balances,alias:bdb"`
+
+	Date           time.Time `bun:"date,pk"`
+	AccountID      uuid.UUID `bun:"account_id,pk"`
+	OpeningBalance float64   `bun:"opening_balance"`
+	TotalCredits   float64   `bun:"total_credits"`
+	TotalDebits    float64   `bun:"total_debits"`
+	ClosingBalance float64   `bun:"closing_balance"`
+}
+
+// movementModel is the movement of an account in a business day summed from its transactions, with its balance in
+// the transactions_balances view and the net amount of its transactions dated after the day.
+type movementModel struct {
+	AccountID    uuid.UUID `bun:"account_id"`
+	TotalCredits float64   `bun:"total_credits"`
+	TotalDebits  float64   `bun:"total_debits"`
+	P2PCredits   float64   `bun:"p2p_credits"`
+	P2PDebits    float64   `bun:"p2p_debits"`
+	Balance      float64   `bun:"balance"`
+	LaterAmount  float64   `bun:"later_amount"`
+}
+
+type businessDayFilter struct {
+	Date database.NullTime
+	// DateBegin and DateEnd keep the business days between them, both inclusive.
+	DateBegin database.NullTime
+	DateEnd   database.NullTime
+	Status    Status
+	// Latest keeps only the most recent business day.
+	Latest bool
+}
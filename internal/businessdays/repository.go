@@ -0,0 +1,319 @@
+package businessdays
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
+)
+
+// recordBalancesQuery records the balances of the accounts opened until the end of the business day, replacing the
+// ones already recorded. The opening balance is the closing balance recorded for the previous day, and only the
+// transactions dated inside the day are summed. The first closed day has no previous day, its opening balances are
+// summed from the transactions before it.
+const recordBalancesQuery = `
+WITH business_day AS (SELECT arg.date,
+                             arg.date::TIMESTAMP AT TIME ZONE 'UTC'       AS begins_at,
+                             (arg.date + 1)::TIMESTAMP AT TIME ZONE 'UTC' AS ends_at
+                      FROM (SELECT ?::DATE AS date) AS arg),
+     previous AS (SELECT bdb.account_id, bdb.closing_balance
+                  FROM business_day
+                           JOIN business_day_balances AS bdb ON bdb.date = business_day.date - 1),
+     history AS (SELECT trxh.account_id, SUM(trxh.amount) AS balance
+                 FROM business_day,
+                      LATERAL (SELECT trx.to_account_id AS account_id, trx.amount
+                               FROM transactions AS trx
+                               WHERE trx.to_account_id IS NOT NULL
+                                 AND trx.created_at < business_day.begins_at
+                               UNION ALL
+                               SELECT trx.from_account_id, -trx.amount
+                               FROM transactions AS trx
+                               WHERE trx.from_account_id IS NOT NULL
+                                 AND trx.created_at < business_day.begins_at) AS trxh
+                 WHERE NOT EXISTS (SELECT 1 FROM business_days AS bd WHERE bd.date = business_day.date - 1)
+                 GROUP BY trxh.account_id),
+     movements AS (SELECT trxm.account_id, SUM(trxm.credit) AS credits, SUM(trxm.debit) AS debits
+                   FROM business_day,
+                        LATERAL (SELECT trx.to_account_id AS account_id, trx.amount AS credit, 0 AS debit
+                                 FROM transactions AS trx
+                                 WHERE trx.to_account_id IS NOT NULL
+                                   AND trx.created_at >= business_day.begins_at
+                                   AND trx.created_at < business_day.ends_at
+                                 UNION ALL
+                                 SELECT trx.from_account_id, 0, trx.amount
+                                 FROM transactions AS trx
+                                 WHERE trx.from_account_id IS NOT NULL
+                                   AND trx.created_at >= business_day.begins_at
+                                   AND trx.created_at < business_day.ends_at) AS trxm
+                   GROUP BY trxm.account_id)
+INSERT
+INTO business_day_balances
+    (date, account_id, opening_balance, total_credits, total_debits, closing_balance)
+SELECT business_day.date,
+       acc.id,
+       COALESCE(prev.closing_balance, hist.balance, 0),
+       COALESCE(mov.credits, 0),
+       COALESCE(mov.debits, 0),
+       COALESCE(prev.closing_balance, hist.balance, 0) + COALESCE(mov.credits, 0) - COALESCE(mov.debits, 0)
+FROM business_day
+         JOIN accounts AS acc ON acc.created_at < business_day.ends_at
+         LEFT JOIN previous AS prev ON prev.account_id = acc.id
+         LEFT JOIN history AS hist ON hist.account_id = acc.id
+         LEFT JOIN movements AS mov ON mov.account_id = acc.id
+ON CONFLICT (date, account_id) DO UPDATE SET opening_balance = EXCLUDED.opening_balance,
+                                             total_credits   = EXCLUDED.total_credits,
+                                             total_debits    = EXCLUDED.total_debits,
+                                             closing_balance = EXCLUDED.closing_balance`
+
+// listMovementsQuery sums the movements of the accounts opened until the end of the business day from the transactions
+// dated inside it, apart from the balances recorded when it was closed. The accounts are the ones of the tenant, all
+// of them when it is empty.
+const listMovementsQuery = `
+WITH business_day AS (SELECT arg.date::TIMESTAMP AT TIME ZONE 'UTC'       AS begins_at,
+                             (arg.date + 1)::TIMESTAMP AT TIME ZONE 'UTC' AS ends_at
+                      FROM (SELECT ?::DATE AS date) AS arg)
+SELECT acc.id                                                                                AS account_id,
+       COALESCE(SUM(trxm.credit) FILTER (WHERE trxm.created_at < business_day.ends_at), 0) AS total_credits,
+       COALESCE(SUM(trxm.debit) FILTER (WHERE trxm.created_at < business_day.ends_at), 0)  AS total_debits,
+       COALESCE(SUM(trxm.credit)
+                FILTER (WHERE trxm.type = 'P2P' AND trxm.created_at < business_day.ends_at), 0)  AS p2p_credits,
+       COALESCE(SUM(trxm.debit)
+                FILTER (WHERE trxm.type = 'P2P' AND trxm.created_at < business_day.ends_at), 0)  AS p2p_debits,
+       COALESCE(MAX(trxb.balance), 0)                                                        AS balance,
+       COALESCE(SUM(trxm.credit - trxm.debit)
+                FILTER (WHERE trxm.created_at >= business_day.ends_at), 0)                   AS later_amount
+FROM business_day
+         JOIN accounts AS acc ON acc.created_at < business_day.ends_at
+         LEFT JOIN LATERAL (SELECT trx.type, trx.created_at, trx.amount AS credit, 0 AS debit
+                            FROM transactions AS trx
+                            WHERE trx.to_account_id = acc.id
+                              AND trx.created_at >= business_day.begins_at
+                            UNION ALL
+                            SELECT trx.type, trx.created_at, 0, trx.amount
+                            FROM transactions AS trx
+                            WHERE trx.from_account_id = acc.id
+                              AND trx.created_at >= business_day.begins_at) AS trxm ON TRUE
+         LEFT JOIN transactions_balances AS trxb ON trxb.account_id = acc.id
+WHERE ? = '' OR acc.tenant_id = ?
+GROUP BY acc.id
+ORDER BY acc.id`
+
+// closeLock serializes the closes of the business days, and lockDayQuery locks a business day against the inserts of
+// the transactions dated into it, which hold the shared lock of their day until they commit (see the trigger
+// transactions_business_day_check).
+const (
+	closeLock    = "business_days"
+	lockDayQuery = "SELECT pg_advisory_xact_lock(hashtext('business_days:' || ?::DATE::TEXT))"
+)
+
+type Repository interface {
+	Close(ctx context.Context, date time.Time) (businessDayModel, error)
+	UpdateStatus(ctx context.Context, date time.Time, from, to Status) (bool, error)
+	GetByFilter(ctx context.Context, filter businessDayFilter) ([]businessDayModel, error)
+	ListBalances(ctx context.Context, tenantID string, date time.Time) ([]balanceModel, error)
+	ListMovements(ctx context.Context, tenantID string, date time.Time) ([]movementModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+// Close closes the business day, reopened for adjustments or not, and records the balances of the accounts in it
+// and in the business days after it, each one from the balances of the previous day. The day and the days after it
+// are locked meanwhile, so no transaction dated into them is created between recording the balances and closing the
+// day, while the transactions of the other days are not blocked.
+func (r repository) Close(ctx context.Context, date time.Time) (businessDayModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	now := time.Now().UTC()
+	model := businessDayModel{
+		Date:      date,
+		Status:    ClosedStatus,
+		ClosedAt:  now,
+		UpdatedAt: now,
+	}
+
+	err := r.db.Master(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewRaw("SELECT pg_advisory_xact_lock(hashtext(?))", closeLock).Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		var following []time.Time
+		err = tx.NewSelect().
+			Model((*businessDayModel)(nil)).
+			Column("bd.date").
+			Where("bd.date > ?", FormatDate(date)).
+			Order("bd.date ASC").
+			Scan(ctx, &following)
+		if err != nil {
+			return err
+		}
+
+		// Locked in order, as the days are recorded.
+		days := append([]time.Time{date}, following...)
+		for _, day := range days {
+			_, err = tx.NewRaw(lockDayQuery, FormatDate(day)).Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.NewInsert().
+			Model(&model).
+			On("CONFLICT (date) DO UPDATE").
+			Set("status = EXCLUDED.status").
+			Set("updated_at = EXCLUDED.updated_at").
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, day := range days {
+			_, err = tx.NewRaw(recordBalancesQuery, FormatDate(day)).Exec(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return businessDayModel{}, err
+	}
+
+	return model, nil
+}
+
+// UpdateStatus changes the status of the business day when it is in the status from, returning false otherwise.
+func (r repository) UpdateStatus(ctx context.Context, date time.Time, from, to Status) (bool, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	res, err := r.db.Master(ctx).
+		NewUpdate().
+		Model((*businessDayModel)(nil)).
+		Set("status = ?", to).
+		Set("updated_at = ?", time.Now().UTC()).
+		Where("date = ?", FormatDate(date)).
+		Where("status = ?", from).
+		Exec(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+// GetByFilter reads from the master, postings are checked against the business days and can not miss a day just
+// closed.
+func (r repository) GetByFilter(ctx context.Context, filter businessDayFilter) ([]businessDayModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Master(ctx).
+		NewSelect().
+		Model(&businessDayModel{}).
+		Order("bd.date DESC")
+
+	if filter.Date.Valid {
+		selectQuery.Where("bd.date = ?", FormatDate(filter.Date.Time))
+	}
+
+	if filter.DateBegin.Valid {
+		selectQuery.Where("bd.date >= ?", FormatDate(filter.DateBegin.Time))
+	}
+
+	if filter.DateEnd.Valid {
+		selectQuery.Where("bd.date <= ?", FormatDate(filter.DateEnd.Time))
+	}
+
+	if filter.Status != "" {
+		selectQuery.Where("bd.status = ?", filter.Status)
+	}
+
+	if filter.Latest {
+		selectQuery.Limit(1)
+	}
+
+	var models []businessDayModel
+	err := selectQuery.Scan(ctx, &models)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// ListBalances lists the closing balances of the accounts of the tenant on the business day, the accounts of all the
+// tenants when tenantID is empty.
+func (r repository) ListBalances(ctx context.Context, tenantID string, date time.Time) ([]balanceModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []balanceModel
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		Model(&models).
+		Where("bdb.date = ?", FormatDate(date)).
+		Order("bdb.account_id")
+
+	if tenantID != "" {
+		selectQuery.
+			Join("JOIN accounts AS acc ON acc.id = bdb.account_id").
+			Where("acc.tenant_id = ?", tenantID)
+	}
+
+	err := selectQuery.Scan(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// ListMovements lists the movements of the accounts of the tenant in the business day summed from the transactions,
+// the accounts of all the tenants when tenantID is empty.
+func (r repository) ListMovements(ctx context.Context, tenantID string, date time.Time) ([]movementModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []movementModel
+	err := r.db.Replica(ctx).
+		NewRaw(listMovementsQuery, FormatDate(date), tenantID, tenantID).
+		Scan(ctx, &models)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
@@ -0,0 +1,200 @@
+//go:build integration
+
+package businessdays
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepository_ListBalances(t *testing.T) {
+	ctx := context.Background()
+
+	url, closeFunc, err := testingcontainers.NewPostgresContainer()
+	require.NoError(t, err)
+	defer closeFunc(ctx) //nolint:errcheck
+
+	_, callerPath, _, _ := runtime.Caller(0) //nolint:dogsled
+	err = testingcontainers.RunMigrateDatabase(
+		url,
+		fmt.Sprintf("file://%s/../../migrations/", filepath.Dir(callerPath)),
+	)
+	require.NoError(t, err)
+
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
+	require.NoError(t, err)
+
+	holdersRepo := holders.NewRepository(tracer.NewNoop(), db)
+	accountsSvc := accounts.NewService(
+		tracer.NewNoop(),
+		accounts.NewRepository(tracer.NewNoop(), db),
+		holdersRepo,
+		database.NewUnitOfWork(tracer.NewNoop(), db),
+	)
+	repo := NewRepository(tracer.NewNoop(), db)
+
+	accountIDs := map[string]uuid.UUID{}
+	for _, tenantID := range []string{"brand-a", "brand-b"} {
+		holder, err := holdersRepo.Create(ctx, holders.HolderModel{
+			ID:             uuid.New(),
+			Name:           gofakeit.Name(),
+			DocumentNumber: gofakeit.SSN(),
+			TenantID:       tenantID,
+		})
+		require.NoError(t, err)
+
+		account, err := accountsSvc.Create(
+			tenancy.WithTenant(ctx, tenancy.Tenant{ID: tenantID, Agency: "0001"}),
+			accounts.Account{Name: gofakeit.Name(), DocumentNumber: holder.DocumentNumber},
+		)
+		require.NoError(t, err)
+		accountIDs[tenantID] = account.ID
+	}
+
+	today := StartOfDay(time.Now())
+	_, err = repo.Close(ctx, today)
+	require.NoError(t, err)
+
+	t.Run("balances of the tenant", func(t *testing.T) {
+		models, err := repo.ListBalances(ctx, "brand-a", today)
+		require.NoError(t, err)
+		if assert.Len(t, models, 1) {
+			assert.Equal(t, accountIDs["brand-a"], models[0].AccountID)
+		}
+
+		models, err = repo.ListBalances(ctx, "brand-b", today)
+		require.NoError(t, err)
+		if assert.Len(t, models, 1) {
+			assert.Equal(t, accountIDs["brand-b"], models[0].AccountID)
+		}
+	})
+
+	t.Run("balances of all the tenants", func(t *testing.T) {
+		models, err := repo.ListBalances(ctx, "", today)
+		require.NoError(t, err)
+		assert.Len(t, models, 2)
+	})
+}
+
+func TestRepository_Close(t *testing.T) {
+	ctx := context.Background()
+
+	url, closeFunc, err := testingcontainers.NewPostgresContainer()
+	require.NoError(t, err)
+	defer closeFunc(ctx) //nolint:errcheck
+
+	_, callerPath, _, _ := runtime.Caller(0) //nolint:dogsled
+	err = testingcontainers.RunMigrateDatabase(
+		url,
+		fmt.Sprintf("file://%s/../../migrations/", filepath.Dir(callerPath)),
+	)
+	require.NoError(t, err)
+
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
+	require.NoError(t, err)
+
+	holdersRepo := holders.NewRepository(tracer.NewNoop(), db)
+	accountsSvc := accounts.NewService(
+		tracer.NewNoop(),
+		accounts.NewRepository(tracer.NewNoop(), db),
+		holdersRepo,
+		database.NewUnitOfWork(tracer.NewNoop(), db),
+	)
+	repo := NewRepository(tracer.NewNoop(), db)
+
+	holder, err := holdersRepo.Create(ctx, holders.HolderModel{
+		ID:             uuid.New(),
+		Name:           gofakeit.Name(),
+		DocumentNumber: gofakeit.SSN(),
+		TenantID:       tenancy.Default.ID,
+	})
+	require.NoError(t, err)
+
+	account, err := accountsSvc.Create(
+		tenancy.WithTenant(ctx, tenancy.Default),
+		accounts.Account{Name: gofakeit.Name(), DocumentNumber: holder.DocumentNumber},
+	)
+	require.NoError(t, err)
+
+	insert := func(trxType string, amount float64, adjustment bool, createdAt time.Time) error {
+		from, to := uuid.NullUUID{}, uuid.NullUUID{UUID: account.ID, Valid: true}
+		if trxType == "DEBIT" {
+			from, to = to, from
+		}
+		_, err := db.Master(ctx).NewRaw(
+			`INSERT INTO transactions (id, from_account_id, to_account_id, type, amount, description, adjustment, created_at)
+			VALUES (?, ?, ?, ?, ?, '', ?, ?)`,
+			uuid.New(), from, to, trxType, amount, adjustment, createdAt,
+		).Exec(ctx)
+		return err
+	}
+
+	balance := func(date time.Time) balanceModel {
+		models, err := repo.ListBalances(ctx, "", date)
+		require.NoError(t, err)
+		require.Len(t, models, 1)
+		return models[0]
+	}
+
+	today := StartOfDay(time.Now())
+	tomorrow := today.AddDate(0, 0, 1)
+
+	require.NoError(t, insert("CREDIT", 50, false, today.Add(-time.Hour)))
+	require.NoError(t, insert("CREDIT", 100, false, today.Add(time.Hour)))
+	require.NoError(t, insert("CREDIT", 30, false, tomorrow.Add(time.Hour)))
+	require.NoError(t, insert("DEBIT", 10, false, tomorrow.Add(2*time.Hour)))
+
+	t.Run("first closed day opens with the history", func(t *testing.T) {
+		_, err := repo.Close(ctx, today)
+		require.NoError(t, err)
+
+		bdb := balance(today)
+		assert.Equal(t, 50.0, bdb.OpeningBalance)
+		assert.Equal(t, 100.0, bdb.TotalCredits)
+		assert.Equal(t, 0.0, bdb.TotalDebits)
+		assert.Equal(t, 150.0, bdb.ClosingBalance)
+
+		assert.Error(t, insert("CREDIT", 1, false, today.Add(3*time.Hour)))
+	})
+
+	t.Run("next day opens with the closing of the previous one", func(t *testing.T) {
+		_, err := repo.Close(ctx, tomorrow)
+		require.NoError(t, err)
+
+		bdb := balance(tomorrow)
+		assert.Equal(t, 150.0, bdb.OpeningBalance)
+		assert.Equal(t, 30.0, bdb.TotalCredits)
+		assert.Equal(t, 10.0, bdb.TotalDebits)
+		assert.Equal(t, 170.0, bdb.ClosingBalance)
+	})
+
+	t.Run("closing an adjusted day records the following days again", func(t *testing.T) {
+		updated, err := repo.UpdateStatus(ctx, today, ClosedStatus, AdjustingStatus)
+		require.NoError(t, err)
+		require.True(t, updated)
+		require.NoError(t, insert("CREDIT", 5, true, today.Add(4*time.Hour)))
+
+		_, err = repo.Close(ctx, today)
+		require.NoError(t, err)
+
+		assert.Equal(t, 155.0, balance(today).ClosingBalance)
+		assert.Equal(t, 155.0, balance(tomorrow).OpeningBalance)
+		assert.Equal(t, 175.0, balance(tomorrow).ClosingBalance)
+	})
+}
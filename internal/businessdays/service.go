@@ -0,0 +1,399 @@
+package businessdays
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBusinessDayNotFound     = errors.New("no closed business day found for this date")
+	ErrBusinessDayNotOver      = errors.New("only business days already over can be closed")
+	ErrPreviousBusinessDayOpen = errors.New("the previous business day must be closed first")
+	ErrBusinessDayClosed       = errors.New("the business day is closed")
+	ErrNotAdjustmentPeriod     = errors.New("adjusting entries are only allowed into business days open for adjustments")
+	ErrBusinessDaysOpen        = errors.New("the business days of the period are not all closed")
+)
+
+type Service interface {
+	Close(ctx context.Context, date time.Time) (BusinessDay, error)
+	OpenAdjustment(ctx context.Context, date time.Time) (BusinessDay, error)
+	GetByDate(ctx context.Context, date time.Time) (BusinessDay, error)
+	GetLatest(ctx context.Context) (BusinessDay, error)
+	GetTrialBalance(ctx context.Context, date time.Time) (TrialBalance, error)
+	CheckPosting(ctx context.Context, postedAt time.Time, adjustment bool) error
+	CheckClosed(ctx context.Context, begin, end time.Time) error
+}
+
+type service struct {
+	tracer     tracer.Tracer
+	repository Repository
+	unitOfWork database.UnitOfWork
+}
+
+func NewService(t tracer.Tracer, r Repository, unitOfWork database.UnitOfWork) Service {
+	return service{tracer: t, repository: r, unitOfWork: unitOfWork}
+}
+
+// Close closes the business day, freezing it and recording the closing balances of the accounts. Days are closed in
+// order, the first one can be any past day but then each day requires the previous one closed. Closing a closed day
+// changes nothing, while closing a day open for adjustments ends its adjustment period. The business days are of all
+// the tenants, they are only closed by the principals not bound to a tenant.
+func (s service) Close(ctx context.Context, date time.Time) (BusinessDay, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := authz.AuthorizeDeployment(ctx); err != nil {
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	date = StartOfDay(date)
+	if date.AddDate(0, 0, 1).After(time.Now()) {
+		span.RecordError(ErrBusinessDayNotOver)
+		return BusinessDay{}, ErrBusinessDayNotOver
+	}
+
+	day, err := s.GetByDate(ctx, date)
+	switch {
+	case err == nil && day.Status == ClosedStatus:
+		return day, nil
+	case err == nil:
+		// Open for adjustments, it is closed again.
+	case errors.Is(err, ErrBusinessDayNotFound):
+		latest, err := s.GetLatest(ctx)
+		if err != nil && !errors.Is(err, ErrBusinessDayNotFound) {
+			span.RecordError(err)
+			return BusinessDay{}, err
+		}
+
+		if err == nil {
+			if date.Before(latest.Date) {
+				// Days before the first closed day are frozen by it, without closing balances.
+				span.RecordError(ErrBusinessDayClosed)
+				return BusinessDay{}, ErrBusinessDayClosed
+			}
+
+			if !latest.Date.AddDate(0, 0, 1).Equal(date) {
+				span.RecordError(ErrPreviousBusinessDayOpen)
+				return BusinessDay{}, ErrPreviousBusinessDayOpen
+			}
+		}
+	default:
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	model, err := s.repository.Close(ctx, date)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"business_days_service_close_repository_error",
+			zap.String("date", FormatDate(date)),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	zapctx.L(ctx).Info("business_days_service_closed", zap.String("date", FormatDate(date)))
+
+	return newBusinessDay(model), nil
+}
+
+// OpenAdjustment flags the closed business day to receive adjusting entries, until it is closed again. Only the
+// principals not bound to a tenant open the adjustments, which are for all the tenants.
+func (s service) OpenAdjustment(ctx context.Context, date time.Time) (BusinessDay, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := authz.AuthorizeDeployment(ctx); err != nil {
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	date = StartOfDay(date)
+
+	_, err := s.repository.UpdateStatus(ctx, date, ClosedStatus, AdjustingStatus)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"business_days_service_open_adjustment_repository_error",
+			zap.String("date", FormatDate(date)),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	// Not updated when already open for adjustments or not closed, both answered by the current state of the day.
+	return s.GetByDate(ctx, date)
+}
+
+func (s service) GetByDate(ctx context.Context, date time.Time) (BusinessDay, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	return s.get(ctx, businessDayFilter{Date: database.NullTime{Time: StartOfDay(date), Valid: true}})
+}
+
+// GetLatest returns the most recent closed business day, all days up to it are frozen.
+func (s service) GetLatest(ctx context.Context) (BusinessDay, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	return s.get(ctx, businessDayFilter{Latest: true})
+}
+
+func (s service) get(ctx context.Context, filter businessDayFilter) (BusinessDay, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	models, err := s.repository.GetByFilter(ctx, filter)
+	if err != nil {
+		zapctx.L(ctx).Error("business_days_service_get_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return BusinessDay{}, err
+	}
+
+	if len(models) != 1 {
+		span.RecordError(ErrBusinessDayNotFound)
+		return BusinessDay{}, ErrBusinessDayNotFound
+	}
+
+	return newBusinessDay(models[0]), nil
+}
+
+// GetTrialBalance returns the closing balances recorded for the business day of the accounts of the tenant, checked
+// against the figures computed apart from them: the opening balances against the closing balances recorded for the
+// previous day, the credits and the debits against the transactions dated into the day, the P2P credits against the
+// P2P debits and, for the latest closed day, the closing balances against the balances of the ledger. For a day open
+// for adjustments they are the balances of its last closing, without the adjustments made since then, so only its
+// opening balances and its P2P movements are checked. All of them are read from the same snapshot.
+func (s service) GetTrialBalance(ctx context.Context, date time.Time) (TrialBalance, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	var tb TrialBalance
+	snapshot := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err := s.unitOfWork.Do(ctx, snapshot, func(ctx context.Context) error {
+		day, err := s.GetByDate(ctx, date)
+		if err != nil {
+			return err
+		}
+
+		models, err := s.repository.ListBalances(ctx, tenancy.FilterID(ctx), day.Date)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"business_days_service_list_balances_repository_error",
+				zap.String("date", FormatDate(day.Date)),
+				zap.Error(err),
+			)
+			return err
+		}
+
+		tb = newTrialBalance(day, models)
+		return s.checkTrialBalance(ctx, &tb)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return TrialBalance{}, err
+	}
+
+	return tb, nil
+}
+
+// checkTrialBalance adds to the trial balance the discrepancies between its figures and the ones computed apart from
+// them, see GetTrialBalance.
+func (s service) checkTrialBalance(ctx context.Context, tb *TrialBalance) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	tenantID := tenancy.FilterID(ctx)
+	day := tb.BusinessDay
+
+	previousDate := day.Date.AddDate(0, 0, -1)
+	_, err := s.GetByDate(ctx, previousDate)
+	switch {
+	case err == nil:
+		models, err := s.repository.ListBalances(ctx, tenantID, previousDate)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"business_days_service_list_balances_repository_error",
+				zap.String("date", FormatDate(previousDate)),
+				zap.Error(err),
+			)
+			span.RecordError(err)
+			return err
+		}
+
+		closings := make(map[uuid.UUID]float64, len(models))
+		for _, model := range models {
+			closings[model.AccountID] = model.ClosingBalance
+		}
+		for _, balance := range tb.Balances {
+			tb.addDiscrepancy(balance.AccountID, OpeningMismatchReason, balance.OpeningBalance, closings[balance.AccountID])
+		}
+	case errors.Is(err, ErrBusinessDayNotFound):
+		// The first closed day, opened with the balances of the transactions before it.
+	default:
+		span.RecordError(err)
+		return err
+	}
+
+	movements, err := s.repository.ListMovements(ctx, tenantID, day.Date)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"business_days_service_list_movements_repository_error",
+			zap.String("date", FormatDate(day.Date)),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return err
+	}
+
+	var p2pCredits, p2pDebits float64
+	for _, movement := range movements {
+		p2pCredits += movement.P2PCredits
+		p2pDebits += movement.P2PDebits
+	}
+	tb.addDiscrepancy(uuid.Nil, P2PUnbalancedReason, p2pCredits, p2pDebits)
+
+	if day.Status != ClosedStatus {
+		return nil
+	}
+
+	ledger, err := s.isLedgerDay(ctx, day)
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	recorded := make(map[uuid.UUID]ClosingBalance, len(tb.Balances))
+	for _, balance := range tb.Balances {
+		recorded[balance.AccountID] = balance
+	}
+	for _, movement := range movements {
+		// The accounts without a recorded balance are checked against zero.
+		balance := recorded[movement.AccountID]
+		tb.addDiscrepancy(movement.AccountID, CreditsMismatchReason, balance.TotalCredits, movement.TotalCredits)
+		tb.addDiscrepancy(movement.AccountID, DebitsMismatchReason, balance.TotalDebits, movement.TotalDebits)
+		if ledger {
+			closing := movement.Balance - movement.LaterAmount
+			tb.addDiscrepancy(movement.AccountID, ClosingMismatchReason, balance.ClosingBalance, closing)
+		}
+	}
+
+	return nil
+}
+
+// isLedgerDay tells the closing balances of the day can be checked against the balances of the ledger: it is the
+// latest closed day and no day is open for adjustments, whose adjustments are not in the recorded balances yet.
+func (s service) isLedgerDay(ctx context.Context, day BusinessDay) (bool, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	latest, err := s.GetLatest(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return false, err
+	}
+
+	if !latest.Date.Equal(day.Date) {
+		return false, nil
+	}
+
+	adjusting, err := s.repository.GetByFilter(ctx, businessDayFilter{Status: AdjustingStatus, Latest: true})
+	if err != nil {
+		zapctx.L(ctx).Error("business_days_service_get_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return false, err
+	}
+
+	return len(adjusting) == 0, nil
+}
+
+// CheckPosting checks a transaction can be dated at postedAt: regular transactions only after the latest closed
+// business day and adjusting entries only into a business day open for adjustments.
+func (s service) CheckPosting(ctx context.Context, postedAt time.Time, adjustment bool) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	date := StartOfDay(postedAt)
+
+	if adjustment {
+		day, err := s.GetByDate(ctx, date)
+		if err != nil && !errors.Is(err, ErrBusinessDayNotFound) {
+			span.RecordError(err)
+			return err
+		}
+
+		if err != nil || day.Status != AdjustingStatus {
+			span.RecordError(ErrNotAdjustmentPeriod)
+			return ErrNotAdjustmentPeriod
+		}
+
+		return nil
+	}
+
+	latest, err := s.GetLatest(ctx)
+	if errors.Is(err, ErrBusinessDayNotFound) {
+		return nil
+	} else if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	if !date.After(latest.Date) {
+		span.RecordError(ErrBusinessDayClosed)
+		return ErrBusinessDayClosed
+	}
+
+	return nil
+}
+
+// CheckClosed checks all the business days from begin through end are closed: the latest closed business day is not
+// before end, and none of them is open for adjustments.
+func (s service) CheckClosed(ctx context.Context, begin, end time.Time) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	begin, end = StartOfDay(begin), StartOfDay(end)
+
+	latest, err := s.GetLatest(ctx)
+	if err != nil && !errors.Is(err, ErrBusinessDayNotFound) {
+		span.RecordError(err)
+		return err
+	}
+
+	if err != nil || latest.Date.Before(end) {
+		span.RecordError(ErrBusinessDaysOpen)
+		return ErrBusinessDaysOpen
+	}
+
+	adjusting, err := s.repository.GetByFilter(ctx, businessDayFilter{
+		DateBegin: database.NullTime{Time: begin, Valid: true},
+		DateEnd:   database.NullTime{Time: end, Valid: true},
+		Status:    AdjustingStatus,
+		Latest:    true,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("business_days_service_get_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return err
+	}
+
+	if len(adjusting) > 0 {
+		span.RecordError(ErrBusinessDaysOpen)
+		return ErrBusinessDaysOpen
+	}
+
+	return nil
+}
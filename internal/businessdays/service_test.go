@@ -0,0 +1,358 @@
+//go:build unit
+
+package businessdays
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Close(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, uowMock)
+
+	yesterday := PreviousDay(time.Now())
+	byDate := func(date time.Time) businessDayFilter {
+		return businessDayFilter{Date: database.NullTime{Time: date, Valid: true}}
+	}
+
+	t.Run("fail close, principal bound to a tenant", func(t *testing.T) {
+		ctx := auth.WithPrincipal(ctx, auth.Principal{ID: "brand-a-operator", TenantID: "brand-a"})
+
+		day, err := svc.Close(ctx, yesterday)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, day)
+
+		day, err = svc.OpenAdjustment(ctx, yesterday)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, day)
+	})
+
+	t.Run("fail close, business day not over", func(t *testing.T) {
+		day, err := svc.Close(ctx, time.Now())
+		assert.ErrorIs(t, err, ErrBusinessDayNotOver)
+		assert.Empty(t, day)
+	})
+
+	t.Run("fail close, previous business day open", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byDate(yesterday)).Return(nil, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: yesterday.AddDate(0, 0, -2), Status: ClosedStatus}}, nil)
+
+		day, err := svc.Close(ctx, yesterday)
+		assert.ErrorIs(t, err, ErrPreviousBusinessDayOpen)
+		assert.Empty(t, day)
+	})
+
+	t.Run("fail close, business day before the first closed day", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byDate(yesterday.AddDate(0, 0, -5))).Return(nil, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: yesterday, Status: ClosedStatus}}, nil)
+
+		day, err := svc.Close(ctx, yesterday.AddDate(0, 0, -5))
+		assert.ErrorIs(t, err, ErrBusinessDayClosed)
+		assert.Empty(t, day)
+	})
+
+	t.Run("success close, already closed", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byDate(yesterday)).
+			Return([]businessDayModel{{Date: yesterday, Status: ClosedStatus}}, nil)
+
+		day, err := svc.Close(ctx, yesterday)
+		assert.NoError(t, err)
+		assert.Equal(t, ClosedStatus, day.Status)
+	})
+
+	t.Run("success close, first business day", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byDate(yesterday)).Return(nil, nil)
+		repoMock.EXPECT().GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).Return(nil, nil)
+		repoMock.EXPECT().
+			Close(gomock.Any(), yesterday).
+			Return(businessDayModel{Date: yesterday, Status: ClosedStatus}, nil)
+
+		day, err := svc.Close(ctx, yesterday.Add(5*time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, BusinessDay{Date: yesterday, Status: ClosedStatus}, day)
+	})
+
+	t.Run("success close, ending the adjustment period", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byDate(yesterday)).
+			Return([]businessDayModel{{Date: yesterday, Status: AdjustingStatus}}, nil)
+		repoMock.EXPECT().
+			Close(gomock.Any(), yesterday).
+			Return(businessDayModel{Date: yesterday, Status: ClosedStatus}, nil)
+
+		day, err := svc.Close(ctx, yesterday)
+		assert.NoError(t, err)
+		assert.Equal(t, ClosedStatus, day.Status)
+	})
+}
+
+func TestService_CheckPosting(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, uowMock)
+
+	closed := time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success posting, no business day closed", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).Return(nil, nil)
+
+		assert.NoError(t, svc.CheckPosting(ctx, closed, false))
+	})
+
+	t.Run("success posting, after the latest closed business day", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: closed, Status: ClosedStatus}}, nil)
+
+		assert.NoError(t, svc.CheckPosting(ctx, closed.AddDate(0, 0, 1), false))
+	})
+
+	t.Run("fail posting, backdated into a closed business day", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: closed, Status: ClosedStatus}}, nil)
+
+		err := svc.CheckPosting(ctx, closed.Add(23*time.Hour), false)
+		assert.ErrorIs(t, err, ErrBusinessDayClosed)
+	})
+
+	t.Run("fail posting, adjustment into a business day not open for adjustments", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Date: database.NullTime{Time: closed, Valid: true}}).
+			Return([]businessDayModel{{Date: closed, Status: ClosedStatus}}, nil)
+
+		err := svc.CheckPosting(ctx, closed.Add(time.Hour), true)
+		assert.ErrorIs(t, err, ErrNotAdjustmentPeriod)
+	})
+
+	t.Run("success posting, adjustment into a business day open for adjustments", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Date: database.NullTime{Time: closed, Valid: true}}).
+			Return([]businessDayModel{{Date: closed, Status: AdjustingStatus}}, nil)
+
+		assert.NoError(t, svc.CheckPosting(ctx, closed.Add(time.Hour), true))
+	})
+}
+
+func TestService_CheckClosed(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, uowMock)
+
+	begin := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 1, 31, 23, 59, 59, 0, time.UTC)
+	adjustingFilter := businessDayFilter{
+		DateBegin: database.NullTime{Time: begin, Valid: true},
+		DateEnd:   database.NullTime{Time: StartOfDay(end), Valid: true},
+		Status:    AdjustingStatus,
+		Latest:    true,
+	}
+
+	t.Run("fail closed, no business day closed", func(t *testing.T) {
+		repoMock.EXPECT().GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).Return(nil, nil)
+
+		assert.ErrorIs(t, svc.CheckClosed(ctx, begin, end), ErrBusinessDaysOpen)
+	})
+
+	t.Run("fail closed, last business day not closed", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: StartOfDay(end).AddDate(0, 0, -1), Status: ClosedStatus}}, nil)
+
+		assert.ErrorIs(t, svc.CheckClosed(ctx, begin, end), ErrBusinessDaysOpen)
+	})
+
+	t.Run("fail closed, business day open for adjustments", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: StartOfDay(end), Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), adjustingFilter).
+			Return([]businessDayModel{{Date: begin.AddDate(0, 0, 9), Status: AdjustingStatus}}, nil)
+
+		assert.ErrorIs(t, svc.CheckClosed(ctx, begin, end), ErrBusinessDaysOpen)
+	})
+
+	t.Run("success closed", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: StartOfDay(end).AddDate(0, 0, 1), Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().GetByFilter(gomock.Any(), adjustingFilter).Return(nil, nil)
+
+		assert.NoError(t, svc.CheckClosed(ctx, begin, end))
+	})
+}
+
+func TestService_GetTrialBalance(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, uowMock)
+
+	date := time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+	previousDate := date.AddDate(0, 0, -1)
+	byDate := businessDayFilter{Date: database.NullTime{Time: date, Valid: true}}
+	byPreviousDate := businessDayFilter{Date: database.NullTime{Time: previousDate, Valid: true}}
+	snapshot := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+
+	expectUnitOfWork := func() {
+		uowMock.EXPECT().
+			Do(gomock.Any(), snapshot, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *sql.TxOptions, fn func(ctx context.Context) error) error {
+				return fn(ctx)
+			})
+	}
+
+	t.Run("fail trial balance, business day not closed", func(t *testing.T) {
+		expectUnitOfWork()
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byDate).Return(nil, nil)
+
+		tb, err := svc.GetTrialBalance(ctx, date)
+		assert.ErrorIs(t, err, ErrBusinessDayNotFound)
+		assert.Empty(t, tb)
+	})
+
+	t.Run("success trial balance with totals", func(t *testing.T) {
+		accountID, otherAccountID := uuid.New(), uuid.New()
+
+		expectUnitOfWork()
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byDate).
+			Return([]businessDayModel{{Date: date, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			ListBalances(gomock.Any(), "", date).
+			Return([]balanceModel{
+				{Date: date, AccountID: accountID, OpeningBalance: 100, TotalCredits: 50, TotalDebits: 30, ClosingBalance: 120},
+				{Date: date, AccountID: otherAccountID, OpeningBalance: 0, TotalCredits: 30, ClosingBalance: 30},
+			}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byPreviousDate).
+			Return([]businessDayModel{{Date: previousDate, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			ListBalances(gomock.Any(), "", previousDate).
+			Return([]balanceModel{{Date: previousDate, AccountID: accountID, ClosingBalance: 100}}, nil)
+		repoMock.EXPECT().
+			ListMovements(gomock.Any(), "", date).
+			Return([]movementModel{
+				{AccountID: accountID, TotalCredits: 50, TotalDebits: 30, P2PDebits: 30, Balance: 140, LaterAmount: 20},
+				{AccountID: otherAccountID, TotalCredits: 30, P2PCredits: 30, Balance: 30},
+			}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: date, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Status: AdjustingStatus, Latest: true}).
+			Return(nil, nil)
+
+		tb, err := svc.GetTrialBalance(ctx, date)
+		assert.NoError(t, err)
+		assert.Len(t, tb.Balances, 2)
+		assert.Equal(t, 100.0, tb.OpeningBalance)
+		assert.Equal(t, 80.0, tb.TotalCredits)
+		assert.Equal(t, 30.0, tb.TotalDebits)
+		assert.Equal(t, 150.0, tb.ClosingBalance)
+		assert.True(t, tb.Balanced)
+		assert.Empty(t, tb.Discrepancies)
+	})
+
+	t.Run("success trial balance unbalanced", func(t *testing.T) {
+		accountID, otherAccountID := uuid.New(), uuid.New()
+
+		expectUnitOfWork()
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byDate).
+			Return([]businessDayModel{{Date: date, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			ListBalances(gomock.Any(), "", date).
+			Return([]balanceModel{
+				{Date: date, AccountID: accountID, OpeningBalance: 100, TotalCredits: 50, ClosingBalance: 150},
+			}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byPreviousDate).
+			Return([]businessDayModel{{Date: previousDate, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			ListBalances(gomock.Any(), "", previousDate).
+			Return([]balanceModel{{Date: previousDate, AccountID: accountID, ClosingBalance: 90}}, nil)
+		repoMock.EXPECT().
+			ListMovements(gomock.Any(), "", date).
+			Return([]movementModel{
+				{AccountID: accountID, TotalCredits: 50, P2PCredits: 50, Balance: 140},
+				{AccountID: otherAccountID, TotalDebits: 10, P2PDebits: 10, Balance: -10},
+			}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Latest: true}).
+			Return([]businessDayModel{{Date: date, Status: ClosedStatus}}, nil)
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), businessDayFilter{Status: AdjustingStatus, Latest: true}).
+			Return(nil, nil)
+
+		tb, err := svc.GetTrialBalance(ctx, date)
+		assert.NoError(t, err)
+		assert.False(t, tb.Balanced)
+		assert.Equal(t, []Discrepancy{
+			{AccountID: accountID, Reason: OpeningMismatchReason, Recorded: 100, Expected: 90},
+			{Reason: P2PUnbalancedReason, Recorded: 50, Expected: 10},
+			{AccountID: accountID, Reason: ClosingMismatchReason, Recorded: 150, Expected: 140},
+			{AccountID: otherAccountID, Reason: DebitsMismatchReason, Recorded: 0, Expected: 10},
+			{AccountID: otherAccountID, Reason: ClosingMismatchReason, Recorded: 0, Expected: -10},
+		}, tb.Discrepancies)
+	})
+
+	t.Run("success trial balance of the tenant open for adjustments", func(t *testing.T) {
+		ctx := tenancy.WithTenant(ctx, tenancy.Tenant{ID: "brand-a"})
+
+		expectUnitOfWork()
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), byDate).
+			Return([]businessDayModel{{Date: date, Status: AdjustingStatus}}, nil)
+		repoMock.EXPECT().
+			ListBalances(gomock.Any(), "brand-a", date).
+			Return([]balanceModel{}, nil)
+		repoMock.EXPECT().GetByFilter(gomock.Any(), byPreviousDate).Return(nil, nil)
+		repoMock.EXPECT().
+			ListMovements(gomock.Any(), "brand-a", date).
+			Return([]movementModel{{AccountID: uuid.New(), TotalCredits: 10}}, nil)
+
+		tb, err := svc.GetTrialBalance(ctx, date)
+		assert.NoError(t, err)
+		assert.Empty(t, tb.Balances)
+		assert.True(t, tb.Balanced)
+	})
+}
@@ -0,0 +1,125 @@
+package dayclosejob
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/dayclosejob/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+var Module = fx.Options(
+	// Infra
+	fx.Provide(
+		environment.NewEnvironment,
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			return database.Setup(lc, t, m, database.Config{MasterURL: e.DatabaseURL})
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+		},
+	),
+	// Domains
+	fx.Provide(
+		database.NewUnitOfWork,
+		businessdays.NewRepository,
+		businessdays.NewService,
+		NewJob,
+	),
+	// Startup applications
+	fx.Invoke(func(
+		env environment.Environment,
+	) (*zap.Logger, error) {
+		return setupLogger(
+			env.Service,
+			env.Version,
+			env.Environment,
+		)
+	}),
+	fx.Invoke(runJob),
+)
+
+func setupLogger(service, version, env string) (*zap.Logger, error) {
+	logger := zap.L().With(
+		zap.String("service", service),
+		zap.String("version", version),
+		zap.String("env", env),
+	)
+	_ = zap.ReplaceGlobals(logger)
+	return logger, nil
+}
+
+// runJob runs the job once the application started and shuts it down when the job finishes, with exit code 1 when
+// the job failed.
+func runJob(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	env environment.Environment,
+	j Job,
+) error {
+	through := businessdays.PreviousDay(time.Now())
+	if env.BusinessDate != "" {
+		var err error
+		through, err = businessdays.ParseDate(env.BusinessDate)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+
+				zap.L().Info("day_close_job_up", zap.String("through", businessdays.FormatDate(through)))
+
+				exitCode := 0
+				if err := j.Run(ctx, through); err != nil {
+					zap.L().Error("day_close_job_error", zap.Error(err))
+					exitCode = 1
+				}
+
+				if err := shutdowner.Shutdown(fx.ExitCode(exitCode)); err != nil {
+					zap.L().Error("day_close_job_shutdown_error", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			// Stopped by a signal the job is cancelled, the days closed so far stay closed and running it again
+			// closes the remaining ones.
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+
+	return nil
+}
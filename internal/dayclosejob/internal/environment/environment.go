@@ -0,0 +1,29 @@
+package environment
+
+import (
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/gosidekick/goconfig"
+)
+
+// Environment this object keep the all environment variables.
+type Environment struct {
+	// Database
+	DatabaseURL string `cfg:"DATABASE_URL" cfgRequired:"true"`
+	// Open Telemetry
+	OtelCollectorHost string `cfg:"OTEL_COLLECTOR_HOST" cfgRequired:"true"`
+	// Tracing is the sampling and the exporter of the spans (OTEL_EXPORTER, OTEL_SAMPLE_RATIO, ...).
+	Tracing tracer.Config `cfg:"OTEL"`
+	// Application
+	Environment string `cfg:"ENVIRONMENT" cfgRequired:"true"`
+	Service     string `cfg:"SERVICE" cfgRequired:"true"`
+	Version     string `cfg:"VERSION" cfgRequired:"true"`
+	// Job
+	// BusinessDate is the business date (yyyy-mm-dd) to close the days through, the previous day when empty.
+	BusinessDate string `cfg:"BUSINESS_DATE"`
+}
+
+func NewEnvironment() (Environment, error) {
+	env := &Environment{}
+	err := goconfig.Parse(env)
+	return *env, err
+}
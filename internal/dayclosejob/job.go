@@ -0,0 +1,118 @@
+package dayclosejob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+)
+
+var ErrTrialBalanceUnbalanced = errors.New("the trial balance of a closed business day is unbalanced")
+
+// Job closes the business days of the ledger.
+type Job interface {
+	Run(ctx context.Context, through time.Time) error
+}
+
+type job struct {
+	tracer          tracer.Tracer
+	businessDaysSvc businessdays.Service
+}
+
+func NewJob(t tracer.Tracer, bds businessdays.Service) Job {
+	return job{tracer: t, businessDaysSvc: bds}
+}
+
+// Run closes, in order, the business days after the latest closed one through the given date, or only the given date
+// when no day was closed yet. The days already closed are kept as they are, including the ones open for adjustments,
+// so running it again for the same date changes nothing. It stops at the first failure and reports the unbalanced
+// trial balances of the days it closed.
+func (j job) Run(ctx context.Context, through time.Time) error {
+	ctx, span := j.tracer.Span(ctx)
+	defer span.End()
+
+	through = businessdays.StartOfDay(through)
+
+	date := through
+	latest, err := j.businessDaysSvc.GetLatest(ctx)
+	if err == nil {
+		date = latest.Date.AddDate(0, 0, 1)
+	} else if !errors.Is(err, businessdays.ErrBusinessDayNotFound) {
+		zapctx.L(ctx).Error("day_close_job_get_latest_error", zap.Error(err))
+		span.RecordError(err)
+		return err
+	}
+
+	var closed, unbalanced int
+	for ; !date.After(through); date = date.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		_, err := j.businessDaysSvc.Close(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"day_close_job_close_error",
+				zap.String("date", businessdays.FormatDate(date)),
+				zap.Error(err),
+			)
+			span.RecordError(err)
+			return err
+		}
+		closed++
+
+		tb, err := j.businessDaysSvc.GetTrialBalance(ctx, date)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"day_close_job_trial_balance_error",
+				zap.String("date", businessdays.FormatDate(date)),
+				zap.Error(err),
+			)
+			span.RecordError(err)
+			return err
+		}
+
+		zapctx.L(ctx).Info(
+			"day_close_job_day_closed",
+			zap.String("date", businessdays.FormatDate(date)),
+			zap.Int("accounts", len(tb.Balances)),
+			zap.Float64("total_credits", tb.TotalCredits),
+			zap.Float64("total_debits", tb.TotalDebits),
+			zap.Float64("closing_balance", tb.ClosingBalance),
+			zap.Bool("balanced", tb.Balanced),
+		)
+
+		if !tb.Balanced {
+			unbalanced++
+			for _, d := range tb.Discrepancies {
+				zapctx.L(ctx).Error(
+					"day_close_job_trial_balance_discrepancy",
+					zap.String("date", businessdays.FormatDate(date)),
+					zap.String("account_id", d.AccountID.String()),
+					zap.String("reason", string(d.Reason)),
+					zap.Float64("recorded", d.Recorded),
+					zap.Float64("expected", d.Expected),
+				)
+			}
+		}
+	}
+
+	zapctx.L(ctx).Info(
+		"day_close_job_finished",
+		zap.String("through", businessdays.FormatDate(through)),
+		zap.Int("closed", closed),
+		zap.Int("unbalanced", unbalanced),
+	)
+
+	if unbalanced > 0 {
+		span.RecordError(ErrTrialBalanceUnbalanced)
+		return ErrTrialBalanceUnbalanced
+	}
+
+	return nil
+}
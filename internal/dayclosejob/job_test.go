@@ -0,0 +1,71 @@
+//go:build unit
+
+package dayclosejob
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Run(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bdSvcMock := businessdays.NewMockService(ctrl)
+
+	j := NewJob(tracer.NewNoop(), bdSvcMock)
+
+	latest := time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("close the days after the latest closed one, in order", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			GetLatest(gomock.Any()).
+			Return(businessdays.BusinessDay{Date: latest, Status: businessdays.ClosedStatus}, nil)
+
+		for _, date := range []time.Time{latest.AddDate(0, 0, 1), latest.AddDate(0, 0, 2)} {
+			gomock.InOrder(
+				bdSvcMock.EXPECT().Close(gomock.Any(), date).Return(businessdays.BusinessDay{Date: date}, nil),
+				bdSvcMock.EXPECT().GetTrialBalance(gomock.Any(), date).Return(businessdays.TrialBalance{Balanced: true}, nil),
+			)
+		}
+
+		err := j.Run(ctx, latest.AddDate(0, 0, 2).Add(time.Hour))
+		assert.NoError(t, err)
+	})
+
+	t.Run("close nothing, already closed", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			GetLatest(gomock.Any()).
+			Return(businessdays.BusinessDay{Date: latest, Status: businessdays.AdjustingStatus}, nil)
+
+		err := j.Run(ctx, latest)
+		assert.NoError(t, err)
+	})
+
+	t.Run("close the date only when no day was closed, reporting unbalanced", func(t *testing.T) {
+		bdSvcMock.EXPECT().GetLatest(gomock.Any()).Return(businessdays.BusinessDay{}, businessdays.ErrBusinessDayNotFound)
+		bdSvcMock.EXPECT().Close(gomock.Any(), latest).Return(businessdays.BusinessDay{Date: latest}, nil)
+		bdSvcMock.EXPECT().GetTrialBalance(gomock.Any(), latest).Return(businessdays.TrialBalance{}, nil)
+
+		err := j.Run(ctx, latest)
+		assert.ErrorIs(t, err, ErrTrialBalanceUnbalanced)
+	})
+
+	t.Run("stop at the first failure", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			GetLatest(gomock.Any()).
+			Return(businessdays.BusinessDay{Date: latest, Status: businessdays.ClosedStatus}, nil)
+		bdSvcMock.EXPECT().Close(gomock.Any(), latest.AddDate(0, 0, 1)).Return(businessdays.BusinessDay{}, sql.ErrConnDone)
+
+		err := j.Run(ctx, latest.AddDate(0, 0, 3))
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+}
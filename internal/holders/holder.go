@@ -1,11 +1,17 @@
 package holders
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Holder struct {
 	ID             uuid.UUID
 	Name           string
 	DocumentNumber string
+	TenantID       string
+	CreatedAt      time.Time
 }
 
 func newHolder(model HolderModel) Holder {
@@ -13,5 +19,7 @@ func newHolder(model HolderModel) Holder {
 		ID:             model.ID,
 		Name:           model.Name,
 		DocumentNumber: model.DocumentNumber,
+		TenantID:       model.TenantID,
+		CreatedAt:      model.CreatedAt,
 	}
 }
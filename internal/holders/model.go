@@ -3,6 +3,7 @@ package holders
 import (
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -14,6 +15,7 @@ type HolderModel struct {
 	ID             uuid.UUID `bun:"id,pk"`
 	Name           string    `bun:"name"`
 	DocumentNumber string    `bun:"document_number"`
+	TenantID       string    `bun:"tenant_id"`
 	CreatedAt      time.Time `bun:"created_at,notnull"`
 	UpdatedAt      time.Time `bun:"updated_at,nullzero"`
 }
@@ -23,6 +25,7 @@ func newHolderModel(h Holder) HolderModel {
 		ID:             h.ID,
 		Name:           h.Name,
 		DocumentNumber: h.DocumentNumber,
+		TenantID:       h.TenantID,
 	}
 }
 
@@ -30,6 +33,8 @@ type HolderFilter struct {
 	ID             uuid.NullUUID
 	Name           string
 	DocumentNumber string
+	// TenantID keeps only the holders of the tenant, the holders of all tenants when empty.
+	TenantID       string
 	CreatedAtBegin database.NullTime
 	CreatedAtEnd   database.NullTime
 	UpdatedAtBegin database.NullTime
@@ -41,4 +46,12 @@ type ListFilter struct {
 	Page           int
 	Size           int
 	DocumentNumber string
+	// ID keeps only the holder with this id, the listings of the principals bound to a holder.
+	ID uuid.NullUUID
+	// TenantID keeps only the holders of the tenant, the holders of all tenants when empty.
+	TenantID string
+	// Cursor selects the page by keyset instead of by Page, it has precedence over Page when not nil.
+	Cursor *cursor.Cursor
+	// WithTotal counts all items matched by the filter, what is expensive for big listings.
+	WithTotal bool
 }
@@ -4,11 +4,20 @@ import (
 	"context"
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/google/uuid"
 )
 
+// queryTimeout is the default deadline of the queries of the holders.
+const queryTimeout = 2 * time.Second
+
+// constraints are the errors of the constraints of the holders, the document number is unique in each tenant.
+var constraints = database.Constraints{
+	"holders_tenant_id_document_number": ErrHolderAlreadyExists,
+}
+
 type Repository interface {
 	Create(ctx context.Context, model HolderModel) (HolderModel, error)
 	Update(ctx context.Context, model HolderModel) (HolderModel, error)
@@ -32,15 +41,19 @@ func (r repository) Create(ctx context.Context, model HolderModel) (HolderModel,
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	model.ID = uuid.New()
 	model.CreatedAt = time.Now().UTC()
 
-	_, err := r.db.Master().
+	_, err := r.db.Master(ctx).
 		NewInsert().
 		Model(&model).
 		Returning("*").
 		Exec(ctx)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return HolderModel{}, err
 	}
@@ -52,16 +65,24 @@ func (r repository) Update(ctx context.Context, model HolderModel) (HolderModel,
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	model.UpdatedAt = time.Now().UTC()
 
-	_, err := r.db.Master().
+	updateQuery := r.db.Master(ctx).
 		NewUpdate().
 		Model(&model).
 		WherePK().
 		Returning("*").
-		OmitZero().
-		Exec(ctx)
+		OmitZero()
+	if model.TenantID != "" {
+		updateQuery.Where("tenant_id = ?", model.TenantID)
+	}
+
+	_, err := updateQuery.Exec(ctx)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return HolderModel{}, err
 	}
@@ -73,7 +94,10 @@ func (r repository) GetByFilter(ctx context.Context, filter HolderFilter) ([]Hol
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
-	selectQuery := r.db.Replica().NewSelect().Model(&HolderModel{})
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	selectQuery := r.db.Replica(ctx).NewSelect().Model(&HolderModel{})
 	if filter.ID.Valid {
 		selectQuery.Where("id = ?", filter.ID.UUID)
 	}
@@ -82,6 +106,10 @@ func (r repository) GetByFilter(ctx context.Context, filter HolderFilter) ([]Hol
 		selectQuery.Where("name = ?", filter.Name)
 	}
 
+	if filter.TenantID != "" {
+		selectQuery.Where("tenant_id = ?", filter.TenantID)
+	}
+
 	if filter.DocumentNumber != "" {
 		selectQuery.Where("document_number = ?", filter.DocumentNumber)
 	}
@@ -105,6 +133,7 @@ func (r repository) GetByFilter(ctx context.Context, filter HolderFilter) ([]Hol
 	var accs []HolderModel
 	err := selectQuery.Scan(ctx, &accs)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return nil, err
 	}
@@ -116,6 +145,9 @@ func (r repository) ListByFilter(ctx context.Context, filter ListFilter) (int, [
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	page := filter.Page
 	if page == 0 {
 		page = 1
@@ -126,28 +158,59 @@ func (r repository) ListByFilter(ctx context.Context, filter ListFilter) (int, [
 		size = 20
 	}
 
-	selectQuery := r.db.Replica().
+	selectQuery := r.db.Replica(ctx).
 		NewSelect().
 		Model(&HolderModel{}).
-		Limit(size).
-		Offset((page - 1) * size)
+		Limit(size)
 
 	if filter.DocumentNumber != "" {
 		selectQuery.Where("document_number = ?", filter.DocumentNumber)
 	}
 
-	if filter.Sort == 0 {
-		selectQuery.Order("created_at ASC")
-	} else if filter.Sort > 0 {
-		selectQuery.Order("created_at DESC")
+	if filter.ID.Valid {
+		selectQuery.Where("id = ?", filter.ID.UUID)
+	}
+
+	if filter.TenantID != "" {
+		selectQuery.Where("tenant_id = ?", filter.TenantID)
+	}
+
+	var total int
+	var err error
+	if filter.WithTotal {
+		// Counted before the keyset restriction, the total is about the whole listing and not what is after the cursor.
+		total, err = selectQuery.Count(ctx)
+		if err != nil {
+			err = database.TranslateError(err, constraints)
+			span.RecordError(err)
+			return 0, nil, err
+		}
+	}
+
+	var reversed bool
+	if filter.Cursor != nil {
+		reversed = cursor.Keyset(selectQuery, *filter.Cursor, filter.Sort > 0, "created_at", "id")
+	} else {
+		selectQuery.Offset((page - 1) * size)
+
+		if filter.Sort == 0 {
+			selectQuery.Order("created_at ASC")
+		} else if filter.Sort > 0 {
+			selectQuery.Order("created_at DESC")
+		}
 	}
 
 	var accs []HolderModel
-	total, err := selectQuery.ScanAndCount(ctx, &accs)
+	err = selectQuery.Scan(ctx, &accs)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return 0, nil, err
 	}
 
+	if reversed {
+		cursor.Reverse(accs)
+	}
+
 	return total, accs, nil
 }
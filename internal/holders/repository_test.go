@@ -12,6 +12,7 @@ import (
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/google/uuid"
@@ -33,7 +34,7 @@ func TestRepository(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	db, err := database.New(tracer.NewNoop(), url, url)
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 	assert.NoError(t, err)
 
 	repo := NewRepository(tracer.NewNoop(), db)
@@ -52,6 +53,16 @@ func TestRepository(t *testing.T) {
 		assert.Equal(t, holder.DocumentNumber, created.DocumentNumber)
 	})
 
+	t.Run("create holder with an existing document number", func(t *testing.T) {
+		holder := Holder{Name: gofakeit.Name(), DocumentNumber: gofakeit.SSN()}
+		_, err := repo.Create(ctx, newHolderModel(holder))
+		assert.NoError(t, err)
+
+		_, err = repo.Create(ctx, newHolderModel(holder))
+		assert.ErrorIs(t, err, ErrHolderAlreadyExists)
+		assert.ErrorIs(t, err, database.ErrUniqueViolation)
+	})
+
 	t.Run("create and update holder", func(t *testing.T) {
 		holder := Holder{Name: gofakeit.Name(), DocumentNumber: gofakeit.SSN()}
 		created, err := repo.Create(
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"github.com/google/uuid"
@@ -13,6 +15,7 @@ import (
 var (
 	ErrHolderNotFound      = errors.New("no holders found with these filters")
 	ErrMultpleHoldersFound = errors.New("multiple holders found with these filters")
+	ErrHolderAlreadyExists = errors.New("a holder with this document_number already exists")
 )
 
 type Service interface {
@@ -35,6 +38,14 @@ func (s service) Create(ctx context.Context, holder Holder) (Holder, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	// The principals bound to a holder can not create other holders.
+	if err := authz.AuthorizeHolder(ctx); err != nil {
+		span.RecordError(err)
+		return Holder{}, err
+	}
+
+	holder.TenantID = tenancy.Current(ctx).ID
+
 	model, err := s.repository.Create(ctx, newHolderModel(holder))
 	if err != nil {
 		zapctx.L(ctx).Error("holder_service_create_repository_error", zap.Error(err))
@@ -50,6 +61,14 @@ func (s service) Update(ctx context.Context, holder Holder) (Holder, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	if err := authz.AuthorizeHolder(ctx, holder.ID); err != nil {
+		span.RecordError(err)
+		return Holder{}, err
+	}
+
+	// Only the holders of the tenant are updated, the tenant of the holder never changes.
+	holder.TenantID = tenancy.FilterID(ctx)
+
 	_, err := s.repository.Update(ctx, newHolderModel(holder))
 	if err != nil {
 		zapctx.L(ctx).Error("holder_service_update_repository_error", zap.Error(err))
@@ -64,6 +83,11 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Holder, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	if err := authz.AuthorizeHolder(ctx, id); err != nil {
+		span.RecordError(err)
+		return Holder{}, err
+	}
+
 	models, err := s.repository.GetByFilter(
 		ctx,
 		HolderFilter{
@@ -71,6 +95,7 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Holder, error) {
 				UUID:  id,
 				Valid: true,
 			},
+			TenantID: tenancy.FilterID(ctx),
 		},
 	)
 	if err != nil {
@@ -94,10 +119,17 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Holder, error) {
 	return newHolder(models[0]), nil
 }
 
+// List lists the holders of the filter in the tenant, only the holder of the principal when it is bound to a holder.
 func (s service) List(ctx context.Context, filter ListFilter) (int, []Holder, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	filter.TenantID = tenancy.FilterID(ctx)
+
+	if holderID, bound := authz.Holder(ctx); bound {
+		filter.ID = uuid.NullUUID{UUID: holderID, Valid: true}
+	}
+
 	total, models, err := s.repository.ListByFilter(ctx, filter)
 	if err != nil {
 		zapctx.L(ctx).Error(
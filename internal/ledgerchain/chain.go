@@ -0,0 +1,97 @@
+package ledgerchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GlobalChain is the chain of all transactions, the chain of an account is named by the account id.
+const GlobalChain = "global"
+
+// Genesis is the previous hash of the first link of every chain.
+var Genesis = strings.Repeat("0", sha256.Size*2)
+
+// TransactionContent is the content of a transaction covered by the hash chains. Any change to these columns of a
+// chained transaction breaks its links.
+type TransactionContent struct {
+	ID          uuid.UUID
+	From        uuid.UUID
+	To          uuid.UUID
+	Type        string
+	Amount      float64
+	Description string
+	Adjustment  bool
+	CreatedAt   time.Time
+}
+
+// Hash returns the SHA-256 of the canonical encoding of the content: the fields in a fixed order, each one prefixed
+// by its length. The date is encoded in UTC with microseconds, the precision it is stored with.
+func (c TransactionContent) Hash() string {
+	fields := []string{
+		uuidString(c.ID),
+		uuidString(c.From),
+		uuidString(c.To),
+		c.Type,
+		strconv.FormatFloat(c.Amount, 'f', -1, 64),
+		c.Description,
+		strconv.FormatBool(c.Adjustment),
+		c.CreatedAt.UTC().Format("2006-01-02T15:04:05.000000Z"),
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(strconv.Itoa(len(field)))
+		b.WriteByte(':')
+		b.WriteString(field)
+	}
+
+	return hashOf(b.String())
+}
+
+// chains returns the chains of the transaction in the order they are locked: the chains of its accounts, by their
+// names, and then the global one. Every transaction locks its chains in the same order, so the appends never deadlock,
+// and the global chain, shared by all of them, is locked for the shortest time.
+func (c TransactionContent) chains() []string {
+	chains := make([]string, 0, 3)
+	if c.From != uuid.Nil {
+		chains = append(chains, c.From.String())
+	}
+	if c.To != uuid.Nil {
+		chains = append(chains, c.To.String())
+	}
+	sort.Strings(chains)
+	return append(chains, GlobalChain)
+}
+
+// chainLock returns the key of the advisory lock of the chain. The global chain keeps the key of the former lock of
+// all the chains, so the instances still taking it never append to the global chain at the same time as the others.
+func chainLock(chain string) string {
+	if chain == GlobalChain {
+		return "chain_links"
+	}
+	return "chain_links:" + chain
+}
+
+// LinkHash returns the hash of a link, chaining the previous hash to the content hash. The chain and the sequence are
+// part of the hash, so a link can not be moved to another chain or position.
+func LinkHash(chain string, sequence int64, previousHash, contentHash string) string {
+	return hashOf(strings.Join([]string{chain, strconv.FormatInt(sequence, 10), previousHash, contentHash}, ":"))
+}
+
+func hashOf(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,108 @@
+//go:build unit
+
+package ledgerchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionContent_Hash(t *testing.T) {
+	content := TransactionContent{
+		ID:          uuid.MustParse("1b7e3c1e-0a43-4f4b-9d3a-3a1f1d2b9c10"),
+		To:          uuid.MustParse("5a0f8d0c-8a0e-4c55-8d57-3c8d4c1f0b21"),
+		Type:        "CREDIT",
+		Amount:      10.5,
+		Description: "salary",
+		CreatedAt:   time.Date(2022, 1, 10, 12, 30, 0, 123456000, time.UTC),
+	}
+
+	t.Run("same content, same hash", func(t *testing.T) {
+		same := content
+		same.CreatedAt = content.CreatedAt.In(time.FixedZone("BRT", -3*60*60))
+		assert.Equal(t, content.Hash(), same.Hash())
+		assert.Len(t, content.Hash(), 64)
+	})
+
+	t.Run("any change, another hash", func(t *testing.T) {
+		changes := []func(c *TransactionContent){
+			func(c *TransactionContent) { c.Amount = 10.51 },
+			func(c *TransactionContent) { c.Description = "salary!" },
+			func(c *TransactionContent) { c.From, c.To = c.To, uuid.Nil },
+			func(c *TransactionContent) { c.Adjustment = true },
+			func(c *TransactionContent) { c.CreatedAt = c.CreatedAt.Add(time.Microsecond) },
+		}
+		for _, change := range changes {
+			changed := content
+			change(&changed)
+			assert.NotEqual(t, content.Hash(), changed.Hash())
+		}
+	})
+
+	t.Run("fields are not ambiguous", func(t *testing.T) {
+		a := TransactionContent{Type: "P2P", Description: "1:x"}
+		b := TransactionContent{Type: "P2P1:x", Description: ""}
+		assert.NotEqual(t, a.Hash(), b.Hash())
+	})
+}
+
+func TestLinkHash(t *testing.T) {
+	hash := LinkHash(GlobalChain, 1, Genesis, "content")
+	assert.Len(t, hash, 64)
+	assert.NotEqual(t, hash, LinkHash(GlobalChain, 2, Genesis, "content"))
+	assert.NotEqual(t, hash, LinkHash(uuid.NewString(), 1, Genesis, "content"))
+}
+
+func TestSigner(t *testing.T) {
+	t.Run("fail signer, invalid seed", func(t *testing.T) {
+		_, err := NewSigner("c2hvcnQ=")
+		assert.ErrorIs(t, err, ErrInvalidSigningKey)
+	})
+
+	t.Run("fail sign, without key", func(t *testing.T) {
+		s, err := NewSigner("")
+		assert.NoError(t, err)
+
+		_, err = s.Sign([]byte("message"))
+		assert.ErrorIs(t, err, ErrSigningKeyRequired)
+	})
+
+	t.Run("success sign and verify", func(t *testing.T) {
+		s, err := NewSigner("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+		assert.NoError(t, err)
+
+		signature, err := s.Sign([]byte("message"))
+		assert.NoError(t, err)
+		assert.True(t, VerifySignature(s.PublicKey(), signature, []byte("message")))
+		assert.False(t, VerifySignature(s.PublicKey(), signature, []byte("tampered")))
+	})
+}
+
+func TestTransactionContent_chains(t *testing.T) {
+	low := uuid.MustParse("1b7e3c1e-0a43-4f4b-9d3a-3a1f1d2b9c10")
+	high := uuid.MustParse("5a0f8d0c-8a0e-4c55-8d57-3c8d4c1f0b21")
+
+	t.Run("accounts by name and the global chain last", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{low.String(), high.String(), GlobalChain},
+			TransactionContent{From: high, To: low}.chains(),
+		)
+		assert.Equal(t,
+			[]string{low.String(), high.String(), GlobalChain},
+			TransactionContent{From: low, To: high}.chains(),
+		)
+	})
+
+	t.Run("credit and debit", func(t *testing.T) {
+		assert.Equal(t, []string{high.String(), GlobalChain}, TransactionContent{To: high}.chains())
+		assert.Equal(t, []string{low.String(), GlobalChain}, TransactionContent{From: low}.chains())
+	})
+
+	t.Run("a lock per chain", func(t *testing.T) {
+		assert.Equal(t, "chain_links", chainLock(GlobalChain))
+		assert.Equal(t, "chain_links:"+low.String(), chainLock(low.String()))
+	})
+}
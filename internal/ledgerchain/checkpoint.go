@@ -0,0 +1,40 @@
+package ledgerchain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+)
+
+// Checkpoint is the signed head of the global chain at the end of a day. Published externally, it proves the chain
+// up to the head was not rewritten since then.
+type Checkpoint struct {
+	Date      time.Time `json:"date"`
+	Sequence  int64     `json:"sequence"`
+	Hash      string    `json:"hash"`
+	Signature string    `json:"signature"`
+	PublicKey string    `json:"public_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newCheckpoint(model checkpointModel) Checkpoint {
+	return Checkpoint{
+		Date:      model.Date,
+		Sequence:  model.Sequence,
+		Hash:      model.Hash,
+		Signature: model.Signature,
+		PublicKey: model.PublicKey,
+		CreatedAt: model.CreatedAt,
+	}
+}
+
+// Message returns the signed message of the checkpoint.
+func (c Checkpoint) Message() []byte {
+	return []byte(fmt.Sprintf("ledger-exp checkpoint\n%s\n%d\n%s", businessdays.FormatDate(c.Date), c.Sequence, c.Hash))
+}
+
+// Verify verifies the signature of the checkpoint with its public key.
+func (c Checkpoint) Verify() bool {
+	return VerifySignature(c.PublicKey, c.Signature, c.Message())
+}
@@ -0,0 +1,83 @@
+package ledgerchain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type linkModel struct {
+	bun.BaseModel `bun:"table:chain_links,alias:lnk"`
+
+	Chain         string    `bun:"chain,pk"`
+	Sequence      int64     `bun:"sequence,pk"`
+	TransactionID uuid.UUID `bun:"transaction_id"`
+	ContentHash   string    `bun:"content_hash"`
+	PreviousHash  string    `bun:"previous_hash"`
+	Hash          string    `bun:"hash"`
+	CreatedAt     time.Time `bun:"created_at,notnull"`
+}
+
+// chainedTransactionModel is a link with the current content of its transaction, empty when the transaction does
+// not exist anymore.
+type chainedTransactionModel struct {
+	linkModel `bun:",extend"`
+
+	TrxID          uuid.UUID `bun:"trx_id,scanonly"`
+	TrxFrom        uuid.UUID `bun:"trx_from_account_id,scanonly"`
+	TrxTo          uuid.UUID `bun:"trx_to_account_id,scanonly"`
+	TrxType        string    `bun:"trx_type,scanonly"`
+	TrxAmount      float64   `bun:"trx_amount,scanonly"`
+	TrxDescription string    `bun:"trx_description,scanonly"`
+	TrxAdjustment  bool      `bun:"trx_adjustment,scanonly"`
+	TrxCreatedAt   time.Time `bun:"trx_created_at,scanonly"`
+}
+
+func (m chainedTransactionModel) content() TransactionContent {
+	return TransactionContent{
+		ID:          m.TrxID,
+		From:        m.TrxFrom,
+		To:          m.TrxTo,
+		Type:        m.TrxType,
+		Amount:      m.TrxAmount,
+		Description: m.TrxDescription,
+		Adjustment:  m.TrxAdjustment,
+		CreatedAt:   m.TrxCreatedAt,
+	}
+}
+
+type checkpointModel struct {
+	bun.BaseModel `bun:"table:chain_checkpoints,alias:cp"`
+
+	Date      time.Time `bun:"date,pk"`
+	Sequence  int64     `bun:"sequence"`
+	Hash      string    `bun:"hash"`
+	Signature string    `bun:"signature"`
+	PublicKey string    `bun:"public_key"`
+	CreatedAt time.Time `bun:"created_at,notnull"`
+}
+
+type unchainedTransactionModel struct {
+	ID            uuid.UUID `bun:"id"`
+	FromAccountID uuid.UUID `bun:"from_account_id"`
+	ToAccountID   uuid.UUID `bun:"to_account_id"`
+	Type          string    `bun:"type"`
+	Amount        float64   `bun:"amount"`
+	Description   string    `bun:"description"`
+	Adjustment    bool      `bun:"adjustment"`
+	CreatedAt     time.Time `bun:"created_at"`
+}
+
+func (m unchainedTransactionModel) content() TransactionContent {
+	return TransactionContent{
+		ID:          m.ID,
+		From:        m.FromAccountID,
+		To:          m.ToAccountID,
+		Type:        m.Type,
+		Amount:      m.Amount,
+		Description: m.Description,
+		Adjustment:  m.Adjustment,
+		CreatedAt:   m.CreatedAt,
+	}
+}
@@ -0,0 +1,250 @@
+package ledgerchain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
+)
+
+// AppendLinks links the transaction to the chains of its accounts and to the global chain. It must run in the same
+// database transaction that inserts the transaction, which holds the lock of each chain until it commits: the links
+// of a chain are appended one transaction at a time, so every link sees the committed head of its chain. The
+// transactions of different accounts only wait for each other on the global chain, locked last. The time of each
+// link is read once its lock is held and never goes before the time of the head, so the links of a chain are ordered
+// by time as they are by sequence.
+func AppendLinks(ctx context.Context, db bun.IDB, content TransactionContent) error {
+	contentHash := content.Hash()
+
+	for _, chain := range content.chains() {
+		_, err := db.NewRaw("SELECT pg_advisory_xact_lock(hashtext(?))", chainLock(chain)).Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		link := linkModel{
+			Chain:         chain,
+			Sequence:      1,
+			TransactionID: content.ID,
+			ContentHash:   contentHash,
+			PreviousHash:  Genesis,
+			CreatedAt:     time.Now().UTC(),
+		}
+
+		var head linkModel
+		err = db.NewSelect().
+			Model(&head).
+			Where("lnk.chain = ?", chain).
+			Order("lnk.sequence DESC").
+			Limit(1).
+			Scan(ctx)
+		if err == nil {
+			link.Sequence = head.Sequence + 1
+			link.PreviousHash = head.Hash
+			if link.CreatedAt.Before(head.CreatedAt) {
+				link.CreatedAt = head.CreatedAt
+			}
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		link.Hash = LinkHash(link.Chain, link.Sequence, link.PreviousHash, link.ContentHash)
+
+		_, err = db.NewInsert().Model(&link).Exec(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type Repository interface {
+	IterateChain(ctx context.Context, chain string, fn func(chainedTransactionModel) error) error
+	GetUnchained(ctx context.Context, limit int) ([]unchainedTransactionModel, error)
+	Chain(ctx context.Context, content TransactionContent) error
+	GetHead(ctx context.Context, chain string, before time.Time) (linkModel, error)
+	CreateCheckpoint(ctx context.Context, model checkpointModel) (checkpointModel, bool, error)
+	GetCheckpoint(ctx context.Context, date time.Time) ([]checkpointModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+// IterateChain calls fn with the links of the chain in order, each one with the current content of its transaction.
+func (r repository) IterateChain(ctx context.Context, chain string, fn func(chainedTransactionModel) error) error {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	rows, err := r.db.Replica(ctx).
+		NewSelect().
+		Model((*chainedTransactionModel)(nil)).
+		ColumnExpr("lnk.*").
+		ColumnExpr("trx.id AS trx_id").
+		ColumnExpr("trx.from_account_id AS trx_from_account_id").
+		ColumnExpr("trx.to_account_id AS trx_to_account_id").
+		ColumnExpr("trx.type AS trx_type").
+		ColumnExpr("trx.amount AS trx_amount").
+		ColumnExpr("trx.description AS trx_description").
+		ColumnExpr("trx.adjustment AS trx_adjustment").
+		ColumnExpr("trx.created_at AS trx_created_at").
+		Join("LEFT JOIN transactions AS trx ON trx.id = lnk.transaction_id").
+		Where("lnk.chain = ?", chain).
+		Order("lnk.sequence ASC").
+		Rows(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model chainedTransactionModel
+		if err := r.db.Replica(ctx).ScanRow(ctx, rows, &model); err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetUnchained returns the oldest transactions not linked to the global chain.
+func (r repository) GetUnchained(ctx context.Context, limit int) ([]unchainedTransactionModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []unchainedTransactionModel
+	err := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		ColumnExpr("trx.id, trx.from_account_id, trx.to_account_id, trx.type, trx.amount").
+		ColumnExpr("trx.description, trx.adjustment, trx.created_at").
+		Where(
+			"NOT EXISTS (SELECT 1 FROM chain_links AS lnk WHERE lnk.transaction_id = trx.id AND lnk.chain = ?)",
+			GlobalChain,
+		).
+		Order("trx.created_at ASC", "trx.id ASC").
+		Limit(limit).
+		Scan(ctx, &models)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// Chain links an existing transaction to the chains.
+func (r repository) Chain(ctx context.Context, content TransactionContent) error {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	err := r.db.Master(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return AppendLinks(ctx, tx, content)
+	})
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetHead returns the last link appended to the chain before the given time, sql.ErrNoRows when there is none. It
+// reads from the master: a lagging replica would miss the last links and the checkpoint would seal an older head.
+func (r repository) GetHead(ctx context.Context, chain string, before time.Time) (linkModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var model linkModel
+	err := r.db.Replica(database.PinMaster(ctx)).
+		NewSelect().
+		Model(&model).
+		Where("lnk.chain = ?", chain).
+		Where("lnk.created_at < ?", before).
+		Order("lnk.sequence DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return linkModel{}, err
+	}
+
+	return model, nil
+}
+
+// CreateCheckpoint inserts the checkpoint unless the day already has one, in this case nothing is changed and
+// created is false.
+func (r repository) CreateCheckpoint(ctx context.Context, model checkpointModel) (checkpointModel, bool, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	model.CreatedAt = time.Now().UTC()
+
+	res, err := r.db.Master(ctx).
+		NewInsert().
+		Model(&model).
+		On("CONFLICT (date) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return checkpointModel{}, false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return checkpointModel{}, false, err
+	}
+
+	return model, rows > 0, nil
+}
+
+func (r repository) GetCheckpoint(ctx context.Context, date time.Time) ([]checkpointModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []checkpointModel
+	err := r.db.Replica(ctx).
+		NewSelect().
+		Model(&models).
+		Where("cp.date = ?", businessdays.FormatDate(date)).
+		Scan(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
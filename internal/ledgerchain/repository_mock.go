@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/ledgerchain/repository.go
+
+// Package ledgerchain is a generated GoMock package.
+package ledgerchain
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Chain mocks base method.
+func (m *MockRepository) Chain(ctx context.Context, content TransactionContent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Chain", ctx, content)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Chain indicates an expected call of Chain.
+func (mr *MockRepositoryMockRecorder) Chain(ctx, content interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Chain", reflect.TypeOf((*MockRepository)(nil).Chain), ctx, content)
+}
+
+// CreateCheckpoint mocks base method.
+func (m *MockRepository) CreateCheckpoint(ctx context.Context, model checkpointModel) (checkpointModel, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCheckpoint", ctx, model)
+	ret0, _ := ret[0].(checkpointModel)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateCheckpoint indicates an expected call of CreateCheckpoint.
+func (mr *MockRepositoryMockRecorder) CreateCheckpoint(ctx, model interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCheckpoint", reflect.TypeOf((*MockRepository)(nil).CreateCheckpoint), ctx, model)
+}
+
+// GetCheckpoint mocks base method.
+func (m *MockRepository) GetCheckpoint(ctx context.Context, date time.Time) ([]checkpointModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckpoint", ctx, date)
+	ret0, _ := ret[0].([]checkpointModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckpoint indicates an expected call of GetCheckpoint.
+func (mr *MockRepositoryMockRecorder) GetCheckpoint(ctx, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckpoint", reflect.TypeOf((*MockRepository)(nil).GetCheckpoint), ctx, date)
+}
+
+// GetHead mocks base method.
+func (m *MockRepository) GetHead(ctx context.Context, chain string, before time.Time) (linkModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHead", ctx, chain, before)
+	ret0, _ := ret[0].(linkModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHead indicates an expected call of GetHead.
+func (mr *MockRepositoryMockRecorder) GetHead(ctx, chain, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHead", reflect.TypeOf((*MockRepository)(nil).GetHead), ctx, chain, before)
+}
+
+// GetUnchained mocks base method.
+func (m *MockRepository) GetUnchained(ctx context.Context, limit int) ([]unchainedTransactionModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnchained", ctx, limit)
+	ret0, _ := ret[0].([]unchainedTransactionModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnchained indicates an expected call of GetUnchained.
+func (mr *MockRepositoryMockRecorder) GetUnchained(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnchained", reflect.TypeOf((*MockRepository)(nil).GetUnchained), ctx, limit)
+}
+
+// IterateChain mocks base method.
+func (m *MockRepository) IterateChain(ctx context.Context, chain string, fn func(chainedTransactionModel) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateChain", ctx, chain, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateChain indicates an expected call of IterateChain.
+func (mr *MockRepositoryMockRecorder) IterateChain(ctx, chain, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateChain", reflect.TypeOf((*MockRepository)(nil).IterateChain), ctx, chain, fn)
+}
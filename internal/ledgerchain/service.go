@@ -0,0 +1,217 @@
+package ledgerchain
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+)
+
+const unchainedPageSize = 100
+
+var (
+	ErrCheckpointNotFound = errors.New("no checkpoint found for this date")
+	ErrDayNotOver         = errors.New("checkpoints can only be created for days already over")
+
+	errBrokenLink = errors.New("broken link")
+)
+
+type Service interface {
+	Verify(ctx context.Context, chain string) (Verification, error)
+	ChainUnchained(ctx context.Context) (int, error)
+	Checkpoint(ctx context.Context, date time.Time) (Checkpoint, error)
+	GetCheckpoint(ctx context.Context, date time.Time) (Checkpoint, error)
+}
+
+type service struct {
+	tracer     tracer.Tracer
+	repository Repository
+	signer     Signer
+}
+
+func NewService(t tracer.Tracer, r Repository, s Signer) Service {
+	return service{tracer: t, repository: r, signer: s}
+}
+
+// Verify walks the chain from its first link, recomputing the hashes from the current content of the transactions,
+// and stops at the first broken link. The global chain is also broken by a transaction not linked to it, inserted
+// without going through the application.
+func (s service) Verify(ctx context.Context, chain string) (Verification, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	v := Verification{Chain: chain, Valid: true, Hash: Genesis}
+
+	err := s.repository.IterateChain(ctx, chain, func(model chainedTransactionModel) error {
+		var reason BrokenReason
+		switch {
+		case model.Sequence != v.Links+1:
+			reason = MissingLinkReason
+		case model.PreviousHash != v.Hash:
+			reason = PreviousHashReason
+		case model.Hash != LinkHash(model.Chain, model.Sequence, model.PreviousHash, model.ContentHash):
+			reason = LinkHashReason
+		case model.TrxID != model.TransactionID:
+			reason = MissingTransactionReason
+		case model.content().Hash() != model.ContentHash:
+			reason = ContentHashReason
+		default:
+			v.Links++
+			v.Hash = model.Hash
+			return nil
+		}
+
+		v.Valid = false
+		v.Broken = &BrokenLink{Sequence: model.Sequence, TransactionID: model.TransactionID, Reason: reason}
+		return errBrokenLink
+	})
+	if err != nil && !errors.Is(err, errBrokenLink) {
+		zapctx.L(ctx).Error("ledger_chain_service_iterate_repository_error", zap.String("chain", chain), zap.Error(err))
+		span.RecordError(err)
+		return Verification{}, err
+	}
+
+	if v.Valid && chain == GlobalChain {
+		unchained, err := s.repository.GetUnchained(ctx, 1)
+		if err != nil {
+			zapctx.L(ctx).Error("ledger_chain_service_unchained_repository_error", zap.Error(err))
+			span.RecordError(err)
+			return Verification{}, err
+		}
+
+		if len(unchained) > 0 {
+			v.Valid = false
+			v.Broken = &BrokenLink{TransactionID: unchained[0].ID, Reason: UnchainedReason}
+		}
+	}
+
+	if !v.Valid {
+		zapctx.L(ctx).Warn(
+			"ledger_chain_service_broken_link",
+			zap.String("chain", chain),
+			zap.Int64("sequence", v.Broken.Sequence),
+			zap.String("transaction_id", v.Broken.TransactionID.String()),
+			zap.String("reason", string(v.Broken.Reason)),
+		)
+	}
+
+	return v, nil
+}
+
+// ChainUnchained links the transactions not linked to the chains yet, oldest first, the ones created before the
+// chains existed. It returns how many were linked.
+func (s service) ChainUnchained(ctx context.Context) (int, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	var chained int
+	for {
+		models, err := s.repository.GetUnchained(ctx, unchainedPageSize)
+		if err != nil {
+			zapctx.L(ctx).Error("ledger_chain_service_unchained_repository_error", zap.Error(err))
+			span.RecordError(err)
+			return chained, err
+		}
+
+		for _, model := range models {
+			if err := s.repository.Chain(ctx, model.content()); err != nil {
+				zapctx.L(ctx).Error(
+					"ledger_chain_service_chain_repository_error",
+					zap.String("transaction_id", model.ID.String()),
+					zap.Error(err),
+				)
+				span.RecordError(err)
+				return chained, err
+			}
+			chained++
+		}
+
+		if len(models) < unchainedPageSize {
+			return chained, nil
+		}
+	}
+}
+
+// Checkpoint signs the head of the global chain at the end of the day. It is idempotent: when the day already has a
+// checkpoint, the stored one is returned as it is.
+func (s service) Checkpoint(ctx context.Context, date time.Time) (Checkpoint, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	date = businessdays.StartOfDay(date)
+	endOfDay := date.AddDate(0, 0, 1)
+	if endOfDay.After(time.Now()) {
+		span.RecordError(ErrDayNotOver)
+		return Checkpoint{}, ErrDayNotOver
+	}
+
+	existing, err := s.GetCheckpoint(ctx, date)
+	if err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrCheckpointNotFound) {
+		span.RecordError(err)
+		return Checkpoint{}, err
+	}
+
+	checkpoint := Checkpoint{Date: date, Hash: Genesis, PublicKey: s.signer.PublicKey()}
+
+	head, err := s.repository.GetHead(ctx, GlobalChain, endOfDay)
+	if err == nil {
+		checkpoint.Sequence = head.Sequence
+		checkpoint.Hash = head.Hash
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		zapctx.L(ctx).Error("ledger_chain_service_head_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Checkpoint{}, err
+	}
+
+	checkpoint.Signature, err = s.signer.Sign(checkpoint.Message())
+	if err != nil {
+		span.RecordError(err)
+		return Checkpoint{}, err
+	}
+
+	model, created, err := s.repository.CreateCheckpoint(ctx, checkpointModel{
+		Date:      checkpoint.Date,
+		Sequence:  checkpoint.Sequence,
+		Hash:      checkpoint.Hash,
+		Signature: checkpoint.Signature,
+		PublicKey: checkpoint.PublicKey,
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("ledger_chain_service_create_checkpoint_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Checkpoint{}, err
+	}
+
+	if !created {
+		// Created concurrently by someone else since we looked for it, the stored one is the official.
+		return s.GetCheckpoint(ctx, date)
+	}
+
+	return newCheckpoint(model), nil
+}
+
+func (s service) GetCheckpoint(ctx context.Context, date time.Time) (Checkpoint, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	models, err := s.repository.GetCheckpoint(ctx, businessdays.StartOfDay(date))
+	if err != nil {
+		zapctx.L(ctx).Error("ledger_chain_service_get_checkpoint_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Checkpoint{}, err
+	}
+
+	if len(models) != 1 {
+		span.RecordError(ErrCheckpointNotFound)
+		return Checkpoint{}, ErrCheckpointNotFound
+	}
+
+	return newCheckpoint(models[0]), nil
+}
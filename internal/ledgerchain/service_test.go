@@ -0,0 +1,208 @@
+//go:build unit
+
+package ledgerchain
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func chained(chain string, sequence int64, previousHash string, content TransactionContent) chainedTransactionModel {
+	contentHash := content.Hash()
+	return chainedTransactionModel{
+		linkModel: linkModel{
+			Chain:         chain,
+			Sequence:      sequence,
+			TransactionID: content.ID,
+			ContentHash:   contentHash,
+			PreviousHash:  previousHash,
+			Hash:          LinkHash(chain, sequence, previousHash, contentHash),
+		},
+		TrxID:          content.ID,
+		TrxFrom:        content.From,
+		TrxTo:          content.To,
+		TrxType:        content.Type,
+		TrxAmount:      content.Amount,
+		TrxDescription: content.Description,
+		TrxAdjustment:  content.Adjustment,
+		TrxCreatedAt:   content.CreatedAt,
+	}
+}
+
+func TestService_Verify(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	signer, _ := NewSigner("")
+
+	svc := NewService(tracer.NewNoop(), repoMock, signer)
+
+	accountID := uuid.New()
+	credit := TransactionContent{ID: uuid.New(), To: accountID, Type: "CREDIT", Amount: 10}
+	debit := TransactionContent{ID: uuid.New(), From: accountID, Type: "DEBIT", Amount: 5}
+	otherDebit := TransactionContent{ID: uuid.New(), From: accountID, Type: "DEBIT", Amount: 1}
+
+	first := chained(GlobalChain, 1, Genesis, credit)
+	second := chained(GlobalChain, 2, first.Hash, debit)
+	third := chained(GlobalChain, 3, second.Hash, otherDebit)
+
+	iterate := func(models ...chainedTransactionModel) {
+		repoMock.EXPECT().
+			IterateChain(gomock.Any(), GlobalChain, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, fn func(chainedTransactionModel) error) error {
+				for _, model := range models {
+					if err := fn(model); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+	}
+
+	t.Run("success verify, valid chain", func(t *testing.T) {
+		iterate(first, second, third)
+		repoMock.EXPECT().GetUnchained(gomock.Any(), 1).Return(nil, nil)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.Equal(t, Verification{Chain: GlobalChain, Valid: true, Links: 3, Hash: third.Hash}, v)
+	})
+
+	t.Run("success verify, transaction edited", func(t *testing.T) {
+		edited := second
+		edited.TrxAmount = 50
+		iterate(first, edited, third)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, int64(1), v.Links)
+		assert.Equal(t, first.Hash, v.Hash)
+		assert.Equal(t, &BrokenLink{Sequence: 2, TransactionID: second.TransactionID, Reason: ContentHashReason}, v.Broken)
+	})
+
+	t.Run("success verify, transaction deleted", func(t *testing.T) {
+		deleted := second
+		deleted.TrxID = uuid.Nil
+		iterate(first, deleted, third)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.Equal(t, MissingTransactionReason, v.Broken.Reason)
+	})
+
+	t.Run("success verify, link removed", func(t *testing.T) {
+		iterate(first, third)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.Equal(t, &BrokenLink{Sequence: 3, TransactionID: third.TransactionID, Reason: MissingLinkReason}, v.Broken)
+	})
+
+	t.Run("success verify, link rehashed without the previous one", func(t *testing.T) {
+		rehashed := second
+		rehashed.PreviousHash = Genesis
+		rehashed.Hash = LinkHash(GlobalChain, 2, Genesis, rehashed.ContentHash)
+		iterate(first, rehashed)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.Equal(t, PreviousHashReason, v.Broken.Reason)
+	})
+
+	t.Run("success verify, transaction inserted out of the chain", func(t *testing.T) {
+		unchainedID := uuid.New()
+		iterate(first)
+		repoMock.EXPECT().GetUnchained(gomock.Any(), 1).Return([]unchainedTransactionModel{{ID: unchainedID}}, nil)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.NoError(t, err)
+		assert.Equal(t, &BrokenLink{TransactionID: unchainedID, Reason: UnchainedReason}, v.Broken)
+	})
+
+	t.Run("fail verify, repository error", func(t *testing.T) {
+		repoMock.EXPECT().IterateChain(gomock.Any(), GlobalChain, gomock.Any()).Return(sql.ErrConnDone)
+
+		v, err := svc.Verify(ctx, GlobalChain)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Empty(t, v)
+	})
+}
+
+func TestService_Checkpoint(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	signer, err := NewSigner("AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8=")
+	assert.NoError(t, err)
+
+	svc := NewService(tracer.NewNoop(), repoMock, signer)
+
+	date := time.Date(2022, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fail checkpoint, day not over", func(t *testing.T) {
+		cp, err := svc.Checkpoint(ctx, time.Now())
+		assert.ErrorIs(t, err, ErrDayNotOver)
+		assert.Empty(t, cp)
+	})
+
+	t.Run("success checkpoint, signed head of the day", func(t *testing.T) {
+		repoMock.EXPECT().GetCheckpoint(gomock.Any(), date).Return(nil, nil)
+		repoMock.EXPECT().
+			GetHead(gomock.Any(), GlobalChain, date.AddDate(0, 0, 1)).
+			Return(linkModel{Chain: GlobalChain, Sequence: 42, Hash: "head"}, nil)
+		repoMock.EXPECT().
+			CreateCheckpoint(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, model checkpointModel) (checkpointModel, bool, error) {
+				return model, true, nil
+			})
+
+		cp, err := svc.Checkpoint(ctx, date.Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, date, cp.Date)
+		assert.Equal(t, int64(42), cp.Sequence)
+		assert.Equal(t, "head", cp.Hash)
+		assert.Equal(t, signer.PublicKey(), cp.PublicKey)
+		assert.True(t, cp.Verify())
+
+		cp.Hash = "other"
+		assert.False(t, cp.Verify())
+	})
+
+	t.Run("success checkpoint, already created", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetCheckpoint(gomock.Any(), date).
+			Return([]checkpointModel{{Date: date, Sequence: 1, Hash: "stored"}}, nil)
+
+		cp, err := svc.Checkpoint(ctx, date)
+		assert.NoError(t, err)
+		assert.Equal(t, "stored", cp.Hash)
+	})
+
+	t.Run("success checkpoint, empty chain", func(t *testing.T) {
+		repoMock.EXPECT().GetCheckpoint(gomock.Any(), date).Return(nil, nil)
+		repoMock.EXPECT().GetHead(gomock.Any(), GlobalChain, date.AddDate(0, 0, 1)).Return(linkModel{}, sql.ErrNoRows)
+		repoMock.EXPECT().
+			CreateCheckpoint(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, model checkpointModel) (checkpointModel, bool, error) {
+				return model, true, nil
+			})
+
+		cp, err := svc.Checkpoint(ctx, date)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), cp.Sequence)
+		assert.Equal(t, Genesis, cp.Hash)
+		assert.True(t, cp.Verify())
+	})
+}
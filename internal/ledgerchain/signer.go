@@ -0,0 +1,66 @@
+package ledgerchain
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+)
+
+var (
+	ErrInvalidSigningKey  = errors.New("the signing key must be an ed25519 seed of 32 bytes encoded in base64")
+	ErrSigningKeyRequired = errors.New("a signing key is required to sign checkpoints")
+)
+
+// Signer signs the checkpoints with an ed25519 key, so anyone with its public key can verify them.
+type Signer interface {
+	Sign(message []byte) (string, error)
+	PublicKey() string
+}
+
+type signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner creates the signer from the base64 seed of the key. Without a seed the signer can not sign, which is
+// enough for the applications that only read the checkpoints.
+func NewSigner(seed string) (Signer, error) {
+	if seed == "" {
+		return signer{}, nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(b) != ed25519.SeedSize {
+		return nil, ErrInvalidSigningKey
+	}
+
+	return signer{key: ed25519.NewKeyFromSeed(b)}, nil
+}
+
+func (s signer) Sign(message []byte) (string, error) {
+	if s.key == nil {
+		return "", ErrSigningKeyRequired
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, message)), nil
+}
+
+func (s signer) PublicKey() string {
+	if s.key == nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// VerifySignature verifies the base64 signature of the message with the base64 public key.
+func VerifySignature(publicKey, signature string, message []byte) bool {
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return ed25519.Verify(key, message, sig)
+}
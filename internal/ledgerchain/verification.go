@@ -0,0 +1,33 @@
+package ledgerchain
+
+import "github.com/google/uuid"
+
+// BrokenReason tells why a link of a chain is broken.
+type BrokenReason string
+
+const (
+	MissingLinkReason        BrokenReason = "missing_link"
+	PreviousHashReason       BrokenReason = "previous_hash_mismatch"
+	LinkHashReason           BrokenReason = "link_hash_mismatch"
+	MissingTransactionReason BrokenReason = "missing_transaction"
+	ContentHashReason        BrokenReason = "content_hash_mismatch"
+	UnchainedReason          BrokenReason = "unchained_transaction"
+)
+
+// BrokenLink is the first broken link found walking a chain. Sequence is zero for a transaction not linked to the
+// chain at all.
+type BrokenLink struct {
+	Sequence      int64        `json:"sequence"`
+	TransactionID uuid.UUID    `json:"transaction_id"`
+	Reason        BrokenReason `json:"reason"`
+}
+
+// Verification is the result of walking a chain. Links is the count of valid links before the broken one, or of
+// all links when the chain is valid, and Hash is the hash of the last valid link.
+type Verification struct {
+	Chain  string      `json:"chain"`
+	Valid  bool        `json:"valid"`
+	Links  int64       `json:"links"`
+	Hash   string      `json:"hash"`
+	Broken *BrokenLink `json:"broken,omitempty"`
+}
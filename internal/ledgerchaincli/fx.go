@@ -0,0 +1,155 @@
+package ledgerchaincli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchaincli/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// ExitBroken is the exit code when the chain is broken.
+	ExitBroken = 1
+	// ExitError is the exit code when the chain could not be verified or the checkpoint could not be created.
+	ExitError = 2
+)
+
+// Options are the arguments of the command line.
+type Options struct {
+	// Chain to verify, the global chain or the id of an account.
+	Chain string
+	// Backfill links the transactions not linked to the chains before verifying them.
+	Backfill bool
+	// Checkpoint signs the head of the global chain at the end of Date when the chain is valid.
+	Checkpoint bool
+	Date       time.Time
+	ReportOut  io.Writer
+}
+
+// Module verifies a chain writing the verification, and the checkpoint when asked, as JSON lines. It must be
+// supplied with the Options.
+var Module = fx.Options(
+	// Infra
+	fx.Provide(
+		environment.NewEnvironment,
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			return database.Setup(lc, t, m, database.Config{MasterURL: e.DatabaseURL})
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+		},
+		func(e environment.Environment) (ledgerchain.Signer, error) {
+			return ledgerchain.NewSigner(e.ChainSigningKey)
+		},
+	),
+	// Domains
+	fx.Provide(
+		ledgerchain.NewRepository,
+		ledgerchain.NewService,
+	),
+	fx.Invoke(runLedgerChain),
+)
+
+// runLedgerChain verifies the chain once the application started and shuts it down with the result.
+func runLedgerChain(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	opts Options,
+	svc ledgerchain.Service,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+
+				exitCode := run(ctx, opts, svc)
+				if ctx.Err() != nil {
+					return
+				}
+
+				if err := shutdowner.Shutdown(fx.ExitCode(exitCode)); err != nil {
+					zap.L().Error("ledgerchain_shutdown_error", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func run(ctx context.Context, opts Options, svc ledgerchain.Service) int {
+	if opts.Backfill {
+		chained, err := svc.ChainUnchained(ctx)
+		if err != nil {
+			zap.L().Error("ledgerchain_backfill_error", zap.Int("chained", chained), zap.Error(err))
+			return ExitError
+		}
+		zap.L().Info("ledgerchain_backfill_finished", zap.Int("chained", chained))
+	}
+
+	encoder := json.NewEncoder(opts.ReportOut)
+
+	verification, err := svc.Verify(ctx, opts.Chain)
+	if err != nil {
+		zap.L().Error("ledgerchain_verify_error", zap.Error(err))
+		return ExitError
+	}
+
+	if err := encoder.Encode(verification); err != nil {
+		zap.L().Error("ledgerchain_report_write_error", zap.Error(err))
+	}
+
+	if !verification.Valid {
+		return ExitBroken
+	}
+
+	if !opts.Checkpoint {
+		return 0
+	}
+
+	checkpoint, err := svc.Checkpoint(ctx, opts.Date)
+	if err != nil {
+		zap.L().Error("ledgerchain_checkpoint_error", zap.Error(err))
+		return ExitError
+	}
+
+	if err := encoder.Encode(checkpoint); err != nil {
+		zap.L().Error("ledgerchain_report_write_error", zap.Error(err))
+	}
+
+	return 0
+}
@@ -0,0 +1,8 @@
+package ledgercheck
+
+type violationModel struct {
+	Subject  string  `bun:"subject"`
+	Detail   string  `bun:"detail"`
+	Expected float64 `bun:"expected"`
+	Actual   float64 `bun:"actual"`
+}
@@ -0,0 +1,54 @@
+package ledgercheck
+
+import (
+	"time"
+)
+
+// CheckName identifies an invariant of the ledger.
+type CheckName string
+
+const (
+	// NegativeBalanceCheck finds accounts with negative balance.
+	NegativeBalanceCheck CheckName = "negative_balance"
+	// DanglingTransactionCheck finds transactions whose sides do not reference existing accounts as their type
+	// requires: credits need the receiver, debits the sender and transfers both of them.
+	DanglingTransactionCheck CheckName = "dangling_transaction"
+	// ClosedAccountMovementCheck finds transactions of closed accounts created after the closure.
+	ClosedAccountMovementCheck CheckName = "closed_account_movement"
+	// BalanceViewCheck finds accounts whose balance in the transactions_balances view differs from the balance
+	// computed from their transactions.
+	BalanceViewCheck CheckName = "balance_view"
+	// NonPositiveAmountCheck finds transactions with zero or negative amount.
+	NonPositiveAmountCheck CheckName = "non_positive_amount"
+	// SystemBalanceCheck verifies the balance of all accounts together is the money that entered (credits) minus
+	// the money that left (debits) the ledger, transfers only move money between accounts.
+	SystemBalanceCheck CheckName = "system_balance"
+)
+
+type (
+	// Violation is an item breaking the invariant of the check.
+	Violation struct {
+		// Subject is the id of the account or of the transaction breaking the invariant, empty for system wide ones.
+		Subject  string  `json:"subject,omitempty"`
+		Detail   string  `json:"detail"`
+		Expected float64 `json:"expected,omitempty"`
+		Actual   float64 `json:"actual,omitempty"`
+	}
+
+	CheckResult struct {
+		Name   CheckName `json:"name"`
+		Passed bool      `json:"passed"`
+		// Violations is capped, Truncated tells there are more violations than the reported ones.
+		Violations []Violation `json:"violations"`
+		Truncated  bool        `json:"truncated"`
+		Error      string      `json:"error,omitempty"`
+	}
+
+	// Report is the machine-readable result of a verification of the ledger.
+	Report struct {
+		StartedAt  time.Time     `json:"started_at"`
+		FinishedAt time.Time     `json:"finished_at"`
+		Passed     bool          `json:"passed"`
+		Checks     []CheckResult `json:"checks"`
+	}
+)
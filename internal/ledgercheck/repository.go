@@ -0,0 +1,196 @@
+package ledgercheck
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
+)
+
+// balanceTolerance absorbs rounding differences of the balances, summed as DECIMAL by the database.
+const balanceTolerance = 0.000001
+
+// Repository finds the violations of each invariant, at most limit of them. Every check is a single query, so it
+// sees a consistent snapshot of the ledger. The transactions_balances view has a row without account, the money
+// that entered and left the ledger through credits and debits, it is not the balance of an account.
+type Repository interface {
+	NegativeBalances(ctx context.Context, limit int) ([]violationModel, error)
+	DanglingTransactions(ctx context.Context, limit int) ([]violationModel, error)
+	ClosedAccountMovements(ctx context.Context, limit int) ([]violationModel, error)
+	BalanceViewMismatches(ctx context.Context, limit int) ([]violationModel, error)
+	NonPositiveAmounts(ctx context.Context, limit int) ([]violationModel, error)
+	SystemBalanceMismatch(ctx context.Context) ([]violationModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+func (r repository) NegativeBalances(ctx context.Context, limit int) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions_balances AS tb").
+		ColumnExpr("tb.account_id AS subject").
+		ColumnExpr("'account has negative balance' AS detail").
+		ColumnExpr("0 AS expected").
+		ColumnExpr("tb.balance AS actual").
+		Where("tb.account_id IS NOT NULL").
+		Where("tb.balance < ?", -balanceTolerance).
+		Order("tb.account_id").
+		Limit(limit)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+func (r repository) DanglingTransactions(ctx context.Context, limit int) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		Join("LEFT JOIN accounts AS from_acc ON from_acc.id = trx.from_account_id").
+		Join("LEFT JOIN accounts AS to_acc ON to_acc.id = trx.to_account_id").
+		ColumnExpr("trx.id AS subject").
+		ColumnExpr(`CASE
+			WHEN trx.from_account_id IS NOT NULL AND from_acc.id IS NULL THEN 'from account does not exist'
+			WHEN trx.to_account_id IS NOT NULL AND to_acc.id IS NULL THEN 'to account does not exist'
+			ELSE trx.type || ' transaction without the account of one of its sides'
+		END AS detail`).
+		ColumnExpr("0 AS expected").
+		ColumnExpr("trx.amount AS actual").
+		WhereOr("trx.from_account_id IS NOT NULL AND from_acc.id IS NULL").
+		WhereOr("trx.to_account_id IS NOT NULL AND to_acc.id IS NULL").
+		WhereOr("trx.type = 'CREDIT' AND trx.to_account_id IS NULL").
+		WhereOr("trx.type = 'DEBIT' AND trx.from_account_id IS NULL").
+		WhereOr("trx.type = 'P2P' AND (trx.from_account_id IS NULL OR trx.to_account_id IS NULL)").
+		WhereOr("trx.type NOT IN ('CREDIT', 'DEBIT', 'P2P')").
+		Order("trx.id").
+		Limit(limit)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+// ClosedAccountMovements takes the last update of a closed account as its closure, closed accounts can not be
+// changed anymore.
+func (r repository) ClosedAccountMovements(ctx context.Context, limit int) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		Join("JOIN accounts AS acc ON acc.id IN (trx.from_account_id, trx.to_account_id)").
+		ColumnExpr("trx.id AS subject").
+		ColumnExpr("'transaction after the closure of account ' || acc.id AS detail").
+		ColumnExpr("0 AS expected").
+		ColumnExpr("trx.amount AS actual").
+		Where("acc.status = 'CLOSED'").
+		Where("acc.updated_at IS NOT NULL").
+		Where("trx.created_at > acc.updated_at").
+		Order("trx.id").
+		Limit(limit)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+func (r repository) BalanceViewMismatches(ctx context.Context, limit int) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ledgerBalances := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("accounts AS acc").
+		Join("LEFT JOIN transactions AS trx ON acc.id IN (trx.from_account_id, trx.to_account_id)").
+		ColumnExpr("acc.id AS account_id").
+		ColumnExpr(
+			"COALESCE(SUM(CASE WHEN trx.to_account_id = acc.id THEN trx.amount ELSE -trx.amount END), 0) AS balance",
+		).
+		Group("acc.id")
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("(?) AS lb", ledgerBalances).
+		Join("LEFT JOIN transactions_balances AS tb ON tb.account_id = lb.account_id").
+		ColumnExpr("lb.account_id AS subject").
+		ColumnExpr("'balance of the view differs from the transactions of the account' AS detail").
+		ColumnExpr("lb.balance AS expected").
+		ColumnExpr("COALESCE(tb.balance, 0) AS actual").
+		Where("ABS(lb.balance - COALESCE(tb.balance, 0)) > ?", balanceTolerance).
+		Order("lb.account_id").
+		Limit(limit)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+func (r repository) NonPositiveAmounts(ctx context.Context, limit int) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		ColumnExpr("trx.id AS subject").
+		ColumnExpr("'transaction amount must be positive' AS detail").
+		ColumnExpr("0 AS expected").
+		ColumnExpr("trx.amount AS actual").
+		Where("trx.amount <= 0").
+		Order("trx.id").
+		Limit(limit)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+func (r repository) SystemBalanceMismatch(ctx context.Context) ([]violationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	movements := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		ColumnExpr(`COALESCE(SUM(CASE trx.type
+			WHEN 'CREDIT' THEN trx.amount
+			WHEN 'DEBIT' THEN -trx.amount
+			ELSE 0
+		END), 0)`)
+
+	balances := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions_balances AS tb").
+		ColumnExpr("COALESCE(SUM(tb.balance), 0)").
+		Where("tb.account_id IS NOT NULL")
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("(SELECT (?) AS expected, (?) AS actual) AS sb", movements, balances).
+		ColumnExpr("'' AS subject").
+		ColumnExpr("'balance of all accounts differs from credits minus debits' AS detail").
+		ColumnExpr("sb.expected, sb.actual").
+		Where("ABS(sb.expected - sb.actual) > ?", balanceTolerance)
+
+	return r.scan(ctx, span, selectQuery)
+}
+
+func (r repository) scan(ctx context.Context, span tracer.TSpan, selectQuery *bun.SelectQuery) ([]violationModel, error) {
+	var violations []violationModel
+	err := selectQuery.Scan(ctx, &violations)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return violations, nil
+}
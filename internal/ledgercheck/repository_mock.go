@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/ledgercheck/repository.go
+
+// Package ledgercheck is a generated GoMock package.
+package ledgercheck
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BalanceViewMismatches mocks base method.
+func (m *MockRepository) BalanceViewMismatches(ctx context.Context, limit int) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceViewMismatches", ctx, limit)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceViewMismatches indicates an expected call of BalanceViewMismatches.
+func (mr *MockRepositoryMockRecorder) BalanceViewMismatches(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceViewMismatches", reflect.TypeOf((*MockRepository)(nil).BalanceViewMismatches), ctx, limit)
+}
+
+// ClosedAccountMovements mocks base method.
+func (m *MockRepository) ClosedAccountMovements(ctx context.Context, limit int) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosedAccountMovements", ctx, limit)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClosedAccountMovements indicates an expected call of ClosedAccountMovements.
+func (mr *MockRepositoryMockRecorder) ClosedAccountMovements(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosedAccountMovements", reflect.TypeOf((*MockRepository)(nil).ClosedAccountMovements), ctx, limit)
+}
+
+// DanglingTransactions mocks base method.
+func (m *MockRepository) DanglingTransactions(ctx context.Context, limit int) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DanglingTransactions", ctx, limit)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DanglingTransactions indicates an expected call of DanglingTransactions.
+func (mr *MockRepositoryMockRecorder) DanglingTransactions(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DanglingTransactions", reflect.TypeOf((*MockRepository)(nil).DanglingTransactions), ctx, limit)
+}
+
+// NegativeBalances mocks base method.
+func (m *MockRepository) NegativeBalances(ctx context.Context, limit int) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NegativeBalances", ctx, limit)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NegativeBalances indicates an expected call of NegativeBalances.
+func (mr *MockRepositoryMockRecorder) NegativeBalances(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NegativeBalances", reflect.TypeOf((*MockRepository)(nil).NegativeBalances), ctx, limit)
+}
+
+// NonPositiveAmounts mocks base method.
+func (m *MockRepository) NonPositiveAmounts(ctx context.Context, limit int) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NonPositiveAmounts", ctx, limit)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NonPositiveAmounts indicates an expected call of NonPositiveAmounts.
+func (mr *MockRepositoryMockRecorder) NonPositiveAmounts(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NonPositiveAmounts", reflect.TypeOf((*MockRepository)(nil).NonPositiveAmounts), ctx, limit)
+}
+
+// SystemBalanceMismatch mocks base method.
+func (m *MockRepository) SystemBalanceMismatch(ctx context.Context) ([]violationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SystemBalanceMismatch", ctx)
+	ret0, _ := ret[0].([]violationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SystemBalanceMismatch indicates an expected call of SystemBalanceMismatch.
+func (mr *MockRepositoryMockRecorder) SystemBalanceMismatch(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SystemBalanceMismatch", reflect.TypeOf((*MockRepository)(nil).SystemBalanceMismatch), ctx)
+}
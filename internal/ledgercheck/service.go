@@ -0,0 +1,95 @@
+package ledgercheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+)
+
+// maxViolations is the maximum of violations reported by check, enough to investigate without flooding the report.
+const maxViolations = 100
+
+type Service interface {
+	Check(ctx context.Context) Report
+}
+
+type service struct {
+	tracer     tracer.Tracer
+	repository Repository
+}
+
+func NewService(t tracer.Tracer, r Repository) Service {
+	return service{tracer: t, repository: r}
+}
+
+// Check verifies all invariants of the ledger. A check that could not run does not pass, the error is reported
+// with it and the other checks still run.
+func (s service) Check(ctx context.Context) Report {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	checks := []struct {
+		name CheckName
+		find func(ctx context.Context) ([]violationModel, error)
+	}{
+		{NegativeBalanceCheck, s.limited(s.repository.NegativeBalances)},
+		{DanglingTransactionCheck, s.limited(s.repository.DanglingTransactions)},
+		{ClosedAccountMovementCheck, s.limited(s.repository.ClosedAccountMovements)},
+		{BalanceViewCheck, s.limited(s.repository.BalanceViewMismatches)},
+		{NonPositiveAmountCheck, s.limited(s.repository.NonPositiveAmounts)},
+		{SystemBalanceCheck, s.repository.SystemBalanceMismatch},
+	}
+
+	report := Report{StartedAt: time.Now().UTC(), Passed: true}
+	for _, check := range checks {
+		result := CheckResult{Name: check.name, Violations: []Violation{}}
+
+		models, err := check.find(ctx)
+		if err != nil {
+			zapctx.L(ctx).Error("ledgercheck_service_check_error", zap.String("check", string(check.name)), zap.Error(err))
+			span.RecordError(err)
+			result.Error = err.Error()
+		}
+
+		if len(models) > maxViolations {
+			models = models[:maxViolations]
+			result.Truncated = true
+		}
+
+		for _, model := range models {
+			result.Violations = append(result.Violations, Violation{
+				Subject:  model.Subject,
+				Detail:   model.Detail,
+				Expected: model.Expected,
+				Actual:   model.Actual,
+			})
+		}
+
+		result.Passed = err == nil && len(result.Violations) == 0
+		if !result.Passed {
+			zapctx.L(ctx).Warn(
+				"ledgercheck_service_check_failed",
+				zap.String("check", string(check.name)),
+				zap.Int("violations", len(result.Violations)),
+			)
+			report.Passed = false
+		}
+
+		report.Checks = append(report.Checks, result)
+	}
+	report.FinishedAt = time.Now().UTC()
+
+	return report
+}
+
+// limited asks one violation more than the maximum reported, to know when the result is truncated.
+func (s service) limited(
+	find func(ctx context.Context, limit int) ([]violationModel, error),
+) func(ctx context.Context) ([]violationModel, error) {
+	return func(ctx context.Context) ([]violationModel, error) {
+		return find(ctx, maxViolations+1)
+	}
+}
@@ -0,0 +1,67 @@
+//go:build unit
+
+package ledgercheck
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Check(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock)
+
+	expectChecks := func(negativeBalances []violationModel, systemErr error) {
+		repoMock.EXPECT().NegativeBalances(gomock.Any(), maxViolations+1).Return(negativeBalances, nil)
+		repoMock.EXPECT().DanglingTransactions(gomock.Any(), maxViolations+1).Return(nil, nil)
+		repoMock.EXPECT().ClosedAccountMovements(gomock.Any(), maxViolations+1).Return(nil, nil)
+		repoMock.EXPECT().BalanceViewMismatches(gomock.Any(), maxViolations+1).Return(nil, nil)
+		repoMock.EXPECT().NonPositiveAmounts(gomock.Any(), maxViolations+1).Return(nil, nil)
+		repoMock.EXPECT().SystemBalanceMismatch(gomock.Any()).Return(nil, systemErr)
+	}
+
+	t.Run("Check passes without violations", func(t *testing.T) {
+		expectChecks(nil, nil)
+
+		report := svc.Check(ctx)
+		assert.True(t, report.Passed)
+		assert.Len(t, report.Checks, 6)
+		for _, check := range report.Checks {
+			assert.True(t, check.Passed)
+			assert.Empty(t, check.Violations)
+		}
+	})
+
+	t.Run("Check fails with violations and errors", func(t *testing.T) {
+		violations := make([]violationModel, maxViolations+1)
+		for i := range violations {
+			violations[i] = violationModel{Subject: uuid.NewString(), Detail: "account has negative balance", Actual: -1}
+		}
+		expectChecks(violations, sql.ErrConnDone)
+
+		report := svc.Check(ctx)
+		assert.False(t, report.Passed)
+
+		assert.Equal(t, NegativeBalanceCheck, report.Checks[0].Name)
+		assert.False(t, report.Checks[0].Passed)
+		assert.True(t, report.Checks[0].Truncated)
+		assert.Len(t, report.Checks[0].Violations, maxViolations)
+
+		assert.True(t, report.Checks[1].Passed)
+
+		assert.Equal(t, SystemBalanceCheck, report.Checks[5].Name)
+		assert.False(t, report.Checks[5].Passed)
+		assert.Equal(t, sql.ErrConnDone.Error(), report.Checks[5].Error)
+	})
+}
@@ -0,0 +1,120 @@
+package ledgercheckcli
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/ledgercheck"
+	"github.com/dalmarcogd/ledger-exp/internal/ledgercheckcli/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ExitViolation is the exit code when the ledger broke an invariant or a check could not run.
+const ExitViolation = 1
+
+// Options are the arguments of the command line.
+type Options struct {
+	// Interval between the checks in scheduled mode, the ledger is checked once when it is zero.
+	Interval  time.Duration
+	ReportOut io.Writer
+}
+
+// Module checks the ledger writing the reports as JSON lines, it must be supplied with the Options.
+var Module = fx.Options(
+	// Infra
+	fx.Provide(
+		environment.NewEnvironment,
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			return database.Setup(lc, t, m, database.Config{MasterURL: e.DatabaseURL})
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+		},
+	),
+	// Domains
+	fx.Provide(
+		ledgercheck.NewRepository,
+		ledgercheck.NewService,
+	),
+	fx.Invoke(runLedgerCheck),
+)
+
+// runLedgerCheck checks the ledger once the application started. Checking once, it shuts the application down with
+// the result of the check. In scheduled mode it checks again at every interval until the application is stopped,
+// the violations are only reported, logged as warnings by the service.
+func runLedgerCheck(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	opts Options,
+	svc ledgercheck.Service,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+
+				encoder := json.NewEncoder(opts.ReportOut)
+				for {
+					report := svc.Check(ctx)
+					if ctx.Err() != nil {
+						return
+					}
+
+					if err := encoder.Encode(report); err != nil {
+						zap.L().Error("ledgercheck_report_write_error", zap.Error(err))
+					}
+
+					exitCode := 0
+					if !report.Passed {
+						exitCode = ExitViolation
+					}
+
+					if opts.Interval <= 0 {
+						_ = shutdowner.Shutdown(fx.ExitCode(exitCode))
+						return
+					}
+
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(opts.Interval):
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
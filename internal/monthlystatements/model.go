@@ -0,0 +1,32 @@
+package monthlystatements
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type monthlyStatementModel struct {
+	bun.BaseModel `bun:"table:monthly_statements,alias:ms"`
+
+	ID           uuid.UUID `bun:"id,pk"`
+	AccountID    uuid.UUID `bun:"account_id"`
+	Month        time.Time `bun:"month"`
+	Content      []byte    `bun:"content"`
+	Document     []byte    `bun:"document"`
+	ContentHash  string    `bun:"content_hash"`
+	DocumentHash string    `bun:"document_hash"`
+	TenantID     string    `bun:"tenant_id,nullzero"`
+	CreatedAt    time.Time `bun:"created_at,notnull"`
+}
+
+type monthlyStatementFilter struct {
+	AccountID uuid.UUID
+	Month     database.NullTime
+	// TenantID keeps only the statements of the tenant, the statements of all tenants when empty.
+	TenantID string
+	// WithoutContent leaves out the snapshot and the document, useful to list the statements without loading them.
+	WithoutContent bool
+}
@@ -0,0 +1,68 @@
+package monthlystatements
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const monthLayout = "2006-01"
+
+var ErrInvalidMonth = errors.New("month must be in the format yyyy-mm")
+
+// MonthlyStatement is the official, closed statement of an account in a month. Content is the JSON snapshot of
+// the statement and Document is the same statement rendered as PDF, each one with the SHA-256 of its bytes.
+type MonthlyStatement struct {
+	ID           uuid.UUID
+	AccountID    uuid.UUID
+	Month        time.Time
+	Content      []byte
+	Document     []byte
+	ContentHash  string
+	DocumentHash string
+	CreatedAt    time.Time
+}
+
+func newMonthlyStatement(model monthlyStatementModel) MonthlyStatement {
+	return MonthlyStatement{
+		ID:           model.ID,
+		AccountID:    model.AccountID,
+		Month:        model.Month,
+		Content:      model.Content,
+		Document:     model.Document,
+		ContentHash:  model.ContentHash,
+		DocumentHash: model.DocumentHash,
+		CreatedAt:    model.CreatedAt,
+	}
+}
+
+// ParseMonth parses a month in the format yyyy-mm, returning its first day in UTC.
+func ParseMonth(value string) (time.Time, error) {
+	month, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidMonth
+	}
+	return month, nil
+}
+
+// FormatMonth formats the month as yyyy-mm.
+func FormatMonth(month time.Time) string {
+	return month.Format(monthLayout)
+}
+
+// StartOfMonth returns the first instant of the month of t in UTC.
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PreviousMonth returns the first day of the month before the month of t, the last closed month at t.
+func PreviousMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, -1, 0)
+}
+
+// endOfMonth returns the last instant of the month stored by the database, which keeps microseconds.
+func endOfMonth(month time.Time) time.Time {
+	return StartOfMonth(month).AddDate(0, 1, 0).Add(-time.Microsecond)
+}
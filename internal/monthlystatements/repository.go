@@ -0,0 +1,94 @@
+package monthlystatements
+
+import (
+	"context"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/google/uuid"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model monthlyStatementModel) (monthlyStatementModel, bool, error)
+	GetByFilter(ctx context.Context, filter monthlyStatementFilter) ([]monthlyStatementModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+// Create inserts the monthly statement unless the account already has one for the month, in this case nothing is
+// changed and created is false.
+func (r repository) Create(ctx context.Context, model monthlyStatementModel) (monthlyStatementModel, bool, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	model.ID = uuid.New()
+	model.CreatedAt = time.Now().UTC()
+
+	res, err := r.db.Master(ctx).
+		NewInsert().
+		Model(&model).
+		On("CONFLICT (account_id, month) DO NOTHING").
+		Exec(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return monthlyStatementModel{}, false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return monthlyStatementModel{}, false, err
+	}
+
+	if rows == 0 {
+		return monthlyStatementModel{}, false, nil
+	}
+
+	return model, true, nil
+}
+
+func (r repository) GetByFilter(ctx context.Context, filter monthlyStatementFilter) ([]monthlyStatementModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		Model(&monthlyStatementModel{}).
+		Where("ms.account_id = ?", filter.AccountID).
+		Order("ms.month DESC")
+
+	if filter.Month.Valid {
+		selectQuery.Where("ms.month = ?", filter.Month.Time.Format("2006-01-02"))
+	}
+
+	if filter.TenantID != "" {
+		selectQuery.Where("ms.tenant_id = ?", filter.TenantID)
+	}
+
+	if filter.WithoutContent {
+		selectQuery.ExcludeColumn("content", "document")
+	}
+
+	var models []monthlyStatementModel
+	err := selectQuery.Scan(ctx, &models)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
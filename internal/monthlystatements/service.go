@@ -0,0 +1,206 @@
+package monthlystatements
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrMonthlyStatementNotFound = errors.New("no monthly statement found for this account and month")
+	ErrMonthNotClosed           = errors.New("monthly statements can only be generated for closed months")
+)
+
+type Service interface {
+	Generate(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error)
+	GetByMonth(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error)
+	List(ctx context.Context, accountID uuid.UUID) ([]MonthlyStatement, error)
+}
+
+type service struct {
+	tracer          tracer.Tracer
+	repository      Repository
+	statementsSvc   statements.Service
+	accountsSvc     accounts.Service
+	businessDaysSvc businessdays.Service
+}
+
+func NewService(
+	t tracer.Tracer,
+	r Repository,
+	ss statements.Service,
+	as accounts.Service,
+	bds businessdays.Service,
+) Service {
+	return service{tracer: t, repository: r, statementsSvc: ss, accountsSvc: as, businessDaysSvc: bds}
+}
+
+// Generate generates the statement of the account for the month, which must be already closed: over, with all its
+// business days closed and none of them open for adjustments. It is idempotent: when the statement of the month was
+// already generated, the stored one is returned as it is.
+func (s service) Generate(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	month = StartOfMonth(month)
+	if !endOfMonth(month).Before(time.Now()) {
+		span.RecordError(ErrMonthNotClosed)
+		return MonthlyStatement{}, ErrMonthNotClosed
+	}
+
+	existing, err := s.GetByMonth(ctx, accountID, month)
+	if err == nil {
+		return existing, nil
+	} else if !errors.Is(err, ErrMonthlyStatementNotFound) {
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	// The transactions can still be backdated or adjusted into the business days not closed yet.
+	err = s.businessDaysSvc.CheckClosed(ctx, month, endOfMonth(month))
+	if errors.Is(err, businessdays.ErrBusinessDaysOpen) {
+		span.RecordError(ErrMonthNotClosed)
+		return MonthlyStatement{}, ErrMonthNotClosed
+	} else if err != nil {
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	var rec recorder
+	err = s.statementsSvc.Export(
+		ctx,
+		statements.ExportFilter{
+			AccountID:      accountID,
+			CreatedAtBegin: month,
+			CreatedAtEnd:   endOfMonth(month),
+		},
+		&rec,
+	)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"monthly_statements_service_export_error",
+			zap.String("account_id", accountID.String()),
+			zap.String("month", FormatMonth(month)),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	content, err := json.Marshal(rec.snapshot(month))
+	if err != nil {
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	var document bytes.Buffer
+	pdfExporter, err := statements.NewExporter(statements.PDFExportFormat, &document)
+	if err != nil {
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	if err := rec.replay(pdfExporter); err != nil {
+		zapctx.L(ctx).Error("monthly_statements_service_render_error", zap.Error(err))
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	model, created, err := s.repository.Create(ctx, monthlyStatementModel{
+		AccountID:    accountID,
+		Month:        month,
+		Content:      content,
+		Document:     document.Bytes(),
+		ContentHash:  hash(content),
+		DocumentHash: hash(document.Bytes()),
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("monthly_statements_service_create_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	if !created {
+		// Generated concurrently by someone else since we looked for it, the stored one is the official.
+		return s.GetByMonth(ctx, accountID, month)
+	}
+
+	return newMonthlyStatement(model), nil
+}
+
+func (s service) GetByMonth(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := s.accountsSvc.Authorize(ctx, accountID); err != nil {
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	models, err := s.repository.GetByFilter(ctx, monthlyStatementFilter{
+		AccountID: accountID,
+		Month:     database.NullTime{Time: StartOfMonth(month), Valid: true},
+		TenantID:  tenancy.FilterID(ctx),
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("monthly_statements_service_get_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return MonthlyStatement{}, err
+	}
+
+	if len(models) != 1 {
+		span.RecordError(ErrMonthlyStatementNotFound)
+		return MonthlyStatement{}, ErrMonthlyStatementNotFound
+	}
+
+	return newMonthlyStatement(models[0]), nil
+}
+
+// List returns the monthly statements of the account, from the most recent month, without their content and
+// document.
+func (s service) List(ctx context.Context, accountID uuid.UUID) ([]MonthlyStatement, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := s.accountsSvc.Authorize(ctx, accountID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	models, err := s.repository.GetByFilter(ctx, monthlyStatementFilter{
+		AccountID:      accountID,
+		WithoutContent: true,
+		TenantID:       tenancy.FilterID(ctx),
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("monthly_statements_service_list_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return nil, err
+	}
+
+	monthlies := make([]MonthlyStatement, len(models))
+	for i, model := range models {
+		monthlies[i] = newMonthlyStatement(model)
+	}
+
+	return monthlies, nil
+}
+
+func hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/monthlystatements/service.go
+
+// Package monthlystatements is a generated GoMock package.
+package monthlystatements
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Generate mocks base method.
+func (m *MockService) Generate(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Generate", ctx, accountID, month)
+	ret0, _ := ret[0].(MonthlyStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Generate indicates an expected call of Generate.
+func (mr *MockServiceMockRecorder) Generate(ctx, accountID, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Generate", reflect.TypeOf((*MockService)(nil).Generate), ctx, accountID, month)
+}
+
+// GetByMonth mocks base method.
+func (m *MockService) GetByMonth(ctx context.Context, accountID uuid.UUID, month time.Time) (MonthlyStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByMonth", ctx, accountID, month)
+	ret0, _ := ret[0].(MonthlyStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByMonth indicates an expected call of GetByMonth.
+func (mr *MockServiceMockRecorder) GetByMonth(ctx, accountID, month interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByMonth", reflect.TypeOf((*MockService)(nil).GetByMonth), ctx, accountID, month)
+}
+
+// List mocks base method.
+func (m *MockService) List(ctx context.Context, accountID uuid.UUID) ([]MonthlyStatement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, accountID)
+	ret0, _ := ret[0].([]MonthlyStatement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), ctx, accountID)
+}
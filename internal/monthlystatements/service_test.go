@@ -0,0 +1,146 @@
+//go:build unit
+
+package monthlystatements
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Generate(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	stmSvcMock := statements.NewMockService(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, stmSvcMock, accSvcMock, bdSvcMock)
+
+	accSvcMock.EXPECT().Authorize(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	accountID := uuid.New()
+	otherAccountID := uuid.New()
+	month := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fail generate, month not closed", func(t *testing.T) {
+		monthly, err := svc.Generate(ctx, accountID, time.Now())
+		assert.ErrorIs(t, err, ErrMonthNotClosed)
+		assert.Empty(t, monthly)
+	})
+
+	t.Run("success generate, already generated", func(t *testing.T) {
+		model := monthlyStatementModel{
+			ID:          uuid.New(),
+			AccountID:   accountID,
+			Month:       month,
+			Content:     []byte(`{}`),
+			Document:    []byte(`%PDF`),
+			ContentHash: "hash",
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), gomock.Any()).
+			Return([]monthlyStatementModel{model}, nil)
+
+		monthly, err := svc.Generate(ctx, accountID, month.AddDate(0, 0, 14))
+		assert.NoError(t, err)
+		assert.Equal(t, newMonthlyStatement(model), monthly)
+	})
+
+	t.Run("fail generate, business days of the month not closed", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		bdSvcMock.EXPECT().
+			CheckClosed(gomock.Any(), month, endOfMonth(month)).
+			Return(businessdays.ErrBusinessDaysOpen)
+
+		monthly, err := svc.Generate(ctx, accountID, month)
+		assert.ErrorIs(t, err, ErrMonthNotClosed)
+		assert.Empty(t, monthly)
+	})
+
+	t.Run("success generate", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), gomock.Any()).
+			Return(nil, nil)
+		bdSvcMock.EXPECT().
+			CheckClosed(gomock.Any(), month, endOfMonth(month)).
+			Return(nil)
+
+		stmSvcMock.EXPECT().
+			Export(
+				gomock.Any(),
+				statements.ExportFilter{
+					AccountID:      accountID,
+					CreatedAtBegin: month,
+					CreatedAtEnd:   time.Date(2022, 1, 31, 23, 59, 59, 999999000, time.UTC),
+				},
+				gomock.Any(),
+			).
+			DoAndReturn(func(_ context.Context, filter statements.ExportFilter, exporter statements.Exporter) error {
+				assert.NoError(t, exporter.WriteHeader(statements.ExportHeader{
+					Holder:         holders.Holder{ID: uuid.New(), Name: "Holder", DocumentNumber: "12345678900"},
+					Account:        accounts.Account{ID: accountID, Name: "Account", Agency: "0001", Number: "123456"},
+					CreatedAtBegin: filter.CreatedAtBegin,
+					CreatedAtEnd:   filter.CreatedAtEnd,
+					OpeningBalance: 10,
+				}))
+				assert.NoError(t, exporter.WriteLine(statements.ExportLine{
+					Statement: statements.Statement{
+						ID:          uuid.New(),
+						FromAccount: accounts.Account{ID: accountID},
+						ToAccount:   accounts.Account{ID: otherAccountID, Name: "Other"},
+						Type:        "P2P",
+						Amount:      4,
+						CreatedAt:   month.AddDate(0, 0, 2),
+					},
+					Amount:  -4,
+					Balance: 6,
+				}))
+				return exporter.WriteFooter(statements.ExportFooter{ClosingBalance: 6, TotalDebits: 4})
+			})
+
+		repoMock.EXPECT().
+			Create(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, model monthlyStatementModel) (monthlyStatementModel, bool, error) {
+				assert.Equal(t, accountID, model.AccountID)
+				assert.Equal(t, month, model.Month)
+				assert.Equal(t, hash(model.Content), model.ContentHash)
+				assert.Equal(t, hash(model.Document), model.DocumentHash)
+				assert.Contains(t, string(model.Document), "%PDF-1.4")
+
+				var snapshot Snapshot
+				assert.NoError(t, json.Unmarshal(model.Content, &snapshot))
+				assert.Equal(t, "2022-01", snapshot.Month)
+				assert.Equal(t, 10.0, snapshot.OpeningBalance)
+				assert.Equal(t, 6.0, snapshot.ClosingBalance)
+				assert.Len(t, snapshot.Statements, 1)
+				assert.Equal(t, -4.0, snapshot.Statements[0].Amount)
+				assert.Equal(t, otherAccountID, *snapshot.Statements[0].CounterpartyID)
+
+				model.ID = uuid.New()
+				return model, true, nil
+			})
+
+		monthly, err := svc.Generate(ctx, accountID, month)
+		assert.NoError(t, err)
+		assert.Equal(t, accountID, monthly.AccountID)
+		assert.NotEmpty(t, monthly.ContentHash)
+	})
+}
@@ -0,0 +1,133 @@
+package monthlystatements
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/statements"
+	"github.com/google/uuid"
+)
+
+type (
+	// Snapshot is the content of a monthly statement, it is stored as JSON and never changes after generated.
+	Snapshot struct {
+		Month          string          `json:"month"`
+		Holder         SnapshotHolder  `json:"holder"`
+		Account        SnapshotAccount `json:"account"`
+		PeriodBegin    time.Time       `json:"period_begin"`
+		PeriodEnd      time.Time       `json:"period_end"`
+		OpeningBalance float64         `json:"opening_balance"`
+		ClosingBalance float64         `json:"closing_balance"`
+		TotalCredits   float64         `json:"total_credits"`
+		TotalDebits    float64         `json:"total_debits"`
+		Statements     []SnapshotLine  `json:"statements"`
+	}
+
+	SnapshotHolder struct {
+		ID             uuid.UUID `json:"id"`
+		Name           string    `json:"name"`
+		DocumentNumber string    `json:"document_number"`
+	}
+
+	SnapshotAccount struct {
+		ID     uuid.UUID `json:"id"`
+		Name   string    `json:"name"`
+		Agency string    `json:"agency"`
+		Number string    `json:"number"`
+	}
+
+	SnapshotLine struct {
+		ID               uuid.UUID  `json:"id"`
+		Type             string     `json:"type"`
+		Description      string     `json:"description"`
+		CounterpartyID   *uuid.UUID `json:"counterparty_account_id,omitempty"`
+		CounterpartyName string     `json:"counterparty_account_name,omitempty"`
+		// Amount is positive for money in and negative for money out of the account.
+		Amount    float64   `json:"amount"`
+		Balance   float64   `json:"balance"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+// recorder is a statements.Exporter that keeps the statement in memory, so it can be stored as a snapshot and
+// replayed into the exporter of the document. A month of statements is small enough for it.
+type recorder struct {
+	header statements.ExportHeader
+	lines  []statements.ExportLine
+	footer statements.ExportFooter
+}
+
+func (r *recorder) WriteHeader(header statements.ExportHeader) error {
+	r.header = header
+	return nil
+}
+
+func (r *recorder) WriteLine(line statements.ExportLine) error {
+	r.lines = append(r.lines, line)
+	return nil
+}
+
+func (r *recorder) WriteFooter(footer statements.ExportFooter) error {
+	r.footer = footer
+	return nil
+}
+
+// replay writes the recorded statement into the exporter.
+func (r *recorder) replay(exporter statements.Exporter) error {
+	if err := exporter.WriteHeader(r.header); err != nil {
+		return err
+	}
+
+	for _, line := range r.lines {
+		if err := exporter.WriteLine(line); err != nil {
+			return err
+		}
+	}
+
+	return exporter.WriteFooter(r.footer)
+}
+
+func (r *recorder) snapshot(month time.Time) Snapshot {
+	lines := make([]SnapshotLine, len(r.lines))
+	for i, line := range r.lines {
+		counterparty := line.Statement.FromAccount
+		if line.Amount < 0 {
+			counterparty = line.Statement.ToAccount
+		}
+
+		lines[i] = SnapshotLine{
+			ID:          line.Statement.ID,
+			Type:        line.Statement.Type,
+			Description: line.Statement.Description,
+			Amount:      line.Amount,
+			Balance:     line.Balance,
+			CreatedAt:   line.Statement.CreatedAt.UTC(),
+		}
+		if counterparty.ID != uuid.Nil {
+			id := counterparty.ID
+			lines[i].CounterpartyID = &id
+			lines[i].CounterpartyName = counterparty.Name
+		}
+	}
+
+	return Snapshot{
+		Month: FormatMonth(month),
+		Holder: SnapshotHolder{
+			ID:             r.header.Holder.ID,
+			Name:           r.header.Holder.Name,
+			DocumentNumber: r.header.Holder.DocumentNumber,
+		},
+		Account: SnapshotAccount{
+			ID:     r.header.Account.ID,
+			Name:   r.header.Account.Name,
+			Agency: r.header.Account.Agency,
+			Number: r.header.Account.Number,
+		},
+		PeriodBegin:    r.header.CreatedAtBegin.UTC(),
+		PeriodEnd:      r.header.CreatedAtEnd.UTC(),
+		OpeningBalance: r.header.OpeningBalance,
+		ClosingBalance: r.footer.ClosingBalance,
+		TotalCredits:   r.footer.TotalCredits,
+		TotalDebits:    r.footer.TotalDebits,
+		Statements:     lines,
+	}
+}
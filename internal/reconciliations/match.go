@@ -0,0 +1,137 @@
+package reconciliations
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/google/uuid"
+)
+
+// amountEpsilon absorbs the float error of the amounts, so a zero tolerance still matches equal amounts.
+const amountEpsilon = 1e-9
+
+// ledgerEntry is a transaction as seen from the reconciled account.
+type ledgerEntry struct {
+	transaction transactions.Transaction
+	amount      float64
+	matched     bool
+}
+
+// match pairs the external lines with the transactions of the account, every transaction matches one line at most.
+// Lines with a reference are matched first by it, the others by the nearest date and amount inside the tolerance.
+// The result has the matched and unmatched lines in the order of the file followed by the unmatched transactions in
+// chronological order.
+func match(accountID uuid.UUID, lines []ExternalLine, trxs []transactions.Transaction, tolerance Tolerance) []Item {
+	entries := make([]*ledgerEntry, len(trxs))
+	for i, trx := range trxs {
+		amount := trx.Amount
+		if trx.To != accountID {
+			amount = -amount
+		}
+		entries[i] = &ledgerEntry{transaction: trx, amount: amount}
+	}
+
+	items := make([]Item, len(lines))
+	for i := range lines {
+		line := lines[i]
+		items[i] = Item{Status: UnmatchedExternalStatus, External: &line}
+	}
+
+	for i, line := range lines {
+		if line.Reference == "" {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.matched || !matchesReference(line.Reference, entry.transaction) {
+				continue
+			}
+			if !withinAmount(line.Amount, entry.amount, tolerance.Amount) {
+				continue
+			}
+
+			items[i] = matched(items[i], entry, ReferenceMatchRule)
+			break
+		}
+	}
+
+	for i, line := range lines {
+		if items[i].Status == MatchedStatus {
+			continue
+		}
+
+		var best *ledgerEntry
+		var bestDays int
+		var bestAmount float64
+		for _, entry := range entries {
+			if entry.matched || !withinAmount(line.Amount, entry.amount, tolerance.Amount) {
+				continue
+			}
+
+			days := daysBetween(line.Date, entry.transaction.CreatedAt)
+			if days > tolerance.Days {
+				continue
+			}
+
+			amountDiff := math.Abs(line.Amount - entry.amount)
+			if best == nil || days < bestDays || (days == bestDays && amountDiff < bestAmount) {
+				best, bestDays, bestAmount = entry, days, amountDiff
+			}
+		}
+
+		if best != nil {
+			items[i] = matched(items[i], best, AmountDateMatchRule)
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.matched {
+			continue
+		}
+
+		items = append(items, Item{
+			Status:        UnmatchedLedgerStatus,
+			TransactionID: uuid.NullUUID{UUID: entry.transaction.ID, Valid: true},
+			LedgerAmount:  entry.amount,
+			LedgerDate:    entry.transaction.CreatedAt,
+		})
+	}
+
+	return items
+}
+
+func matched(item Item, entry *ledgerEntry, rule MatchRule) Item {
+	entry.matched = true
+
+	item.Status = MatchedStatus
+	item.Rule = rule
+	item.TransactionID = uuid.NullUUID{UUID: entry.transaction.ID, Valid: true}
+	item.LedgerAmount = entry.amount
+	item.LedgerDate = entry.transaction.CreatedAt
+	return item
+}
+
+// matchesReference tells if the reference of the bank is the id of the transaction or its description.
+func matchesReference(reference string, trx transactions.Transaction) bool {
+	reference = strings.TrimSpace(reference)
+	return strings.EqualFold(reference, trx.ID.String()) ||
+		(trx.Description != "" && strings.EqualFold(reference, strings.TrimSpace(trx.Description)))
+}
+
+func withinAmount(a, b, tolerance float64) bool {
+	return math.Abs(a-b) <= tolerance+amountEpsilon
+}
+
+// daysBetween counts the calendar days between the dates in UTC, ignoring the time of the day.
+func daysBetween(a, b time.Time) int {
+	a = a.UTC().Truncate(24 * time.Hour)
+	b = b.UTC().Truncate(24 * time.Hour)
+
+	days := int(a.Sub(b).Hours() / 24)
+	if days < 0 {
+		return -days
+	}
+	return days
+}
@@ -0,0 +1,78 @@
+//go:build unit
+
+package reconciliations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	accountID := uuid.New()
+	day := time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	byReference := transactions.Transaction{
+		ID:        uuid.New(),
+		To:        accountID,
+		Amount:    100,
+		CreatedAt: day.Add(5 * 24 * time.Hour),
+	}
+	nearest := transactions.Transaction{
+		ID:        uuid.New(),
+		From:      accountID,
+		Amount:    20,
+		CreatedAt: day.Add(26 * time.Hour),
+	}
+	farther := transactions.Transaction{
+		ID:        uuid.New(),
+		From:      accountID,
+		Amount:    20,
+		CreatedAt: day.Add(50 * time.Hour),
+	}
+	missing := transactions.Transaction{
+		ID:        uuid.New(),
+		To:        accountID,
+		Amount:    7,
+		CreatedAt: day.Add(3 * time.Hour),
+	}
+
+	lines := []ExternalLine{
+		{LineNumber: 2, Date: day, Amount: 100.01, Reference: byReference.ID.String()},
+		{LineNumber: 3, Date: day.Add(24 * time.Hour), Amount: -20},
+		{LineNumber: 4, Date: day.Add(24 * time.Hour), Amount: 55},
+	}
+
+	items := match(
+		accountID,
+		lines,
+		[]transactions.Transaction{missing, nearest, farther, byReference},
+		Tolerance{Amount: 0.05, Days: 1},
+	)
+
+	assert.Len(t, items, 5)
+
+	assert.Equal(t, MatchedStatus, items[0].Status)
+	assert.Equal(t, ReferenceMatchRule, items[0].Rule)
+	assert.Equal(t, byReference.ID, items[0].TransactionID.UUID)
+	assert.Equal(t, 100.0, items[0].LedgerAmount)
+
+	assert.Equal(t, MatchedStatus, items[1].Status)
+	assert.Equal(t, AmountDateMatchRule, items[1].Rule)
+	assert.Equal(t, nearest.ID, items[1].TransactionID.UUID)
+	assert.Equal(t, -20.0, items[1].LedgerAmount)
+
+	assert.Equal(t, UnmatchedExternalStatus, items[2].Status)
+	assert.False(t, items[2].TransactionID.Valid)
+	assert.Equal(t, 4, items[2].External.LineNumber)
+
+	assert.Equal(t, UnmatchedLedgerStatus, items[3].Status)
+	assert.Equal(t, missing.ID, items[3].TransactionID.UUID)
+	assert.Nil(t, items[3].External)
+
+	assert.Equal(t, UnmatchedLedgerStatus, items[4].Status)
+	assert.Equal(t, farther.ID, items[4].TransactionID.UUID)
+}
@@ -0,0 +1,138 @@
+package reconciliations
+
+import (
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type (
+	reconciliationModel struct {
+		bun.BaseModel `bun:"table:reconciliations,alias:rc"`
+
+		ID                uuid.UUID `bun:"id,pk"`
+		AccountID         uuid.UUID `bun:"account_id"`
+		Format            Format    `bun:"format"`
+		FileName          string    `bun:"file_name"`
+		PeriodBegin       time.Time `bun:"period_begin"`
+		PeriodEnd         time.Time `bun:"period_end"`
+		AmountTolerance   float64   `bun:"amount_tolerance"`
+		DateToleranceDays int       `bun:"date_tolerance_days"`
+		Matched           int       `bun:"matched"`
+		UnmatchedExternal int       `bun:"unmatched_external"`
+		UnmatchedLedger   int       `bun:"unmatched_ledger"`
+		TenantID          string    `bun:"tenant_id,nullzero"`
+		CreatedAt         time.Time `bun:"created_at,notnull"`
+	}
+
+	itemModel struct {
+		bun.BaseModel `bun:"table:reconciliation_items,alias:rci"`
+
+		ID                  uuid.UUID            `bun:"id,pk"`
+		ReconciliationID    uuid.UUID            `bun:"reconciliation_id"`
+		Status              ItemStatus           `bun:"status"`
+		Rule                MatchRule            `bun:"rule,nullzero"`
+		ExternalLineNumber  int                  `bun:"external_line_number,nullzero"`
+		ExternalDate        time.Time            `bun:"external_date,nullzero"`
+		ExternalAmount      database.NullFloat64 `bun:"external_amount"`
+		ExternalReference   string               `bun:"external_reference,nullzero"`
+		ExternalDescription string               `bun:"external_description,nullzero"`
+		TransactionID       uuid.NullUUID        `bun:"transaction_id"`
+		LedgerAmount        database.NullFloat64 `bun:"ledger_amount"`
+		LedgerDate          time.Time            `bun:"ledger_date,nullzero"`
+	}
+
+	reconciliationFilter struct {
+		ID uuid.UUID
+		// TenantID keeps only the reconciliations of the tenant, the reconciliations of all tenants when empty.
+		TenantID string
+	}
+
+	itemFilter struct {
+		ReconciliationID uuid.UUID
+		Status           ItemStatus
+	}
+)
+
+func newReconciliationModel(rc Reconciliation) reconciliationModel {
+	return reconciliationModel{
+		ID:                rc.ID,
+		AccountID:         rc.AccountID,
+		Format:            rc.Format,
+		FileName:          rc.FileName,
+		PeriodBegin:       rc.PeriodBegin,
+		PeriodEnd:         rc.PeriodEnd,
+		AmountTolerance:   rc.Tolerance.Amount,
+		DateToleranceDays: rc.Tolerance.Days,
+		Matched:           rc.Matched,
+		UnmatchedExternal: rc.UnmatchedExternal,
+		UnmatchedLedger:   rc.UnmatchedLedger,
+		CreatedAt:         rc.CreatedAt,
+	}
+}
+
+func newReconciliation(model reconciliationModel) Reconciliation {
+	return Reconciliation{
+		ID:                model.ID,
+		AccountID:         model.AccountID,
+		Format:            model.Format,
+		FileName:          model.FileName,
+		PeriodBegin:       model.PeriodBegin,
+		PeriodEnd:         model.PeriodEnd,
+		Tolerance:         Tolerance{Amount: model.AmountTolerance, Days: model.DateToleranceDays},
+		Matched:           model.Matched,
+		UnmatchedExternal: model.UnmatchedExternal,
+		UnmatchedLedger:   model.UnmatchedLedger,
+		CreatedAt:         model.CreatedAt,
+	}
+}
+
+func newItemModel(reconciliationID uuid.UUID, item Item) itemModel {
+	model := itemModel{
+		ID:               item.ID,
+		ReconciliationID: reconciliationID,
+		Status:           item.Status,
+		Rule:             item.Rule,
+		TransactionID:    item.TransactionID,
+	}
+
+	if item.External != nil {
+		model.ExternalLineNumber = item.External.LineNumber
+		model.ExternalDate = item.External.Date
+		model.ExternalAmount = database.NullFloat64{Float64: item.External.Amount, Valid: true}
+		model.ExternalReference = item.External.Reference
+		model.ExternalDescription = item.External.Description
+	}
+
+	if item.TransactionID.Valid {
+		model.LedgerAmount = database.NullFloat64{Float64: item.LedgerAmount, Valid: true}
+		model.LedgerDate = item.LedgerDate
+	}
+
+	return model
+}
+
+func newItem(model itemModel) Item {
+	item := Item{
+		ID:            model.ID,
+		Status:        model.Status,
+		Rule:          model.Rule,
+		TransactionID: model.TransactionID,
+		LedgerAmount:  model.LedgerAmount.Float64,
+		LedgerDate:    model.LedgerDate,
+	}
+
+	if model.ExternalAmount.Valid {
+		item.External = &ExternalLine{
+			LineNumber:  model.ExternalLineNumber,
+			Date:        model.ExternalDate,
+			Amount:      model.ExternalAmount.Float64,
+			Reference:   model.ExternalReference,
+			Description: model.ExternalDescription,
+		}
+	}
+
+	return item
+}
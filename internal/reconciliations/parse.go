@@ -0,0 +1,196 @@
+package reconciliations
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported reconciliation file format")
+	ErrInvalidFile       = errors.New("invalid reconciliation file")
+	ErrEmptyFile         = errors.New("reconciliation file has no lines")
+)
+
+// Parse reads the lines of a settlement file in the format.
+func Parse(format Format, r io.Reader) ([]ExternalLine, error) {
+	var lines []ExternalLine
+	var err error
+	switch format {
+	case CSVFormat:
+		lines, err = parseCSV(r)
+	case CNAB240Format:
+		lines, err = parseCNAB240(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	return lines, nil
+}
+
+var csvColumns = []string{"date", "amount", "type", "reference", "description"}
+
+// parseCSV reads a CSV with a header naming its columns, in any order. The date is a plain date (2006-01-02) or a
+// RFC3339 timestamp, the amount uses dot as decimal separator and the type is C for credits and D for debits.
+func parseCSV(r io.Reader) ([]ExternalLine, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fileError(1, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return nil, fileError(1, fmt.Errorf("missing column %s", name))
+		}
+	}
+
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var lines []ExternalLine
+	for lineNumber := 2; ; lineNumber++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		date, err := parseDate(column(record, "date"))
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		amount, err := strconv.ParseFloat(column(record, "amount"), 64)
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		amount, err = signAmount(amount, column(record, "type"))
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		lines = append(lines, ExternalLine{
+			LineNumber:  lineNumber,
+			Date:        date,
+			Amount:      amount,
+			Reference:   column(record, "reference"),
+			Description: column(record, "description"),
+		})
+	}
+
+	return lines, nil
+}
+
+const (
+	cnab240LineSize      = 240
+	cnab240DetailRecord  = '3'
+	cnab240SegmentE      = 'E'
+	cnab240DateLayout    = "02012006"
+	cnab240AmountDecimal = 100
+)
+
+// parseCNAB240 reads the entries of a CNAB 240 bank statement (extrato para conciliação bancária). Only the detail
+// records of segment E are entries, the headers and trailers of the file and of the batches are skipped.
+// Positions of segment E used, 1-based and inclusive: 146-153 entry date (DDMMAAAA), 154-171 amount with two
+// decimals, 172 type (C or D), 180-204 description and 205-240 document number, used as reference.
+func parseCNAB240(r io.Reader) ([]ExternalLine, error) {
+	scanner := bufio.NewScanner(r)
+
+	var lines []ExternalLine
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if len(line) != cnab240LineSize {
+			return nil, fileError(lineNumber, fmt.Errorf("line must have %d characters", cnab240LineSize))
+		}
+
+		if line[7] != cnab240DetailRecord || line[13] != cnab240SegmentE {
+			continue
+		}
+
+		date, err := time.Parse(cnab240DateLayout, line[145:153])
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		cents, err := strconv.ParseInt(line[153:171], 10, 64)
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		amount, err := signAmount(float64(cents)/cnab240AmountDecimal, line[171:172])
+		if err != nil {
+			return nil, fileError(lineNumber, err)
+		}
+
+		lines = append(lines, ExternalLine{
+			LineNumber:  lineNumber,
+			Date:        date,
+			Amount:      amount,
+			Reference:   strings.TrimSpace(line[204:240]),
+			Description: strings.TrimSpace(line[179:204]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fileError(0, err)
+	}
+
+	return lines, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// signAmount applies the type of the entry to the amount, C (credit) is money in and D (debit) is money out.
+func signAmount(amount float64, tp string) (float64, error) {
+	if amount < 0 {
+		return 0, errors.New("amount must be positive")
+	}
+
+	switch strings.ToUpper(tp) {
+	case "C":
+		return amount, nil
+	case "D":
+		return -amount, nil
+	default:
+		return 0, fmt.Errorf("type must be C or D, got %q", tp)
+	}
+}
+
+func fileError(lineNumber int, err error) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidFile, lineNumber, err.Error())
+}
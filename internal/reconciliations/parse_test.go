@@ -0,0 +1,112 @@
+//go:build unit
+
+package reconciliations
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Parse CSV", func(t *testing.T) {
+		file := "type,date,amount,reference,description\n" +
+			"C,2022-01-03,100.50,ref-1,Deposit\n" +
+			"d, 2022-01-04T10:00:00-03:00, 20,,Fee\n"
+
+		lines, err := Parse(CSVFormat, strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, []ExternalLine{
+			{
+				LineNumber:  2,
+				Date:        time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+				Amount:      100.50,
+				Reference:   "ref-1",
+				Description: "Deposit",
+			},
+			{
+				LineNumber:  3,
+				Date:        time.Date(2022, 1, 4, 13, 0, 0, 0, time.UTC),
+				Amount:      -20,
+				Description: "Fee",
+			},
+		}, mapUTC(lines))
+	})
+
+	t.Run("Do not parse CSV without required columns", func(t *testing.T) {
+		_, err := Parse(CSVFormat, strings.NewReader("date,amount\n2022-01-03,10\n"))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Do not parse CSV with invalid type", func(t *testing.T) {
+		_, err := Parse(CSVFormat, strings.NewReader("date,amount,type\n2022-01-03,10,X\n"))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Do not parse CSV without lines", func(t *testing.T) {
+		_, err := Parse(CSVFormat, strings.NewReader("date,amount,type\n"))
+		assert.ErrorIs(t, err, ErrEmptyFile)
+	})
+
+	t.Run("Parse CNAB 240", func(t *testing.T) {
+		file := strings.Join([]string{
+			cnab240Line('0', ' ', ""),
+			cnab240Line('1', ' ', ""),
+			cnab240Line('3', 'E', "03012022"+"000000000000010050"+"C"+"000"+"0000"+pad("TED RECEBIDA", 25)+pad("DOC123", 36)),
+			cnab240Line('3', 'E', "05012022"+"000000000000002000"+"D"+"000"+"0000"+pad("TARIFA", 25)+pad("", 36)),
+			cnab240Line('5', ' ', ""),
+			cnab240Line('9', ' ', ""),
+		}, "\r\n")
+
+		lines, err := Parse(CNAB240Format, strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, []ExternalLine{
+			{
+				LineNumber:  3,
+				Date:        time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC),
+				Amount:      100.50,
+				Reference:   "DOC123",
+				Description: "TED RECEBIDA",
+			},
+			{
+				LineNumber:  4,
+				Date:        time.Date(2022, 1, 5, 0, 0, 0, 0, time.UTC),
+				Amount:      -20,
+				Description: "TARIFA",
+			},
+		}, lines)
+	})
+
+	t.Run("Do not parse CNAB 240 with lines of wrong size", func(t *testing.T) {
+		_, err := Parse(CNAB240Format, strings.NewReader("00100000"))
+		assert.ErrorIs(t, err, ErrInvalidFile)
+	})
+
+	t.Run("Do not parse unsupported format", func(t *testing.T) {
+		_, err := Parse("xml", strings.NewReader(""))
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
+
+// cnab240Line builds a line of the record type and segment, with the entry fields starting at position 146.
+func cnab240Line(record, segment byte, entry string) string {
+	line := []byte(fmt.Sprintf("%-240s", "00100013"))
+	line[7] = record
+	line[13] = segment
+	copy(line[145:], entry)
+	return string(line)
+}
+
+func pad(s string, size int) string {
+	return fmt.Sprintf("%-*s", size, s)
+}
+
+func mapUTC(lines []ExternalLine) []ExternalLine {
+	for i := range lines {
+		lines[i].Date = lines[i].Date.UTC()
+	}
+	return lines
+}
@@ -0,0 +1,86 @@
+package reconciliations
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Format string
+
+const (
+	// CSVFormat is a CSV file with a header line naming the columns date, amount, type, reference and description.
+	CSVFormat Format = "csv"
+	// CNAB240Format is the FEBRABAN CNAB 240 bank statement file, its lines are read from the detail segment E.
+	CNAB240Format Format = "cnab240"
+)
+
+type ItemStatus string
+
+const (
+	// MatchedStatus is an external line matched to a ledger transaction.
+	MatchedStatus ItemStatus = "MATCHED"
+	// UnmatchedExternalStatus is an external line without a ledger transaction.
+	UnmatchedExternalStatus ItemStatus = "UNMATCHED_EXTERNAL"
+	// UnmatchedLedgerStatus is a ledger transaction in the period of the file without an external line.
+	UnmatchedLedgerStatus ItemStatus = "UNMATCHED_LEDGER"
+)
+
+type MatchRule string
+
+const (
+	// ReferenceMatchRule matched the reference of the line with the id or the description of the transaction.
+	ReferenceMatchRule MatchRule = "REFERENCE"
+	// AmountDateMatchRule matched the amount and the date of the line with the transaction, inside the tolerance.
+	AmountDateMatchRule MatchRule = "AMOUNT_DATE"
+)
+
+// Tolerance is how far the amount and the date of an external line can be from the transaction to match it.
+type Tolerance struct {
+	Amount float64
+	Days   int
+}
+
+// ExternalLine is a line of the settlement file of the partner bank.
+type ExternalLine struct {
+	LineNumber int
+	Date       time.Time
+	// Amount is signed from the account point of view: positive for money in and negative for money out.
+	Amount      float64
+	Reference   string
+	Description string
+}
+
+// Item is the result of the reconciliation of an external line, a ledger transaction or both when they matched.
+type Item struct {
+	ID            uuid.UUID
+	Status        ItemStatus
+	Rule          MatchRule
+	External      *ExternalLine
+	TransactionID uuid.NullUUID
+	// LedgerAmount is the amount of the transaction signed like ExternalLine.Amount.
+	LedgerAmount float64
+	LedgerDate   time.Time
+}
+
+type Reconciliation struct {
+	ID                uuid.UUID
+	AccountID         uuid.UUID
+	Format            Format
+	FileName          string
+	PeriodBegin       time.Time
+	PeriodEnd         time.Time
+	Tolerance         Tolerance
+	Matched           int
+	UnmatchedExternal int
+	UnmatchedLedger   int
+	CreatedAt         time.Time
+}
+
+// Request is a settlement file to reconcile with the transactions of the account.
+type Request struct {
+	AccountID uuid.UUID
+	Format    Format
+	FileName  string
+	Tolerance Tolerance
+}
@@ -0,0 +1,114 @@
+package reconciliations
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model reconciliationModel, items []itemModel) (reconciliationModel, error)
+	GetByFilter(ctx context.Context, filter reconciliationFilter) ([]reconciliationModel, error)
+	ListItems(ctx context.Context, filter itemFilter) ([]itemModel, error)
+}
+
+type repository struct {
+	tracer tracer.Tracer
+	db     database.Database
+}
+
+func NewRepository(t tracer.Tracer, db database.Database) Repository {
+	return repository{
+		tracer: t,
+		db:     db,
+	}
+}
+
+// Create stores the reconciliation with all its items in the same transaction.
+func (r repository) Create(
+	ctx context.Context,
+	model reconciliationModel,
+	items []itemModel,
+) (reconciliationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	err := r.db.Master(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(&model).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		_, err = tx.NewInsert().
+			Model(&items).
+			Exec(ctx)
+		return err
+	})
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return reconciliationModel{}, err
+	}
+
+	return model, nil
+}
+
+func (r repository) GetByFilter(ctx context.Context, filter reconciliationFilter) ([]reconciliationModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []reconciliationModel
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		Model(&models).
+		Where("rc.id = ?", filter.ID)
+
+	if filter.TenantID != "" {
+		selectQuery.Where("rc.tenant_id = ?", filter.TenantID)
+	}
+
+	err := selectQuery.Scan(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
+
+// ListItems returns the items of the reconciliation, the external lines in the order of the file followed by the
+// transactions missing in the file.
+func (r repository) ListItems(ctx context.Context, filter itemFilter) ([]itemModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	var models []itemModel
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		Model(&models).
+		Where("rci.reconciliation_id = ?", filter.ReconciliationID).
+		OrderExpr("rci.external_line_number ASC NULLS LAST, rci.ledger_date ASC")
+
+	if filter.Status != "" {
+		selectQuery.Where("rci.status = ?", filter.Status)
+	}
+
+	err := selectQuery.Scan(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return models, nil
+}
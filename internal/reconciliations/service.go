@@ -0,0 +1,173 @@
+package reconciliations
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReconciliationNotFound = errors.New("no reconciliation found with this id")
+	ErrInvalidTolerance       = errors.New("tolerance of amount and days must not be negative")
+)
+
+type Service interface {
+	Reconcile(ctx context.Context, req Request, file io.Reader) (Reconciliation, error)
+	GetByID(ctx context.Context, id uuid.UUID) (Reconciliation, error)
+	ListItems(ctx context.Context, id uuid.UUID, status ItemStatus) ([]Item, error)
+}
+
+type service struct {
+	tracer          tracer.Tracer
+	repository      Repository
+	accountsSvc     accounts.Service
+	transactionsSvc transactions.Service
+}
+
+func NewService(t tracer.Tracer, r Repository, as accounts.Service, ts transactions.Service) Service {
+	return service{tracer: t, repository: r, accountsSvc: as, transactionsSvc: ts}
+}
+
+// Reconcile matches the lines of the settlement file with the transactions of the account and stores the result.
+// The period of the reconciliation goes from the first to the last day of the file. Transactions up to the date
+// tolerance outside of it can still match lines, but only the ones inside of it are reported as missing in the file.
+func (s service) Reconcile(ctx context.Context, req Request, file io.Reader) (Reconciliation, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if req.Tolerance.Amount < 0 || req.Tolerance.Days < 0 {
+		span.RecordError(ErrInvalidTolerance)
+		return Reconciliation{}, ErrInvalidTolerance
+	}
+
+	_, err := s.accountsSvc.GetByID(ctx, req.AccountID)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"reconciliation_service_account_error",
+			zap.String("account_id", req.AccountID.String()),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return Reconciliation{}, err
+	}
+
+	lines, err := Parse(req.Format, file)
+	if err != nil {
+		zapctx.L(ctx).Error("reconciliation_service_parse_error", zap.Error(err))
+		span.RecordError(err)
+		return Reconciliation{}, err
+	}
+
+	periodBegin, periodEnd := period(lines)
+	margin := time.Duration(req.Tolerance.Days) * 24 * time.Hour
+
+	trxs, err := s.transactionsSvc.ListByAccount(ctx, req.AccountID, periodBegin.Add(-margin), periodEnd.Add(margin))
+	if err != nil {
+		zapctx.L(ctx).Error("reconciliation_service_transactions_error", zap.Error(err))
+		span.RecordError(err)
+		return Reconciliation{}, err
+	}
+
+	rc := Reconciliation{
+		ID:          uuid.New(),
+		AccountID:   req.AccountID,
+		Format:      req.Format,
+		FileName:    req.FileName,
+		PeriodBegin: periodBegin,
+		PeriodEnd:   periodEnd,
+		Tolerance:   req.Tolerance,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	var items []itemModel
+	for _, item := range match(req.AccountID, lines, trxs, req.Tolerance) {
+		switch item.Status {
+		case MatchedStatus:
+			rc.Matched++
+		case UnmatchedExternalStatus:
+			rc.UnmatchedExternal++
+		case UnmatchedLedgerStatus:
+			if item.LedgerDate.Before(periodBegin) || item.LedgerDate.After(periodEnd) {
+				continue
+			}
+			rc.UnmatchedLedger++
+		}
+
+		item.ID = uuid.New()
+		items = append(items, newItemModel(rc.ID, item))
+	}
+
+	model, err := s.repository.Create(ctx, newReconciliationModel(rc), items)
+	if err != nil {
+		zapctx.L(ctx).Error("reconciliation_service_create_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Reconciliation{}, err
+	}
+
+	return newReconciliation(model), nil
+}
+
+func (s service) GetByID(ctx context.Context, id uuid.UUID) (Reconciliation, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	models, err := s.repository.GetByFilter(ctx, reconciliationFilter{ID: id, TenantID: tenancy.FilterID(ctx)})
+	if err != nil {
+		zapctx.L(ctx).Error("reconciliation_service_get_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Reconciliation{}, err
+	}
+
+	if len(models) != 1 {
+		span.RecordError(ErrReconciliationNotFound)
+		return Reconciliation{}, ErrReconciliationNotFound
+	}
+
+	return newReconciliation(models[0]), nil
+}
+
+// ListItems returns the items of the reconciliation in the status, all of them when status is empty.
+func (s service) ListItems(ctx context.Context, id uuid.UUID, status ItemStatus) ([]Item, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	models, err := s.repository.ListItems(ctx, itemFilter{ReconciliationID: id, Status: status})
+	if err != nil {
+		zapctx.L(ctx).Error("reconciliation_service_list_items_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return nil, err
+	}
+
+	items := make([]Item, len(models))
+	for i, model := range models {
+		items[i] = newItem(model)
+	}
+
+	return items, nil
+}
+
+// period returns the first instant of the first day and the last instant of the last day of the lines.
+func period(lines []ExternalLine) (time.Time, time.Time) {
+	first, last := lines[0].Date, lines[0].Date
+	for _, line := range lines[1:] {
+		if line.Date.Before(first) {
+			first = line.Date
+		}
+		if line.Date.After(last) {
+			last = line.Date
+		}
+	}
+
+	begin := first.UTC().Truncate(24 * time.Hour)
+	end := last.UTC().Truncate(24 * time.Hour).Add(24*time.Hour - time.Microsecond)
+	return begin, end
+}
@@ -0,0 +1,106 @@
+//go:build unit
+
+package reconciliations
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	trxSvcMock := transactions.NewMockService(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, accSvcMock, trxSvcMock)
+
+	accountID := uuid.New()
+	req := Request{
+		AccountID: accountID,
+		Format:    CSVFormat,
+		FileName:  "settlement.csv",
+		Tolerance: Tolerance{Amount: 0, Days: 1},
+	}
+	file := "date,amount,type\n2022-01-03,10,C\n2022-01-04,5,D\n"
+
+	t.Run("fail reconcile, negative tolerance", func(t *testing.T) {
+		rc, err := svc.Reconcile(ctx, Request{AccountID: accountID, Tolerance: Tolerance{Days: -1}}, strings.NewReader(file))
+		assert.ErrorIs(t, err, ErrInvalidTolerance)
+		assert.Empty(t, rc)
+	})
+
+	t.Run("fail reconcile, account not found", func(t *testing.T) {
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID).
+			Return(accounts.Account{}, accounts.ErrAccountNotFound)
+
+		rc, err := svc.Reconcile(ctx, req, strings.NewReader(file))
+		assert.ErrorIs(t, err, accounts.ErrAccountNotFound)
+		assert.Empty(t, rc)
+	})
+
+	t.Run("success reconcile", func(t *testing.T) {
+		matched := transactions.Transaction{
+			ID:        uuid.New(),
+			To:        accountID,
+			Amount:    10,
+			CreatedAt: time.Date(2022, 1, 3, 12, 0, 0, 0, time.UTC),
+		}
+		missing := transactions.Transaction{
+			ID:        uuid.New(),
+			To:        accountID,
+			Amount:    3,
+			CreatedAt: time.Date(2022, 1, 4, 12, 0, 0, 0, time.UTC),
+		}
+		outsidePeriod := transactions.Transaction{
+			ID:        uuid.New(),
+			From:      accountID,
+			Amount:    1,
+			CreatedAt: time.Date(2022, 1, 5, 8, 0, 0, 0, time.UTC),
+		}
+
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID).
+			Return(accounts.Account{ID: accountID}, nil)
+
+		trxSvcMock.EXPECT().
+			ListByAccount(
+				gomock.Any(),
+				accountID,
+				time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC),
+				time.Date(2022, 1, 5, 23, 59, 59, 999999000, time.UTC),
+			).
+			Return([]transactions.Transaction{matched, missing, outsidePeriod}, nil)
+
+		repoMock.EXPECT().
+			Create(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, model reconciliationModel, items []itemModel) (reconciliationModel, error) {
+				assert.Len(t, items, 3)
+				for _, item := range items {
+					assert.Equal(t, model.ID, item.ReconciliationID)
+				}
+				return model, nil
+			})
+
+		rc, err := svc.Reconcile(ctx, req, strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, rc.Matched)
+		assert.Equal(t, 1, rc.UnmatchedExternal)
+		assert.Equal(t, 1, rc.UnmatchedLedger)
+		assert.Equal(t, time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), rc.PeriodBegin)
+		assert.Equal(t, time.Date(2022, 1, 4, 23, 59, 59, 999999000, time.UTC), rc.PeriodEnd)
+	})
+}
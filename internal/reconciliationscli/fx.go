@@ -0,0 +1,170 @@
+package reconciliationscli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliationscli/internal/environment"
+	"github.com/dalmarcogd/ledger-exp/internal/transactions"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/distlock"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"go.uber.org/fx"
+)
+
+const (
+	// ExitUnmatched is the exit code when the reconciliation finished with unmatched items.
+	ExitUnmatched = 2
+	// ExitError is the exit code when the reconciliation could not be done.
+	ExitError = 1
+)
+
+var errUnmatched = errors.New("reconciliation has unmatched items")
+
+// Options are the arguments of the command line.
+type Options struct {
+	FilePath  string
+	Request   reconciliations.Request
+	ReportOut io.Writer
+}
+
+// Module reconciles the file of the Options and writes the report, it must be supplied with the Options.
+var Module = fx.Options(
+	// Infra
+	fx.Provide(
+		environment.NewEnvironment,
+		func(
+			lc fx.Lifecycle,
+			e environment.Environment,
+			t tracer.Tracer,
+			m metrics.Metrics,
+		) (database.Database, error) {
+			return database.Setup(lc, t, m, database.Config{MasterURL: e.DatabaseURL})
+		},
+		func(env environment.Environment) (redis.Client, error) {
+			return redis.NewClient(env.RedisURL, env.RedisCACert)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (tracer.Tracer, error) {
+			return tracer.Setup(
+				lc,
+				e.OtelCollectorHost,
+				e.Service,
+				e.Environment,
+				e.Version,
+				tracer.WithConfig(e.Tracing),
+			)
+		},
+		func(lc fx.Lifecycle, e environment.Environment) (metrics.Metrics, error) {
+			return metrics.Setup(lc, e.OtelCollectorHost, e.Service, e.Environment, e.Version)
+		},
+		distlock.NewDistock,
+	),
+	// Domains
+	fx.Provide(
+		database.NewUnitOfWork,
+		holders.NewRepository,
+		accounts.NewRepository,
+		accounts.NewService,
+		balances.NewRepository,
+		balances.NewService,
+		businessdays.NewRepository,
+		businessdays.NewService,
+		transactions.NewRepository,
+		transactions.NewService,
+		reconciliations.NewRepository,
+		reconciliations.NewService,
+	),
+	fx.Invoke(runReconciliation),
+)
+
+// runReconciliation runs the reconciliation once the application started and shuts it down when it finishes.
+func runReconciliation(
+	lc fx.Lifecycle,
+	shutdowner fx.Shutdowner,
+	opts Options,
+	svc reconciliations.Service,
+) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			go func() {
+				defer close(done)
+
+				exitCode := 0
+				if err := reconcile(ctx, svc, opts); errors.Is(err, errUnmatched) {
+					exitCode = ExitUnmatched
+				} else if err != nil {
+					fmt.Fprintln(os.Stderr, err)
+					exitCode = ExitError
+				}
+
+				_ = shutdowner.Shutdown(fx.ExitCode(exitCode))
+			}()
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+}
+
+func reconcile(ctx context.Context, svc reconciliations.Service, opts Options) error {
+	file, err := os.Open(opts.FilePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	req := opts.Request
+	if req.FileName == "" {
+		req.FileName = filepath.Base(opts.FilePath)
+	}
+
+	rc, err := svc.Reconcile(ctx, req, file)
+	if err != nil {
+		return err
+	}
+
+	var items []reconciliations.Item
+	if rc.UnmatchedExternal > 0 || rc.UnmatchedLedger > 0 {
+		external, err := svc.ListItems(ctx, rc.ID, reconciliations.UnmatchedExternalStatus)
+		if err != nil {
+			return err
+		}
+
+		ledger, err := svc.ListItems(ctx, rc.ID, reconciliations.UnmatchedLedgerStatus)
+		if err != nil {
+			return err
+		}
+
+		items = append(external, ledger...)
+	}
+
+	if err := writeReport(opts.ReportOut, rc, items); err != nil {
+		return err
+	}
+
+	if len(items) > 0 {
+		return errUnmatched
+	}
+	return nil
+}
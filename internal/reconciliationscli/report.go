@@ -0,0 +1,50 @@
+package reconciliationscli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/dalmarcogd/ledger-exp/internal/reconciliations"
+)
+
+// writeReport writes the summary of the reconciliation followed by its unmatched items as a table.
+func writeReport(w io.Writer, rc reconciliations.Reconciliation, unmatched []reconciliations.Item) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "reconciliation\t%s\n", rc.ID)
+	fmt.Fprintf(tw, "account\t%s\n", rc.AccountID)
+	fmt.Fprintf(tw, "file\t%s (%s)\n", rc.FileName, rc.Format)
+	fmt.Fprintf(tw, "period\t%s to %s\n", rc.PeriodBegin.Format("2006-01-02"), rc.PeriodEnd.Format("2006-01-02"))
+	fmt.Fprintf(tw, "matched\t%d\n", rc.Matched)
+	fmt.Fprintf(tw, "unmatched in file\t%d\n", rc.UnmatchedExternal)
+	fmt.Fprintf(tw, "unmatched in ledger\t%d\n", rc.UnmatchedLedger)
+
+	if len(unmatched) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SIDE\tLINE/TRANSACTION\tDATE\tAMOUNT\tREFERENCE")
+		for _, item := range unmatched {
+			if item.External != nil {
+				fmt.Fprintf(
+					tw,
+					"file\t%d\t%s\t%.2f\t%s\n",
+					item.External.LineNumber,
+					item.External.Date.Format("2006-01-02"),
+					item.External.Amount,
+					item.External.Reference,
+				)
+				continue
+			}
+
+			fmt.Fprintf(
+				tw,
+				"ledger\t%s\t%s\t%.2f\t\n",
+				item.TransactionID.UUID,
+				item.LedgerDate.Format("2006-01-02"),
+				item.LedgerAmount,
+			)
+		}
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,110 @@
+package statements
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type csvExporter struct {
+	w            *csv.Writer
+	createdAtEnd time.Time
+}
+
+func newCSVExporter(w io.Writer) Exporter {
+	return &csvExporter{w: csv.NewWriter(w)}
+}
+
+func (e *csvExporter) WriteHeader(header ExportHeader) error {
+	e.createdAtEnd = header.CreatedAtEnd
+
+	err := e.w.Write([]string{
+		"created_at",
+		"id",
+		"type",
+		"description",
+		"from_account_id",
+		"from_account_name",
+		"to_account_id",
+		"to_account_name",
+		"amount",
+		"balance",
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.w.Write([]string{
+		header.CreatedAtBegin.UTC().Format(time.RFC3339),
+		"",
+		"OPENING_BALANCE",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		formatAmount(header.OpeningBalance),
+	})
+}
+
+func (e *csvExporter) WriteLine(line ExportLine) error {
+	stm := line.Statement
+	return e.w.Write([]string{
+		stm.CreatedAt.UTC().Format(time.RFC3339),
+		stm.ID.String(),
+		stm.Type,
+		csvText(stm.Description),
+		uuidEmpty(stm.FromAccount.ID),
+		csvText(stm.FromAccount.Name),
+		uuidEmpty(stm.ToAccount.ID),
+		csvText(stm.ToAccount.Name),
+		formatAmount(line.Amount),
+		formatAmount(line.Balance),
+	})
+}
+
+func (e *csvExporter) WriteFooter(footer ExportFooter) error {
+	err := e.w.Write([]string{
+		e.createdAtEnd.UTC().Format(time.RFC3339),
+		"",
+		"CLOSING_BALANCE",
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+		formatAmount(footer.ClosingBalance),
+	})
+	if err != nil {
+		return err
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvText returns the free text s as a cell, prefixed by ' when it starts like a formula, so the spreadsheets opening
+// the statement show it as text instead of evaluating it.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func uuidEmpty(u uuid.UUID) string {
+	if u == uuid.Nil {
+		return ""
+	}
+	return u.String()
+}
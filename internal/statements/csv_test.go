@@ -0,0 +1,57 @@
+//go:build unit
+
+package statements
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVExporter_WriteLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		want        string
+	}{
+		{name: "Keep the plain text", description: "Rent of May", want: "Rent of May"},
+		{name: "Escape =", description: "=HYPERLINK(\"http://evil\")", want: "'=HYPERLINK(\"http://evil\")"},
+		{name: "Escape +", description: "+1+1", want: "'+1+1"},
+		{name: "Escape -", description: "-2+3", want: "'-2+3"},
+		{name: "Escape @", description: "@SUM(A1:A2)", want: "'@SUM(A1:A2)"},
+		{name: "Escape tab", description: "\t=1", want: "'\t=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			exporter := newCSVExporter(&buf)
+
+			err := exporter.WriteLine(ExportLine{
+				Statement: Statement{
+					ID:          uuid.New(),
+					Type:        "DEBIT",
+					Description: tt.description,
+					FromAccount: accounts.Account{ID: uuid.New(), Name: tt.description},
+					CreatedAt:   time.Now(),
+				},
+				Amount:  -10,
+				Balance: -5,
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, exporter.WriteFooter(ExportFooter{}))
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, records[0][3])
+			assert.Equal(t, tt.want, records[0][5])
+			// The amounts are numbers, never escaped.
+			assert.Equal(t, "-10.00", records[0][8])
+			assert.Equal(t, "-5.00", records[0][9])
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package statements
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+)
+
+type ExportFormat string
+
+const (
+	CSVExportFormat ExportFormat = "csv"
+	OFXExportFormat ExportFormat = "ofx"
+	PDFExportFormat ExportFormat = "pdf"
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported statement export format")
+
+// ContentType returns the media type of the documents rendered in this format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case CSVExportFormat:
+		return "text/csv"
+	case OFXExportFormat:
+		return "application/x-ofx"
+	case PDFExportFormat:
+		return "application/pdf"
+	default:
+		return ""
+	}
+}
+
+type (
+	ExportHeader struct {
+		Holder  holders.Holder
+		Account accounts.Account
+		// BankCode is the code of the institution of the account, the one of its tenant.
+		BankCode       string
+		CreatedAtBegin time.Time
+		CreatedAtEnd   time.Time
+		OpeningBalance float64
+	}
+
+	ExportLine struct {
+		Statement Statement
+		// Amount is signed from the account point of view: positive for money in and negative for money out.
+		Amount float64
+		// Balance is the balance of the account right after the statement.
+		Balance float64
+	}
+
+	ExportFooter struct {
+		ClosingBalance float64
+		TotalCredits   float64
+		TotalDebits    float64
+	}
+)
+
+// Exporter renders a statement while it is read from the database, receiving the header once, then every line in
+// chronological order and finally the footer.
+type Exporter interface {
+	WriteHeader(header ExportHeader) error
+	WriteLine(line ExportLine) error
+	WriteFooter(footer ExportFooter) error
+}
+
+// NewExporter returns the Exporter of the format writing to w.
+func NewExporter(format ExportFormat, w io.Writer) (Exporter, error) {
+	switch format {
+	case CSVExportFormat:
+		return newCSVExporter(w), nil
+	case OFXExportFormat:
+		return newOFXExporter(w), nil
+	case PDFExportFormat:
+		return newPDFExporter(w), nil
+	default:
+		return nil, ErrUnsupportedExportFormat
+	}
+}
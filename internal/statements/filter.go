@@ -3,9 +3,21 @@ package statements
 import (
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/google/uuid"
 )
 
+// Direction is the side of the transaction from the point of view of the account of the statement.
+type Direction string
+
+const (
+	// DirectionIn is the money in of the account, it received the amount.
+	DirectionIn Direction = "in"
+	// DirectionOut is the money out of the account, it sent the amount.
+	DirectionOut Direction = "out"
+)
+
 type ListFilter struct {
 	Sort           int
 	Page           int
@@ -13,4 +25,24 @@ type ListFilter struct {
 	AccountID      uuid.UUID
 	CreatedAtBegin time.Time
 	CreatedAtEnd   time.Time
+	// Types keeps only the transactions of these types, all of them when empty.
+	Types []string
+	// Direction keeps only the money in or the money out of the account, both when empty.
+	Direction Direction
+	MinAmount database.NullFloat64
+	MaxAmount database.NullFloat64
+	// CounterpartyAccountID keeps only the transactions between the account and the counterparty account.
+	CounterpartyAccountID uuid.NullUUID
+	// Description is a full text search over the description of the transactions.
+	Description string
+	// Cursor selects the page by keyset instead of by Page, it has precedence over Page when not nil.
+	Cursor *cursor.Cursor
+	// WithTotal counts all items matched by the filter, what is expensive for big listings.
+	WithTotal bool
+}
+
+type ExportFilter struct {
+	AccountID      uuid.UUID
+	CreatedAtBegin time.Time
+	CreatedAtEnd   time.Time
 }
@@ -3,6 +3,8 @@ package statements
 import (
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
 )
@@ -19,15 +21,36 @@ type (
 		Type            string    `bun:"type"`
 		Amount          float64   `bun:"amount"`
 		Description     string    `bun:"description"`
+		Adjustment      bool      `bun:"adjustment"`
+		TenantID        string    `bun:"tenant_id"`
 		CreatedAt       time.Time `bun:"created_at"`
+		// Balance is the balance of the account right after this transaction.
+		Balance float64 `bun:"balance,scanonly"`
+	}
+
+	summaryModel struct {
+		OpeningBalance float64 `bun:"opening_balance"`
+		ClosingBalance float64 `bun:"closing_balance"`
+		TotalCredits   float64 `bun:"total_credits"`
+		TotalDebits    float64 `bun:"total_debits"`
 	}
 
 	StatementFilter struct {
-		Page           int
-		Size           int
-		Sort           int
-		AccountID      uuid.UUID
-		CreatedAtBegin time.Time
-		CreatedAtEnd   time.Time
+		Page                  int
+		Size                  int
+		Sort                  int
+		AccountID             uuid.UUID
+		CreatedAtBegin        time.Time
+		CreatedAtEnd          time.Time
+		Types                 []string
+		Direction             Direction
+		MinAmount             database.NullFloat64
+		MaxAmount             database.NullFloat64
+		CounterpartyAccountID uuid.NullUUID
+		Description           string
+		Cursor                *cursor.Cursor
+		WithTotal             bool
+		// TenantID keeps only the transactions of the tenant, the transactions of all tenants when empty.
+		TenantID string
 	}
 )
@@ -0,0 +1,118 @@
+package statements
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ofxCurrency is the currency of every account of the ledger.
+const ofxCurrency = "BRL"
+
+type ofxExporter struct {
+	w            *bufio.Writer
+	createdAtEnd time.Time
+}
+
+func newOFXExporter(w io.Writer) Exporter {
+	return &ofxExporter{w: bufio.NewWriter(w)}
+}
+
+func (e *ofxExporter) WriteHeader(header ExportHeader) error {
+	e.createdAtEnd = header.CreatedAtEnd
+
+	now := formatOFXTime(time.Now())
+	_, err := fmt.Fprintf(
+		e.w,
+		`<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS><DTSERVER>%s</DTSERVER><LANGUAGE>POR</LANGUAGE></SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>%s</TRNUID><STATUS><CODE>0</CODE><SEVERITY>INFO</SEVERITY></STATUS>
+<STMTRS><CURDEF>%s</CURDEF>
+<BANKACCTFROM><BANKID>%s</BANKID><BRANCHID>%s</BRANCHID><ACCTID>%s</ACCTID><ACCTTYPE>CHECKING</ACCTTYPE></BANKACCTFROM>
+<BANKTRANLIST><DTSTART>%s</DTSTART><DTEND>%s</DTEND>
+`,
+		now,
+		uuid.New().String(),
+		ofxCurrency,
+		escapeXML(header.BankCode),
+		escapeXML(header.Account.Agency),
+		escapeXML(header.Account.Number),
+		formatOFXTime(header.CreatedAtBegin),
+		formatOFXTime(header.CreatedAtEnd),
+	)
+	return err
+}
+
+func (e *ofxExporter) WriteLine(line ExportLine) error {
+	stm := line.Statement
+
+	name := stm.ToAccount.Name
+	if line.Amount > 0 {
+		name = stm.FromAccount.Name
+	}
+
+	_, err := fmt.Fprintf(
+		e.w,
+		"<STMTTRN><TRNTYPE>%s</TRNTYPE><DTPOSTED>%s</DTPOSTED><TRNAMT>%s</TRNAMT><FITID>%s</FITID>%s<MEMO>%s</MEMO></STMTTRN>\n",
+		ofxTransactionType(stm.Type, line.Amount),
+		formatOFXTime(stm.CreatedAt),
+		formatAmount(line.Amount),
+		stm.ID.String(),
+		ofxOptionalElement("NAME", name),
+		escapeXML(stm.Description),
+	)
+	return err
+}
+
+func (e *ofxExporter) WriteFooter(footer ExportFooter) error {
+	_, err := fmt.Fprintf(
+		e.w,
+		`</BANKTRANLIST>
+<LEDGERBAL><BALAMT>%s</BALAMT><DTASOF>%s</DTASOF></LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`,
+		formatAmount(footer.ClosingBalance),
+		formatOFXTime(e.createdAtEnd),
+	)
+	if err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+func ofxTransactionType(trxType string, amount float64) string {
+	switch {
+	case trxType == "P2P":
+		return "XFER"
+	case amount < 0:
+		return "DEBIT"
+	default:
+		return "CREDIT"
+	}
+}
+
+func ofxOptionalElement(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	return fmt.Sprintf("<%s>%s</%s>", name, escapeXML(value), name)
+}
+
+func formatOFXTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,32 @@
+//go:build unit
+
+package statements
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOFXExporter_WriteHeader(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := newOFXExporter(&buf)
+
+	err := exporter.WriteHeader(ExportHeader{
+		Account:        accounts.Account{Agency: "0001", Number: "12345"},
+		BankCode:       "260",
+		CreatedAtBegin: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAtEnd:   time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, exporter.WriteFooter(ExportFooter{}))
+
+	assert.Contains(
+		t,
+		buf.String(),
+		"<BANKACCTFROM><BANKID>260</BANKID><BRANCHID>0001</BRANCHID><ACCTID>12345</ACCTID>",
+	)
+}
@@ -0,0 +1,157 @@
+package statements
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/pdf"
+)
+
+const (
+	pdfMargin     = 40
+	pdfLineHeight = 12
+	pdfFontSize   = 7
+)
+
+type pdfExporter struct {
+	out    io.Writer
+	w      *pdf.Writer
+	y      float64
+	pages  int
+	header ExportHeader
+}
+
+func newPDFExporter(w io.Writer) Exporter {
+	return &pdfExporter{out: w}
+}
+
+func (e *pdfExporter) WriteHeader(header ExportHeader) error {
+	w, err := pdf.NewWriter(e.out, pdf.A4)
+	if err != nil {
+		return err
+	}
+	e.w = w
+	e.header = header
+
+	return e.newPage()
+}
+
+func (e *pdfExporter) WriteLine(line ExportLine) error {
+	stm := line.Statement
+
+	counterparty := stm.ToAccount.Name
+	if line.Amount > 0 {
+		counterparty = stm.FromAccount.Name
+	}
+
+	return e.writeRow(fmt.Sprintf(
+		"%-19s %-6s %-24.24s %-30.30s %14s %14s",
+		stm.CreatedAt.UTC().Format("2006-01-02 15:04:05"),
+		stm.Type,
+		counterparty,
+		stm.Description,
+		formatAmount(line.Amount),
+		formatAmount(line.Balance),
+	))
+}
+
+func (e *pdfExporter) WriteFooter(footer ExportFooter) error {
+	rows := []string{
+		"",
+		fmt.Sprintf("%-82s %28s", "Total credits", formatAmount(footer.TotalCredits)),
+		fmt.Sprintf("%-82s %28s", "Total debits", formatAmount(footer.TotalDebits)),
+		fmt.Sprintf(
+			"%-82s %28s",
+			"Closing balance at "+e.header.CreatedAtEnd.UTC().Format(time.RFC3339),
+			formatAmount(footer.ClosingBalance),
+		),
+	}
+	for _, row := range rows {
+		if err := e.writeRow(row); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Close()
+}
+
+func (e *pdfExporter) writeRow(row string) error {
+	if e.y < pdfMargin {
+		if err := e.newPage(); err != nil {
+			return err
+		}
+	}
+
+	err := e.w.Text(pdfMargin, e.y, pdf.Courier, pdfFontSize, row)
+	e.y -= pdfLineHeight
+	return err
+}
+
+// newPage starts a page repeating the holder and account identification, so every page can be read on its own.
+func (e *pdfExporter) newPage() error {
+	if err := e.w.AddPage(); err != nil {
+		return err
+	}
+	e.pages++
+
+	size := e.w.Size()
+	width := size.Width - 2*pdfMargin
+	e.y = size.Height - pdfMargin
+
+	acc := e.header.Account
+	texts := []struct {
+		font pdf.Font
+		size float64
+		text string
+	}{
+		{font: pdf.HelveticaBold, size: 14, text: "Account statement"},
+		{font: pdf.Helvetica, size: 10, text: fmt.Sprintf("Holder: %s - %s", e.header.Holder.Name, e.header.Holder.DocumentNumber)},
+		{font: pdf.Helvetica, size: 10, text: fmt.Sprintf("Account: %s - agency %s number %s", acc.Name, acc.Agency, acc.Number)},
+		{
+			font: pdf.Helvetica,
+			size: 10,
+			text: fmt.Sprintf(
+				"Period: %s to %s",
+				e.header.CreatedAtBegin.UTC().Format(time.RFC3339),
+				e.header.CreatedAtEnd.UTC().Format(time.RFC3339),
+			),
+		},
+	}
+	for _, t := range texts {
+		if err := e.w.Text(pdfMargin, e.y, t.font, t.size, t.text); err != nil {
+			return err
+		}
+		e.y -= t.size + 6
+	}
+
+	if err := e.w.Line(pdfMargin, e.y+pdfLineHeight/2, pdfMargin+width, e.y+pdfLineHeight/2); err != nil {
+		return err
+	}
+	e.y -= pdfLineHeight / 2
+
+	err := e.w.Text(
+		pdfMargin,
+		e.y,
+		pdf.Courier,
+		pdfFontSize,
+		fmt.Sprintf(
+			"%-19s %-6s %-24s %-30s %14s %14s",
+			"Date",
+			"Type",
+			"Counterparty",
+			"Description",
+			"Amount",
+			"Balance",
+		),
+	)
+	if err != nil {
+		return err
+	}
+	e.y -= pdfLineHeight
+
+	if e.pages == 1 {
+		return e.writeRow(fmt.Sprintf("%-82s %28s", "Opening balance", formatAmount(e.header.OpeningBalance)))
+	}
+	return nil
+}
@@ -2,13 +2,23 @@ package statements
 
 import (
 	"context"
+	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
 )
 
+// queryTimeout is the default deadline of the pages and the summaries of the statements, the streams are only
+// bounded by the deadline of their ctx.
+const queryTimeout = 5 * time.Second
+
 type Repository interface {
 	ListByFilter(ctx context.Context, filter StatementFilter) (int, []statementModel, error)
+	IterateByFilter(ctx context.Context, filter StatementFilter, fn func(statementModel) error) error
+	GetSummary(ctx context.Context, filter StatementFilter) (summaryModel, error)
 }
 
 type repository struct {
@@ -27,6 +37,9 @@ func (r repository) ListByFilter(ctx context.Context, filter StatementFilter) (i
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
 	page := filter.Page
 	if page == 0 {
 		page = 1
@@ -37,28 +50,186 @@ func (r repository) ListByFilter(ctx context.Context, filter StatementFilter) (i
 		size = 20
 	}
 
-	selectQuery := r.db.Replica().
+	selectQuery := r.selectByFilter(ctx, filter).
+		Limit(size)
+
+	var total int
+	var err error
+	if filter.WithTotal {
+		// Counted before the keyset restriction, the total is about the whole listing and not what is after the cursor.
+		total, err = selectQuery.Count(ctx)
+		if err != nil {
+			err = database.TranslateError(err, nil)
+			span.RecordError(err)
+			return 0, []statementModel{}, err
+		}
+	}
+
+	var reversed bool
+	if filter.Cursor != nil {
+		reversed = cursor.Keyset(selectQuery, *filter.Cursor, filter.Sort > 0, "trx.created_at", "trx.id")
+	} else {
+		selectQuery.Offset((page - 1) * size)
+
+		if filter.Sort == 0 {
+			selectQuery.Order("trx.created_at ASC")
+		} else if filter.Sort > 0 {
+			selectQuery.Order("trx.created_at DESC")
+		}
+	}
+
+	var stms []statementModel
+	err = selectQuery.Scan(ctx, &stms)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return 0, []statementModel{}, err
+	}
+
+	if reversed {
+		cursor.Reverse(stms)
+	}
+
+	return total, stms, nil
+}
+
+// IterateByFilter streams in chronological order all statements matched by the filter, calling fn for each
+// one of them. Pagination and sort of the filter are ignored. When fn returns an error the iteration stops
+// and the error is returned.
+func (r repository) IterateByFilter(
+	ctx context.Context,
+	filter StatementFilter,
+	fn func(statementModel) error,
+) error {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	rows, err := r.selectByFilter(ctx, filter).
+		Order("trx.created_at ASC", "trx.id ASC").
+		Rows(ctx)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stm statementModel
+		if err := r.db.Replica(ctx).ScanRow(ctx, rows, &stm); err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		if err := fn(stm); err != nil {
+			span.RecordError(err)
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// GetSummary returns the balances of the account at the beginning and at the end of the period of the filter,
+// plus the money in and out of the account in the period.
+func (r repository) GetSummary(ctx context.Context, filter StatementFilter) (summaryModel, error) {
+	ctx, span := r.tracer.Span(ctx)
+	defer span.End()
+
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	accountID := filter.AccountID.String()
+	signedAmount := signedAmountExpr(accountID)
+
+	openingBalance := schema.SafeQuery("0", nil)
+	closingBalance := schema.SafeQuery("SUM(?)", []interface{}{signedAmount})
+	period := "TRUE"
+	var periodArgs []interface{}
+	if !filter.CreatedAtBegin.IsZero() {
+		openingBalance = schema.SafeQuery(
+			"SUM(?) FILTER (WHERE trx.created_at < ?)",
+			[]interface{}{signedAmount, filter.CreatedAtBegin},
+		)
+		period += " AND trx.created_at >= ?"
+		periodArgs = append(periodArgs, filter.CreatedAtBegin)
+	}
+	if !filter.CreatedAtEnd.IsZero() {
+		closingBalance = schema.SafeQuery(
+			"SUM(?) FILTER (WHERE trx.created_at <= ?)",
+			[]interface{}{signedAmount, filter.CreatedAtEnd},
+		)
+		period += " AND trx.created_at <= ?"
+		periodArgs = append(periodArgs, filter.CreatedAtEnd)
+	}
+	inPeriod := schema.SafeQuery(period, periodArgs)
+
+	selectQuery := r.db.Replica(ctx).
+		NewSelect().
+		TableExpr("transactions AS trx").
+		ColumnExpr("COALESCE(?, 0) AS opening_balance", openingBalance).
+		ColumnExpr("COALESCE(?, 0) AS closing_balance", closingBalance).
+		ColumnExpr(
+			"COALESCE(SUM(trx.amount) FILTER (WHERE trx.to_account_id = ? AND ?), 0) AS total_credits",
+			accountID,
+			inPeriod,
+		).
+		ColumnExpr(
+			"COALESCE(SUM(trx.amount) FILTER (WHERE trx.from_account_id = ? AND ?), 0) AS total_debits",
+			accountID,
+			inPeriod,
+		).
+		Where("(trx.from_account_id = ? OR trx.to_account_id = ?)", accountID, accountID)
+	if filter.TenantID != "" {
+		selectQuery.Where("trx.tenant_id = ?", filter.TenantID)
+	}
+
+	var summary summaryModel
+	err := selectQuery.Scan(ctx, &summary)
+	if err != nil {
+		err = database.TranslateError(err, nil)
+		span.RecordError(err)
+		return summaryModel{}, err
+	}
+
+	return summary, nil
+}
+
+// selectByFilter selects the transactions of the account with the running balance of the account after each one
+// of them. The balance is computed with a window function over all transactions of the account before applying
+// the other conditions of the filter, otherwise it would only consider the transactions matched by them.
+func (r repository) selectByFilter(ctx context.Context, filter StatementFilter) *bun.SelectQuery {
+	accountID := filter.AccountID.String()
+	db := r.db.Replica(ctx)
+
+	transactionsWithBalance := db.
+		NewSelect().
+		TableExpr("transactions AS trx").
+		ColumnExpr("trx.*").
+		ColumnExpr(
+			"SUM(?) OVER (ORDER BY trx.created_at ASC, trx.id ASC) AS balance",
+			signedAmountExpr(accountID),
+		).
+		Where("(trx.from_account_id = ? OR trx.to_account_id = ?)", accountID, accountID)
+	if filter.TenantID != "" {
+		transactionsWithBalance.Where("trx.tenant_id = ?", filter.TenantID)
+	}
+
+	selectQuery := db.
 		NewSelect().
 		Model(&statementModel{}).
+		ModelTableExpr("(?) AS trx", transactionsWithBalance).
 		ColumnExpr("trx.*").
 		ColumnExpr("from_acc.name AS from_account_name, to_acc.name AS to_account_name").
-		Where(
-			"(from_account_id = ? OR to_account_id = ?)",
-			filter.AccountID.String(),
-			filter.AccountID.String(),
-		).
 		Join("LEFT JOIN accounts AS from_acc").
 		JoinOn("from_acc.id = from_account_id").
 		Join("LEFT JOIN accounts AS to_acc").
-		JoinOn("to_acc.id = to_account_id").
-		Limit(size).
-		Offset((page - 1) * size)
-
-	if filter.Sort == 0 {
-		selectQuery.Order("trx.created_at ASC")
-	} else if filter.Sort > 0 {
-		selectQuery.Order("trx.created_at DESC")
-	}
+		JoinOn("to_acc.id = to_account_id")
 
 	if !filter.CreatedAtBegin.IsZero() {
 		selectQuery.Where("trx.created_at >= ?", filter.CreatedAtBegin)
@@ -66,13 +237,46 @@ func (r repository) ListByFilter(ctx context.Context, filter StatementFilter) (i
 	if !filter.CreatedAtEnd.IsZero() {
 		selectQuery.Where("trx.created_at <= ?", filter.CreatedAtEnd)
 	}
-
-	var stms []statementModel
-	total, err := selectQuery.ScanAndCount(ctx, &stms)
-	if err != nil {
-		span.RecordError(err)
-		return 0, []statementModel{}, err
+	if len(filter.Types) > 0 {
+		selectQuery.Where("trx.type IN (?)", bun.In(filter.Types))
+	}
+	switch filter.Direction {
+	case DirectionIn:
+		selectQuery.Where("trx.to_account_id = ?", accountID)
+	case DirectionOut:
+		selectQuery.Where("trx.from_account_id = ?", accountID)
+	}
+	if filter.MinAmount.Valid {
+		selectQuery.Where("trx.amount >= ?", filter.MinAmount.Float64)
+	}
+	if filter.MaxAmount.Valid {
+		selectQuery.Where("trx.amount <= ?", filter.MaxAmount.Float64)
+	}
+	if filter.CounterpartyAccountID.Valid {
+		counterpartyID := filter.CounterpartyAccountID.UUID.String()
+		selectQuery.Where(
+			"((trx.from_account_id = ? AND trx.to_account_id = ?) OR (trx.from_account_id = ? AND trx.to_account_id = ?))",
+			accountID,
+			counterpartyID,
+			counterpartyID,
+			accountID,
+		)
+	}
+	if filter.Description != "" {
+		// Same expression of the transactions_description_search_index.
+		selectQuery.Where(
+			"to_tsvector('simple', trx.description) @@ websearch_to_tsquery('simple', ?)",
+			filter.Description,
+		)
 	}
 
-	return total, stms, nil
+	return selectQuery
+}
+
+// signedAmountExpr returns the amount of the transaction as money in (positive) or money out (negative) of the account.
+func signedAmountExpr(accountID string) schema.QueryWithArgs {
+	return schema.SafeQuery(
+		"CASE WHEN trx.to_account_id = ? THEN trx.amount ELSE -trx.amount END",
+		[]interface{}{accountID},
+	)
 }
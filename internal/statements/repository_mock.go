@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/statements/repository.go
+
+// Package statements is a generated GoMock package.
+package statements
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetSummary mocks base method.
+func (m *MockRepository) GetSummary(ctx context.Context, filter StatementFilter) (summaryModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSummary", ctx, filter)
+	ret0, _ := ret[0].(summaryModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSummary indicates an expected call of GetSummary.
+func (mr *MockRepositoryMockRecorder) GetSummary(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSummary", reflect.TypeOf((*MockRepository)(nil).GetSummary), ctx, filter)
+}
+
+// IterateByFilter mocks base method.
+func (m *MockRepository) IterateByFilter(ctx context.Context, filter StatementFilter, fn func(statementModel) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IterateByFilter", ctx, filter, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IterateByFilter indicates an expected call of IterateByFilter.
+func (mr *MockRepositoryMockRecorder) IterateByFilter(ctx, filter, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IterateByFilter", reflect.TypeOf((*MockRepository)(nil).IterateByFilter), ctx, filter, fn)
+}
+
+// ListByFilter mocks base method.
+func (m *MockRepository) ListByFilter(ctx context.Context, filter StatementFilter) (int, []statementModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByFilter", ctx, filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].([]statementModel)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListByFilter indicates an expected call of ListByFilter.
+func (mr *MockRepositoryMockRecorder) ListByFilter(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByFilter", reflect.TypeOf((*MockRepository)(nil).ListByFilter), ctx, filter)
+}
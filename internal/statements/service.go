@@ -2,37 +2,87 @@ package statements
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 
 	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrExportPeriodRequired = errors.New("created_at_begin and created_at_end are required to export a statement")
+	ErrInvalidExportPeriod  = errors.New("created_at_begin must be before created_at_end")
+	ErrInvalidDirection     = errors.New("direction must be in or out")
+	ErrInvalidAmountRange   = errors.New("min_amount must be less than or equal to max_amount")
+)
+
 type Service interface {
 	List(ctx context.Context, filter ListFilter) (int, []Statement, error)
+	Summarize(ctx context.Context, filter ListFilter) (Summary, error)
+	Export(ctx context.Context, filter ExportFilter, exporter Exporter) error
 }
 
 type service struct {
-	tracer     tracer.Tracer
-	repository Repository
+	tracer      tracer.Tracer
+	repository  Repository
+	accountsSvc accounts.Service
+	holdersSvc  holders.Service
+	unitOfWork  database.UnitOfWork
+	tenants     tenancy.Tenants
 }
 
-func NewService(t tracer.Tracer, r Repository) Service {
-	return service{tracer: t, repository: r}
+func NewService(
+	t tracer.Tracer,
+	r Repository,
+	as accounts.Service,
+	hs holders.Service,
+	unitOfWork database.UnitOfWork,
+	tenants tenancy.Tenants,
+) Service {
+	return service{tracer: t, repository: r, accountsSvc: as, holdersSvc: hs, unitOfWork: unitOfWork, tenants: tenants}
 }
 
 func (s service) List(ctx context.Context, filter ListFilter) (int, []Statement, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
+	if err := s.accountsSvc.Authorize(ctx, filter.AccountID); err != nil {
+		span.RecordError(err)
+		return 0, []Statement{}, err
+	}
+
+	if filter.Direction != "" && filter.Direction != DirectionIn && filter.Direction != DirectionOut {
+		span.RecordError(ErrInvalidDirection)
+		return 0, []Statement{}, ErrInvalidDirection
+	}
+
+	if filter.MinAmount.Valid && filter.MaxAmount.Valid && filter.MinAmount.Float64 > filter.MaxAmount.Float64 {
+		span.RecordError(ErrInvalidAmountRange)
+		return 0, []Statement{}, ErrInvalidAmountRange
+	}
+
 	total, statementModels, err := s.repository.ListByFilter(ctx, StatementFilter{
-		Page:           filter.Page,
-		Size:           filter.Size,
-		Sort:           filter.Sort,
-		AccountID:      filter.AccountID,
-		CreatedAtBegin: filter.CreatedAtBegin,
-		CreatedAtEnd:   filter.CreatedAtEnd,
+		Page:                  filter.Page,
+		Size:                  filter.Size,
+		Sort:                  filter.Sort,
+		AccountID:             filter.AccountID,
+		CreatedAtBegin:        filter.CreatedAtBegin,
+		CreatedAtEnd:          filter.CreatedAtEnd,
+		Types:                 filter.Types,
+		Direction:             filter.Direction,
+		MinAmount:             filter.MinAmount,
+		MaxAmount:             filter.MaxAmount,
+		CounterpartyAccountID: filter.CounterpartyAccountID,
+		Description:           filter.Description,
+		Cursor:                filter.Cursor,
+		WithTotal:             filter.WithTotal,
+		TenantID:              tenancy.FilterID(ctx),
 	})
 	if err != nil {
 		zapctx.L(ctx).Error("statements_service_repository_error", zap.Error(err))
@@ -42,21 +92,153 @@ func (s service) List(ctx context.Context, filter ListFilter) (int, []Statement,
 
 	stmts := make([]Statement, len(statementModels))
 	for i, model := range statementModels {
-		stmts[i] = Statement{
-			FromAccount: accounts.Account{
-				ID:   model.FromAccountID,
-				Name: model.FromAccountName,
-			},
-			ToAccount: accounts.Account{
-				ID:   model.ToAccountID,
-				Name: model.ToAccountName,
-			},
-			Type:        model.Type,
-			Amount:      model.Amount,
-			Description: model.Description,
-			CreatedAt:   model.CreatedAt,
-		}
+		stmts[i] = newStatement(model)
 	}
 
 	return total, stmts, nil
 }
+
+// Summarize returns the opening balance at the beginning of the period of the filter, the closing balance at its end
+// and the totals of credits and debits inside it.
+func (s service) Summarize(ctx context.Context, filter ListFilter) (Summary, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := s.accountsSvc.Authorize(ctx, filter.AccountID); err != nil {
+		span.RecordError(err)
+		return Summary{}, err
+	}
+
+	model, err := s.repository.GetSummary(ctx, StatementFilter{
+		AccountID:      filter.AccountID,
+		CreatedAtBegin: filter.CreatedAtBegin,
+		CreatedAtEnd:   filter.CreatedAtEnd,
+		TenantID:       tenancy.FilterID(ctx),
+	})
+	if err != nil {
+		zapctx.L(ctx).Error("statements_service_summary_repository_error", zap.Error(err))
+		span.RecordError(err)
+		return Summary{}, err
+	}
+
+	return Summary{
+		OpeningBalance: model.OpeningBalance,
+		ClosingBalance: model.ClosingBalance,
+		TotalCredits:   model.TotalCredits,
+		TotalDebits:    model.TotalDebits,
+	}, nil
+}
+
+// Export renders into the exporter the whole statement of the account for the period, with the opening balance,
+// the balance after each statement and the closing balance. The statements are streamed from the database, so
+// nothing but the current line is kept in memory. The balances and the statements are read from the same snapshot, in
+// a read-only REPEATABLE READ unit of work, so the statements committed meanwhile are in none of them.
+func (s service) Export(ctx context.Context, filter ExportFilter, exporter Exporter) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if filter.CreatedAtBegin.IsZero() || filter.CreatedAtEnd.IsZero() {
+		span.RecordError(ErrExportPeriodRequired)
+		return ErrExportPeriodRequired
+	}
+
+	if filter.CreatedAtBegin.After(filter.CreatedAtEnd) {
+		span.RecordError(ErrInvalidExportPeriod)
+		return ErrInvalidExportPeriod
+	}
+
+	account, err := s.accountsSvc.GetByID(ctx, filter.AccountID)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"statements_service_export_account_error",
+			zap.String("account_id", filter.AccountID.String()),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return err
+	}
+
+	holder, err := s.holdersSvc.GetByID(ctx, account.HolderID)
+	if err != nil {
+		zapctx.L(ctx).Error(
+			"statements_service_export_holder_error",
+			zap.String("holder_id", account.HolderID.String()),
+			zap.Error(err),
+		)
+		span.RecordError(err)
+		return err
+	}
+
+	// The accounts of a tenant missing in the configuration are the ones created before the tenants.
+	tenant, err := s.tenants.Get(account.TenantID)
+	if err != nil {
+		tenant = tenancy.Default
+	}
+
+	filterPeriod := StatementFilter{
+		AccountID:      filter.AccountID,
+		CreatedAtBegin: filter.CreatedAtBegin,
+		CreatedAtEnd:   filter.CreatedAtEnd,
+		TenantID:       tenancy.FilterID(ctx),
+	}
+
+	snapshot := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	err = s.unitOfWork.Do(ctx, snapshot, func(ctx context.Context) error {
+		summary, err := s.repository.GetSummary(ctx, filterPeriod)
+		if err != nil {
+			zapctx.L(ctx).Error("statements_service_export_summary_error", zap.Error(err))
+			return err
+		}
+
+		err = exporter.WriteHeader(ExportHeader{
+			Holder:         holder,
+			Account:        account,
+			BankCode:       tenant.BankCode,
+			CreatedAtBegin: filter.CreatedAtBegin,
+			CreatedAtEnd:   filter.CreatedAtEnd,
+			OpeningBalance: summary.OpeningBalance,
+		})
+		if err != nil {
+			zapctx.L(ctx).Error("statements_service_export_write_error", zap.Error(err))
+			return err
+		}
+
+		err = s.repository.IterateByFilter(ctx, filterPeriod, func(model statementModel) error {
+			return exporter.WriteLine(ExportLine{
+				Statement: newStatement(model),
+				Amount:    signedAmount(filter.AccountID, model),
+				Balance:   model.Balance,
+			})
+		})
+		if err != nil {
+			zapctx.L(ctx).Error("statements_service_export_iterate_error", zap.Error(err))
+			return err
+		}
+
+		err = exporter.WriteFooter(ExportFooter{
+			ClosingBalance: summary.ClosingBalance,
+			TotalCredits:   summary.TotalCredits,
+			TotalDebits:    summary.TotalDebits,
+		})
+		if err != nil {
+			zapctx.L(ctx).Error("statements_service_export_write_error", zap.Error(err))
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// signedAmount returns the amount of the statement as money in (positive) or money out (negative) of the account.
+func signedAmount(accountID uuid.UUID, model statementModel) float64 {
+	if model.ToAccountID == accountID {
+		return model.Amount
+	}
+	return -model.Amount
+}
@@ -0,0 +1,308 @@
+//go:build unit
+
+package statements
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type _unitOfWorkTest struct{}
+
+func TestService_Export(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	hdSvcMock := holders.NewMockService(ctrl)
+	uowMock := database.NewMockUnitOfWork(ctrl)
+
+	svc := NewService(tracer.NewNoop(), repoMock, accSvcMock, hdSvcMock, uowMock, tenancy.Tenants{})
+
+	accountID := uuid.New()
+	otherAccountID := uuid.New()
+	holderID := uuid.New()
+	begin := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("fail export, period required", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, err := NewExporter(CSVExportFormat, &buf)
+		assert.NoError(t, err)
+
+		err = svc.Export(ctx, ExportFilter{AccountID: accountID, CreatedAtBegin: begin}, exporter)
+		assert.ErrorIs(t, err, ErrExportPeriodRequired)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("fail export, account not found", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, err := NewExporter(CSVExportFormat, &buf)
+		assert.NoError(t, err)
+
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID).
+			Return(accounts.Account{}, accounts.ErrAccountNotFound)
+
+		err = svc.Export(
+			ctx,
+			ExportFilter{AccountID: accountID, CreatedAtBegin: begin, CreatedAtEnd: end},
+			exporter,
+		)
+		assert.ErrorIs(t, err, accounts.ErrAccountNotFound)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("success export with signed amounts and balances", func(t *testing.T) {
+		var buf bytes.Buffer
+		exporter, err := NewExporter(CSVExportFormat, &buf)
+		assert.NoError(t, err)
+
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID).
+			Return(accounts.Account{ID: accountID, HolderID: holderID, Name: gofakeit.Name()}, nil)
+
+		hdSvcMock.EXPECT().
+			GetByID(gomock.Any(), holderID).
+			Return(holders.Holder{ID: holderID, Name: gofakeit.Name()}, nil)
+
+		// The summary and the statements are read in the same read-only snapshot.
+		uowMock.EXPECT().
+			Do(gomock.Any(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ *sql.TxOptions, fn func(ctx context.Context) error) error {
+				return fn(context.WithValue(ctx, _unitOfWorkTest{}, true))
+			})
+
+		repoMock.EXPECT().
+			GetSummary(
+				gomock.Any(),
+				StatementFilter{AccountID: accountID, CreatedAtBegin: begin, CreatedAtEnd: end},
+			).
+			DoAndReturn(func(ctx context.Context, _ StatementFilter) (summaryModel, error) {
+				assert.Equal(t, true, ctx.Value(_unitOfWorkTest{}))
+				return summaryModel{OpeningBalance: 100, ClosingBalance: 100, TotalCredits: 50, TotalDebits: 50}, nil
+			})
+
+		repoMock.EXPECT().
+			IterateByFilter(
+				gomock.Any(),
+				StatementFilter{AccountID: accountID, CreatedAtBegin: begin, CreatedAtEnd: end},
+				gomock.Any(),
+			).
+			DoAndReturn(func(ctx context.Context, _ StatementFilter, fn func(statementModel) error) error {
+				assert.Equal(t, true, ctx.Value(_unitOfWorkTest{}))
+				models := []statementModel{
+					{
+						ID:          uuid.New(),
+						ToAccountID: accountID,
+						Type:        "CREDIT",
+						Amount:      50,
+						CreatedAt:   begin.Add(time.Hour),
+						Balance:     150,
+					},
+					{
+						ID:            uuid.New(),
+						FromAccountID: accountID,
+						Type:          "DEBIT",
+						Amount:        30,
+						CreatedAt:     begin.Add(2 * time.Hour),
+						Balance:       120,
+					},
+					{
+						ID:            uuid.New(),
+						FromAccountID: accountID,
+						ToAccountID:   otherAccountID,
+						Type:          "P2P",
+						Amount:        20,
+						CreatedAt:     begin.Add(3 * time.Hour),
+						Balance:       100,
+					},
+				}
+				for _, model := range models {
+					if err := fn(model); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+		err = svc.Export(
+			ctx,
+			ExportFilter{AccountID: accountID, CreatedAtBegin: begin, CreatedAtEnd: end},
+			exporter,
+		)
+		assert.NoError(t, err)
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 6)
+
+		balances := make([]string, 0, len(records)-1)
+		amounts := make([]string, 0, len(records)-1)
+		for _, record := range records[1:] {
+			amounts = append(amounts, record[8])
+			balances = append(balances, record[9])
+		}
+		assert.Equal(t, []string{"", "50.00", "-30.00", "-20.00", ""}, amounts)
+		assert.Equal(t, []string{"100.00", "150.00", "120.00", "100.00", "100.00"}, balances)
+	})
+}
+
+func TestService_Summarize(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+
+	accSvcMock := accounts.NewMockService(ctrl)
+	svc := NewService(
+		tracer.NewNoop(),
+		repoMock,
+		accSvcMock,
+		holders.NewMockService(ctrl),
+		database.NewMockUnitOfWork(ctrl),
+		tenancy.Tenants{},
+	)
+
+	accSvcMock.EXPECT().Authorize(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	filter := ListFilter{
+		Page:           2,
+		Size:           10,
+		AccountID:      uuid.New(),
+		CreatedAtBegin: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedAtEnd:   time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("fail summary, repository error", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetSummary(gomock.Any(), gomock.Any()).
+			Return(summaryModel{}, sql.ErrConnDone)
+
+		smr, err := svc.Summarize(ctx, filter)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.Empty(t, smr)
+	})
+
+	t.Run("success summary ignoring pagination", func(t *testing.T) {
+		repoMock.EXPECT().
+			GetSummary(
+				gomock.Any(),
+				StatementFilter{
+					AccountID:      filter.AccountID,
+					CreatedAtBegin: filter.CreatedAtBegin,
+					CreatedAtEnd:   filter.CreatedAtEnd,
+				},
+			).
+			Return(summaryModel{OpeningBalance: 10, ClosingBalance: 25, TotalCredits: 20, TotalDebits: 5}, nil)
+
+		smr, err := svc.Summarize(ctx, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, Summary{OpeningBalance: 10, ClosingBalance: 25, TotalCredits: 20, TotalDebits: 5}, smr)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+
+	accSvcMock := accounts.NewMockService(ctrl)
+	svc := NewService(
+		tracer.NewNoop(),
+		repoMock,
+		accSvcMock,
+		holders.NewMockService(ctrl),
+		database.NewMockUnitOfWork(ctrl),
+		tenancy.Tenants{},
+	)
+
+	accSvcMock.EXPECT().Authorize(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	accountID := uuid.New()
+	counterpartyID := uuid.New()
+
+	t.Run("fail list, invalid direction", func(t *testing.T) {
+		total, stmts, err := svc.List(ctx, ListFilter{AccountID: accountID, Direction: "sideways"})
+		assert.ErrorIs(t, err, ErrInvalidDirection)
+		assert.Zero(t, total)
+		assert.Empty(t, stmts)
+	})
+
+	t.Run("fail list, invalid amount range", func(t *testing.T) {
+		total, stmts, err := svc.List(ctx, ListFilter{
+			AccountID: accountID,
+			MinAmount: sql.NullFloat64{Float64: 100, Valid: true},
+			MaxAmount: sql.NullFloat64{Float64: 10, Valid: true},
+		})
+		assert.ErrorIs(t, err, ErrInvalidAmountRange)
+		assert.Zero(t, total)
+		assert.Empty(t, stmts)
+	})
+
+	t.Run("success list with filters", func(t *testing.T) {
+		filter := ListFilter{
+			Page:                  1,
+			Size:                  10,
+			AccountID:             accountID,
+			Types:                 []string{"P2P"},
+			Direction:             DirectionOut,
+			MinAmount:             sql.NullFloat64{Float64: 10, Valid: true},
+			MaxAmount:             sql.NullFloat64{Float64: 10, Valid: true},
+			CounterpartyAccountID: uuid.NullUUID{UUID: counterpartyID, Valid: true},
+			Description:           "rent",
+		}
+
+		model := statementModel{
+			ID:            uuid.New(),
+			FromAccountID: accountID,
+			ToAccountID:   counterpartyID,
+			Type:          "P2P",
+			Amount:        10,
+			Description:   "rent of january",
+			CreatedAt:     time.Now().UTC(),
+			Balance:       -10,
+		}
+
+		repoMock.EXPECT().
+			ListByFilter(
+				gomock.Any(),
+				StatementFilter{
+					Page:                  filter.Page,
+					Size:                  filter.Size,
+					AccountID:             filter.AccountID,
+					Types:                 filter.Types,
+					Direction:             filter.Direction,
+					MinAmount:             filter.MinAmount,
+					MaxAmount:             filter.MaxAmount,
+					CounterpartyAccountID: filter.CounterpartyAccountID,
+					Description:           filter.Description,
+				},
+			).
+			Return(0, []statementModel{model}, nil)
+
+		total, stmts, err := svc.List(ctx, filter)
+		assert.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Equal(t, []Statement{newStatement(model)}, stmts)
+	})
+}
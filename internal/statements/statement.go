@@ -4,13 +4,47 @@ import (
 	"time"
 
 	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/google/uuid"
 )
 
 type Statement struct {
+	ID          uuid.UUID
 	FromAccount accounts.Account
 	ToAccount   accounts.Account
 	Type        string
 	Amount      float64
 	Description string
-	CreatedAt   time.Time
+	// Adjustment tells the transaction is an adjusting entry posted into a closed business day.
+	Adjustment bool
+	CreatedAt  time.Time
+	// Balance is the balance of the account of the statement right after this transaction.
+	Balance float64
+}
+
+// Summary sums up the statement of an account in a period.
+type Summary struct {
+	OpeningBalance float64
+	ClosingBalance float64
+	TotalCredits   float64
+	TotalDebits    float64
+}
+
+func newStatement(model statementModel) Statement {
+	return Statement{
+		ID: model.ID,
+		FromAccount: accounts.Account{
+			ID:   model.FromAccountID,
+			Name: model.FromAccountName,
+		},
+		ToAccount: accounts.Account{
+			ID:   model.ToAccountID,
+			Name: model.ToAccountName,
+		},
+		Type:        model.Type,
+		Amount:      model.Amount,
+		Description: model.Description,
+		Adjustment:  model.Adjustment,
+		CreatedAt:   model.CreatedAt,
+		Balance:     model.Balance,
+	}
 }
@@ -0,0 +1,33 @@
+package environment
+
+import (
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/gosidekick/goconfig"
+)
+
+// Environment this object keep the all environment variables.
+type Environment struct {
+	// Database
+	DatabaseURL string `cfg:"DATABASE_URL" cfgRequired:"true"`
+	// Open Telemetry
+	OtelCollectorHost string `cfg:"OTEL_COLLECTOR_HOST" cfgRequired:"true"`
+	// Tracing is the sampling and the exporter of the spans (OTEL_EXPORTER, OTEL_SAMPLE_RATIO, ...).
+	Tracing tracer.Config `cfg:"OTEL"`
+	// Application
+	Environment string `cfg:"ENVIRONMENT" cfgRequired:"true"`
+	Service     string `cfg:"SERVICE" cfgRequired:"true"`
+	Version     string `cfg:"VERSION" cfgRequired:"true"`
+	// Tenancy
+	// TenantsFile is the JSON file with the tenants of the API, the bank code of the statements is the one of the
+	// tenant of the account.
+	TenantsFile string `cfg:"TENANTS_FILE"`
+	// Job
+	// StatementMonth is the month (yyyy-mm) to generate the statements, the previous month when empty.
+	StatementMonth string `cfg:"STATEMENT_MONTH"`
+}
+
+func NewEnvironment() (Environment, error) {
+	env := &Environment{}
+	err := goconfig.Parse(env)
+	return *env, err
+}
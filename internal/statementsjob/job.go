@@ -0,0 +1,112 @@
+package statementsjob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/monthlystatements"
+	"github.com/dalmarcogd/ledger-exp/pkg/cursor"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+)
+
+const accountsPageSize = 100
+
+var ErrMonthlyStatementsFailed = errors.New("failed to generate the monthly statements of some accounts")
+
+// Job generates the monthly statements of the active accounts.
+type Job interface {
+	Run(ctx context.Context, month time.Time) error
+}
+
+type job struct {
+	tracer       tracer.Tracer
+	accountsSvc  accounts.Service
+	monthliesSvc monthlystatements.Service
+}
+
+func NewJob(t tracer.Tracer, as accounts.Service, ms monthlystatements.Service) Job {
+	return job{tracer: t, accountsSvc: as, monthliesSvc: ms}
+}
+
+// Run generates the statement of the month for every active account opened until the end of the month. The
+// statements already generated are kept as they are, so running it again for the same month only generates the
+// missing ones. A failure on an account does not stop the others, they are reported together at the end.
+func (j job) Run(ctx context.Context, month time.Time) error {
+	ctx, span := j.tracer.Span(ctx)
+	defer span.End()
+
+	month = monthlystatements.StartOfMonth(month)
+	nextMonth := month.AddDate(0, 1, 0)
+
+	var generated, failed int
+	var crs *cursor.Cursor
+	for {
+		_, accs, err := j.accountsSvc.List(ctx, accounts.ListFilter{
+			Size:   accountsPageSize,
+			Status: accounts.ActiveStatus,
+			Cursor: crs,
+		})
+		if err != nil {
+			zapctx.L(ctx).Error("statements_job_list_accounts_error", zap.Error(err))
+			span.RecordError(err)
+			return err
+		}
+
+		for _, acc := range accs {
+			if !acc.CreatedAt.Before(nextMonth) {
+				continue
+			}
+
+			_, err := j.monthliesSvc.Generate(ctx, acc.ID, month)
+			if errors.Is(err, monthlystatements.ErrMonthNotClosed) {
+				// The same for all the accounts, the month is generated once its business days are closed.
+				zapctx.L(ctx).Error(
+					"statements_job_month_not_closed",
+					zap.String("month", monthlystatements.FormatMonth(month)),
+				)
+				span.RecordError(err)
+				return err
+			}
+			if err != nil {
+				zapctx.L(ctx).Error(
+					"statements_job_generate_error",
+					zap.String("account_id", acc.ID.String()),
+					zap.String("month", monthlystatements.FormatMonth(month)),
+					zap.Error(err),
+				)
+				span.RecordError(err)
+				failed++
+				continue
+			}
+			generated++
+		}
+
+		if len(accs) < accountsPageSize {
+			break
+		}
+
+		last := accs[len(accs)-1]
+		crs = &cursor.Cursor{
+			Key:       cursor.Key{CreatedAt: last.CreatedAt, ID: last.ID.String()},
+			Direction: cursor.Next,
+		}
+	}
+
+	zapctx.L(ctx).Info(
+		"statements_job_finished",
+		zap.String("month", monthlystatements.FormatMonth(month)),
+		zap.Int("generated", generated),
+		zap.Int("failed", failed),
+	)
+
+	if failed > 0 {
+		span.RecordError(ErrMonthlyStatementsFailed)
+		return ErrMonthlyStatementsFailed
+	}
+
+	return nil
+}
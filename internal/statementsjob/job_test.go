@@ -0,0 +1,62 @@
+//go:build unit
+
+package statementsjob
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/monthlystatements"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJob_Run(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accSvcMock := accounts.NewMockService(ctrl)
+	msSvcMock := monthlystatements.NewMockService(ctrl)
+
+	j := NewJob(tracer.NewNoop(), accSvcMock, msSvcMock)
+
+	month := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	opened := accounts.Account{ID: uuid.New(), CreatedAt: month.AddDate(0, -1, 0)}
+	failing := accounts.Account{ID: uuid.New(), CreatedAt: month.AddDate(0, 0, 10)}
+	openedLater := accounts.Account{ID: uuid.New(), CreatedAt: month.AddDate(0, 1, 0)}
+
+	t.Run("generate for active accounts opened until the month, reporting failures", func(t *testing.T) {
+		accSvcMock.EXPECT().
+			List(gomock.Any(), accounts.ListFilter{Size: accountsPageSize, Status: accounts.ActiveStatus}).
+			Return(0, []accounts.Account{opened, failing, openedLater}, nil)
+
+		msSvcMock.EXPECT().
+			Generate(gomock.Any(), opened.ID, month).
+			Return(monthlystatements.MonthlyStatement{}, nil)
+		msSvcMock.EXPECT().
+			Generate(gomock.Any(), failing.ID, month).
+			Return(monthlystatements.MonthlyStatement{}, sql.ErrConnDone)
+
+		err := j.Run(ctx, month.AddDate(0, 0, 5))
+		assert.ErrorIs(t, err, ErrMonthlyStatementsFailed)
+	})
+
+	t.Run("stop when the month is not closed", func(t *testing.T) {
+		accSvcMock.EXPECT().
+			List(gomock.Any(), accounts.ListFilter{Size: accountsPageSize, Status: accounts.ActiveStatus}).
+			Return(0, []accounts.Account{opened, failing}, nil)
+
+		msSvcMock.EXPECT().
+			Generate(gomock.Any(), opened.ID, month).
+			Return(monthlystatements.MonthlyStatement{}, monthlystatements.ErrMonthNotClosed)
+
+		err := j.Run(ctx, month)
+		assert.ErrorIs(t, err, monthlystatements.ErrMonthNotClosed)
+	})
+}
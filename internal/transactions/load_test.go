@@ -0,0 +1,93 @@
+//go:build integration
+
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/dalmarcogd/ledger-exp/internal/accounts"
+	"github.com/dalmarcogd/ledger-exp/internal/holders"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkRepository_Create creates credits concurrently with pools of several sizes, the throughput is reported as
+// tx/s: go test -tags integration -run '^$' -bench BenchmarkRepository_Create ./internal/transactions/
+func BenchmarkRepository_Create(b *testing.B) {
+	ctx := context.Background()
+
+	url, closeFunc, err := testingcontainers.NewPostgresContainer()
+	require.NoError(b, err)
+	defer closeFunc(ctx) //nolint:errcheck
+
+	_, callerPath, _, _ := runtime.Caller(0) //nolint:dogsled
+	err = testingcontainers.RunMigrateDatabase(
+		url,
+		fmt.Sprintf("file://%s/../../migrations/", filepath.Dir(callerPath)),
+	)
+	require.NoError(b, err)
+
+	for _, maxOpenConns := range []int{1, 4, 20} {
+		b.Run(fmt.Sprintf("max_open_conns=%d", maxOpenConns), func(b *testing.B) {
+			db, err := database.New(
+				tracer.NewNoop(),
+				metrics.NewNoop(),
+				database.Config{MasterURL: url, Pool: database.PoolConfig{MaxOpenConns: maxOpenConns}},
+			)
+			require.NoError(b, err)
+			defer db.Stop(ctx) //nolint:errcheck
+
+			holdersRepo := holders.NewRepository(tracer.NewNoop(), db)
+			holder, err := holdersRepo.Create(ctx, holders.HolderModel{
+				Name:           gofakeit.Name(),
+				DocumentNumber: gofakeit.SSN(),
+			})
+			require.NoError(b, err)
+
+			account, err := accounts.NewService(
+				tracer.NewNoop(),
+				accounts.NewRepository(tracer.NewNoop(), db),
+				holdersRepo,
+				database.NewUnitOfWork(tracer.NewNoop(), db),
+			).Create(ctx, accounts.Account{
+				ID:             uuid.New(),
+				Name:           gofakeit.Name(),
+				Agency:         "0001",
+				Number:         gofakeit.Numerify("######"),
+				DocumentNumber: holder.DocumentNumber,
+				HolderID:       holder.ID,
+				Status:         accounts.ActiveStatus,
+			})
+			require.NoError(b, err)
+
+			repo := NewRepository(tracer.NewNoop(), db)
+
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, err := repo.Create(ctx, newTransactionModel(Transaction{
+						Type:        CreditTransaction,
+						To:          account.ID,
+						Amount:      1,
+						Description: "load test",
+					}))
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "tx/s")
+		})
+	}
+}
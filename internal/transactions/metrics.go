@@ -0,0 +1,103 @@
+package transactions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// rejections are the reasons of the rejected transactions, the other errors are failures and are not counted.
+var rejections = []struct {
+	err    error
+	reason string
+}{
+	{err: ErrFromAccountToAccountShouldBeDifferent, reason: "same_account"},
+	{err: ErrAccountNotfound, reason: "account_not_found"},
+	{err: ErrAccountInactive, reason: "account_inactive"},
+	{err: ErrBalanceInsufficientFunds, reason: "insufficient_funds"},
+	{err: ErrInsufficientDailyLimit, reason: "daily_limit_exceeded"},
+	{err: ErrTransactionInFuture, reason: "transaction_in_future"},
+	{err: ErrPeriodClosed, reason: "period_closed"},
+	{err: ErrNotAdjustmentPeriod, reason: "not_adjustment_period"},
+	{err: ErrFailLockAccount, reason: "account_locked"},
+	{err: authz.ErrForbidden, reason: "forbidden"},
+}
+
+// transactionMetrics are the business metrics of the transactions.
+type transactionMetrics struct {
+	created   metric.Int64Counter
+	amount    metric.Float64Counter
+	rejected  metric.Int64Counter
+	limitHits metric.Int64Counter
+}
+
+func newTransactionMetrics(m metrics.Metrics) transactionMetrics {
+	meter := m.Meter("github.com/dalmarcogd/ledger-exp/internal/transactions")
+
+	// The instruments created with an error still work, the error is only reported.
+	created, err := meter.Int64Counter(
+		"ledger.transactions.created",
+		metric.WithDescription("Number of the transactions created, by type."),
+		metric.WithUnit("{transaction}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	amount, err := meter.Float64Counter(
+		"ledger.transactions.amount",
+		metric.WithDescription("Volume of the amounts of the transactions created, by type."),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	rejected, err := meter.Int64Counter(
+		"ledger.transactions.rejected",
+		metric.WithDescription("Number of the transactions rejected, by type and reason."),
+		metric.WithUnit("{transaction}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	limitHits, err := meter.Int64Counter(
+		"ledger.transactions.limit_hits",
+		metric.WithDescription("Number of the transactions refused by a limit of the account, by type and limit."),
+		metric.WithUnit("{transaction}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return transactionMetrics{
+		created:   created,
+		amount:    amount,
+		rejected:  rejected,
+		limitHits: limitHits,
+	}
+}
+
+// record records the transaction of the type created, or rejected with err.
+func (m transactionMetrics) record(ctx context.Context, trxType TransactionType, created Transaction, err error) {
+	typeAttr := attribute.String("type", string(trxType))
+
+	if err == nil {
+		m.created.Add(ctx, 1, metric.WithAttributes(typeAttr))
+		m.amount.Add(ctx, created.Amount, metric.WithAttributes(typeAttr))
+		return
+	}
+
+	for _, rejection := range rejections {
+		if errors.Is(err, rejection.err) {
+			m.rejected.Add(ctx, 1, metric.WithAttributes(typeAttr, attribute.String("reason", rejection.reason)))
+			break
+		}
+	}
+
+	if errors.Is(err, ErrInsufficientDailyLimit) {
+		m.limitHits.Add(ctx, 1, metric.WithAttributes(typeAttr, attribute.String("limit", "daily_debit")))
+	}
+}
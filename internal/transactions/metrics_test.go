@@ -0,0 +1,40 @@
+//go:build unit
+
+package transactions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics/metricstest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestTransactionMetrics_record(t *testing.T) {
+	ctx := context.Background()
+	collector := metricstest.New()
+	m := newTransactionMetrics(collector)
+
+	m.record(ctx, P2PTransaction, Transaction{Amount: 10.5}, nil)
+	m.record(ctx, P2PTransaction, Transaction{Amount: 4.5}, nil)
+	m.record(ctx, DebitTransaction, Transaction{}, fmt.Errorf("debit: %w", ErrInsufficientDailyLimit))
+	m.record(ctx, DebitTransaction, Transaction{}, errors.New("connection refused"))
+
+	assert.Equal(t, float64(2), collector.Sum(t, "ledger.transactions.created", attribute.String("type", "P2P")))
+	assert.Equal(t, float64(15), collector.Sum(t, "ledger.transactions.amount", attribute.String("type", "P2P")))
+	assert.Equal(t, float64(1), collector.Sum(
+		t,
+		"ledger.transactions.rejected",
+		attribute.String("type", "DEBIT"),
+		attribute.String("reason", "daily_limit_exceeded"),
+	))
+	assert.Equal(t, float64(1), collector.Sum(t, "ledger.transactions.rejected"))
+	assert.Equal(t, float64(1), collector.Sum(
+		t,
+		"ledger.transactions.limit_hits",
+		attribute.String("limit", "daily_debit"),
+	))
+}
@@ -3,6 +3,7 @@ package transactions
 import (
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/google/uuid"
 	"github.com/uptrace/bun"
@@ -17,10 +18,17 @@ type transactionModel struct {
 	Type          TransactionType `bun:"type"`
 	Amount        float64         `bun:"amount"`
 	Description   string          `bun:"description"`
+	Adjustment    bool            `bun:"adjustment"`
+	TenantID      string          `bun:"tenant_id,nullzero"`
 	CreatedAt     time.Time       `bun:"created_at,notnull"`
 }
 
 func newTransactionModel(tx Transaction) transactionModel {
+	createdAt := tx.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
 	return transactionModel{
 		ID:            uuid.New(),
 		FromAccountID: tx.From,
@@ -28,7 +36,22 @@ func newTransactionModel(tx Transaction) transactionModel {
 		Type:          tx.Type,
 		Amount:        tx.Amount,
 		Description:   tx.Description,
-		CreatedAt:     time.Now().UTC(),
+		Adjustment:    tx.Adjustment,
+		// Stored with microseconds, the precision its hash is computed with.
+		CreatedAt: createdAt.UTC().Truncate(time.Microsecond),
+	}
+}
+
+func (m transactionModel) content() ledgerchain.TransactionContent {
+	return ledgerchain.TransactionContent{
+		ID:          m.ID,
+		From:        m.FromAccountID,
+		To:          m.ToAccountID,
+		Type:        string(m.Type),
+		Amount:      m.Amount,
+		Description: m.Description,
+		Adjustment:  m.Adjustment,
+		CreatedAt:   m.CreatedAt,
 	}
 }
 
@@ -38,4 +61,5 @@ type transactionFilter struct {
 	ToAccountID    uuid.NullUUID
 	CreatedAtBegin database.NullTime
 	CreatedAtEnd   database.NullTime
+	TenantID       string
 }
@@ -2,11 +2,23 @@ package transactions
 
 import (
 	"context"
+	"time"
 
+	"github.com/dalmarcogd/ledger-exp/internal/ledgerchain"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/uptrace/bun"
 )
 
+// queryTimeout is the default deadline of the queries of the transactions, on the path of every transfer.
+const queryTimeout = 2 * time.Second
+
+// constraints are the errors of the constraints of the transactions, both accounts must exist.
+var constraints = database.Constraints{
+	"transactions_from_account_id_fkey": ErrAccountNotfound,
+	"transactions_to_account_id_fkey":   ErrAccountNotfound,
+}
+
 type Repository interface {
 	Create(ctx context.Context, model transactionModel) (transactionModel, error)
 	GetByFilter(ctx context.Context, filter transactionFilter) ([]transactionModel, error)
@@ -24,16 +36,27 @@ func NewRepository(t tracer.Tracer, db database.Database) Repository {
 	}
 }
 
+// Create inserts the transaction linking it to the hash chains, in the same database transaction.
 func (r repository) Create(ctx context.Context, model transactionModel) (transactionModel, error) {
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
-	_, err := r.db.Master().
-		NewInsert().
-		Model(&model).
-		Returning("*").
-		Exec(ctx)
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	err := r.db.Master(ctx).RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewInsert().
+			Model(&model).
+			Returning("*").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+
+		return ledgerchain.AppendLinks(ctx, tx, model.content())
+	})
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return transactionModel{}, err
 	}
@@ -45,7 +68,10 @@ func (r repository) GetByFilter(ctx context.Context, filter transactionFilter) (
 	ctx, span := r.tracer.Span(ctx)
 	defer span.End()
 
-	selectQuery := r.db.Replica().NewSelect().Model(&transactionModel{})
+	ctx, cancel := database.WithQueryTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	selectQuery := r.db.Replica(ctx).NewSelect().Model(&transactionModel{})
 	if filter.ID.Valid {
 		selectQuery.Where("id = ?", filter.ID.UUID)
 	}
@@ -66,9 +92,14 @@ func (r repository) GetByFilter(ctx context.Context, filter transactionFilter) (
 		selectQuery.Where("created_at <= ?", filter.CreatedAtEnd.Time)
 	}
 
+	if filter.TenantID != "" {
+		selectQuery.Where("tenant_id = ?", filter.TenantID)
+	}
+
 	var trxs []transactionModel
 	_, err := selectQuery.Exec(ctx, &trxs)
 	if err != nil {
+		err = database.TranslateError(err, constraints)
 		span.RecordError(err)
 		return nil, err
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/dalmarcogd/ledger-exp/internal/holders"
 	"github.com/dalmarcogd/ledger-exp/internal/statements"
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/golang/mock/gomock"
@@ -40,7 +41,7 @@ func TestRepository(t *testing.T) {
 	)
 	assert.NoError(t, err)
 
-	db, err := database.New(tracer.NewNoop(), url, url)
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 	assert.NoError(t, err)
 
 	holdersRepo := holders.NewRepository(tracer.NewNoop(), db)
@@ -52,7 +53,12 @@ func TestRepository(t *testing.T) {
 	holderModel, err = holdersRepo.Create(ctx, holderModel)
 	assert.NoError(t, err)
 
-	accSvc := accounts.NewService(tracer.NewNoop(), accounts.NewRepository(tracer.NewNoop(), db), holdersRepo)
+	accSvc := accounts.NewService(
+		tracer.NewNoop(),
+		accounts.NewRepository(tracer.NewNoop(), db),
+		holdersRepo,
+		database.NewUnitOfWork(tracer.NewNoop(), db),
+	)
 
 	account1, err := accSvc.Create(ctx, accounts.Account{
 		ID:             uuid.New(),
@@ -216,11 +222,11 @@ func TestRepository(t *testing.T) {
 	})
 
 	t.Run("check accounts balance", func(t *testing.T) {
-		accountBalance1, err := balanceRepo.GetByAccountID(ctx, account1.ID)
+		accountBalance1, err := balanceRepo.GetByAccountID(ctx, "", account1.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(30), accountBalance1.Balance)
 
-		accountBalance2, err := balanceRepo.GetByAccountID(ctx, account2.ID)
+		accountBalance2, err := balanceRepo.GetByAccountID(ctx, "", account2.ID)
 		assert.NoError(t, err)
 		assert.Equal(t, float64(120), accountBalance2.Balance)
 	})
@@ -5,13 +5,19 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"time"
 
 	"github.com/dalmarcogd/ledger-exp/internal/accounts"
 	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/distlock"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	redis2 "github.com/go-redis/redis/v8"
@@ -29,6 +35,9 @@ var (
 	ErrBalanceInsufficientFunds              = errors.New("insufficient funds to complete the transaction")
 	ErrAccountInactive                       = errors.New("the account related to the transaction must be active")
 	ErrInsufficientDailyLimit                = errors.New("the account has insufficient daily limit")
+	ErrTransactionInFuture                   = errors.New("the transaction can not be dated in the future")
+	ErrPeriodClosed                          = errors.New("the transaction can not be dated into a closed business day")
+	ErrNotAdjustmentPeriod                   = errors.New("adjusting entries are only allowed into business days open for adjustments")
 )
 
 type Service interface {
@@ -36,42 +45,56 @@ type Service interface {
 	CreateDebit(ctx context.Context, transaction Transaction) (Transaction, error)
 	CreateP2P(ctx context.Context, transaction Transaction) (Transaction, error)
 	GetByID(ctx context.Context, id uuid.UUID) (Transaction, error)
+	ListByAccount(ctx context.Context, accountID uuid.UUID, begin, end time.Time) ([]Transaction, error)
 }
 
 type service struct {
-	tracer      tracer.Tracer
-	repository  Repository
-	locker      distlock.DistLock
-	accountsSvs accounts.Service
-	balancesSvs balances.Service
-	redis       redis.Client
+	tracer          tracer.Tracer
+	repository      Repository
+	locker          distlock.DistLock
+	accountsSvs     accounts.Service
+	balancesSvs     balances.Service
+	businessDaysSvs businessdays.Service
+	redis           redis.Client
+	metrics         transactionMetrics
 }
 
 func NewService(
 	t tracer.Tracer,
+	m metrics.Metrics,
 	r Repository,
 	l distlock.DistLock,
 	as accounts.Service,
 	bs balances.Service,
+	bds businessdays.Service,
 	redis redis.Client,
 ) Service {
 	return service{
-		tracer:      t,
-		repository:  r,
-		locker:      l,
-		accountsSvs: as,
-		balancesSvs: bs,
-		redis:       redis,
+		tracer:          t,
+		repository:      r,
+		locker:          l,
+		accountsSvs:     as,
+		balancesSvs:     bs,
+		businessDaysSvs: bds,
+		redis:           redis,
+		metrics:         newTransactionMetrics(m),
 	}
 }
 
-func (s service) CreateCredit(ctx context.Context, transaction Transaction) (Transaction, error) {
+func (s service) CreateCredit(ctx context.Context, transaction Transaction) (created Transaction, err error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
+	defer func() { s.metrics.record(ctx, CreditTransaction, created, err) }()
 
 	transaction.Type = CreditTransaction
 
-	err := s.checkAccount(ctx, transaction.To)
+	transaction, err = s.checkPosting(ctx, transaction)
+	if err != nil {
+		span.RecordError(err)
+		return Transaction{}, err
+	}
+
+	_, err = s.checkAccount(ctx, transaction.To)
 	if err != nil {
 		span.RecordError(err)
 		return Transaction{}, err
@@ -80,13 +103,20 @@ func (s service) CreateCredit(ctx context.Context, transaction Transaction) (Tra
 	return s.createCredit(ctx, transaction)
 }
 
-func (s service) CreateDebit(ctx context.Context, transaction Transaction) (Transaction, error) {
+func (s service) CreateDebit(ctx context.Context, transaction Transaction) (created Transaction, err error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
+	defer func() { s.metrics.record(ctx, DebitTransaction, created, err) }()
 
 	transaction.Type = DebitTransaction
 
-	err := s.checkAccount(ctx, transaction.From)
+	transaction, err = s.checkPosting(ctx, transaction)
+	if err != nil {
+		span.RecordError(err)
+		return Transaction{}, err
+	}
+
+	_, err = s.checkAccount(ctx, transaction.From)
 	if err != nil {
 		span.RecordError(err)
 		return Transaction{}, err
@@ -95,9 +125,10 @@ func (s service) CreateDebit(ctx context.Context, transaction Transaction) (Tran
 	return s.createDebit(ctx, transaction)
 }
 
-func (s service) CreateP2P(ctx context.Context, transaction Transaction) (Transaction, error) {
+func (s service) CreateP2P(ctx context.Context, transaction Transaction) (created Transaction, err error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
+	defer func() { s.metrics.record(ctx, P2PTransaction, created, err) }()
 
 	transaction.Type = P2PTransaction
 
@@ -112,22 +143,89 @@ func (s service) CreateP2P(ctx context.Context, transaction Transaction) (Transa
 		return Transaction{}, ErrFromAccountToAccountShouldBeDifferent
 	}
 
-	err := s.checkAccount(ctx, transaction.From)
+	transaction, err = s.checkPosting(ctx, transaction)
+	if err != nil {
+		span.RecordError(err)
+		return Transaction{}, err
+	}
+
+	from, err := s.checkAccount(ctx, transaction.From)
 	if err != nil {
 		span.RecordError(err)
 		return Transaction{}, err
 	}
 
-	err = s.checkAccount(ctx, transaction.To)
+	// The money can be sent to the accounts of any holder, but only of the same tenant.
+	to, err := s.checkAccount(authz.Unrestricted(ctx), transaction.To)
 	if err != nil {
 		span.RecordError(err)
 		return Transaction{}, err
 	}
 
+	if from.TenantID != to.TenantID {
+		zapctx.L(ctx).Error(
+			"transaction_service_cross_tenant_error",
+			zap.String("from_tenant_id", from.TenantID),
+			zap.String("to_tenant_id", to.TenantID),
+		)
+		span.RecordError(ErrAccountNotfound)
+		return Transaction{}, ErrAccountNotfound
+	}
+
 	return s.createDebit(ctx, transaction)
 }
 
-func (s service) checkAccount(ctx context.Context, accountID uuid.UUID) error {
+// checkPosting dates the transaction now when it has no date and checks its date against the business days. Only the
+// principals with the scope of the business days date the transactions or post adjusting entries, the others always
+// post now.
+func (s service) checkPosting(ctx context.Context, transaction Transaction) (Transaction, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if !transaction.CreatedAt.IsZero() || transaction.Adjustment {
+		if err := authz.Authorize(ctx, authz.ScopeBusinessDays); err != nil {
+			span.RecordError(err)
+			return Transaction{}, err
+		}
+	}
+
+	now := time.Now().UTC()
+	if transaction.CreatedAt.IsZero() {
+		transaction.CreatedAt = now
+	} else if transaction.CreatedAt.After(now) {
+		span.RecordError(ErrTransactionInFuture)
+		return Transaction{}, ErrTransactionInFuture
+	}
+	transaction.CreatedAt = transaction.CreatedAt.UTC()
+
+	err := s.businessDaysSvs.CheckPosting(ctx, transaction.CreatedAt, transaction.Adjustment)
+	if err != nil {
+		span.RecordError(err)
+
+		switch {
+		case errors.Is(err, businessdays.ErrBusinessDayClosed):
+			err = ErrPeriodClosed
+		case errors.Is(err, businessdays.ErrNotAdjustmentPeriod):
+			err = ErrNotAdjustmentPeriod
+		default:
+			zapctx.L(ctx).Error("transaction_service_business_day_check_error", zap.Error(err))
+			return Transaction{}, err
+		}
+
+		zapctx.L(ctx).Error(
+			"transaction_service_business_day_closed_error",
+			zap.Error(err),
+			zap.Time("created_at", transaction.CreatedAt),
+			zap.Bool("adjustment", transaction.Adjustment),
+		)
+		return Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// checkAccount returns the account when it is active, looking it up in the tenant of ctx.
+func (s service) checkAccount(ctx context.Context, accountID uuid.UUID) (accounts.Account, error) {
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
@@ -135,6 +233,10 @@ func (s service) checkAccount(ctx context.Context, accountID uuid.UUID) error {
 	if err != nil {
 		span.RecordError(err)
 
+		if errors.Is(err, authz.ErrForbidden) {
+			return accounts.Account{}, err
+		}
+
 		if !errors.Is(err, accounts.ErrAccountNotFound) {
 			zapctx.L(ctx).Error(
 				"transaction_service_acccount_check_error",
@@ -142,7 +244,7 @@ func (s service) checkAccount(ctx context.Context, accountID uuid.UUID) error {
 				zap.String("account_id", accountID.String()),
 			)
 		}
-		return ErrAccountNotfound
+		return accounts.Account{}, ErrAccountNotfound
 	}
 
 	if acc.Status != accounts.ActiveStatus {
@@ -152,10 +254,10 @@ func (s service) checkAccount(ctx context.Context, accountID uuid.UUID) error {
 			zap.String("account_id", accountID.String()),
 		)
 		span.RecordError(ErrAccountInactive)
-		return ErrAccountInactive
+		return accounts.Account{}, ErrAccountInactive
 	}
 
-	return nil
+	return acc, nil
 }
 
 func (s service) createDebit(ctx context.Context, transaction Transaction) (Transaction, error) {
@@ -177,7 +279,9 @@ func (s service) createDebit(ctx context.Context, transaction Transaction) (Tran
 		50*time.Millisecond,
 		3,
 	) {
-		accountBalance, err := s.balancesSvs.GetByAccountID(ctx, transaction.From)
+		// the balance must include the transactions just created by the previous debits of the account, which the
+		// replicas may not have replicated yet.
+		accountBalance, err := s.balancesSvs.GetByAccountID(database.PinMaster(ctx), transaction.From)
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			zapctx.L(ctx).Error("transaction_service_get_balance_error", zap.Error(err))
 			span.RecordError(err)
@@ -239,7 +343,7 @@ func (s service) checkDebitLimit(ctx context.Context, accountID uuid.UUID, amoun
 		return err
 	}
 
-	if (value + amount) > 2000 {
+	if (value + amount) > tenancy.Current(ctx).DailyDebitLimit {
 		span.RecordError(ErrInsufficientDailyLimit)
 		return ErrInsufficientDailyLimit
 	}
@@ -313,7 +417,10 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Transaction, error)
 	ctx, span := s.tracer.Span(ctx)
 	defer span.End()
 
-	models, err := s.repository.GetByFilter(ctx, transactionFilter{ID: uuid.NullUUID{UUID: id, Valid: true}})
+	models, err := s.repository.GetByFilter(ctx, transactionFilter{
+		ID:       uuid.NullUUID{UUID: id, Valid: true},
+		TenantID: tenancy.FilterID(ctx),
+	})
 	if err != nil {
 		zapctx.L(ctx).Error(
 			"transaction_service_get_repository_error",
@@ -332,5 +439,91 @@ func (s service) GetByID(ctx context.Context, id uuid.UUID) (Transaction, error)
 		return Transaction{}, ErrMultpleTransactionsFound
 	}
 
-	return newTransaction(models[0]), nil
+	transaction := newTransaction(models[0])
+
+	if err := s.authorize(ctx, transaction); err != nil {
+		span.RecordError(err)
+		return Transaction{}, err
+	}
+
+	return transaction, nil
+}
+
+// authorize returns authz.ErrForbidden when the principal is bound to a holder and the transaction neither came
+// from nor went to one of its accounts.
+func (s service) authorize(ctx context.Context, transaction Transaction) error {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if _, bound := authz.Holder(ctx); !bound {
+		return nil
+	}
+
+	var holderIDs []uuid.UUID
+	for _, accountID := range []uuid.UUID{transaction.From, transaction.To} {
+		if accountID == uuid.Nil {
+			continue
+		}
+
+		acc, err := s.accountsSvs.GetByID(authz.Unrestricted(ctx), accountID)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"transaction_service_authorize_account_error",
+				zap.String("account_id", accountID.String()),
+				zap.Error(err),
+			)
+			span.RecordError(err)
+			return err
+		}
+		holderIDs = append(holderIDs, acc.HolderID)
+	}
+
+	return authz.AuthorizeHolder(ctx, holderIDs...)
+}
+
+// ListByAccount returns the transactions sent or received by the account created between begin and end, both
+// inclusive, in chronological order.
+func (s service) ListByAccount(ctx context.Context, accountID uuid.UUID, begin, end time.Time) ([]Transaction, error) {
+	ctx, span := s.tracer.Span(ctx)
+	defer span.End()
+
+	if err := s.accountsSvs.Authorize(ctx, accountID); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	period := transactionFilter{
+		CreatedAtBegin: database.NullTime{Time: begin, Valid: true},
+		CreatedAtEnd:   database.NullTime{Time: end, Valid: true},
+		TenantID:       tenancy.FilterID(ctx),
+	}
+
+	sent := period
+	sent.FromAccountID = uuid.NullUUID{UUID: accountID, Valid: true}
+	received := period
+	received.ToAccountID = uuid.NullUUID{UUID: accountID, Valid: true}
+
+	var trxs []Transaction
+	for _, filter := range []transactionFilter{sent, received} {
+		models, err := s.repository.GetByFilter(ctx, filter)
+		if err != nil {
+			zapctx.L(ctx).Error(
+				"transaction_service_list_repository_error",
+				zap.String("account_id", accountID.String()),
+				zap.Error(err),
+			)
+			span.RecordError(err)
+			return nil, err
+		}
+
+		for _, model := range models {
+			trxs = append(trxs, newTransaction(model))
+		}
+	}
+
+	sort.SliceStable(trxs, func(i, j int) bool {
+		return trxs[i].CreatedAt.Before(trxs[j].CreatedAt)
+	})
+
+	return trxs, nil
 }
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/transactions/service.go
+
+// Package transactions is a generated GoMock package.
+package transactions
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateCredit mocks base method.
+func (m *MockService) CreateCredit(ctx context.Context, transaction Transaction) (Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCredit", ctx, transaction)
+	ret0, _ := ret[0].(Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCredit indicates an expected call of CreateCredit.
+func (mr *MockServiceMockRecorder) CreateCredit(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredit", reflect.TypeOf((*MockService)(nil).CreateCredit), ctx, transaction)
+}
+
+// CreateDebit mocks base method.
+func (m *MockService) CreateDebit(ctx context.Context, transaction Transaction) (Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDebit", ctx, transaction)
+	ret0, _ := ret[0].(Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDebit indicates an expected call of CreateDebit.
+func (mr *MockServiceMockRecorder) CreateDebit(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDebit", reflect.TypeOf((*MockService)(nil).CreateDebit), ctx, transaction)
+}
+
+// CreateP2P mocks base method.
+func (m *MockService) CreateP2P(ctx context.Context, transaction Transaction) (Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateP2P", ctx, transaction)
+	ret0, _ := ret[0].(Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateP2P indicates an expected call of CreateP2P.
+func (mr *MockServiceMockRecorder) CreateP2P(ctx, transaction interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateP2P", reflect.TypeOf((*MockService)(nil).CreateP2P), ctx, transaction)
+}
+
+// GetByID mocks base method.
+func (m *MockService) GetByID(ctx context.Context, id uuid.UUID) (Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), ctx, id)
+}
+
+// ListByAccount mocks base method.
+func (m *MockService) ListByAccount(ctx context.Context, accountID uuid.UUID, begin, end time.Time) ([]Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByAccount", ctx, accountID, begin, end)
+	ret0, _ := ret[0].([]Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByAccount indicates an expected call of ListByAccount.
+func (mr *MockServiceMockRecorder) ListByAccount(ctx, accountID, begin, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByAccount", reflect.TypeOf((*MockService)(nil).ListByAccount), ctx, accountID, begin, end)
+}
@@ -11,14 +11,23 @@ import (
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/dalmarcogd/ledger-exp/internal/accounts"
 	"github.com/dalmarcogd/ledger-exp/internal/balances"
+	"github.com/dalmarcogd/ledger-exp/internal/businessdays"
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
 	"github.com/dalmarcogd/ledger-exp/pkg/distlock"
 	"github.com/dalmarcogd/ledger-exp/pkg/gomockeq"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics/metricstest"
 	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer/tracertest"
 	redis2 "github.com/go-redis/redis/v8"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func TestService_CreateCredit(t *testing.T) {
@@ -29,17 +38,25 @@ func TestService_CreateCredit(t *testing.T) {
 	repoMock := NewMockRepository(ctrl)
 	accSvcMock := accounts.NewMockService(ctrl)
 	blcSvcMock := balances.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
 	redisMock := redis.NewMockClient(ctrl)
 
 	svc := NewService(
 		tracer.NewNoop(),
+		metrics.NewNoop(),
 		repoMock,
 		distlock.NewDistlockNoop(),
 		accSvcMock,
 		blcSvcMock,
+		bdSvcMock,
 		redisMock,
 	)
 
+	bdSvcMock.EXPECT().
+		CheckPosting(gomock.Any(), gomock.Any(), false).
+		Return(nil).
+		AnyTimes()
+
 	accountID := uuid.New()
 
 	t.Run("fail transaction, account not found", func(t *testing.T) {
@@ -115,6 +132,59 @@ func TestService_CreateCredit(t *testing.T) {
 	})
 }
 
+func TestService_CreateCredit_spans(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
+	recorder := tracertest.New(t)
+
+	svc := NewService(
+		recorder,
+		metrics.NewNoop(),
+		repoMock,
+		distlock.NewDistlockNoop(),
+		accSvcMock,
+		balances.NewMockService(ctrl),
+		bdSvcMock,
+		redis.NewMockClient(ctrl),
+	)
+
+	bdSvcMock.EXPECT().CheckPosting(gomock.Any(), gomock.Any(), false).Return(nil).AnyTimes()
+	accSvcMock.EXPECT().
+		GetByID(gomock.Any(), gomock.Any()).
+		Return(accounts.Account{Status: accounts.ActiveStatus}, nil)
+	repoMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(transactionModel{}, nil)
+
+	_, err := svc.CreateCredit(ctx, Transaction{To: uuid.New(), Amount: 10})
+	assert.NoError(t, err)
+	recorder.AssertTree(t, tracertest.Span{
+		Name: "transactions.service.CreateCredit",
+		Children: []tracertest.Span{
+			{Name: "transactions.service.checkPosting"},
+			{Name: "transactions.service.checkAccount"},
+			{Name: "transactions.service.createCredit"},
+		},
+	})
+
+	recorder.Reset()
+	accSvcMock.EXPECT().GetByID(gomock.Any(), gomock.Any()).Return(accounts.Account{}, accounts.ErrAccountNotFound)
+
+	_, err = svc.CreateCredit(ctx, Transaction{To: uuid.New(), Amount: 10})
+	assert.ErrorIs(t, err, ErrAccountNotfound)
+	recorder.AssertTree(t, tracertest.Span{
+		Name:  "transactions.service.CreateCredit",
+		Error: true,
+		Children: []tracertest.Span{
+			{Name: "transactions.service.checkPosting"},
+			{Name: "transactions.service.checkAccount", Error: true},
+		},
+	})
+}
+
 func TestService_CreateDebit(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -123,17 +193,26 @@ func TestService_CreateDebit(t *testing.T) {
 	repoMock := NewMockRepository(ctrl)
 	accSvcMock := accounts.NewMockService(ctrl)
 	blcSvcMock := balances.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
 	redisMock := redis.NewMockClient(ctrl)
+	collector := metricstest.New()
 
 	svc := NewService(
 		tracer.NewNoop(),
+		collector,
 		repoMock,
 		distlock.NewDistlockNoop(),
 		accSvcMock,
 		blcSvcMock,
+		bdSvcMock,
 		redisMock,
 	)
 
+	bdSvcMock.EXPECT().
+		CheckPosting(gomock.Any(), gomock.Any(), false).
+		Return(nil).
+		AnyTimes()
+
 	accountID := uuid.New()
 
 	t.Run("fail transaction, account not found", func(t *testing.T) {
@@ -150,6 +229,12 @@ func TestService_CreateDebit(t *testing.T) {
 		credit, err := svc.CreateDebit(ctx, trx)
 		assert.EqualError(t, err, "the to account could be found")
 		assert.Empty(t, credit)
+		assert.Equal(t, float64(1), collector.Sum(
+			t,
+			"ledger.transactions.rejected",
+			attribute.String("type", string(DebitTransaction)),
+			attribute.String("reason", "account_not_found"),
+		))
 	})
 
 	t.Run("fail transaction, account inactive", func(t *testing.T) {
@@ -216,7 +301,9 @@ func TestService_CreateDebit(t *testing.T) {
 			},
 		).Return(redis2.NewStatusResult("10", nil))
 
-		blcSvcMock.EXPECT().GetByAccountID(ctx, accountID).Return(balances.AccountBalance{CurrentBalance: 1000}, nil)
+		blcSvcMock.EXPECT().
+			GetByAccountID(database.PinMaster(ctx), accountID).
+			Return(balances.AccountBalance{CurrentBalance: 1000}, nil)
 
 		repoMock.EXPECT().
 			Create(
@@ -235,6 +322,12 @@ func TestService_CreateDebit(t *testing.T) {
 		credit, err := svc.CreateDebit(ctx, trx)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, credit)
+		assert.Equal(t, float64(1), collector.Sum(
+			t,
+			"ledger.transactions.created",
+			attribute.String("type", string(DebitTransaction)),
+		))
+		assert.Equal(t, float64(2), collector.Sum(t, "ledger.transactions.rejected"))
 	})
 }
 
@@ -246,17 +339,25 @@ func TestService_CreateP2P(t *testing.T) {
 	repoMock := NewMockRepository(ctrl)
 	accSvcMock := accounts.NewMockService(ctrl)
 	blcSvcMock := balances.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
 	redisMock := redis.NewMockClient(ctrl)
 
 	svc := NewService(
 		tracer.NewNoop(),
+		metrics.NewNoop(),
 		repoMock,
 		distlock.NewDistlockNoop(),
 		accSvcMock,
 		blcSvcMock,
+		bdSvcMock,
 		redisMock,
 	)
 
+	bdSvcMock.EXPECT().
+		CheckPosting(gomock.Any(), gomock.Any(), false).
+		Return(nil).
+		AnyTimes()
+
 	accountID1 := uuid.New()
 	accountID2 := uuid.New()
 
@@ -278,7 +379,7 @@ func TestService_CreateP2P(t *testing.T) {
 			)
 
 		accSvcMock.EXPECT().
-			GetByID(ctx, accountID2).
+			GetByID(gomock.Any(), accountID2).
 			Return(
 				accounts.Account{
 					Status: accounts.BlockedStatus,
@@ -309,7 +410,7 @@ func TestService_CreateP2P(t *testing.T) {
 				nil,
 			)
 		accSvcMock.EXPECT().
-			GetByID(ctx, accountID2).
+			GetByID(gomock.Any(), accountID2).
 			Return(
 				accounts.Account{
 					Status: accounts.ActiveStatus,
@@ -343,7 +444,9 @@ func TestService_CreateP2P(t *testing.T) {
 			},
 		).Return(redis2.NewStatusResult("10", nil))
 
-		blcSvcMock.EXPECT().GetByAccountID(ctx, accountID1).Return(balances.AccountBalance{CurrentBalance: 1000}, nil)
+		blcSvcMock.EXPECT().
+			GetByAccountID(database.PinMaster(ctx), accountID1).
+			Return(balances.AccountBalance{CurrentBalance: 1000}, nil)
 
 		repoMock.EXPECT().
 			Create(
@@ -365,3 +468,237 @@ func TestService_CreateP2P(t *testing.T) {
 		assert.NotEmpty(t, credit)
 	})
 }
+
+func TestService_CreateCreditBusinessDays(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
+
+	svc := NewService(
+		tracer.NewNoop(),
+		metrics.NewNoop(),
+		repoMock,
+		distlock.NewDistlockNoop(),
+		accSvcMock,
+		balances.NewMockService(ctrl),
+		bdSvcMock,
+		redis.NewMockClient(ctrl),
+	)
+
+	accountID := uuid.New()
+	backdated := time.Now().UTC().AddDate(0, 0, -3).Truncate(time.Microsecond)
+
+	t.Run("fail transaction, dated in the future", func(t *testing.T) {
+		credit, err := svc.CreateCredit(ctx, Transaction{
+			To:        accountID,
+			Amount:    10,
+			CreatedAt: time.Now().Add(time.Hour),
+		})
+		assert.ErrorIs(t, err, ErrTransactionInFuture)
+		assert.Empty(t, credit)
+	})
+
+	t.Run("fail transaction, backdated into a closed business day", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			CheckPosting(gomock.Any(), backdated, false).
+			Return(businessdays.ErrBusinessDayClosed)
+
+		credit, err := svc.CreateCredit(ctx, Transaction{To: accountID, Amount: 10, CreatedAt: backdated})
+		assert.ErrorIs(t, err, ErrPeriodClosed)
+		assert.Empty(t, credit)
+	})
+
+	t.Run("fail transaction, adjustment out of an adjustment period", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			CheckPosting(gomock.Any(), backdated, true).
+			Return(businessdays.ErrNotAdjustmentPeriod)
+
+		credit, err := svc.CreateCredit(ctx, Transaction{
+			To:         accountID,
+			Amount:     10,
+			Adjustment: true,
+			CreatedAt:  backdated,
+		})
+		assert.ErrorIs(t, err, ErrNotAdjustmentPeriod)
+		assert.Empty(t, credit)
+	})
+
+	t.Run("fail transaction, backdated by a principal without the scope of the business days", func(t *testing.T) {
+		ctx := auth.WithPrincipal(ctx, auth.Principal{
+			ID:     "key",
+			Type:   auth.APIKeyPrincipal,
+			Scopes: []string{string(authz.ScopeTransactionsCredit), string(authz.ScopeTransactionsDebit)},
+		})
+
+		debit, err := svc.CreateDebit(ctx, Transaction{From: accountID, Amount: 10, CreatedAt: backdated})
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, debit)
+
+		credit, err := svc.CreateCredit(ctx, Transaction{To: accountID, Amount: 10, Adjustment: true})
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, credit)
+	})
+
+	t.Run("success adjusting entry dated into an adjustment period", func(t *testing.T) {
+		bdSvcMock.EXPECT().
+			CheckPosting(gomock.Any(), backdated, true).
+			Return(nil)
+
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID).
+			Return(accounts.Account{Status: accounts.ActiveStatus}, nil)
+
+		repoMock.EXPECT().
+			Create(
+				gomock.Any(),
+				gomockeq.Eq(
+					transactionModel{
+						ToAccountID: accountID,
+						Type:        CreditTransaction,
+						Amount:      10,
+						Adjustment:  true,
+						CreatedAt:   backdated,
+					},
+					gomockeq.IgnoreFields("ID"),
+				),
+			).Return(transactionModel{}, nil)
+
+		credit, err := svc.CreateCredit(ctx, Transaction{
+			To:         accountID,
+			Amount:     10,
+			Adjustment: true,
+			CreatedAt:  backdated,
+		})
+		assert.NoError(t, err)
+		assert.True(t, credit.Adjustment)
+		assert.Equal(t, backdated, credit.CreatedAt)
+	})
+}
+
+func TestService_GetByIDHolderBoundPrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+
+	svc := NewService(
+		tracer.NewNoop(),
+		metrics.NewNoop(),
+		repoMock,
+		distlock.NewDistlockNoop(),
+		accSvcMock,
+		balances.NewMockService(ctrl),
+		businessdays.NewMockService(ctrl),
+		redis.NewMockClient(ctrl),
+	)
+
+	holderID := uuid.New()
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:       "user",
+		Type:     auth.JWTPrincipal,
+		HolderID: uuid.NullUUID{UUID: holderID, Valid: true},
+	})
+
+	fromAccountID := uuid.New()
+	toAccountID := uuid.New()
+	trxID := uuid.New()
+
+	repoMock.EXPECT().
+		GetByFilter(gomock.Any(), transactionFilter{ID: uuid.NullUUID{UUID: trxID, Valid: true}}).
+		Return([]transactionModel{{ID: trxID, FromAccountID: fromAccountID, ToAccountID: toAccountID}}, nil).
+		Times(2)
+
+	t.Run("success get, received by the holder", func(t *testing.T) {
+		accSvcMock.EXPECT().GetByID(gomock.Any(), fromAccountID).Return(accounts.Account{HolderID: uuid.New()}, nil)
+		accSvcMock.EXPECT().GetByID(gomock.Any(), toAccountID).Return(accounts.Account{HolderID: holderID}, nil)
+
+		trx, err := svc.GetByID(ctx, trxID)
+		assert.NoError(t, err)
+		assert.Equal(t, trxID, trx.ID)
+	})
+
+	t.Run("fail get, transaction of other holders", func(t *testing.T) {
+		accSvcMock.EXPECT().GetByID(gomock.Any(), fromAccountID).Return(accounts.Account{HolderID: uuid.New()}, nil)
+		accSvcMock.EXPECT().GetByID(gomock.Any(), toAccountID).Return(accounts.Account{HolderID: uuid.New()}, nil)
+
+		trx, err := svc.GetByID(ctx, trxID)
+		assert.ErrorIs(t, err, authz.ErrForbidden)
+		assert.Empty(t, trx)
+	})
+}
+
+func TestService_CreateP2PTenants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repoMock := NewMockRepository(ctrl)
+	accSvcMock := accounts.NewMockService(ctrl)
+	bdSvcMock := businessdays.NewMockService(ctrl)
+
+	svc := NewService(
+		tracer.NewNoop(),
+		metrics.NewNoop(),
+		repoMock,
+		distlock.NewDistlockNoop(),
+		accSvcMock,
+		balances.NewMockService(ctrl),
+		bdSvcMock,
+		redis.NewMockClient(ctrl),
+	)
+
+	bdSvcMock.EXPECT().
+		CheckPosting(gomock.Any(), gomock.Any(), false).
+		Return(nil).
+		AnyTimes()
+
+	accountID1 := uuid.New()
+	accountID2 := uuid.New()
+
+	t.Run("fail transaction, accounts of different tenants", func(t *testing.T) {
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID1).
+			Return(accounts.Account{Status: accounts.ActiveStatus, TenantID: "brand-a"}, nil)
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID2).
+			Return(accounts.Account{Status: accounts.ActiveStatus, TenantID: "brand-b"}, nil)
+
+		trx, err := svc.CreateP2P(context.Background(), Transaction{From: accountID1, To: accountID2, Amount: 10})
+		assert.ErrorIs(t, err, ErrAccountNotfound)
+		assert.Empty(t, trx)
+	})
+
+	t.Run("fail transaction, account of other tenant not found", func(t *testing.T) {
+		ctx := tenancy.WithTenant(context.Background(), tenancy.Tenant{ID: "brand-a"})
+
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID1).
+			Return(accounts.Account{Status: accounts.ActiveStatus, TenantID: "brand-a"}, nil)
+		accSvcMock.EXPECT().
+			GetByID(gomock.Any(), accountID2).
+			Return(accounts.Account{}, accounts.ErrAccountNotFound)
+
+		trx, err := svc.CreateP2P(ctx, Transaction{From: accountID1, To: accountID2, Amount: 10})
+		assert.ErrorIs(t, err, ErrAccountNotfound)
+		assert.Empty(t, trx)
+	})
+
+	t.Run("fail get, transaction of other tenant", func(t *testing.T) {
+		ctx := tenancy.WithTenant(context.Background(), tenancy.Tenant{ID: "brand-a"})
+		trxID := uuid.New()
+
+		repoMock.EXPECT().
+			GetByFilter(gomock.Any(), transactionFilter{
+				ID:       uuid.NullUUID{UUID: trxID, Valid: true},
+				TenantID: "brand-a",
+			}).
+			Return(nil, nil)
+
+		_, err := svc.GetByID(ctx, trxID)
+		assert.ErrorIs(t, err, ErrTransactionNotFound)
+	})
+}
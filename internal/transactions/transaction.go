@@ -1,6 +1,8 @@
 package transactions
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -19,6 +21,11 @@ type Transaction struct {
 	Type        TransactionType
 	Amount      float64
 	Description string
+	// Adjustment marks an adjusting entry, only allowed dated into a business day open for adjustments.
+	Adjustment bool
+	// CreatedAt is the date of the transaction, when empty it is dated now. Dating it into the past (backdating) is
+	// only allowed after the latest closed business day.
+	CreatedAt time.Time
 }
 
 func newTransaction(model transactionModel) Transaction {
@@ -29,5 +36,7 @@ func newTransaction(model transactionModel) Transaction {
 		Type:        model.Type,
 		Amount:      model.Amount,
 		Description: model.Description,
+		Adjustment:  model.Adjustment,
+		CreatedAt:   model.CreatedAt,
 	}
 }
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS transactions_description_search_index;
+
+CREATE INDEX IF NOT EXISTS transactions_from_account_id_index ON transactions (from_account_id);
+CREATE INDEX IF NOT EXISTS transactions_to_account_id_index ON transactions (to_account_id);
+
+DROP INDEX IF EXISTS transactions_from_account_id_created_at_id_index;
+DROP INDEX IF EXISTS transactions_to_account_id_created_at_id_index;
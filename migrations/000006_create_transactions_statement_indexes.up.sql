@@ -0,0 +1,10 @@
+-- The statements of an account are selected by account and sorted/paged by (created_at, id), the composite indexes
+-- replace the single column ones.
+CREATE INDEX IF NOT EXISTS transactions_from_account_id_created_at_id_index ON transactions (from_account_id, created_at, id);
+CREATE INDEX IF NOT EXISTS transactions_to_account_id_created_at_id_index ON transactions (to_account_id, created_at, id);
+
+DROP INDEX IF EXISTS transactions_from_account_id_index;
+DROP INDEX IF EXISTS transactions_to_account_id_index;
+
+-- The expression must be the same used by the description search of the statements.
+CREATE INDEX IF NOT EXISTS transactions_description_search_index ON transactions USING GIN (to_tsvector('simple', description));
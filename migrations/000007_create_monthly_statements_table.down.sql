@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS monthly_statements;
+DROP FUNCTION IF EXISTS monthly_statements_immutable;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS monthly_statements
+(
+    id            VARCHAR(36) PRIMARY KEY,
+    account_id    VARCHAR(36) NOT NULL,
+    month         DATE        NOT NULL,
+    content       BYTEA       NOT NULL,
+    document      BYTEA       NOT NULL,
+    content_hash  VARCHAR(64) NOT NULL,
+    document_hash VARCHAR(64) NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+CREATE UNIQUE INDEX monthly_statements_account_id_month ON monthly_statements (account_id, month);
+
+-- Monthly statements are official documents, once generated they can not be changed or removed.
+CREATE OR REPLACE FUNCTION monthly_statements_immutable() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'monthly statements are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER monthly_statements_immutable
+    BEFORE UPDATE OR DELETE
+    ON monthly_statements
+    FOR EACH ROW
+EXECUTE FUNCTION monthly_statements_immutable();
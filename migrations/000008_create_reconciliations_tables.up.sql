@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS reconciliations
+(
+    id                  VARCHAR(36) PRIMARY KEY,
+    account_id          VARCHAR(36)  NOT NULL,
+    format              VARCHAR(36)  NOT NULL,
+    file_name           VARCHAR(200) NOT NULL,
+    period_begin        TIMESTAMPTZ  NOT NULL,
+    period_end          TIMESTAMPTZ  NOT NULL,
+    amount_tolerance    DECIMAL      NOT NULL,
+    date_tolerance_days INTEGER      NOT NULL,
+    matched             INTEGER      NOT NULL,
+    unmatched_external  INTEGER      NOT NULL,
+    unmatched_ledger    INTEGER      NOT NULL,
+    created_at          TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+CREATE INDEX reconciliations_account_id_index ON reconciliations (account_id);
+
+CREATE TABLE IF NOT EXISTS reconciliation_items
+(
+    id                   VARCHAR(36) PRIMARY KEY,
+    reconciliation_id    VARCHAR(36) NOT NULL,
+    status               VARCHAR(36) NOT NULL,
+    rule                 VARCHAR(36),
+    external_line_number INTEGER,
+    external_date        TIMESTAMPTZ,
+    external_amount      DECIMAL,
+    external_reference   VARCHAR(200),
+    external_description VARCHAR(200),
+    transaction_id       VARCHAR(36),
+    ledger_amount        DECIMAL,
+    ledger_date          TIMESTAMPTZ,
+
+    FOREIGN KEY (reconciliation_id) REFERENCES reconciliations (id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+);
+
+CREATE INDEX reconciliation_items_reconciliation_id_status_index ON reconciliation_items (reconciliation_id, status);
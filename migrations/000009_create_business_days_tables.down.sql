@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS transactions_business_day_check ON transactions;
+DROP FUNCTION IF EXISTS transactions_business_day_check;
+ALTER TABLE transactions DROP COLUMN IF EXISTS adjustment;
+DROP TABLE IF EXISTS business_day_balances;
+DROP TABLE IF EXISTS business_days;
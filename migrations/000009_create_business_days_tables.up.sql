@@ -0,0 +1,57 @@
+CREATE TABLE IF NOT EXISTS business_days
+(
+    date       DATE        PRIMARY KEY,
+    status     VARCHAR(36) NOT NULL,
+    closed_at  TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS business_day_balances
+(
+    date            DATE        NOT NULL,
+    account_id      VARCHAR(36) NOT NULL,
+    opening_balance DECIMAL     NOT NULL,
+    total_credits   DECIMAL     NOT NULL,
+    total_debits    DECIMAL     NOT NULL,
+    closing_balance DECIMAL     NOT NULL,
+
+    PRIMARY KEY (date, account_id),
+    FOREIGN KEY (date) REFERENCES business_days (date),
+    FOREIGN KEY (account_id) REFERENCES accounts (id)
+);
+
+ALTER TABLE transactions
+    ADD COLUMN adjustment BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Business days are UTC dates, a closed day and all the days before it are frozen: transactions dated into them are
+-- rejected, except the adjusting entries dated into a day reopened for adjustments.
+CREATE OR REPLACE FUNCTION transactions_business_day_check() RETURNS TRIGGER AS
+$$
+DECLARE
+    business_date DATE := (NEW.created_at AT TIME ZONE 'UTC')::DATE;
+    last_closed   DATE;
+    day_status    VARCHAR(36);
+BEGIN
+    SELECT MAX(date) INTO last_closed FROM business_days;
+    SELECT status INTO day_status FROM business_days WHERE date = business_date;
+
+    IF NEW.adjustment THEN
+        IF day_status = 'ADJUSTING' THEN
+            RETURN NEW;
+        END IF;
+        RAISE EXCEPTION 'business day % is not open for adjustments', business_date;
+    END IF;
+
+    IF last_closed IS NOT NULL AND business_date <= last_closed THEN
+        RAISE EXCEPTION 'business day % is closed', business_date;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_business_day_check
+    BEFORE INSERT
+    ON transactions
+    FOR EACH ROW
+EXECUTE FUNCTION transactions_business_day_check();
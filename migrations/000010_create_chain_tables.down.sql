@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS chain_checkpoints;
+DROP TABLE IF EXISTS chain_links;
+DROP FUNCTION IF EXISTS chain_immutable;
@@ -0,0 +1,48 @@
+-- Hash chains over the transactions, the global one (chain 'global') and one per account (chain is the account id).
+-- Each link stores the hash of the canonical content of the transaction and the hash of the previous link.
+CREATE TABLE IF NOT EXISTS chain_links
+(
+    chain          VARCHAR(36) NOT NULL,
+    sequence       BIGINT      NOT NULL,
+    transaction_id VARCHAR(36) NOT NULL,
+    content_hash   VARCHAR(64) NOT NULL,
+    previous_hash  VARCHAR(64) NOT NULL,
+    hash           VARCHAR(64) NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (chain, sequence)
+);
+
+CREATE UNIQUE INDEX chain_links_transaction_id_chain ON chain_links (transaction_id, chain);
+CREATE INDEX chain_links_chain_created_at ON chain_links (chain, created_at);
+
+-- Signed hash of the head of the global chain at the end of each day, to be published externally.
+CREATE TABLE IF NOT EXISTS chain_checkpoints
+(
+    date       DATE        PRIMARY KEY,
+    sequence   BIGINT      NOT NULL,
+    hash       VARCHAR(64) NOT NULL,
+    signature  VARCHAR(88) NOT NULL,
+    public_key VARCHAR(44) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Links and checkpoints are evidence, once created they can not be changed or removed.
+CREATE OR REPLACE FUNCTION chain_immutable() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION '% is immutable', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER chain_links_immutable
+    BEFORE UPDATE OR DELETE
+    ON chain_links
+    FOR EACH ROW
+EXECUTE FUNCTION chain_immutable();
+
+CREATE TRIGGER chain_checkpoints_immutable
+    BEFORE UPDATE OR DELETE
+    ON chain_checkpoints
+    FOR EACH ROW
+EXECUTE FUNCTION chain_immutable();
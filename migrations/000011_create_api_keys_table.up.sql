@@ -0,0 +1,15 @@
+-- API keys of the clients of the API, only the SHA-256 of the keys is stored, the keys are shown once when created or
+-- rotated. The prefix identifies the key without revealing it.
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id         VARCHAR(36) PRIMARY KEY,
+    name       VARCHAR(100) NOT NULL,
+    prefix     VARCHAR(12)  NOT NULL,
+    key_hash   VARCHAR(64)  NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NULL,
+    rotated_at TIMESTAMPTZ  NULL,
+    revoked_at TIMESTAMPTZ  NULL
+);
+
+CREATE UNIQUE INDEX api_keys_key_hash ON api_keys (key_hash);
@@ -0,0 +1,3 @@
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS holder_id,
+    DROP COLUMN IF EXISTS scopes;
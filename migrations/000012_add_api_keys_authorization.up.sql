@@ -0,0 +1,10 @@
+-- Scopes granted to the API keys and the holder the key is restricted to, if any. The keys created before the scopes
+-- keep all of them, as they could do everything.
+ALTER TABLE api_keys
+    ADD COLUMN scopes    TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN holder_id VARCHAR(36) NULL REFERENCES holders (id);
+
+UPDATE api_keys
+SET scopes = ARRAY ['holders:read', 'holders:write', 'accounts:read', 'accounts:write', 'accounts:manage',
+    'balances:read', 'statements:read', 'transactions:read', 'transactions:credit', 'transactions:debit',
+    'transactions:p2p', 'reconciliations', 'business-days', 'ledger-chain', 'api-keys'];
@@ -0,0 +1,20 @@
+DROP TRIGGER IF EXISTS reconciliations_tenant ON reconciliations;
+DROP TRIGGER IF EXISTS monthly_statements_tenant ON monthly_statements;
+DROP FUNCTION IF EXISTS account_tenant;
+DROP TRIGGER IF EXISTS transactions_tenant ON transactions;
+DROP FUNCTION IF EXISTS transactions_tenant;
+DROP TRIGGER IF EXISTS accounts_tenant ON accounts;
+DROP FUNCTION IF EXISTS accounts_tenant;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE reconciliations DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE monthly_statements DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS accounts_tenant_id_created_at_id;
+ALTER TABLE accounts DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS holders_tenant_id_created_at_id;
+DROP INDEX IF EXISTS holders_tenant_id_document_number;
+ALTER TABLE holders DROP COLUMN IF EXISTS tenant_id;
+CREATE UNIQUE INDEX IF NOT EXISTS holders_document_number ON holders (document_number);
@@ -0,0 +1,98 @@
+-- Tenants are the brands served by the deployment, configured in the TENANTS_FILE of the API. The rows created before
+-- the tenants are of the 'default' tenant.
+ALTER TABLE holders
+    ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE holders
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- The document number is unique per tenant, the same person can be a holder of several brands.
+DROP INDEX IF EXISTS holders_document_number;
+CREATE UNIQUE INDEX holders_tenant_id_document_number ON holders (tenant_id, document_number);
+CREATE INDEX holders_tenant_id_created_at_id ON holders (tenant_id, created_at, id);
+
+ALTER TABLE accounts
+    ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE accounts
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+CREATE INDEX accounts_tenant_id_created_at_id ON accounts (tenant_id, created_at, id);
+
+ALTER TABLE transactions
+    ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE transactions
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE monthly_statements
+    ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE monthly_statements
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE reconciliations
+    ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE reconciliations
+    ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- The API keys without tenant pick the tenant of each request.
+ALTER TABLE api_keys
+    ADD COLUMN tenant_id VARCHAR(36) NULL;
+
+-- The accounts are of the tenant of their holder.
+CREATE OR REPLACE FUNCTION accounts_tenant() RETURNS TRIGGER AS
+$$
+BEGIN
+    SELECT tenant_id INTO NEW.tenant_id FROM holders WHERE id = NEW.holder_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_tenant
+    BEFORE INSERT OR UPDATE OF holder_id, tenant_id
+    ON accounts
+    FOR EACH ROW
+EXECUTE FUNCTION accounts_tenant();
+
+-- The transactions are of the tenant of their accounts, the money can not move between tenants.
+CREATE OR REPLACE FUNCTION transactions_tenant() RETURNS TRIGGER AS
+$$
+DECLARE
+    from_tenant VARCHAR(36);
+    to_tenant   VARCHAR(36);
+BEGIN
+    SELECT tenant_id INTO from_tenant FROM accounts WHERE id = NEW.from_account_id;
+    SELECT tenant_id INTO to_tenant FROM accounts WHERE id = NEW.to_account_id;
+
+    IF from_tenant IS NOT NULL AND to_tenant IS NOT NULL AND from_tenant <> to_tenant THEN
+        RAISE EXCEPTION 'transactions between the tenants % and % are not allowed', from_tenant, to_tenant;
+    END IF;
+
+    NEW.tenant_id := COALESCE(from_tenant, to_tenant);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_tenant
+    BEFORE INSERT
+    ON transactions
+    FOR EACH ROW
+EXECUTE FUNCTION transactions_tenant();
+
+-- The monthly statements and the reconciliations are of the tenant of their account.
+CREATE OR REPLACE FUNCTION account_tenant() RETURNS TRIGGER AS
+$$
+BEGIN
+    SELECT tenant_id INTO NEW.tenant_id FROM accounts WHERE id = NEW.account_id;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER monthly_statements_tenant
+    BEFORE INSERT
+    ON monthly_statements
+    FOR EACH ROW
+EXECUTE FUNCTION account_tenant();
+
+CREATE TRIGGER reconciliations_tenant
+    BEFORE INSERT
+    ON reconciliations
+    FOR EACH ROW
+EXECUTE FUNCTION account_tenant();
@@ -0,0 +1,26 @@
+DROP INDEX IF EXISTS transactions_created_at_index;
+
+CREATE OR REPLACE FUNCTION transactions_business_day_check() RETURNS TRIGGER AS
+$$
+DECLARE
+    business_date DATE := (NEW.created_at AT TIME ZONE 'UTC')::DATE;
+    last_closed   DATE;
+    day_status    VARCHAR(36);
+BEGIN
+    SELECT MAX(date) INTO last_closed FROM business_days;
+    SELECT status INTO day_status FROM business_days WHERE date = business_date;
+
+    IF NEW.adjustment THEN
+        IF day_status = 'ADJUSTING' THEN
+            RETURN NEW;
+        END IF;
+        RAISE EXCEPTION 'business day % is not open for adjustments', business_date;
+    END IF;
+
+    IF last_closed IS NOT NULL AND business_date <= last_closed THEN
+        RAISE EXCEPTION 'business day % is closed', business_date;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,32 @@
+-- The inserts of the transactions hold the shared lock of their business day until they commit, the close of the day
+-- takes it exclusively: it waits for the transactions dated into the day in progress, and the ones after it are
+-- checked against the closed day. The transactions of the other days are not blocked by the close.
+CREATE OR REPLACE FUNCTION transactions_business_day_check() RETURNS TRIGGER AS
+$$
+DECLARE
+    business_date DATE := (NEW.created_at AT TIME ZONE 'UTC')::DATE;
+    last_closed   DATE;
+    day_status    VARCHAR(36);
+BEGIN
+    PERFORM pg_advisory_xact_lock_shared(hashtext('business_days:' || business_date::TEXT));
+
+    SELECT MAX(date) INTO last_closed FROM business_days;
+    SELECT status INTO day_status FROM business_days WHERE date = business_date;
+
+    IF NEW.adjustment THEN
+        IF day_status = 'ADJUSTING' THEN
+            RETURN NEW;
+        END IF;
+        RAISE EXCEPTION 'business day % is not open for adjustments', business_date;
+    END IF;
+
+    IF last_closed IS NOT NULL AND business_date <= last_closed THEN
+        RAISE EXCEPTION 'business day % is closed', business_date;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- The balances of a business day are recorded from the transactions dated inside it.
+CREATE INDEX IF NOT EXISTS transactions_created_at_index ON transactions (created_at);
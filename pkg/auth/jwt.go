@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+)
+
+// leeway tolerates the clock skew between the issuer of the tokens and the API.
+const leeway = time.Minute
+
+var ErrNoJWKS = errors.New("no json web key set configured")
+
+// tokenClaims are the private claims of the tokens about the authorization of the principal.
+type tokenClaims struct {
+	// Scope holds the scopes separated by spaces, as the scope claim of OAuth 2.0.
+	Scope    string `json:"scope"`
+	HolderID string `json:"holder_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+type jwtAuthenticator struct {
+	keys     jose.JSONWebKeySet
+	issuer   string
+	audience string
+}
+
+// NewJWTAuthenticator authenticates the bearer tokens signed by the public keys of the JWKS files. The issuer and
+// audience of the tokens are only validated when they are not empty.
+func NewJWTAuthenticator(issuer, audience string, jwksFiles ...string) (Authenticator, error) {
+	if len(jwksFiles) == 0 {
+		return nil, ErrNoJWKS
+	}
+
+	var keys jose.JSONWebKeySet
+	for _, file := range jwksFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks %s: %w", file, err)
+		}
+
+		var set jose.JSONWebKeySet
+		if err := json.Unmarshal(content, &set); err != nil {
+			return nil, fmt.Errorf("parse jwks %s: %w", file, err)
+		}
+
+		for _, key := range set.Keys {
+			if !key.IsPublic() {
+				return nil, fmt.Errorf("jwks %s: key %s is not a public key", file, key.KeyID)
+			}
+			keys.Keys = append(keys.Keys, key)
+		}
+	}
+
+	return jwtAuthenticator{keys: keys, issuer: issuer, audience: audience}, nil
+}
+
+// Authenticate verifies the signature and the claims of the token, the subject of the token is the principal with the
+// scopes of the scope claim, bound to the holder of the holder_id claim and to the tenant of the tenant_id claim when
+// present.
+func (a jwtAuthenticator) Authenticate(_ context.Context, token string) (Principal, error) {
+	parsed, err := jwt.ParseSigned(token)
+	if err != nil || len(parsed.Headers) == 0 {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	var (
+		claims      jwt.Claims
+		tokenClaims tokenClaims
+	)
+	if !a.verify(parsed, &claims, &tokenClaims) {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	expected := jwt.Expected{Issuer: a.issuer, Time: time.Now()}
+	if a.audience != "" {
+		expected.Audience = jwt.Audience{a.audience}
+	}
+
+	if claims.Expiry == nil || claims.Subject == "" || claims.ValidateWithLeeway(expected, leeway) != nil {
+		return Principal{}, ErrInvalidCredentials
+	}
+
+	principal := Principal{
+		ID:       claims.Subject,
+		Type:     JWTPrincipal,
+		Scopes:   strings.Fields(tokenClaims.Scope),
+		TenantID: tokenClaims.TenantID,
+	}
+
+	if tokenClaims.HolderID != "" {
+		holderID, err := uuid.Parse(tokenClaims.HolderID)
+		if err != nil {
+			return Principal{}, ErrInvalidCredentials
+		}
+		principal.HolderID = uuid.NullUUID{UUID: holderID, Valid: true}
+	}
+
+	return principal, nil
+}
+
+// verify checks the signature with the key of the token, or with all the signing keys when it names none.
+func (a jwtAuthenticator) verify(token *jwt.JSONWebToken, claims ...interface{}) bool {
+	keys := a.keys.Keys
+	if kid := token.Headers[0].KeyID; kid != "" {
+		keys = a.keys.Key(kid)
+	}
+
+	for _, key := range keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		if key.Algorithm != "" && key.Algorithm != token.Headers[0].Algorithm {
+			continue
+		}
+		if token.Claims(key.Key, claims...) == nil {
+			return true
+		}
+	}
+
+	return false
+}
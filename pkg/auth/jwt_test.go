@@ -0,0 +1,125 @@
+//go:build unit
+
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeJWKS(t *testing.T, keys ...jose.JSONWebKey) string {
+	t.Helper()
+
+	content, err := json.Marshal(jose.JSONWebKeySet{Keys: keys})
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+	return file
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims, private ...interface{}) string {
+	t.Helper()
+
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: key},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader("kid", kid),
+	)
+	require.NoError(t, err)
+
+	builder := jwt.Signed(signer).Claims(claims)
+	for _, p := range private {
+		builder = builder.Claims(p)
+	}
+
+	token, err := builder.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	ctx := context.Background()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	file := writeJWKS(t, jose.JSONWebKey{Key: &key.PublicKey, KeyID: "key-1", Algorithm: "RS256", Use: "sig"})
+
+	authenticator, err := NewJWTAuthenticator("https://issuer", "ledger", file)
+	require.NoError(t, err)
+
+	valid := jwt.Claims{
+		Subject:  "backoffice",
+		Issuer:   "https://issuer",
+		Audience: jwt.Audience{"ledger"},
+		Expiry:   jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}
+
+	t.Run("Valid token", func(t *testing.T) {
+		principal, err := authenticator.Authenticate(ctx, signToken(t, key, "key-1", valid))
+		require.NoError(t, err)
+		assert.Equal(t, Principal{ID: "backoffice", Type: JWTPrincipal, Scopes: []string{}}, principal)
+	})
+
+	t.Run("Scopes, holder and tenant", func(t *testing.T) {
+		holderID := uuid.New()
+		token := signToken(t, key, "key-1", valid, tokenClaims{Scope: "accounts:read statements:read",
+			HolderID: holderID.String(), TenantID: "brand"})
+
+		principal, err := authenticator.Authenticate(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"accounts:read", "statements:read"}, principal.Scopes)
+		assert.Equal(t, uuid.NullUUID{UUID: holderID, Valid: true}, principal.HolderID)
+		assert.Equal(t, "brand", principal.TenantID)
+	})
+
+	expired := with(valid, func(c *jwt.Claims) { c.Expiry = jwt.NewNumericDate(time.Now().Add(-time.Hour)) })
+
+	invalid := map[string]string{
+		"malformed":      "not.a.token",
+		"unknown key":    signToken(t, key, "key-2", valid),
+		"wrong key":      signToken(t, otherKey, "key-1", valid),
+		"expired":        signToken(t, key, "key-1", expired),
+		"no expiry":      signToken(t, key, "key-1", with(valid, func(c *jwt.Claims) { c.Expiry = nil })),
+		"wrong issuer":   signToken(t, key, "key-1", with(valid, func(c *jwt.Claims) { c.Issuer = "https://other" })),
+		"wrong audience": signToken(t, key, "key-1", with(valid, func(c *jwt.Claims) { c.Audience = jwt.Audience{"other"} })),
+		"no subject":     signToken(t, key, "key-1", with(valid, func(c *jwt.Claims) { c.Subject = "" })),
+		"invalid holder": signToken(t, key, "key-1", valid, tokenClaims{HolderID: "holder"}),
+	}
+
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := authenticator.Authenticate(ctx, token)
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+
+	t.Run("Private keys refused", func(t *testing.T) {
+		_, err := NewJWTAuthenticator("", "", writeJWKS(t, jose.JSONWebKey{Key: key, KeyID: "key-1"}))
+		assert.Error(t, err)
+	})
+
+	t.Run("No JWKS", func(t *testing.T) {
+		_, err := NewJWTAuthenticator("", "")
+		assert.ErrorIs(t, err, ErrNoJWKS)
+	})
+}
+
+func with(claims jwt.Claims, change func(*jwt.Claims)) jwt.Claims {
+	change(&claims)
+	return claims
+}
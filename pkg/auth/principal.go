@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCredentials is returned by the authenticators when the credential is unknown, expired or revoked, the
+// other errors are failures to authenticate it.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// PrincipalType is the kind of credential that authenticated the principal.
+type PrincipalType string
+
+var (
+	APIKeyPrincipal PrincipalType = "api_key"
+	JWTPrincipal    PrincipalType = "jwt"
+)
+
+// Principal is the authenticated caller of the API.
+type Principal struct {
+	// ID is the id of the API key or the subject of the token.
+	ID   string
+	Type PrincipalType
+	// Name is the name of the API key, empty for the tokens.
+	Name string
+	// Scopes are the operations the principal is allowed to do, see the authz package.
+	Scopes []string
+	// HolderID restricts the principal to the holder and its accounts when valid, e.g. the tokens of the end users.
+	HolderID uuid.NullUUID
+	// TenantID restricts the principal to the tenant when not empty, otherwise it picks the tenant of each request.
+	TenantID string
+}
+
+// Authenticator authenticates the principal of a credential, an API key or a token.
+type Authenticator interface {
+	Authenticate(ctx context.Context, credential string) (Principal, error)
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of ctx, false when there is none.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
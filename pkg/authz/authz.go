@@ -0,0 +1,157 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ErrForbidden is returned when the principal is not allowed to do the operation.
+var ErrForbidden = errors.New("the principal is not allowed to do this operation")
+
+// Scope is an operation granted to the principals.
+type Scope string
+
+const (
+	ScopeHoldersRead        Scope = "holders:read"
+	ScopeHoldersWrite       Scope = "holders:write"
+	ScopeAccountsRead       Scope = "accounts:read"
+	ScopeAccountsWrite      Scope = "accounts:write"
+	ScopeAccountsManage     Scope = "accounts:manage"
+	ScopeBalancesRead       Scope = "balances:read"
+	ScopeStatementsRead     Scope = "statements:read"
+	ScopeTransactionsRead   Scope = "transactions:read"
+	ScopeTransactionsCredit Scope = "transactions:credit"
+	ScopeTransactionsDebit  Scope = "transactions:debit"
+	ScopeTransactionsP2P    Scope = "transactions:p2p"
+	ScopeReconciliations    Scope = "reconciliations"
+	ScopeBusinessDays       Scope = "business-days"
+	ScopeLedgerChain        Scope = "ledger-chain"
+	ScopeAPIKeys            Scope = "api-keys"
+	ScopeAdmin              Scope = "admin"
+)
+
+// Scopes are all the scopes, the ones of the back-office operators.
+var Scopes = []Scope{
+	ScopeHoldersRead,
+	ScopeHoldersWrite,
+	ScopeAccountsRead,
+	ScopeAccountsWrite,
+	ScopeAccountsManage,
+	ScopeBalancesRead,
+	ScopeStatementsRead,
+	ScopeTransactionsRead,
+	ScopeTransactionsCredit,
+	ScopeTransactionsDebit,
+	ScopeTransactionsP2P,
+	ScopeReconciliations,
+	ScopeBusinessDays,
+	ScopeLedgerChain,
+	ScopeAPIKeys,
+	ScopeAdmin,
+}
+
+// Authorize returns ErrForbidden when the principal of ctx lacks one of the scopes. Calls without a principal are
+// made by the application itself (jobs and command lines), they are authorized.
+func Authorize(ctx context.Context, scopes ...Scope) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !hasScope(principal, scope) {
+			deny(ctx, "missing_scope", attribute.String("authz.scope", string(scope)))
+			return ErrForbidden
+		}
+	}
+
+	return nil
+}
+
+// AuthorizeHolder returns ErrForbidden when the principal of ctx is bound to a holder other than the holderIDs, the
+// holders owning the resource. The principals not bound to a holder are authorized.
+func AuthorizeHolder(ctx context.Context, holderIDs ...uuid.UUID) error {
+	holderID, bound := Holder(ctx)
+	if !bound {
+		return nil
+	}
+
+	for _, id := range holderIDs {
+		if id == holderID {
+			return nil
+		}
+	}
+
+	deny(ctx, "other_holder")
+	return ErrForbidden
+}
+
+// AuthorizeDeployment returns ErrForbidden when the principal of ctx is bound to a tenant, for the operations over
+// all the tenants of the deployment, e.g. closing the business days. The principals not bound to a tenant are
+// authorized.
+func AuthorizeDeployment(ctx context.Context) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || principal.TenantID == "" {
+		return nil
+	}
+
+	deny(ctx, "tenant_bound", attribute.String("authz.tenant", principal.TenantID))
+	return ErrForbidden
+}
+
+// Holder returns the holder the principal of ctx is bound to, false when it is not bound to a holder or the context
+// was made Unrestricted.
+func Holder(ctx context.Context) (uuid.UUID, bool) {
+	if unrestricted, _ := ctx.Value(unrestrictedKey{}).(bool); unrestricted {
+		return uuid.UUID{}, false
+	}
+
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok || !principal.HolderID.Valid {
+		return uuid.UUID{}, false
+	}
+
+	return principal.HolderID.UUID, true
+}
+
+type unrestrictedKey struct{}
+
+// Unrestricted returns a copy of ctx not restricted to the holder of the principal, for the lookups the services do
+// on behalf of the principal outside of its holder, e.g. the receiving account of a transfer. What is looked up with
+// it must not be returned to the principal.
+func Unrestricted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unrestrictedKey{}, true)
+}
+
+func hasScope(principal auth.Principal, scope Scope) bool {
+	for _, s := range principal.Scopes {
+		if Scope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// deny audits the denial in the logs and in the span of the operation.
+func deny(ctx context.Context, reason string, attrs ...attribute.KeyValue) {
+	attrs = append(attrs, attribute.String("authz.reason", reason))
+
+	fields := make([]zap.Field, 0, len(attrs)+1)
+	fields = append(fields, zap.Bool("audit", true))
+	for _, attr := range attrs {
+		fields = append(fields, zap.String(string(attr.Key), attr.Value.AsString()))
+	}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.HolderID.Valid {
+		fields = append(fields, zap.String("principal_holder_id", principal.HolderID.UUID.String()))
+	}
+
+	zapctx.L(ctx).Warn("authorization_denied", fields...)
+	trace.SpanFromContext(ctx).AddEvent("authorization_denied", trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,77 @@
+//go:build unit
+
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestAuthorize(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	partner := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:     "partner",
+		Type:   auth.APIKeyPrincipal,
+		Scopes: []string{string(ScopeTransactionsCredit)},
+	})
+
+	assert.NoError(t, Authorize(partner, ScopeTransactionsCredit))
+	assert.ErrorIs(t, Authorize(partner, ScopeTransactionsDebit), ErrForbidden)
+	assert.ErrorIs(t, Authorize(partner, ScopeTransactionsCredit, ScopeTransactionsDebit), ErrForbidden)
+	assert.NoError(t, Authorize(context.Background(), ScopeTransactionsDebit))
+
+	denials := logs.FilterMessage("authorization_denied").All()
+	if assert.Len(t, denials, 2) {
+		assert.Equal(t, "transactions:debit", denials[0].ContextMap()["authz.scope"])
+		assert.Equal(t, "partner", denials[0].ContextMap()["principal_id"])
+		assert.Equal(t, true, denials[0].ContextMap()["audit"])
+	}
+}
+
+func TestAuthorizeHolder(t *testing.T) {
+	holderID := uuid.New()
+	otherHolderID := uuid.New()
+
+	endUser := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:       "user",
+		Type:     auth.JWTPrincipal,
+		HolderID: uuid.NullUUID{UUID: holderID, Valid: true},
+	})
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{ID: "operator", Type: auth.JWTPrincipal})
+
+	assert.NoError(t, AuthorizeHolder(endUser, holderID))
+	assert.NoError(t, AuthorizeHolder(endUser, otherHolderID, holderID))
+	assert.ErrorIs(t, AuthorizeHolder(endUser, otherHolderID), ErrForbidden)
+	assert.ErrorIs(t, AuthorizeHolder(endUser), ErrForbidden)
+	assert.NoError(t, AuthorizeHolder(Unrestricted(endUser), otherHolderID))
+	assert.NoError(t, AuthorizeHolder(operator, otherHolderID))
+	assert.NoError(t, AuthorizeHolder(context.Background(), otherHolderID))
+
+	bound, ok := Holder(endUser)
+	assert.True(t, ok)
+	assert.Equal(t, holderID, bound)
+
+	_, ok = Holder(operator)
+	assert.False(t, ok)
+}
+
+func TestAuthorizeDeployment(t *testing.T) {
+	tenantOperator := auth.WithPrincipal(context.Background(), auth.Principal{
+		ID:       "brand-a-operator",
+		Type:     auth.APIKeyPrincipal,
+		TenantID: "brand-a",
+	})
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{ID: "operator", Type: auth.APIKeyPrincipal})
+
+	assert.ErrorIs(t, AuthorizeDeployment(tenantOperator), ErrForbidden)
+	assert.NoError(t, AuthorizeDeployment(operator))
+	assert.NoError(t, AuthorizeDeployment(context.Background()))
+}
@@ -0,0 +1,128 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Direction string
+
+const (
+	// Next selects the items after the key of the cursor.
+	Next Direction = "next"
+	// Prev selects the items before the key of the cursor.
+	Prev Direction = "prev"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Key is the position of an item in a listing sorted by creation time, the id breaks the tie between items
+// created at the same time.
+type Key struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Cursor points to one side of an item of a listing. Unlike offsets, it keeps pointing to the same items when new
+// ones are created while the client is paging.
+type Cursor struct {
+	Key
+	Direction Direction
+}
+
+type token struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+	Direction Direction `json:"d"`
+}
+
+// String returns the cursor as an opaque token to be sent to clients.
+func (c Cursor) String() string {
+	b, _ := json.Marshal(token{CreatedAt: c.CreatedAt, ID: c.ID, Direction: c.Direction})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Parse reads a token created by Cursor.String.
+func Parse(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var t token
+	if err := json.Unmarshal(b, &t); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	if t.CreatedAt.IsZero() || t.ID == "" || (t.Direction != Next && t.Direction != Prev) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{Key: Key{CreatedAt: t.CreatedAt, ID: t.ID}, Direction: t.Direction}, nil
+}
+
+// Keyset restricts the query to the items on the side of the cursor and sorts them by creation time, nearest of
+// the cursor first. When the direction of the cursor is Prev the items are selected in the inverse order of the
+// listing, so the caller must reverse them (see Reverse) when reversed is true.
+func Keyset(q *bun.SelectQuery, c Cursor, desc bool, createdAtColumn, idColumn string) (reversed bool) {
+	reversed = c.Direction == Prev
+	after := desc == reversed
+
+	if after {
+		q.Where("(?, ?) > (?, ?)", bun.Ident(createdAtColumn), bun.Ident(idColumn), c.CreatedAt, c.ID)
+		q.OrderExpr("? ASC, ? ASC", bun.Ident(createdAtColumn), bun.Ident(idColumn))
+	} else {
+		q.Where("(?, ?) < (?, ?)", bun.Ident(createdAtColumn), bun.Ident(idColumn), c.CreatedAt, c.ID)
+		q.OrderExpr("? DESC, ? DESC", bun.Ident(createdAtColumn), bun.Ident(idColumn))
+	}
+
+	return reversed
+}
+
+// Reverse reverses the items in place.
+func Reverse[T any](items []T) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// Links are the cursors to the pages around the current one, empty when there is no page on that side.
+type Links struct {
+	Next string
+	Prev string
+}
+
+// NewLinks returns the cursors around a page of items, given the keys of its items in the order of the listing.
+// The current cursor is nil when the page was selected by offset, in this case hasPrev tells if it is not the first page.
+// A page with less items than its size is the last one on the side it was paged to.
+func NewLinks(current *Cursor, hasPrev bool, size int, keys []Key) Links {
+	if len(keys) == 0 {
+		return Links{}
+	}
+
+	full := len(keys) >= size
+	hasNext := full
+	if current != nil {
+		switch current.Direction {
+		case Next:
+			hasPrev = true
+		case Prev:
+			hasPrev = full
+			hasNext = true
+		}
+	}
+
+	var links Links
+	if hasNext {
+		links.Next = Cursor{Key: keys[len(keys)-1], Direction: Next}.String()
+	}
+	if hasPrev {
+		links.Prev = Cursor{Key: keys[0], Direction: Prev}.String()
+	}
+
+	return links
+}
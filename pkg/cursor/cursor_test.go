@@ -0,0 +1,137 @@
+//go:build unit
+
+package cursor
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Parse a cursor token", func(t *testing.T) {
+		c := Cursor{
+			Key:       Key{CreatedAt: time.Now().UTC(), ID: uuid.NewString()},
+			Direction: Prev,
+		}
+
+		parsed, err := Parse(c.String())
+		assert.NoError(t, err)
+		assert.True(t, c.CreatedAt.Equal(parsed.CreatedAt))
+		assert.Equal(t, c.ID, parsed.ID)
+		assert.Equal(t, c.Direction, parsed.Direction)
+	})
+
+	t.Run("Do not parse invalid tokens", func(t *testing.T) {
+		for _, token := range []string{
+			"not base64!",
+			"bm90IGpzb24",
+			Cursor{Key: Key{ID: uuid.NewString()}, Direction: Next}.String(),
+			Cursor{Key: Key{CreatedAt: time.Now(), ID: uuid.NewString()}, Direction: "up"}.String(),
+		} {
+			_, err := Parse(token)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		}
+	})
+}
+
+func TestKeyset(t *testing.T) {
+	db := bun.NewDB(sql.OpenDB(pgdriver.NewConnector()), pgdialect.New())
+	createdAt := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		direction Direction
+		desc      bool
+		reversed  bool
+		sql       string
+	}{
+		{
+			name:      "Next items in ascending listing",
+			direction: Next,
+			sql:       `WHERE (("a"."created_at", "a"."id") > ('2022-01-01 00:00:00+00:00', 'x')) ORDER BY "a"."created_at" ASC, "a"."id" ASC`,
+		},
+		{
+			name:      "Previous items in ascending listing",
+			direction: Prev,
+			reversed:  true,
+			sql:       `WHERE (("a"."created_at", "a"."id") < ('2022-01-01 00:00:00+00:00', 'x')) ORDER BY "a"."created_at" DESC, "a"."id" DESC`,
+		},
+		{
+			name:      "Next items in descending listing",
+			direction: Next,
+			desc:      true,
+			sql:       `WHERE (("a"."created_at", "a"."id") < ('2022-01-01 00:00:00+00:00', 'x')) ORDER BY "a"."created_at" DESC, "a"."id" DESC`,
+		},
+		{
+			name:      "Previous items in descending listing",
+			direction: Prev,
+			desc:      true,
+			reversed:  true,
+			sql:       `WHERE (("a"."created_at", "a"."id") > ('2022-01-01 00:00:00+00:00', 'x')) ORDER BY "a"."created_at" ASC, "a"."id" ASC`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := db.NewSelect().TableExpr("accounts AS a")
+			reversed := Keyset(
+				q,
+				Cursor{Key: Key{CreatedAt: createdAt, ID: "x"}, Direction: tt.direction},
+				tt.desc,
+				"a.created_at",
+				"a.id",
+			)
+			assert.Equal(t, tt.reversed, reversed)
+			assert.Contains(t, q.String(), tt.sql)
+		})
+	}
+}
+
+func TestNewLinks(t *testing.T) {
+	keys := []Key{
+		{CreatedAt: time.Now(), ID: "1"},
+		{CreatedAt: time.Now(), ID: "2"},
+	}
+
+	t.Run("First page by offset", func(t *testing.T) {
+		links := NewLinks(nil, false, 2, keys)
+		assert.Empty(t, links.Prev)
+
+		next, err := Parse(links.Next)
+		assert.NoError(t, err)
+		assert.Equal(t, "2", next.ID)
+		assert.Equal(t, Next, next.Direction)
+	})
+
+	t.Run("Last page by offset", func(t *testing.T) {
+		links := NewLinks(nil, true, 3, keys)
+		assert.Empty(t, links.Next)
+
+		prev, err := Parse(links.Prev)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", prev.ID)
+		assert.Equal(t, Prev, prev.Direction)
+	})
+
+	t.Run("Last page by next cursor", func(t *testing.T) {
+		links := NewLinks(&Cursor{Direction: Next}, false, 3, keys)
+		assert.Empty(t, links.Next)
+		assert.NotEmpty(t, links.Prev)
+	})
+
+	t.Run("First page by previous cursor", func(t *testing.T) {
+		links := NewLinks(&Cursor{Direction: Prev}, false, 3, keys)
+		assert.NotEmpty(t, links.Next)
+		assert.Empty(t, links.Prev)
+	})
+
+	t.Run("Empty page", func(t *testing.T) {
+		assert.Empty(t, NewLinks(&Cursor{Direction: Next}, true, 3, nil))
+	})
+}
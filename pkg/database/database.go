@@ -3,14 +3,21 @@ package database
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 )
 
-type NullTime = sql.NullTime
+type (
+	NullTime    = sql.NullTime
+	NullFloat64 = sql.NullFloat64
+)
 
 type DB interface {
 	bun.IConn
@@ -20,66 +27,216 @@ type DB interface {
 	// RunInTx the mapping of this method here is necessary because in bun.IDB there is no RunInTx method and
 	// in no other interface, so we need to add a declaration to make it available.
 	RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error
+	// ScanRow is exposed to let repositories stream large results with SelectQuery.Rows, scanning one row at a time
+	// into a model instead of loading the whole result in memory.
+	ScanRow(ctx context.Context, rows *sql.Rows, dest ...interface{}) error
 	Close() error
 }
 
 type Database interface {
-	Master() DB
-	Replica() DB
+	// Master returns the transaction of the unit of work of ctx (UnitOfWork), otherwise the master.
+	Master(ctx context.Context) DB
+	// Replica returns the database of the reads of ctx: the transaction of the unit of work of ctx, the master when
+	// ctx is pinned to it (PinMaster) or when all the replicas lag behind, otherwise the replicas in turn.
+	Replica(ctx context.Context) DB
+	// Replicas returns all the replicas, for the health checks.
+	Replicas() []DB
+	// Stats returns the statistics of the pools of connections by their names, master and replica-<n>.
+	Stats() map[string]sql.DBStats
+	// QueryStats returns the statistics of the queries of all the pools by fingerprint (Fingerprint), the ones with the
+	// largest total duration first.
+	QueryStats() []QueryStat
 	Stop(ctx context.Context) error
 }
 
+// Config is the configuration of the databases. The master is also the only replica when there is no replica, with
+// its own pool of connections. The replicas lagging more than MaxReplicaLag behind the master are not read until they
+// catch up, zero disables the lag checks. The queries taking SlowQueryThreshold or more are logged redacted, zero
+// disables the log.
+type Config struct {
+	MasterURL          string
+	ReplicaURLs        []string
+	MaxReplicaLag      time.Duration
+	Pool               PoolConfig
+	SlowQueryThreshold time.Duration
+}
+
+// PoolConfig is the configuration of each pool of connections, the master and every replica has its own. The zero
+// values keep the defaults of database/sql: unlimited open connections, 2 idle ones kept forever, and no statement
+// timeout.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout is the statement_timeout of the connections, the statements running longer are canceled by
+	// Postgres even when the client is gone.
+	StatementTimeout time.Duration
+}
+
 type database struct {
-	dbMaster  DB
-	dbReplica DB
+	dbMaster   *bun.DB
+	dbReplicas []*replica
+	queryStats *queryStats
+	next       *atomic.Uint64
+	stop       context.CancelFunc
+	stopped    chan struct{}
 }
 
-func New(
-	t tracer.Tracer,
-	postgresMasterURL string,
-	postgresReplicaURL string,
-) (Database, error) {
-	d := &database{}
+func New(t tracer.Tracer, m metrics.Metrics, config Config) (Database, error) {
+	d := &database{next: &atomic.Uint64{}, queryStats: newQueryStats()}
+	meter := m.Meter("github.com/dalmarcogd/ledger-exp/pkg/database")
 
-	connector, err := pgdriver.NewDriver().OpenConnector(postgresMasterURL)
+	db, err := d.open(t, meter, "master", config.MasterURL, config, bun.WithDiscardUnknownColumns())
 	if err != nil {
 		return nil, err
 	}
-
-	masterDBSQL := sql.OpenDB(connector)
-	masterDBSQL.SetMaxOpenConns(1)
-	db := bun.NewDB(masterDBSQL, pgdialect.New(), bun.WithDiscardUnknownColumns())
-	db.AddQueryHook(newDatabaseLogger())
-	db.AddQueryHook(newTracingHook(t))
 	d.dbMaster = db
 
-	connector, err = pgdriver.NewDriver().OpenConnector(postgresReplicaURL)
+	replicaURLs := config.ReplicaURLs
+	if len(replicaURLs) == 0 {
+		replicaURLs = []string{config.MasterURL}
+	}
+
+	for i, replicaURL := range replicaURLs {
+		name := fmt.Sprintf("replica-%d", i)
+		db, err := d.open(t, meter, name, replicaURL, config)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &replica{name: name, db: db}
+		r.healthy.Store(true)
+		d.dbReplicas = append(d.dbReplicas, r)
+	}
+
+	err = registerPoolMetrics(meter, d.pools())
 	if err != nil {
 		return nil, err
 	}
 
-	replicaDBSQL := sql.OpenDB(connector)
-	replicaDBSQL.SetMaxOpenConns(1)
-	db = bun.NewDB(replicaDBSQL, pgdialect.New())
+	if config.MaxReplicaLag > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		d.stop = cancel
+		d.stopped = make(chan struct{})
+		go func() {
+			defer close(d.stopped)
+			d.watchReplicas(ctx, config.MaxReplicaLag)
+		}()
+	}
+
+	return d, nil
+}
+
+// open opens the database of the url, its pool named as name in the metrics and the logs.
+func (m *database) open(
+	t tracer.Tracer,
+	meter metrics.Meter,
+	name, url string,
+	config Config,
+	opts ...bun.DBOption,
+) (*bun.DB, error) {
+	pool := config.Pool
+	connector, err := pgdriver.NewDriver().OpenConnector(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if pool.StatementTimeout > 0 {
+		cfg := connector.(*pgdriver.Connector).Config()
+		params := map[string]interface{}{}
+		for k, v := range cfg.ConnParams {
+			params[k] = v
+		}
+		params["statement_timeout"] = pool.StatementTimeout.Milliseconds()
+		cfg.ConnParams = params
+	}
+
+	sqlDB := sql.OpenDB(connector)
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	if pool.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	db := bun.NewDB(sqlDB, pgdialect.New(), opts...)
 	db.AddQueryHook(newDatabaseLogger())
 	db.AddQueryHook(newTracingHook(t))
-	d.dbReplica = db
+	hook, err := newMetricsHook(meter, name)
+	if err != nil {
+		return nil, err
+	}
+	db.AddQueryHook(hook)
+	db.AddQueryHook(newQueryStatsHook(name, config.SlowQueryThreshold, m.queryStats))
 
-	return d, nil
+	return db, nil
 }
 
-func (m *database) Master() DB {
+func (m *database) Master(ctx context.Context) DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	return m.dbMaster
+}
+
+func (m *database) Replica(ctx context.Context) DB {
+	if tx, ok := txFromContext(ctx); ok {
+		return tx
+	}
+	if IsPinnedToMaster(ctx) {
+		return m.dbMaster
+	}
+
+	start := m.next.Add(1)
+	for i := range m.dbReplicas {
+		r := m.dbReplicas[(start+uint64(i))%uint64(len(m.dbReplicas))]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+
 	return m.dbMaster
 }
 
-func (m *database) Replica() DB {
-	return m.dbReplica
+func (m *database) Replicas() []DB {
+	dbs := make([]DB, len(m.dbReplicas))
+	for i, r := range m.dbReplicas {
+		dbs[i] = r.db
+	}
+	return dbs
+}
+
+func (m *database) Stats() map[string]sql.DBStats {
+	stats := map[string]sql.DBStats{}
+	for name, pool := range m.pools() {
+		stats[name] = pool.Stats()
+	}
+	return stats
+}
+
+// pools returns the pools of connections by their names.
+func (m *database) pools() map[string]*sql.DB {
+	pools := map[string]*sql.DB{"master": m.dbMaster.DB}
+	for _, r := range m.dbReplicas {
+		pools[r.name] = r.db.DB
+	}
+	return pools
+}
+
+func (m *database) QueryStats() []QueryStat {
+	return m.queryStats.snapshot()
 }
 
 func (m *database) Stop(_ context.Context) error {
-	err := m.dbReplica.Close()
-	if err != nil {
-		return err
+	if m.stop != nil {
+		m.stop()
+		<-m.stopped
+	}
+
+	for _, r := range m.dbReplicas {
+		if err := r.db.Close(); err != nil {
+			return err
+		}
 	}
 
 	return m.dbMaster.Close()
@@ -0,0 +1,83 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun/driver/pgdriver"
+)
+
+var (
+	ErrUniqueViolation      = errors.New("unique constraint violated")
+	ErrForeignKeyViolation  = errors.New("foreign key constraint violated")
+	ErrCheckViolation       = errors.New("check constraint violated")
+	ErrNotNullViolation     = errors.New("not null constraint violated")
+	ErrSerializationFailure = errors.New("could not serialize the transaction")
+)
+
+// sqlStateErrors are the typed errors of the SQLSTATE codes, the deadlocks are serialization failures as well.
+var sqlStateErrors = map[string]error{
+	"23505": ErrUniqueViolation,
+	"23503": ErrForeignKeyViolation,
+	"23514": ErrCheckViolation,
+	"23502": ErrNotNullViolation,
+	"40001": ErrSerializationFailure,
+	"40P01": ErrSerializationFailure,
+}
+
+// Constraints are the domain errors of the violations of the constraints, by their names (e.g. the name of a unique
+// index or accounts_holder_id_fkey).
+type Constraints map[string]error
+
+// Error is an error of Postgres translated by TranslateError.
+type Error struct {
+	// Err is the typed error of the SQLSTATE, e.g. ErrUniqueViolation.
+	Err error
+	// Domain is the domain error of the violated constraint, nil when it has none.
+	Domain error
+	// Constraint is the name of the violated constraint, empty when the error is not a violation.
+	Constraint string
+
+	cause error
+}
+
+func (e *Error) Error() string {
+	if e.Domain != nil {
+		return e.Domain.Error()
+	}
+	if e.Constraint != "" {
+		return fmt.Sprintf("%s (%s): %s", e.Err, e.Constraint, e.cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Err, e.cause)
+}
+
+// Unwrap makes errors.Is and errors.As match the domain error, the typed error and the error of the driver.
+func (e *Error) Unwrap() []error {
+	if e.Domain != nil {
+		return []error{e.Domain, e.Err, e.cause}
+	}
+	return []error{e.Err, e.cause}
+}
+
+// TranslateError returns the errors of Postgres with a typed SQLSTATE (unique, foreign key, check and not null
+// violations, serialization failures and deadlocks) as Error, with the domain error of the violated constraint in
+// constraints, if any. Any other error is returned as it is.
+func TranslateError(err error, constraints Constraints) error {
+	var pgErr pgdriver.Error
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	typed, ok := sqlStateErrors[pgErr.Field('C')]
+	if !ok {
+		return err
+	}
+
+	constraint := pgErr.Field('n')
+	return &Error{
+		Err:        typed,
+		Domain:     constraints[constraint],
+		Constraint: constraint,
+		cause:      err,
+	}
+}
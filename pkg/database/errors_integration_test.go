@@ -0,0 +1,67 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateError_postgres(t *testing.T) {
+	ctx := context.Background()
+
+	url, terminate, err := testingcontainers.NewPostgresContainer()
+	require.NoError(t, err)
+	defer terminate(ctx)
+
+	db, err := New(tracer.NewNoop(), metrics.NewNoop(), Config{MasterURL: url})
+	require.NoError(t, err)
+	defer db.Stop(ctx)
+
+	errDomain := errors.New("domain")
+	constraints := Constraints{"a_constraint": errDomain}
+
+	tests := []struct {
+		name       string
+		code       string
+		constraint string
+		err        error
+	}{
+		{name: "Unique violation", code: "23505", constraint: "a_constraint", err: ErrUniqueViolation},
+		{name: "Foreign key violation", code: "23503", constraint: "another_constraint", err: ErrForeignKeyViolation},
+		{name: "Check violation", code: "23514", constraint: "another_constraint", err: ErrCheckViolation},
+		{name: "Serialization failure", code: "40001", err: ErrSerializationFailure},
+		{name: "Deadlock", code: "40P01", err: ErrSerializationFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Master(ctx).ExecContext(
+				ctx,
+				"DO $$ BEGIN RAISE EXCEPTION USING ERRCODE = ?, CONSTRAINT = ?; END $$",
+				tt.code,
+				tt.constraint,
+			)
+			err = TranslateError(err, constraints)
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.constraint == "a_constraint", errors.Is(err, errDomain))
+
+			var dbErr *Error
+			require.ErrorAs(t, err, &dbErr)
+			assert.Equal(t, tt.constraint, dbErr.Constraint)
+		})
+	}
+
+	t.Run("Keep the other errors of postgres", func(t *testing.T) {
+		_, err := db.Master(ctx).ExecContext(ctx, "SELECT * FROM a_missing_table")
+		assert.Error(t, err)
+		assert.Equal(t, err, TranslateError(err, constraints))
+	})
+}
@@ -0,0 +1,31 @@
+//go:build unit
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	errDomain := errors.New("domain")
+
+	t.Run("Keep the other errors", func(t *testing.T) {
+		err := errors.New("failed")
+
+		assert.Nil(t, TranslateError(nil, nil))
+		assert.Same(t, err, TranslateError(err, Constraints{"constraint": errDomain}))
+	})
+
+	t.Run("Match the domain and the typed errors", func(t *testing.T) {
+		err := fmt.Errorf("create: %w", &Error{Err: ErrUniqueViolation, Domain: errDomain, Constraint: "constraint"})
+
+		assert.ErrorIs(t, err, errDomain)
+		assert.ErrorIs(t, err, ErrUniqueViolation)
+		assert.NotErrorIs(t, err, ErrForeignKeyViolation)
+		assert.Equal(t, "create: domain", err.Error())
+	})
+}
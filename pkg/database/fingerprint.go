@@ -0,0 +1,84 @@
+package database
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// fingerprintLists are the lists of values, e.g. the IN (?, ?, ?), replaced by a single ? to group the queries
+	// differing only in the size of their lists.
+	fingerprintLists = regexp.MustCompile(`\(\?(?:, \?)+\)`)
+	// fingerprintRows are the rows of a multi-row VALUES, kept as a single one.
+	fingerprintRows = regexp.MustCompile(`\(\?\)(?:, \(\?\))+`)
+)
+
+// Fingerprint returns query normalized with its values redacted: the strings and the numbers are replaced by ?, the
+// lists of values by a single ?, and the spaces are collapsed. The queries differing only in their values have the
+// same fingerprint.
+func Fingerprint(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			continue
+		case c == '\'':
+			// '' is an escaped quote inside the string.
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			c = '?'
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			for i+1 < len(query) && isDigit(query[i+1]) {
+				i++
+			}
+			c = '?'
+		case isDigit(c) && (i == 0 || !isIdentifier(query[i-1])):
+			for i+1 < len(query) && (isDigit(query[i+1]) || query[i+1] == '.') {
+				i++
+			}
+			c = '?'
+		case c == '"':
+			// The quoted identifiers are kept as they are.
+			end := len(query)
+			if j := strings.IndexByte(query[i+1:], '"'); j >= 0 {
+				end = i + j + 2
+			}
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteString(query[i:end])
+			i = end - 1
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		b.WriteByte(c)
+	}
+
+	fingerprint := fingerprintLists.ReplaceAllString(b.String(), "(?)")
+	return fingerprintRows.ReplaceAllString(fingerprint, "(?)")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || c == '.' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
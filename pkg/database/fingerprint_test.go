@@ -0,0 +1,60 @@
+//go:build unit
+
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "Redact the strings and the numbers",
+			query: `SELECT * FROM "holders" WHERE ("document_number" = 'it''s 123') AND ("amount" > 10.5) LIMIT 10`,
+			want:  `SELECT * FROM "holders" WHERE ("document_number" = ?) AND ("amount" > ?) LIMIT ?`,
+		},
+		{
+			name:  "Redact the placeholders",
+			query: `UPDATE "accounts" SET "status" = $1 WHERE "id" = $2`,
+			want:  `UPDATE "accounts" SET "status" = ? WHERE "id" = ?`,
+		},
+		{
+			name:  "Keep the identifiers with digits",
+			query: `SELECT "t1"."id", md5(x) FROM "table_2" AS t1 WHERE "t1"."n2" = 3`,
+			want:  `SELECT "t1"."id", md5(x) FROM "table_2" AS t1 WHERE "t1"."n2" = ?`,
+		},
+		{
+			name:  "Collapse the spaces",
+			query: "SELECT 1\n\t FROM   \"accounts\"  ",
+			want:  `SELECT ? FROM "accounts"`,
+		},
+		{
+			name:  "Collapse the lists of values",
+			query: `SELECT * FROM "accounts" WHERE "id" IN ('a', 'b', 'c')`,
+			want:  `SELECT * FROM "accounts" WHERE "id" IN (?)`,
+		},
+		{
+			name:  "Collapse the rows",
+			query: `INSERT INTO "statements" ("id", "amount") VALUES ('a', 1), ('b', 2)`,
+			want:  `INSERT INTO "statements" ("id", "amount") VALUES (?)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fingerprint(tt.query))
+		})
+	}
+
+	t.Run("Group the queries differing only in their values", func(t *testing.T) {
+		assert.Equal(t,
+			Fingerprint(`SELECT * FROM "accounts" WHERE "id" IN ('a') AND "number" = 1`),
+			Fingerprint(`SELECT * FROM "accounts" WHERE "id" IN ('b', 'c') AND "number" = 22`),
+		)
+	})
+}
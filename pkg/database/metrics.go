@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// poolAttribute is the attribute of the pool of the metrics, master or replica.
+const poolAttribute = attribute.Key("db.pool")
+
+type metricsHook struct {
+	pool     attribute.KeyValue
+	duration metric.Float64Histogram
+}
+
+// newMetricsHook returns a new implementation QueryHook to record the latency of the queries by operation.
+func newMetricsHook(meter metrics.Meter, pool string) (bun.QueryHook, error) {
+	duration, err := meter.Float64Histogram(
+		"db.client.query.duration",
+		metric.WithDescription("Duration of the database queries."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return metricsHook{pool: poolAttribute.String(pool), duration: duration}, nil
+}
+
+// BeforeQuery implements bun.QueryHook.
+func (m metricsHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook.
+func (m metricsHook) AfterQuery(ctx context.Context, qe *bun.QueryEvent) {
+	if isPingQuery(qe) {
+		return
+	}
+
+	m.duration.Record(
+		ctx,
+		time.Since(qe.StartTime).Seconds(),
+		metric.WithAttributes(
+			m.pool,
+			attribute.String("db.operation", qe.Operation()),
+			attribute.Bool("error", qe.Err != nil && !errors.Is(qe.Err, sql.ErrNoRows)),
+		),
+	)
+}
+
+// registerPoolMetrics observes the statistics of the connection pools, keyed by their name.
+func registerPoolMetrics(meter metrics.Meter, pools map[string]*sql.DB) error {
+	open, err := meter.Int64ObservableGauge(
+		"db.client.connections.open",
+		metric.WithDescription("Number of the open connections, in use or idle."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	inUse, err := meter.Int64ObservableGauge(
+		"db.client.connections.in_use",
+		metric.WithDescription("Number of the connections in use."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	idle, err := meter.Int64ObservableGauge(
+		"db.client.connections.idle",
+		metric.WithDescription("Number of the idle connections."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	maxOpen, err := meter.Int64ObservableGauge(
+		"db.client.connections.max",
+		metric.WithDescription("Maximum number of the open connections."),
+		metric.WithUnit("{connection}"),
+	)
+	if err != nil {
+		return err
+	}
+	waits, err := meter.Int64ObservableCounter(
+		"db.client.connections.waits",
+		metric.WithDescription("Number of the waits for a connection."),
+		metric.WithUnit("{wait}"),
+	)
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter(
+		"db.client.connections.wait_duration",
+		metric.WithDescription("Total time waited for a connection."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(
+		func(_ context.Context, o metric.Observer) error {
+			for name, pool := range pools {
+				stats := pool.Stats()
+				attrs := metric.WithAttributes(poolAttribute.String(name))
+				o.ObserveInt64(open, int64(stats.OpenConnections), attrs)
+				o.ObserveInt64(inUse, int64(stats.InUse), attrs)
+				o.ObserveInt64(idle, int64(stats.Idle), attrs)
+				o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections), attrs)
+				o.ObserveInt64(waits, stats.WaitCount, attrs)
+				o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds(), attrs)
+			}
+			return nil
+		},
+		open, inUse, idle, maxOpen, waits, waitDuration,
+	)
+	return err
+}
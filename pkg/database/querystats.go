@@ -0,0 +1,135 @@
+package database
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxFingerprints bounds the fingerprints aggregated, the queries of the new ones are aggregated in
+	// otherFingerprint once it is reached.
+	maxFingerprints  = 1000
+	otherFingerprint = "other"
+	// queryStatSamples are the durations of the last queries of each fingerprint kept for the percentiles.
+	queryStatSamples = 512
+)
+
+// QueryStat are the statistics of the queries of a fingerprint (Fingerprint) since the start of the process, the
+// percentiles are of its last queries.
+type QueryStat struct {
+	Fingerprint string
+	Count       int64
+	Errors      int64
+	Total       time.Duration
+	Max         time.Duration
+	P50         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+}
+
+// Mean returns the mean duration of the queries.
+func (s QueryStat) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+type queryStat struct {
+	count   int64
+	errors  int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+// queryStats aggregates the queries of all the pools by fingerprint.
+type queryStats struct {
+	mu           sync.Mutex
+	fingerprints map[string]*queryStat
+}
+
+func newQueryStats() *queryStats {
+	return &queryStats{fingerprints: map[string]*queryStat{}}
+}
+
+func (s *queryStats) record(fingerprint string, duration time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.fingerprints[fingerprint]
+	if !ok {
+		if len(s.fingerprints) >= maxFingerprints {
+			fingerprint = otherFingerprint
+			stat = s.fingerprints[fingerprint]
+		}
+		if stat == nil {
+			stat = &queryStat{samples: make([]time.Duration, 0, queryStatSamples)}
+			s.fingerprints[fingerprint] = stat
+		}
+	}
+
+	stat.count++
+	if failed {
+		stat.errors++
+	}
+	stat.total += duration
+	if duration > stat.max {
+		stat.max = duration
+	}
+	if len(stat.samples) < queryStatSamples {
+		stat.samples = append(stat.samples, duration)
+	} else {
+		stat.samples[stat.next] = duration
+		stat.next = (stat.next + 1) % queryStatSamples
+	}
+}
+
+// snapshot returns the statistics of the fingerprints, the ones with the largest total duration first.
+func (s *queryStats) snapshot() []QueryStat {
+	s.mu.Lock()
+	stats := make([]QueryStat, 0, len(s.fingerprints))
+	samples := make([][]time.Duration, 0, len(s.fingerprints))
+	for fingerprint, stat := range s.fingerprints {
+		stats = append(stats, QueryStat{
+			Fingerprint: fingerprint,
+			Count:       stat.count,
+			Errors:      stat.errors,
+			Total:       stat.total,
+			Max:         stat.max,
+		})
+		samples = append(samples, append([]time.Duration(nil), stat.samples...))
+	}
+	s.mu.Unlock()
+
+	for i := range stats {
+		sort.Slice(samples[i], func(a, b int) bool { return samples[i][a] < samples[i][b] })
+		stats[i].P50 = percentile(samples[i], 0.50)
+		stats[i].P95 = percentile(samples[i], 0.95)
+		stats[i].P99 = percentile(samples[i], 0.99)
+	}
+
+	sort.Slice(stats, func(a, b int) bool {
+		if stats[a].Total == stats[b].Total {
+			return stats[a].Fingerprint < stats[b].Fingerprint
+		}
+		return stats[a].Total > stats[b].Total
+	})
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
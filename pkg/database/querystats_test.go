@@ -0,0 +1,64 @@
+//go:build unit
+
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryStats(t *testing.T) {
+	t.Run("Aggregate the queries by fingerprint", func(t *testing.T) {
+		stats := newQueryStats()
+		for i := 1; i <= 100; i++ {
+			stats.record("a", time.Duration(i)*time.Millisecond, i%10 == 0)
+		}
+		stats.record("b", time.Second, false)
+
+		snapshot := stats.snapshot()
+		assert.Len(t, snapshot, 2)
+		assert.Equal(t, QueryStat{
+			Fingerprint: "a",
+			Count:       100,
+			Errors:      10,
+			Total:       5050 * time.Millisecond,
+			Max:         100 * time.Millisecond,
+			P50:         50 * time.Millisecond,
+			P95:         95 * time.Millisecond,
+			P99:         99 * time.Millisecond,
+		}, snapshot[0])
+		assert.Equal(t, 50500*time.Microsecond, snapshot[0].Mean())
+		assert.Equal(t, "b", snapshot[1].Fingerprint)
+		assert.Equal(t, time.Second, snapshot[1].P99)
+	})
+
+	t.Run("Keep the last samples", func(t *testing.T) {
+		stats := newQueryStats()
+		for i := 0; i < queryStatSamples; i++ {
+			stats.record("a", time.Hour, false)
+		}
+		for i := 0; i < queryStatSamples; i++ {
+			stats.record("a", time.Millisecond, false)
+		}
+
+		snapshot := stats.snapshot()
+		assert.Equal(t, time.Millisecond, snapshot[0].P99)
+		assert.Equal(t, time.Hour, snapshot[0].Max)
+		assert.Equal(t, int64(2*queryStatSamples), snapshot[0].Count)
+	})
+
+	t.Run("Bound the fingerprints", func(t *testing.T) {
+		stats := newQueryStats()
+		for i := 0; i < maxFingerprints+10; i++ {
+			stats.record(fmt.Sprintf("q%d", i), time.Millisecond, false)
+		}
+
+		snapshot := stats.snapshot()
+		assert.Len(t, snapshot, maxFingerprints+1)
+		assert.Equal(t, otherFingerprint, snapshot[0].Fingerprint)
+		assert.Equal(t, int64(10), snapshot[0].Count)
+	})
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+)
+
+// replicaLagInterval is the interval between the checks of the lag of the replicas.
+const replicaLagInterval = time.Second
+
+// queryReplicationLag returns the seconds the replica is behind the master, zero when it is the master or it
+// replayed all the changes it received.
+const queryReplicationLag = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+type pinnedToMasterKey struct{}
+
+// PinMaster pins the reads of ctx to the master, for the flows that must read their own writes (e.g. the balance
+// checked before a debit), which may not be replicated yet.
+func PinMaster(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pinnedToMasterKey{}, true)
+}
+
+// IsPinnedToMaster returns whether the reads of ctx are pinned to the master.
+func IsPinnedToMaster(ctx context.Context) bool {
+	pinned, _ := ctx.Value(pinnedToMasterKey{}).(bool)
+	return pinned
+}
+
+// ReplicationLag returns the time the replica db is behind its master, zero when db is a master.
+func ReplicationLag(ctx context.Context, db DB) (time.Duration, error) {
+	var seconds float64
+	if err := db.QueryRowContext(ctx, queryReplicationLag).Scan(&seconds); err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+type replica struct {
+	name    string
+	db      *bun.DB
+	healthy atomic.Bool
+}
+
+// watchReplicas checks the lag of the replicas at every replicaLagInterval until ctx is done, the replicas lagging
+// more than maxLag, or failing the check, are not read until they catch up.
+func (m *database) watchReplicas(ctx context.Context, maxLag time.Duration) {
+	ticker := time.NewTicker(replicaLagInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, r := range m.dbReplicas {
+			m.checkReplica(ctx, r, maxLag)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *database) checkReplica(ctx context.Context, r *replica, maxLag time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, replicaLagInterval)
+	defer cancel()
+
+	lag, err := ReplicationLag(ctx, r.db)
+	healthy := err == nil && lag <= maxLag
+	if r.healthy.Swap(healthy) == healthy {
+		return
+	}
+
+	if healthy {
+		zapctx.L(ctx).Info("database_replica_caught_up", zap.String("replica", r.name), zap.Duration("lag", lag))
+		return
+	}
+	zapctx.L(ctx).Warn(
+		"database_replica_lagging",
+		zap.String("replica", r.name),
+		zap.Duration("lag", lag),
+		zap.Duration("max_lag", maxLag),
+		zap.Error(err),
+	)
+}
@@ -0,0 +1,60 @@
+//go:build unit
+
+package database
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+func _newDatabaseTest(healthy ...bool) *database {
+	d := &database{dbMaster: &bun.DB{}, next: &atomic.Uint64{}}
+	for _, h := range healthy {
+		r := &replica{db: &bun.DB{}}
+		r.healthy.Store(h)
+		d.dbReplicas = append(d.dbReplicas, r)
+	}
+	return d
+}
+
+func TestDatabase_Replica(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Round robin over the replicas", func(t *testing.T) {
+		d := _newDatabaseTest(true, true)
+
+		first := d.Replica(ctx)
+		second := d.Replica(ctx)
+
+		assert.NotSame(t, first, second)
+		assert.Same(t, first, d.Replica(ctx))
+		assert.NotSame(t, d.Master(ctx), first)
+		assert.NotSame(t, d.Master(ctx), second)
+	})
+
+	t.Run("Skip the lagging replicas", func(t *testing.T) {
+		d := _newDatabaseTest(false, true)
+
+		for i := 0; i < 3; i++ {
+			assert.Same(t, d.dbReplicas[1].db, d.Replica(ctx))
+		}
+	})
+
+	t.Run("Fall back to master when all replicas lag", func(t *testing.T) {
+		d := _newDatabaseTest(false, false)
+
+		assert.Same(t, d.Master(ctx), d.Replica(ctx))
+	})
+
+	t.Run("Read from master when pinned", func(t *testing.T) {
+		d := _newDatabaseTest(true, true)
+
+		assert.False(t, IsPinnedToMaster(ctx))
+		assert.True(t, IsPinnedToMaster(PinMaster(ctx)))
+		assert.Same(t, d.Master(ctx), d.Replica(PinMaster(ctx)))
+	})
+}
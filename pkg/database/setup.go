@@ -1,6 +1,7 @@
 package database
 
 import (
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"go.uber.org/fx"
 )
@@ -8,10 +9,10 @@ import (
 func Setup(
 	lc fx.Lifecycle,
 	t tracer.Tracer,
-	postgresMasterURL string,
-	postgresReplicaURL string,
+	m metrics.Metrics,
+	config Config,
 ) (Database, error) {
-	database, err := New(t, postgresMasterURL, postgresReplicaURL)
+	database, err := New(t, m, config)
 	if err != nil {
 		return database, err
 	}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/uptrace/bun"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type queryStatsHook struct {
+	pool      string
+	threshold time.Duration
+	stats     *queryStats
+}
+
+// newQueryStatsHook returns a new implementation QueryHook to aggregate the queries in stats by fingerprint, and to
+// log the ones taking threshold or more, redacted. The slow queries are not logged when threshold is zero.
+func newQueryStatsHook(pool string, threshold time.Duration, stats *queryStats) bun.QueryHook {
+	return queryStatsHook{pool: pool, threshold: threshold, stats: stats}
+}
+
+// BeforeQuery implements bun.QueryHook.
+func (h queryStatsHook) BeforeQuery(ctx context.Context, _ *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements bun.QueryHook.
+func (h queryStatsHook) AfterQuery(ctx context.Context, qe *bun.QueryEvent) {
+	if isPingQuery(qe) {
+		return
+	}
+
+	duration := time.Since(qe.StartTime)
+	fingerprint := Fingerprint(qe.Query)
+	h.stats.record(fingerprint, duration, qe.Err != nil && !errors.Is(qe.Err, sql.ErrNoRows))
+
+	if h.threshold <= 0 || duration < h.threshold {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("pool", h.pool),
+		zap.String("operation", qe.Operation()),
+		zap.String("query", fingerprint),
+		zap.Duration("duration", duration),
+		zap.Duration("threshold", h.threshold),
+	}
+	if qe.Result != nil {
+		if rows, err := qe.Result.RowsAffected(); err == nil {
+			fields = append(fields, zap.Int64("rows_affected", rows))
+		}
+	}
+	// zapctx only logs the trace of the spans recorded, the slow queries of the traces not sampled are still found by
+	// their trace.
+	if span := trace.SpanFromContext(ctx); !span.IsRecording() && span.SpanContext().HasTraceID() {
+		fields = append(fields, zap.String("trace_id", span.SpanContext().TraceID().String()))
+	}
+	if qe.Err != nil {
+		fields = append(fields, zap.Error(qe.Err))
+	}
+
+	zapctx.L(ctx).Warn("database_slow_query", fields...)
+}
@@ -0,0 +1,15 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// WithQueryTimeout returns ctx bounded by timeout, for the default deadlines of the queries of the repositories. The
+// deadline of ctx is kept when it is earlier, and ctx is not bounded when timeout is not positive.
+func WithQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,43 @@
+//go:build unit
+
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithQueryTimeout(t *testing.T) {
+	t.Run("Bound ctx by the timeout", func(t *testing.T) {
+		ctx, cancel := WithQueryTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+	})
+
+	t.Run("Keep the earlier deadline of ctx", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancelParent()
+		want, _ := parent.Deadline()
+
+		ctx, cancel := WithQueryTimeout(parent, time.Hour)
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.Equal(t, want, deadline)
+	})
+
+	t.Run("Keep ctx without timeout", func(t *testing.T) {
+		ctx, cancel := WithQueryTimeout(context.Background(), 0)
+		defer cancel()
+
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+}
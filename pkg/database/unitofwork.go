@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/uptrace/bun"
+	"go.uber.org/zap"
+)
+
+const (
+	// unitOfWorkAttempts is the number of times a unit of work is run while it fails by serialization failures or
+	// deadlocks, waiting unitOfWorkBackoff more after each attempt.
+	unitOfWorkAttempts = 3
+	unitOfWorkBackoff  = 25 * time.Millisecond
+)
+
+// UnitOfWork runs functions in transactions of the master propagated by their ctx, Master and Replica return the
+// transaction of ctx, so the queries of all the repositories called with ctx are in the transaction.
+type UnitOfWork interface {
+	// Do runs fn in a transaction committed when fn returns nil, and rolled back when it returns an error or panics.
+	// In the unit of work of ctx, fn runs in a savepoint of its transaction, rolled back alone and without opts. The
+	// outermost unit is run again in a new transaction when it fails by a serialization failure or a deadlock, so fn
+	// must not have effects out of the database. The read-only units (opts.ReadOnly) run in a transaction of a replica,
+	// or of the master when ctx is pinned to it, and are never run again, so fn may stream what it reads.
+	Do(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error
+}
+
+type unitOfWork struct {
+	tracer tracer.Tracer
+	db     Database
+}
+
+func NewUnitOfWork(t tracer.Tracer, db Database) UnitOfWork {
+	return unitOfWork{
+		tracer: t,
+		db:     db,
+	}
+}
+
+func (u unitOfWork) Do(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	ctx, span := u.tracer.Span(ctx)
+	defer span.End()
+
+	if tx, ok := txFromContext(ctx); ok {
+		err := tx.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			return fn(withTx(ctx, txDB{Tx: sp, db: tx.db}))
+		})
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+
+	if opts != nil && opts.ReadOnly {
+		replica := u.db.Replica(ctx)
+		err := replica.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+			return fn(withTx(ctx, txDB{Tx: tx, db: replica}))
+		})
+		if err != nil {
+			span.RecordError(err)
+		}
+		return err
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		master := u.db.Master(ctx)
+		err = master.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+			return fn(withTx(ctx, txDB{Tx: tx, db: master}))
+		})
+		if attempt == unitOfWorkAttempts || !IsRetryable(err) {
+			break
+		}
+
+		zapctx.L(ctx).Warn("database_unit_of_work_retry", zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			span.RecordError(ctx.Err())
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * unitOfWorkBackoff):
+		}
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	return err
+}
+
+// IsRetryable returns whether err is a serialization failure or a deadlock, whose transaction may succeed when run
+// again.
+func IsRetryable(err error) bool {
+	return errors.Is(TranslateError(err, nil), ErrSerializationFailure)
+}
+
+type txKey struct{}
+
+func withTx(ctx context.Context, tx txDB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+func txFromContext(ctx context.Context) (txDB, bool) {
+	tx, ok := ctx.Value(txKey{}).(txDB)
+	return tx, ok
+}
+
+// txDB is the transaction of a unit of work as a DB, db is the master or the replica it was begun in.
+type txDB struct {
+	bun.Tx
+	db DB
+}
+
+func (t txDB) PingContext(ctx context.Context) error {
+	return t.db.PingContext(ctx)
+}
+
+func (t txDB) ScanRow(ctx context.Context, rows *sql.Rows, dest ...interface{}) error {
+	return t.db.ScanRow(ctx, rows, dest...)
+}
+
+// Close does nothing, the transaction is ended by its unit of work.
+func (t txDB) Close() error {
+	return nil
+}
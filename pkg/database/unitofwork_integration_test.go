@@ -0,0 +1,159 @@
+//go:build integration
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+
+	url, terminate, err := testingcontainers.NewPostgresContainer()
+	require.NoError(t, err)
+	defer terminate(ctx)
+
+	db, err := New(tracer.NewNoop(), metrics.NewNoop(), Config{MasterURL: url, Pool: PoolConfig{MaxOpenConns: 4}})
+	require.NoError(t, err)
+	defer db.Stop(ctx)
+
+	_, err = db.Master(ctx).ExecContext(ctx, "CREATE TABLE units (name TEXT PRIMARY KEY)")
+	require.NoError(t, err)
+
+	uow := NewUnitOfWork(tracer.NewNoop(), db)
+
+	insert := func(ctx context.Context, name string) error {
+		_, err := db.Master(ctx).NewInsert().TableExpr("units").Value("name", "?", name).Exec(ctx)
+		return err
+	}
+	exists := func(name string) bool {
+		ok, err := db.Replica(ctx).NewSelect().TableExpr("units").Where("name = ?", name).Exists(ctx)
+		require.NoError(t, err)
+		return ok
+	}
+
+	t.Run("Commit the repositories of ctx", func(t *testing.T) {
+		err := uow.Do(ctx, nil, func(ctx context.Context) error {
+			if err := insert(ctx, "commit-1"); err != nil {
+				return err
+			}
+			assert.False(t, exists("commit-1"))
+			return insert(ctx, "commit-2")
+		})
+		assert.NoError(t, err)
+		assert.True(t, exists("commit-1"))
+		assert.True(t, exists("commit-2"))
+	})
+
+	t.Run("Rollback on error", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		err := uow.Do(ctx, nil, func(ctx context.Context) error {
+			if err := insert(ctx, "error"); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		assert.False(t, exists("error"))
+	})
+
+	t.Run("Rollback on panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = uow.Do(ctx, nil, func(ctx context.Context) error {
+				if err := insert(ctx, "panic"); err != nil {
+					return err
+				}
+				panic("failed")
+			})
+		})
+		assert.False(t, exists("panic"))
+	})
+
+	t.Run("Rollback nested savepoint alone", func(t *testing.T) {
+		err := uow.Do(ctx, nil, func(ctx context.Context) error {
+			if err := insert(ctx, "outer"); err != nil {
+				return err
+			}
+			err := uow.Do(ctx, nil, func(ctx context.Context) error {
+				if err := insert(ctx, "inner"); err != nil {
+					return err
+				}
+				return insert(ctx, "outer")
+			})
+			assert.Error(t, err)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.True(t, exists("outer"))
+		assert.False(t, exists("inner"))
+	})
+
+	t.Run("Retry serialization failures", func(t *testing.T) {
+		attempts := 0
+		err := uow.Do(ctx, nil, func(ctx context.Context) error {
+			attempts++
+			if err := insert(ctx, "retried"); err != nil {
+				return err
+			}
+			if attempts == 1 {
+				_, err := db.Master(ctx).ExecContext(ctx, "DO $$ BEGIN RAISE EXCEPTION USING ERRCODE = '40001'; END $$")
+				assert.True(t, IsRetryable(err))
+				return err
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.True(t, exists("retried"))
+	})
+
+	t.Run("Give up after the attempts", func(t *testing.T) {
+		attempts := 0
+		err := uow.Do(ctx, nil, func(ctx context.Context) error {
+			attempts++
+			_, err := db.Master(ctx).ExecContext(ctx, "DO $$ BEGIN RAISE EXCEPTION USING ERRCODE = '40P01'; END $$")
+			return err
+		})
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, unitOfWorkAttempts, attempts)
+	})
+
+	t.Run("Read a snapshot in read-only units", func(t *testing.T) {
+		count := func(ctx context.Context) int {
+			n, err := db.Replica(ctx).NewSelect().TableExpr("units").Count(ctx)
+			require.NoError(t, err)
+			return n
+		}
+
+		snapshot := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+		err := uow.Do(ctx, snapshot, func(ctx context.Context) error {
+			before := count(ctx)
+			require.NoError(t, insert(context.Background(), "concurrent"))
+			assert.Equal(t, before, count(ctx))
+			return insert(ctx, "read-only")
+		})
+		assert.Error(t, err)
+		assert.True(t, exists("concurrent"))
+		assert.False(t, exists("read-only"))
+	})
+
+	t.Run("Never retry read-only units", func(t *testing.T) {
+		attempts := 0
+		err := uow.Do(ctx, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context) error {
+			attempts++
+			_, err := db.Master(ctx).ExecContext(ctx, "DO $$ BEGIN RAISE EXCEPTION USING ERRCODE = '40001'; END $$")
+			return err
+		})
+		assert.True(t, IsRetryable(err))
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,28 @@
+//go:build unit
+
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDatabase_unitOfWork(t *testing.T) {
+	d := _newDatabaseTest(true, true)
+	tx := txDB{db: d.dbMaster}
+	ctx := withTx(context.Background(), tx)
+
+	assert.Equal(t, tx, d.Master(ctx))
+	assert.Equal(t, tx, d.Replica(ctx))
+	assert.Equal(t, tx, d.Replica(PinMaster(ctx)))
+	assert.Same(t, d.dbMaster, d.Master(context.Background()))
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("failed")))
+	assert.False(t, IsRetryable(context.DeadlineExceeded))
+}
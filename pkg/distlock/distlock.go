@@ -5,10 +5,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/redis"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	redis2 "github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
@@ -21,22 +25,54 @@ type DistLock interface {
 }
 
 type distLock struct {
-	store  redis.Client
-	tracer tracer.Tracer
+	store    redis.Client
+	tracer   tracer.Tracer
+	acquires metric.Int64Counter
+	wait     metric.Float64Histogram
 }
 
-// NewDistock returns a DistLock.
-func NewDistock(t tracer.Tracer, store redis.Client) DistLock {
+// NewDistock returns a DistLock that records the acquires, by whether the lock was acquired, and the time waited
+// for them.
+func NewDistock(t tracer.Tracer, m metrics.Metrics, store redis.Client) DistLock {
+	meter := m.Meter("github.com/dalmarcogd/ledger-exp/pkg/distlock")
+
+	// The instruments created with an error still work, the error is only reported.
+	acquires, err := meter.Int64Counter(
+		"distlock.acquires",
+		metric.WithDescription("Number of the acquires of locks."),
+		metric.WithUnit("{acquire}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	wait, err := meter.Float64Histogram(
+		"distlock.acquire.wait",
+		metric.WithDescription("Time waited to acquire the locks."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
 	return distLock{
-		tracer: t,
-		store:  store,
+		tracer:   t,
+		store:    store,
+		acquires: acquires,
+		wait:     wait,
 	}
 }
 
-func (rl distLock) Acquire(ctx context.Context, key string, duration time.Duration, retryCount int) bool {
+func (rl distLock) Acquire(ctx context.Context, key string, duration time.Duration, retryCount int) (acquired bool) {
 	ctx, span := rl.tracer.Span(ctx)
 	defer span.End()
 
+	start := time.Now()
+	defer func() {
+		attrs := metric.WithAttributes(attribute.Bool("acquired", acquired))
+		rl.acquires.Add(ctx, 1, attrs)
+		rl.wait.Record(ctx, time.Since(start).Seconds(), attrs)
+	}()
+
 	ctxTimeout, cancelFunc := context.WithTimeout(ctx, 5*time.Millisecond)
 	defer cancelFunc()
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/redis"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
@@ -28,7 +29,7 @@ func TestDistLock(t *testing.T) {
 	assert.NoError(t, err)
 
 	t.Run("fail to acquire, lock already exists", func(t *testing.T) {
-		distock := NewDistock(tracer.NewNoop(), client)
+		distock := NewDistock(tracer.NewNoop(), metrics.NewNoop(), client)
 		key := uuid.NewString()
 
 		acquire := distock.Acquire(ctx, key, time.Second, 1)
@@ -39,7 +40,7 @@ func TestDistLock(t *testing.T) {
 	})
 
 	t.Run("acquire and release successfull", func(t *testing.T) {
-		distock := NewDistock(tracer.NewNoop(), client)
+		distock := NewDistock(tracer.NewNoop(), metrics.NewNoop(), client)
 		key := uuid.NewString()
 
 		acquire := distock.Acquire(ctx, key, time.Second, 1)
@@ -50,7 +51,7 @@ func TestDistLock(t *testing.T) {
 	})
 
 	t.Run("try release, no locked key", func(t *testing.T) {
-		distock := NewDistock(tracer.NewNoop(), client)
+		distock := NewDistock(tracer.NewNoop(), metrics.NewNoop(), client)
 		key := uuid.NewString()
 
 		release := distock.Release(ctx, key)
@@ -64,7 +65,7 @@ func TestDistLock(t *testing.T) {
 		c, err := redis.NewClient(u, "")
 		assert.NoError(t, err)
 
-		distock := NewDistock(tracer.NewNoop(), c)
+		distock := NewDistock(tracer.NewNoop(), metrics.NewNoop(), c)
 		key := uuid.NewString()
 
 		assert.NoError(t, clo(ctx))
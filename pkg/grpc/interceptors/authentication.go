@@ -0,0 +1,86 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	apiKeyMetadata = "x-api-key"
+	bearerPrefix   = "bearer "
+)
+
+// NewAuthenticationUnaryServerInterceptor authenticates the calls by the API key of the x-api-key metadata or by the
+// bearer token of the authorization metadata, the same credentials of the HTTP API, failing with Unauthenticated
+// when none is valid. The principal is put in the context of the call. The bearer tokens are refused when tokens is
+// nil and the calls of the ignoreMethods are not authenticated.
+func NewAuthenticationUnaryServerInterceptor(
+	apiKeys, tokens auth.Authenticator,
+	ignoreMethods ...string,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		for _, ignoreMethod := range ignoreMethods {
+			if info.FullMethod == ignoreMethod {
+				return handler(ctx, req)
+			}
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		authenticator, credential := credentialOf(md, apiKeys, tokens)
+		if authenticator == nil {
+			zapctx.L(ctx).Info("authentication_missing_credentials")
+			return nil, status.Error(codes.Unauthenticated, "missing credentials")
+		}
+
+		principal, err := authenticator.Authenticate(ctx, credential)
+		if err != nil {
+			if !errors.Is(err, auth.ErrInvalidCredentials) {
+				zapctx.L(ctx).Error("authentication_error", zap.Error(err))
+				return nil, status.Error(codes.Internal, codes.Internal.String())
+			}
+			zapctx.L(ctx).Info("authentication_invalid_credentials")
+			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(
+			semconv.EnduserIDKey.String(principal.ID),
+			attribute.String("enduser.type", string(principal.Type)),
+		)
+
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// credentialOf returns the credential of the call and its authenticator, the API key has precedence over the bearer
+// token.
+func credentialOf(md metadata.MD, apiKeys, tokens auth.Authenticator) (auth.Authenticator, string) {
+	if keys := md.Get(apiKeyMetadata); len(keys) > 0 && keys[0] != "" {
+		return apiKeys, keys[0]
+	}
+
+	authorizations := md.Get("authorization")
+	if tokens != nil && len(authorizations) > 0 && len(authorizations[0]) > len(bearerPrefix) &&
+		strings.EqualFold(authorizations[0][:len(bearerPrefix)], bearerPrefix) {
+		return tokens, strings.TrimSpace(authorizations[0][len(bearerPrefix):])
+	}
+
+	return nil, ""
+}
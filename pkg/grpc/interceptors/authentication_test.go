@@ -0,0 +1,68 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type _authenticatorTest map[string]auth.Principal
+
+func (a _authenticatorTest) Authenticate(_ context.Context, credential string) (auth.Principal, error) {
+	principal, ok := a[credential]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+	return principal, nil
+}
+
+func TestAuthenticationUnaryServerInterceptor(t *testing.T) {
+	apiKeys := _authenticatorTest{"lk_key": {ID: "key", Type: auth.APIKeyPrincipal}}
+	tokens := _authenticatorTest{"token": {ID: "subject", Type: auth.JWTPrincipal}}
+	interceptor := NewAuthenticationUnaryServerInterceptor(apiKeys, tokens, "/grpc.health.v1.Health/Check")
+	info := &grpc.UnaryServerInfo{FullMethod: "/ledger.v1.HolderService/GetHolder"}
+
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		principal, _ := auth.PrincipalFromContext(ctx)
+		return principal, nil
+	}
+
+	t.Run("API key", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "lk_key"))
+		resp, err := interceptor(ctx, "req", info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, apiKeys["lk_key"], resp)
+	})
+
+	t.Run("Bearer token", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+		resp, err := interceptor(ctx, "req", info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, tokens["token"], resp)
+	})
+
+	t.Run("Invalid credentials", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", "lk_other"))
+		_, err := interceptor(ctx, "req", info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Missing credentials", func(t *testing.T) {
+		_, err := interceptor(context.Background(), "req", info, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("Ignored method", func(t *testing.T) {
+		healthInfo := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+		_, err := interceptor(context.Background(), "req", healthInfo, handler)
+		assert.NoError(t, err)
+	})
+}
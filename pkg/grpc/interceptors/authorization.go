@@ -0,0 +1,45 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewAuthorizationUnaryServerInterceptor fails with PermissionDenied the calls whose principal lacks the scope of
+// the method, it goes after the authentication interceptor. The methods missing in scopes are denied, but the ones
+// of ignoreMethods.
+func NewAuthorizationUnaryServerInterceptor(
+	scopes map[string]authz.Scope,
+	ignoreMethods ...string,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		for _, ignoreMethod := range ignoreMethods {
+			if info.FullMethod == ignoreMethod {
+				return handler(ctx, req)
+			}
+		}
+
+		scope, ok := scopes[info.FullMethod]
+		if !ok {
+			zapctx.L(ctx).Error("authorization_method_without_scope", zap.String("method", info.FullMethod))
+			return nil, status.Error(codes.PermissionDenied, authz.ErrForbidden.Error())
+		}
+
+		if err := authz.Authorize(ctx, scope); err != nil {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,51 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizationUnaryServerInterceptor(t *testing.T) {
+	interceptor := NewAuthorizationUnaryServerInterceptor(
+		map[string]authz.Scope{"/ledger.v1.TransactionService/CreateCredit": authz.ScopeTransactionsCredit},
+		"/grpc.health.v1.Health/Check",
+	)
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return "resp", nil
+	}
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "partner", Scopes: []string{"transactions:credit"}})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		method string
+		code   codes.Code
+	}{
+		{name: "Granted scope", ctx: ctx, method: "/ledger.v1.TransactionService/CreateCredit", code: codes.OK},
+		{
+			name:   "Missing scope",
+			ctx:    auth.WithPrincipal(context.Background(), auth.Principal{ID: "user"}),
+			method: "/ledger.v1.TransactionService/CreateCredit",
+			code:   codes.PermissionDenied,
+		},
+		{name: "Method without scope", ctx: ctx, method: "/ledger.v1.TransactionService/CreateDebit",
+			code: codes.PermissionDenied},
+		{name: "Ignored method", ctx: ctx, method: "/grpc.health.v1.Health/Check", code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := interceptor(tt.ctx, "req", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package interceptors
+
+import (
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataCarrier adapts the metadata of the gRPC calls to the propagation.TextMapCarrier, the same way
+// propagation.HeaderCarrier does with the HTTP headers.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
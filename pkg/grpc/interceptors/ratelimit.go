@@ -0,0 +1,148 @@
+package interceptors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimitKey is a part of the key the calls of a RateLimitPolicy are counted by.
+type RateLimitKey string
+
+const (
+	// RateLimitByPrincipal counts the calls of each authenticated principal.
+	RateLimitByPrincipal RateLimitKey = "principal"
+	// RateLimitByIP counts the calls of each remote address.
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitByMethod counts the calls of each method.
+	RateLimitByMethod RateLimitKey = "method"
+	// RateLimitByAccount counts the calls of each account, see RateLimitPolicy.AccountID.
+	RateLimitByAccount RateLimitKey = "account"
+)
+
+// RateLimitPolicy is the rate of the calls of the methods, counted by the keys. The keys are the ones of the policies
+// of the HTTP API, so the policies of the same name and keys share their windows with them.
+type RateLimitPolicy struct {
+	// Name identifies the windows of the policy, the policies of the same name share them.
+	Name string
+	Rate ratelimit.Rate
+	Keys []RateLimitKey
+	// Methods are the methods counted by the policy, all of them when it is empty.
+	Methods []string
+	// AccountID returns the account of the request for RateLimitByAccount.
+	AccountID func(req interface{}) string
+}
+
+// NewRateLimitUnaryServerInterceptor fails with ResourceExhausted the calls over the rate of the policy, with the
+// ratelimit-* headers in all the responses and retry-after in the refused ones. It goes after the authentication
+// interceptor to count by principal, the calls are allowed when the limiter fails and when the rate is zero.
+func NewRateLimitUnaryServerInterceptor(
+	limiter ratelimit.Limiter,
+	policy RateLimitPolicy,
+	ignoreMethods ...string,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if policy.Rate.IsZero() || !policy.counts(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		for _, ignoreMethod := range ignoreMethods {
+			if info.FullMethod == ignoreMethod {
+				return handler(ctx, req)
+			}
+		}
+
+		key := rateLimitKey(ctx, req, info.FullMethod, policy)
+		result, err := limiter.Allow(ctx, key, policy.Rate)
+		if err != nil {
+			zapctx.L(ctx).Error("ratelimit_allow_error", zap.String("key", key), zap.Error(err))
+			return handler(ctx, req)
+		}
+
+		reset := strconv.Itoa(int(math.Ceil(result.Reset.Seconds())))
+		header := metadata.Pairs(
+			"ratelimit-limit", strconv.Itoa(result.Limit),
+			"ratelimit-remaining", strconv.Itoa(result.Remaining),
+			"ratelimit-reset", reset,
+			"ratelimit-policy",
+			fmt.Sprintf("%d;w=%d", policy.Rate.Limit, int(math.Ceil(policy.Rate.Window.Seconds()))),
+		)
+
+		if !result.Allowed {
+			zapctx.L(ctx).Info("ratelimit_exceeded", zap.String("policy", policy.Name), zap.String("key", key))
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("ratelimit.policy", policy.Name))
+
+			header.Set("retry-after", reset)
+			_ = grpc.SetHeader(ctx, header)
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+
+		_ = grpc.SetHeader(ctx, header)
+		return handler(ctx, req)
+	}
+}
+
+// counts returns if the policy counts the calls of the method.
+func (p RateLimitPolicy) counts(method string) bool {
+	if len(p.Methods) == 0 {
+		return true
+	}
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// rateLimitKey returns the key of the window of the call in the policy.
+func rateLimitKey(ctx context.Context, req interface{}, method string, policy RateLimitPolicy) string {
+	var key strings.Builder
+	key.WriteString("ratelimit:")
+	key.WriteString(policy.Name)
+
+	for _, part := range policy.Keys {
+		var value string
+		switch part {
+		case RateLimitByPrincipal:
+			if principal, ok := auth.PrincipalFromContext(ctx); ok {
+				value = principal.ID
+			}
+		case RateLimitByIP:
+			if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+				value = p.Addr.String()
+				if host, _, err := net.SplitHostPort(value); err == nil {
+					value = host
+				}
+			}
+		case RateLimitByMethod:
+			value = method
+		case RateLimitByAccount:
+			if policy.AccountID != nil {
+				value = policy.AccountID(req)
+			}
+		}
+		_, _ = fmt.Fprintf(&key, ":%s=%s", part, value)
+	}
+
+	return key.String()
+}
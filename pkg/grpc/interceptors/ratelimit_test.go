@@ -0,0 +1,119 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type _limiterTest struct {
+	keys   []string
+	result ratelimit.Result
+	err    error
+}
+
+func (l *_limiterTest) Allow(_ context.Context, key string, _ ratelimit.Rate) (ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return l.result, l.err
+}
+
+func TestRateLimitUnaryServerInterceptor(t *testing.T) {
+	const method = "/ledger.v1.TransactionService/CreateP2P"
+	policy := RateLimitPolicy{
+		Name:    "p2p",
+		Rate:    ratelimit.Rate{Limit: 2, Window: time.Minute},
+		Keys:    []RateLimitKey{RateLimitByPrincipal, RateLimitByIP, RateLimitByMethod, RateLimitByAccount},
+		Methods: []string{method},
+		AccountID: func(req interface{}) string {
+			return req.(string)
+		},
+	}
+
+	var called bool
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		called = true
+		return "resp", nil
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "key-1"})
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}})
+
+	t.Run("Allowed call", func(t *testing.T) {
+		called = false
+		limiter := &_limiterTest{result: ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1}}
+		interceptor := NewRateLimitUnaryServerInterceptor(limiter, policy)
+
+		_, err := interceptor(ctx, "account-1", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		assert.NoError(t, err)
+		assert.True(t, called)
+		assert.Equal(
+			t,
+			[]string{"ratelimit:p2p:principal=key-1:ip=10.0.0.1:method=" + method + ":account=account-1"},
+			limiter.keys,
+		)
+	})
+
+	t.Run("Refused call", func(t *testing.T) {
+		called = false
+		limiter := &_limiterTest{result: ratelimit.Result{Limit: 2, Reset: 1500 * time.Millisecond}}
+		interceptor := NewRateLimitUnaryServerInterceptor(limiter, policy)
+
+		_, err := interceptor(ctx, "account-1", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.False(t, called)
+	})
+
+	t.Run("Limiter failure", func(t *testing.T) {
+		called = false
+		limiter := &_limiterTest{err: errors.New("failure")}
+		interceptor := NewRateLimitUnaryServerInterceptor(limiter, policy)
+
+		_, err := interceptor(ctx, "account-1", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("Method out of the policy", func(t *testing.T) {
+		limiter := &_limiterTest{}
+		interceptor := NewRateLimitUnaryServerInterceptor(limiter, policy)
+
+		info := &grpc.UnaryServerInfo{FullMethod: "/ledger.v1.TransactionService/CreateCredit"}
+		_, err := interceptor(ctx, "account-1", info, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, limiter.keys)
+	})
+
+	t.Run("Ignored method", func(t *testing.T) {
+		limiter := &_limiterTest{}
+		interceptor := NewRateLimitUnaryServerInterceptor(
+			limiter,
+			RateLimitPolicy{Name: "ip", Rate: policy.Rate, Keys: []RateLimitKey{RateLimitByIP}},
+			"/grpc.health.v1.Health/Check",
+		)
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, limiter.keys)
+	})
+
+	t.Run("Zero rate", func(t *testing.T) {
+		limiter := &_limiterTest{}
+		interceptor := NewRateLimitUnaryServerInterceptor(limiter, RateLimitPolicy{Name: "none"})
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+		assert.NoError(t, err)
+		assert.Empty(t, limiter.keys)
+	})
+}
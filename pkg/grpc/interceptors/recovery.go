@@ -0,0 +1,29 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func NewRecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				zapctx.L(ctx).Error("recovery_panic", zap.Reflect("error", r))
+				resp, err = nil, status.Error(codes.Internal, codes.Internal.String())
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryUnaryServerInterceptor(t *testing.T) {
+	interceptor := NewRecoveryUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/ledger.v1.HolderService/GetHolder"}
+
+	t.Run("Handle panic", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+			panic("boom")
+		})
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("Handle success", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), "req", info, func(context.Context, interface{}) (interface{}, error) {
+			return "resp", nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "resp", resp)
+	})
+}
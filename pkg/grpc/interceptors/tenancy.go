@@ -0,0 +1,57 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const tenantMetadata = "x-tenant-id"
+
+// NewTenancyUnaryServerInterceptor resolves the tenant of the calls, the one of the principal or of the x-tenant-id
+// metadata, the same of the HTTP API, failing with PermissionDenied when the principal is bound to another tenant and
+// with InvalidArgument when it is unknown. It goes after the authentication interceptor, the tenant is put in the
+// context of the call.
+func NewTenancyUnaryServerInterceptor(tenants tenancy.Tenants, ignoreMethods ...string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		for _, ignoreMethod := range ignoreMethods {
+			if info.FullMethod == ignoreMethod {
+				return handler(ctx, req)
+			}
+		}
+
+		var requested string
+		md, _ := metadata.FromIncomingContext(ctx)
+		if ids := md.Get(tenantMetadata); len(ids) > 0 {
+			requested = ids[0]
+		}
+
+		tenant, err := tenants.Resolve(ctx, requested)
+		if err != nil {
+			zapctx.L(ctx).Info("tenancy_resolve_error", zap.String("tenant_id", requested), zap.Error(err))
+
+			if errors.Is(err, tenancy.ErrTenantMismatch) {
+				return nil, status.Error(codes.PermissionDenied, err.Error())
+			}
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", tenant.ID))
+
+		return handler(tenancy.WithTenant(ctx, tenant), req)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenancyUnaryServerInterceptor(t *testing.T) {
+	brandA := tenancy.Tenant{ID: "brand-a", Agency: "0002", DailyDebitLimit: 100}
+	interceptor := NewTenancyUnaryServerInterceptor(
+		tenancy.Tenants{tenancy.Default.ID: tenancy.Default, brandA.ID: brandA},
+		"/grpc.health.v1.Health/Check",
+	)
+	info := &grpc.UnaryServerInfo{FullMethod: "/ledger.v1.HolderService/GetHolder"}
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		tenant, _ := tenancy.FromContext(ctx)
+		return tenant, nil
+	}
+	partner := auth.WithPrincipal(context.Background(), auth.Principal{ID: "partner", TenantID: "brand-a"})
+
+	t.Run("Default tenant", func(t *testing.T) {
+		resp, err := interceptor(context.Background(), "req", info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, tenancy.Default, resp)
+	})
+
+	t.Run("Tenant of the metadata", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "brand-a"))
+		resp, err := interceptor(ctx, "req", info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, brandA, resp)
+	})
+
+	t.Run("Tenant of the principal", func(t *testing.T) {
+		resp, err := interceptor(partner, "req", info, handler)
+		assert.NoError(t, err)
+		assert.Equal(t, brandA, resp)
+	})
+
+	t.Run("Other tenant of the principal", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(partner, metadata.Pairs("x-tenant-id", "default"))
+		_, err := interceptor(ctx, "req", info, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Unknown tenant", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", "brand-c"))
+		_, err := interceptor(ctx, "req", info, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
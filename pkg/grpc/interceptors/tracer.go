@@ -0,0 +1,63 @@
+package interceptors
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewTracerUnaryServerInterceptor starts a span for each call named by its full method (/package.Service/Method),
+// continuing the trace propagated in the metadata of the call. The calls of the ignoreMethods are not traced.
+func NewTracerUnaryServerInterceptor(t tracer.Tracer, ignoreMethods ...string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		for _, ignoreMethod := range ignoreMethods {
+			if info.FullMethod == ignoreMethod {
+				return handler(ctx, req)
+			}
+		}
+
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			md = metadata.MD{}
+		}
+		ctx = t.Extract(ctx, metadataCarrier(md))
+
+		service, method := splitFullMethod(info.FullMethod)
+		ctx, span := t.SpanName(
+			ctx,
+			strings.TrimPrefix(info.FullMethod, "/"),
+			semconv.RPCSystemGRPC,
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(method),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return resp, err
+	}
+}
+
+// splitFullMethod splits /package.Service/Method in the service and method names.
+func splitFullMethod(fullMethod string) (string, string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(fullMethod, '/'); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
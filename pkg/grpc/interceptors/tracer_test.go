@@ -0,0 +1,84 @@
+//go:build unit
+
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestTracerUnaryServerInterceptor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTracing := tracer.NewMockTracer(ctrl)
+
+	t.Run("Ignored method", func(t *testing.T) {
+		interceptor := NewTracerUnaryServerInterceptor(mockTracing, "/grpc.health.v1.Health/Check")
+
+		resp, err := interceptor(
+			context.Background(),
+			"req",
+			&grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"},
+			func(context.Context, interface{}) (interface{}, error) {
+				return "resp", nil
+			},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "resp", resp)
+	})
+
+	t.Run("Traced method", func(t *testing.T) {
+		traceparent := "00-" + gofakeit.Regex("[0-9a-f]{32}") + "-" + gofakeit.Regex("[0-9a-f]{16}") + "-01"
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+
+		mockTracing.
+			EXPECT().
+			Extract(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, carrier tracer.TextMapCarrier) context.Context {
+				assert.Equal(t, traceparent, carrier.Get("traceparent"))
+				assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+				return ctx
+			})
+
+		mockTracing.
+			EXPECT().
+			SpanName(ctx, "ledger.v1.TransactionService/CreateDebit", gomock.Any()).
+			Return(ctx, tracer.TSpan{Span: trace.SpanFromContext(ctx)})
+
+		interceptor := NewTracerUnaryServerInterceptor(mockTracing)
+
+		expectedErr := status.Error(codes.FailedPrecondition, "insufficient funds")
+		resp, err := interceptor(
+			ctx,
+			"req",
+			&grpc.UnaryServerInfo{FullMethod: "/ledger.v1.TransactionService/CreateDebit"},
+			func(context.Context, interface{}) (interface{}, error) {
+				return nil, expectedErr
+			},
+		)
+		assert.True(t, errors.Is(err, expectedErr))
+		assert.Nil(t, resp)
+	})
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	service, method := splitFullMethod("/ledger.v1.HolderService/GetHolder")
+	assert.Equal(t, "ledger.v1.HolderService", service)
+	assert.Equal(t, "GetHolder", method)
+
+	service, method = splitFullMethod("GetHolder")
+	assert.Empty(t, service)
+	assert.Equal(t, "GetHolder", method)
+}
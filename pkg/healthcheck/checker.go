@@ -0,0 +1,201 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout is the timeout of the checks of the components without one.
+const DefaultTimeout = 2 * time.Second
+
+var ErrTimeout = errors.New("the check timed out")
+
+// Status is the status of a component or of the whole application.
+type Status string
+
+const (
+	StatusUp Status = "up"
+	// StatusDegraded is the status of the application with non-critical components down, it still serves.
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Component is a dependency checked by the Checker. The application is down when a critical component is down, and
+// degraded when only non-critical ones are.
+type Component struct {
+	Name     string
+	Check    HealthCheck
+	Critical bool
+	// Timeout of the check, DefaultTimeout when zero.
+	Timeout time.Duration
+}
+
+// ComponentReport is the result of the check of a component.
+type ComponentReport struct {
+	Name      string  `json:"name"`
+	Status    Status  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	// Error of the check, left out of the JSON because the reports are replied to unauthenticated probes.
+	Error string `json:"-"`
+}
+
+// Report is the result of the checks of all the components, in the order they were given.
+type Report struct {
+	Status     Status            `json:"status"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	Components []ComponentReport `json:"components"`
+}
+
+// Err returns an error with the critical components down, nil when the application is up or degraded.
+func (r Report) Err() error {
+	if r.Status != StatusDown {
+		return nil
+	}
+
+	var failures []string
+	for _, component := range r.Components {
+		if component.Critical && component.Status == StatusDown {
+			failures = append(failures, fmt.Sprintf("%s: %s", component.Name, component.Error))
+		}
+	}
+	return errors.New(strings.Join(failures, "; "))
+}
+
+// Reporter is a HealthCheck that also reports the status of each component.
+type Reporter interface {
+	HealthCheck
+	ReadinessReport(ctx context.Context) Report
+	LivenessReport(ctx context.Context) Report
+}
+
+type cachedReport struct {
+	mu     sync.Mutex
+	report Report
+}
+
+type checker struct {
+	components []Component
+	ttl        time.Duration
+	now        func() time.Time
+	readiness  *cachedReport
+	liveness   *cachedReport
+}
+
+// NewChecker returns a Reporter that checks the components concurrently, each one with its timeout. The reports are
+// cached for the ttl, so frequent probes don't overload the dependencies, and the concurrent probes wait for the same
+// checks.
+func NewChecker(ttl time.Duration, components ...Component) Reporter {
+	return checker{
+		components: components,
+		ttl:        ttl,
+		now:        time.Now,
+		readiness:  &cachedReport{},
+		liveness:   &cachedReport{},
+	}
+}
+
+func (c checker) Readiness(ctx context.Context) error {
+	return c.ReadinessReport(ctx).Err()
+}
+
+func (c checker) Liveness(ctx context.Context) error {
+	return c.LivenessReport(ctx).Err()
+}
+
+func (c checker) ReadinessReport(ctx context.Context) Report {
+	return c.report(ctx, c.readiness, HealthCheck.Readiness)
+}
+
+func (c checker) LivenessReport(ctx context.Context) Report {
+	return c.report(ctx, c.liveness, HealthCheck.Liveness)
+}
+
+func (c checker) report(
+	ctx context.Context,
+	cached *cachedReport,
+	check func(HealthCheck, context.Context) error,
+) Report {
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+
+	if !cached.report.CheckedAt.IsZero() && c.now().Sub(cached.report.CheckedAt) < c.ttl {
+		return cached.report
+	}
+
+	// The report is shared by the concurrent probes, so the checks are not canceled with the one that runs them.
+	ctx = context.WithoutCancel(ctx)
+
+	report := Report{
+		Status:     StatusUp,
+		CheckedAt:  c.now(),
+		Components: make([]ComponentReport, len(c.components)),
+	}
+
+	var wg sync.WaitGroup
+	for i, component := range c.components {
+		wg.Add(1)
+		go func(i int, component Component) {
+			defer wg.Done()
+			report.Components[i] = c.checkComponent(ctx, component, check)
+		}(i, component)
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != StatusDown {
+			continue
+		}
+		if component.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+
+	cached.report = report
+	return report
+}
+
+func (c checker) checkComponent(
+	ctx context.Context,
+	component Component,
+	check func(HealthCheck, context.Context) error,
+) ComponentReport {
+	timeout := component.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	result := make(chan error, 1)
+	go func() {
+		result <- check(component.Check, ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	report := ComponentReport{
+		Name:      component.Name,
+		Status:    StatusUp,
+		Critical:  component.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		report.Status = StatusDown
+		report.Error = err.Error()
+	}
+	return report
+}
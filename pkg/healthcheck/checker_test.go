@@ -0,0 +1,131 @@
+//go:build unit
+
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type _checkTest struct {
+	calls *int32
+	delay time.Duration
+	err   error
+}
+
+func (c _checkTest) Readiness(ctx context.Context) error {
+	if c.calls != nil {
+		atomic.AddInt32(c.calls, 1)
+	}
+	select {
+	case <-time.After(c.delay):
+		return c.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c _checkTest) Liveness(_ context.Context) error {
+	return nil
+}
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("connection refused")
+
+	t.Run("Up", func(t *testing.T) {
+		report := NewChecker(0,
+			Component{Name: "database", Check: _checkTest{}, Critical: true},
+			Component{Name: "otel_collector", Check: _checkTest{}},
+		).ReadinessReport(ctx)
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.NoError(t, report.Err())
+		require.Len(t, report.Components, 2)
+		assert.Equal(t, "database", report.Components[0].Name)
+		assert.Equal(t, StatusUp, report.Components[0].Status)
+		assert.True(t, report.Components[0].Critical)
+	})
+
+	t.Run("Degraded by a non-critical component", func(t *testing.T) {
+		checker := NewChecker(0,
+			Component{Name: "database", Check: _checkTest{}, Critical: true},
+			Component{Name: "otel_collector", Check: _checkTest{err: failure}},
+		)
+		report := checker.ReadinessReport(ctx)
+
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, ComponentReport{
+			Name:      "otel_collector",
+			Status:    StatusDown,
+			LatencyMS: report.Components[1].LatencyMS,
+			Error:     "connection refused",
+		}, report.Components[1])
+		body, err := json.Marshal(report)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), "connection refused")
+		assert.NoError(t, checker.Readiness(ctx))
+	})
+
+	t.Run("Down by a critical component", func(t *testing.T) {
+		checker := NewChecker(0,
+			Component{Name: "database", Check: _checkTest{err: failure}, Critical: true},
+			Component{Name: "otel_collector", Check: _checkTest{err: failure}},
+		)
+
+		assert.Equal(t, StatusDown, checker.ReadinessReport(ctx).Status)
+		assert.EqualError(t, checker.Readiness(ctx), "database: connection refused")
+		assert.NoError(t, checker.Liveness(ctx))
+	})
+
+	t.Run("Concurrent checks with timeouts", func(t *testing.T) {
+		start := time.Now()
+		report := NewChecker(0,
+			Component{Name: "slow", Check: _checkTest{delay: time.Second}, Timeout: 50 * time.Millisecond},
+			Component{Name: "a", Check: _checkTest{delay: 40 * time.Millisecond}},
+			Component{Name: "b", Check: _checkTest{delay: 40 * time.Millisecond}},
+		).ReadinessReport(ctx)
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, StatusDegraded, report.Status)
+		assert.Equal(t, StatusDown, report.Components[0].Status)
+		assert.Equal(t, StatusUp, report.Components[1].Status)
+		assert.Equal(t, StatusUp, report.Components[2].Status)
+	})
+
+	t.Run("Cached reports", func(t *testing.T) {
+		var calls int32
+		now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		checker := NewChecker(time.Second, Component{Name: "database", Check: _checkTest{calls: &calls}}).(checker)
+		checker.now = func() time.Time { return now }
+
+		checker.ReadinessReport(ctx)
+		checker.ReadinessReport(ctx)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		now = now.Add(time.Second)
+		checker.ReadinessReport(ctx)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestOtelCollector(t *testing.T) {
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := listener.Addr().String()
+
+	assert.NoError(t, NewOtelCollector(endpoint).Readiness(ctx))
+
+	require.NoError(t, listener.Close())
+	assert.Error(t, NewOtelCollector(endpoint).Readiness(ctx))
+}
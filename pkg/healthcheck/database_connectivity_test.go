@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/stretchr/testify/assert"
@@ -19,12 +20,12 @@ func TestDatabaseConnectivity(t *testing.T) {
 	assert.NoError(t, err)
 	defer terminate(ctx)
 
-	db, err := database.New(tracer.NewNoop(), url, url)
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 	assert.NoError(t, err)
 	defer db.Stop(ctx)
 
 	t.Run("Validate readiness database when there is connectivity", func(t *testing.T) {
-		healthCheck := NewDatabaseConnectivity(db.Master())
+		healthCheck := NewDatabaseConnectivity(db.Master(ctx))
 
 		err := healthCheck.Readiness(ctx)
 
@@ -32,7 +33,7 @@ func TestDatabaseConnectivity(t *testing.T) {
 	})
 
 	t.Run("Validate liveness database when there is connectivity", func(t *testing.T) {
-		healthCheck := NewDatabaseConnectivity(db.Master())
+		healthCheck := NewDatabaseConnectivity(db.Master(ctx))
 
 		err := healthCheck.Liveness(ctx)
 
@@ -41,10 +42,10 @@ func TestDatabaseConnectivity(t *testing.T) {
 
 	t.Run("Do not validate readiness database when there is no connectivity", func(t *testing.T) {
 		url := "postgres://localhost:5432/ledger-exp?sslmode=disable"
-		db, err := database.New(tracer.NewNoop(), url, url)
+		db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 		assert.NoError(t, err)
 
-		healthCheck := NewDatabaseConnectivity(db.Master())
+		healthCheck := NewDatabaseConnectivity(db.Master(ctx))
 
 		err = healthCheck.Readiness(ctx)
 
@@ -53,10 +54,10 @@ func TestDatabaseConnectivity(t *testing.T) {
 
 	t.Run("Do not validate liveness database when there is no connectivity", func(t *testing.T) {
 		url := "postgres://localhost:5432/ledger-exp?sslmode=disable"
-		db, err := database.New(tracer.NewNoop(), url, url)
+		db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 		assert.NoError(t, err)
 
-		healthCheck := NewDatabaseConnectivity(db.Master())
+		healthCheck := NewDatabaseConnectivity(db.Master(ctx))
 
 		err = healthCheck.Liveness(ctx)
 
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
 	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
 	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
 	"github.com/stretchr/testify/assert"
@@ -19,12 +20,12 @@ func TestDatabaseMigration(t *testing.T) {
 	assert.NoError(t, err)
 	defer terminate(ctx)
 
-	db, err := database.New(tracer.NewNoop(), url, url)
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
 	assert.NoError(t, err)
 	defer db.Stop(ctx)
 
 	migrationTable := "migration"
-	_, err = db.Master().
+	_, err = db.Master(ctx).
 		ExecContext(ctx, `
 CREATE TABLE migration (
     version  integer PRIMARY KEY,
@@ -35,7 +36,7 @@ INSERT INTO migration (version, dirty) VALUES (?, ?);
 	assert.NoError(t, err)
 
 	t.Run("Validate readiness database when migration table is not dirty", func(t *testing.T) {
-		healthCheck := NewDatabaseMigration(db.Master(), migrationTable)
+		healthCheck := NewDatabaseMigration(db.Master(ctx), migrationTable)
 
 		err := healthCheck.Readiness(ctx)
 
@@ -43,21 +44,21 @@ INSERT INTO migration (version, dirty) VALUES (?, ?);
 	})
 
 	t.Run("Validate liveness even when when migration table is not dirty", func(t *testing.T) {
-		healthCheck := NewDatabaseMigration(db.Master(), migrationTable)
+		healthCheck := NewDatabaseMigration(db.Master(ctx), migrationTable)
 
 		err := healthCheck.Liveness(ctx)
 
 		assert.NoError(t, err)
 	})
 
-	_, err = db.Master().
+	_, err = db.Master(ctx).
 		ExecContext(ctx, `
 UPDATE migration SET dirty = ?;
 `, true)
 	assert.NoError(t, err)
 
 	t.Run("Do not validate readiness database when migration table is dirty", func(t *testing.T) {
-		healthCheck := NewDatabaseMigration(db.Master(), migrationTable)
+		healthCheck := NewDatabaseMigration(db.Master(ctx), migrationTable)
 
 		err := healthCheck.Readiness(ctx)
 
@@ -65,7 +66,7 @@ UPDATE migration SET dirty = ?;
 	})
 
 	t.Run("Validate liveness even when there's database migration is dirty", func(t *testing.T) {
-		healthCheck := NewDatabaseMigration(db.Master(), migrationTable)
+		healthCheck := NewDatabaseMigration(db.Master(ctx), migrationTable)
 
 		err := healthCheck.Liveness(ctx)
 
@@ -0,0 +1,29 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+type OtelCollector struct {
+	endpoint string
+	dialer   net.Dialer
+}
+
+// NewOtelCollector checks the OpenTelemetry collector of the endpoint (host:port) accepts connections.
+func NewOtelCollector(endpoint string) OtelCollector {
+	return OtelCollector{endpoint: endpoint}
+}
+
+func (h OtelCollector) Readiness(ctx context.Context) error {
+	conn, err := h.dialer.DialContext(ctx, "tcp", h.endpoint)
+	if err != nil {
+		return fmt.Errorf("failed connect otel collector: %w", err)
+	}
+	return conn.Close()
+}
+
+func (h OtelCollector) Liveness(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+)
+
+type ReplicaLag struct {
+	db     database.DB
+	maxLag time.Duration
+}
+
+// NewReplicaLag checks the replica is at most maxLag behind the master.
+func NewReplicaLag(db database.DB, maxLag time.Duration) ReplicaLag {
+	return ReplicaLag{db: db, maxLag: maxLag}
+}
+
+func (h ReplicaLag) Readiness(ctx context.Context) error {
+	lag, err := database.ReplicationLag(ctx, h.db)
+	if err != nil {
+		return fmt.Errorf("failed query replica lag: %w", err)
+	}
+
+	if lag > h.maxLag {
+		return fmt.Errorf("replica lag of %s is over %s", lag.Round(time.Millisecond), h.maxLag)
+	}
+	return nil
+}
+
+func (h ReplicaLag) Liveness(_ context.Context) error {
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build integration
+
+package healthcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/database"
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"github.com/dalmarcogd/ledger-exp/pkg/testingcontainers"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaLag(t *testing.T) {
+	ctx := context.Background()
+
+	url, terminate, err := testingcontainers.NewPostgresContainer()
+	assert.NoError(t, err)
+	defer terminate(ctx)
+
+	db, err := database.New(tracer.NewNoop(), metrics.NewNoop(), database.Config{MasterURL: url})
+	assert.NoError(t, err)
+	defer db.Stop(ctx)
+
+	t.Run("Validate readiness of the master, it has no lag", func(t *testing.T) {
+		healthCheck := NewReplicaLag(db.Replica(ctx), time.Second)
+
+		assert.NoError(t, healthCheck.Readiness(ctx))
+		assert.NoError(t, healthCheck.Liveness(ctx))
+	})
+}
@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// APIKeyHeader is the header of the API keys.
+	APIKeyHeader = "X-API-Key"
+	bearerPrefix = "Bearer "
+)
+
+// NewAuthenticationMiddleware returns a middleware that authenticates the requests by the API key of the X-API-Key
+// header or by the bearer token of the Authorization header, replying 401 when none is valid. The principal is put
+// in the context of the request, and so in its logs and span. The bearer tokens are refused when tokens is nil.
+func NewAuthenticationMiddleware(apiKeys, tokens auth.Authenticator, ignorePaths ...string) Middleware {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := request.Context()
+			for _, ignorePath := range ignorePaths {
+				if request.URL.Path == ignorePath {
+					handler.ServeHTTP(writer, request)
+					return
+				}
+			}
+
+			authenticator, credential := credentialOf(request, apiKeys, tokens)
+			if authenticator == nil {
+				zapctx.L(ctx).Info("authentication_missing_credentials")
+				writeUnauthorized(writer, request, "missing credentials")
+				return
+			}
+
+			principal, err := authenticator.Authenticate(ctx, credential)
+			if err != nil {
+				if !errors.Is(err, auth.ErrInvalidCredentials) {
+					zapctx.L(ctx).Error("authentication_error", zap.Error(err))
+					problem.Write(
+						writer,
+						request,
+						problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error"),
+					)
+					return
+				}
+				zapctx.L(ctx).Info("authentication_invalid_credentials")
+				writeUnauthorized(writer, request, "invalid credentials")
+				return
+			}
+
+			trace.SpanFromContext(ctx).SetAttributes(
+				semconv.EnduserIDKey.String(principal.ID),
+				attribute.String("enduser.type", string(principal.Type)),
+			)
+
+			handler.ServeHTTP(writer, request.WithContext(auth.WithPrincipal(ctx, principal)))
+		})
+	}
+}
+
+// credentialOf returns the credential of the request and its authenticator, the API key has precedence over the
+// bearer token.
+func credentialOf(request *http.Request, apiKeys, tokens auth.Authenticator) (auth.Authenticator, string) {
+	if key := request.Header.Get(APIKeyHeader); key != "" {
+		return apiKeys, key
+	}
+
+	authorization := request.Header.Get("Authorization")
+	if tokens != nil && len(authorization) > len(bearerPrefix) &&
+		strings.EqualFold(authorization[:len(bearerPrefix)], bearerPrefix) {
+		return tokens, strings.TrimSpace(authorization[len(bearerPrefix):])
+	}
+
+	return nil, ""
+}
+
+func writeUnauthorized(writer http.ResponseWriter, request *http.Request, message string) {
+	writer.Header().Set("WWW-Authenticate", `Bearer, ApiKey header="`+APIKeyHeader+`"`)
+	problem.Write(writer, request, problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, message))
+}
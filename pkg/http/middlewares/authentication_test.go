@@ -0,0 +1,85 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+type _authenticatorTest map[string]auth.Principal
+
+func (a _authenticatorTest) Authenticate(_ context.Context, credential string) (auth.Principal, error) {
+	if credential == "broken" {
+		return auth.Principal{}, errors.New("database is down")
+	}
+	principal, ok := a[credential]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidCredentials
+	}
+	return principal, nil
+}
+
+func TestNewAuthenticationMiddleware(t *testing.T) {
+	apiKeys := _authenticatorTest{
+		"lk_key": {ID: "key", Type: auth.APIKeyPrincipal},
+		"broken": {},
+	}
+	tokens := _authenticatorTest{"token": {ID: "subject", Type: auth.JWTPrincipal}}
+
+	var principal auth.Principal
+	h := func(w http.ResponseWriter, r *http.Request) {
+		principal, _ = auth.PrincipalFromContext(r.Context())
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		header    string
+		value     string
+		tokens    auth.Authenticator
+		status    int
+		principal auth.Principal
+	}{
+		{name: "API key", path: "/v1/holders", header: APIKeyHeader, value: "lk_key", tokens: tokens,
+			status: http.StatusOK, principal: apiKeys["lk_key"]},
+		{name: "Bearer token", path: "/v1/holders", header: "Authorization", value: "Bearer token", tokens: tokens,
+			status: http.StatusOK, principal: tokens["token"]},
+		{name: "Bearer tokens refused", path: "/v1/holders", header: "Authorization", value: "Bearer token",
+			status: http.StatusUnauthorized},
+		{name: "Invalid API key", path: "/v1/holders", header: APIKeyHeader, value: "lk_other", tokens: tokens,
+			status: http.StatusUnauthorized},
+		{name: "Invalid token", path: "/v1/holders", header: "Authorization", value: "Bearer other", tokens: tokens,
+			status: http.StatusUnauthorized},
+		{name: "Missing credentials", path: "/v1/holders", tokens: tokens, status: http.StatusUnauthorized},
+		{name: "Authentication error", path: "/v1/holders", header: APIKeyHeader, value: "broken", tokens: tokens,
+			status: http.StatusInternalServerError},
+		{name: "Ignored path", path: "/liveness", tokens: tokens, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal = auth.Principal{}
+			chain := Chain(_handleHTTPTest{h}, NewAuthenticationMiddleware(apiKeys, tt.tokens, "/liveness"))
+
+			request := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			if tt.header != "" {
+				request.Header.Set(tt.header, tt.value)
+			}
+			response := httptest.NewRecorder()
+			chain.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.status, response.Code)
+			assert.Equal(t, tt.principal, principal)
+			if tt.status == http.StatusUnauthorized {
+				assert.NotEmpty(t, response.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
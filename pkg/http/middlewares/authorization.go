@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+)
+
+// NewAuthorizationMiddleware returns a middleware that replies 403 to the principals lacking one of the scopes, it
+// goes after the authentication middleware on each route.
+func NewAuthorizationMiddleware(scopes ...authz.Scope) Middleware {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if err := authz.Authorize(request.Context(), scopes...); err != nil {
+				problem.Write(writer, request, problem.New(http.StatusForbidden, problem.CodePermissionDenied, err.Error()))
+				return
+			}
+
+			handler.ServeHTTP(writer, request)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/authz"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthorizationMiddleware(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}
+	chain := Chain(_handleHTTPTest{h}, NewAuthorizationMiddleware(authz.ScopeTransactionsCredit))
+
+	tests := []struct {
+		name   string
+		scopes []string
+		status int
+	}{
+		{name: "Granted scope", scopes: []string{"transactions:credit"}, status: http.StatusOK},
+		{name: "Missing scope", scopes: []string{"transactions:debit"}, status: http.StatusForbidden},
+		{name: "No scopes", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "partner", Scopes: tt.scopes})
+			request := httptest.NewRequest(http.MethodPost, "/v1/transactions/credits", http.NoBody).WithContext(ctx)
+			response := httptest.NewRecorder()
+			chain.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.status, response.Code)
+		})
+	}
+}
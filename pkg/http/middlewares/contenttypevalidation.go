@@ -10,6 +10,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"go.uber.org/zap"
 )
@@ -58,26 +59,42 @@ func validateContentType(
 
 			if ct == "" {
 				zapctx.L(ctx).Error("missing_content_type")
-				writer.WriteHeader(http.StatusBadRequest)
+				problem.Write(
+					writer,
+					request,
+					problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "missing content type"),
+				)
 				return
 			}
 
 			mt, _, err := mime.ParseMediaType(ct)
 			if err != nil {
 				zapctx.L(ctx).Error("invalid_content_type", zap.Error(err))
-				writer.WriteHeader(http.StatusBadRequest)
+				problem.Write(
+					writer,
+					request,
+					problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "invalid content type"),
+				)
 				return
 			}
 
 			if !acceptedContentType(mt, acceptedContentTypes) {
 				zapctx.L(ctx).Error("unaccepted_content_type", zap.String("content-type", mt))
-				writer.WriteHeader(http.StatusUnsupportedMediaType)
+				problem.Write(
+					writer,
+					request,
+					problem.New(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType, "unsupported content type"),
+				)
 				return
 			}
 
 			if !validContentBody(request, mt) {
 				zapctx.L(ctx).Error("invalid_content_body")
-				writer.WriteHeader(http.StatusBadRequest)
+				problem.Write(
+					writer,
+					request,
+					problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "invalid content body"),
+				)
 				return
 			}
 
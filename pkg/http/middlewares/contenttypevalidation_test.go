@@ -72,10 +72,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -87,10 +97,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "application/")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -102,10 +122,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "text/css")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusUnsupportedMediaType).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -117,10 +147,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "application/json")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -132,10 +172,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "text/plain")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -147,10 +197,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "application/json")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -164,10 +224,20 @@ func TestNewDefaultContentTypeValidator(t *testing.T) {
 		}
 		request.Header.Set("Content-Type", "text/css")
 
+		mockResponseWriter.
+			EXPECT().
+			Header().
+			Return(http.Header{}).
+			Times(1)
 		mockResponseWriter.
 			EXPECT().
 			WriteHeader(http.StatusBadRequest).
 			Times(1)
+		mockResponseWriter.
+			EXPECT().
+			Write(gomock.Any()).
+			Return(0, nil).
+			Times(1)
 
 		handlerFunc.ServeHTTP(mockResponseWriter, request)
 	})
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// NewMetricsHTTPMiddleware records the rate, the errors and the duration (RED) of the requests: the duration
+// histogram counts the requests by method, route and status code, the errors are the ones with a 5xx status code.
+// The route is the path with the ids obfuscated, as the name of the spans.
+func NewMetricsHTTPMiddleware(m metrics.Metrics, ignorePaths ...string) (Middleware, error) {
+	meter := m.Meter("github.com/dalmarcogd/ledger-exp/pkg/http/middlewares")
+
+	duration, err := meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithDescription("Duration of the HTTP requests."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := meter.Int64UpDownCounter(
+		"http.server.active_requests",
+		metric.WithDescription("Number of the HTTP requests in flight."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			for _, ignorePath := range ignorePaths {
+				if request.URL.Path == ignorePath {
+					handler.ServeHTTP(writer, request)
+					return
+				}
+			}
+
+			ctx := request.Context()
+			method := metric.WithAttributes(semconv.HTTPMethodKey.String(request.Method))
+			active.Add(ctx, 1, method)
+			defer active.Add(ctx, -1, method)
+
+			start := time.Now()
+			wrapWriter := &wrapResponseWriter{ResponseWriter: writer}
+			handler.ServeHTTP(wrapWriter, request)
+
+			statusCode := wrapWriter.statusCode
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+			duration.Record(
+				ctx,
+				time.Since(start).Seconds(),
+				metric.WithAttributeSet(attribute.NewSet(
+					semconv.HTTPMethodKey.String(request.Method),
+					semconv.HTTPRouteKey.String(obfuscateURL(request.URL.Path)),
+					semconv.HTTPStatusCodeKey.Int(statusCode),
+				)),
+			)
+		})
+	}, nil
+}
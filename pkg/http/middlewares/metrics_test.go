@@ -0,0 +1,52 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics/metricstest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+func TestNewMetricsHTTPMiddleware(t *testing.T) {
+	collector := metricstest.New()
+	middleware, err := NewMetricsHTTPMiddleware(collector, "/liveness")
+	require.NoError(t, err)
+
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/accounts/8b5b3fe2-5b8f-4fd4-b8b8-1c7e1b0f0a4e" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+	chain := Chain(_handleHTTPTest{h}, middleware)
+
+	for _, path := range []string{
+		"/v1/accounts",
+		"/v1/accounts",
+		"/v1/accounts/8b5b3fe2-5b8f-4fd4-b8b8-1c7e1b0f0a4e",
+		"/liveness",
+	} {
+		chain.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, http.NoBody))
+	}
+
+	assert.Equal(t, float64(3), collector.Sum(t, "http.server.request.duration"))
+	assert.Equal(t, float64(2), collector.Sum(
+		t,
+		"http.server.request.duration",
+		semconv.HTTPRouteKey.String("/v1/accounts"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusOK),
+	))
+	assert.Equal(t, float64(1), collector.Sum(
+		t,
+		"http.server.request.duration",
+		semconv.HTTPMethodKey.String(http.MethodGet),
+		semconv.HTTPRouteKey.String("/v1/accounts/{UUID}"),
+		semconv.HTTPStatusCodeKey.Int(http.StatusInternalServerError),
+	))
+	assert.Equal(t, float64(0), collector.Sum(t, "http.server.active_requests"))
+}
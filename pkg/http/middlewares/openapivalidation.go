@@ -0,0 +1,191 @@
+package middlewares
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"go.uber.org/zap"
+)
+
+const responseLocation = "response"
+
+// fieldError is one invalid field, its location is the path, query, header, cookie, body or response.
+type fieldError = problem.FieldError
+
+// NewOpenAPIValidationMiddleware returns a middleware that validates the requests against the OpenAPI document,
+// replying 422 with the invalid fields. The requests out of the document are passed through, the router of the
+// application replies them. When validateResponses is true the responses are buffered and validated too, replying
+// 500 with the invalid fields when they break the document, it is meant to the tests only.
+func NewOpenAPIValidationMiddleware(doc *openapi3.T, validateResponses bool) (Middleware, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// The credentials are verified by the authentication middleware.
+	options := &openapi3filter.Options{MultiError: true, AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := request.Context()
+
+			route, pathParams, err := router.FindRoute(request)
+			if err != nil {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    request,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			if err := openapi3filter.ValidateRequest(ctx, requestInput); err != nil {
+				zapctx.L(ctx).Error("openapi_request_validation_error", zap.Error(err))
+				writeValidationError(writer, request, http.StatusUnprocessableEntity, requestFieldErrors(err))
+				return
+			}
+
+			if !validateResponses {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			bw := &bufferResponseWriter{ResponseWriter: writer, statusCode: http.StatusOK}
+			handler.ServeHTTP(bw, request)
+
+			err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 bw.statusCode,
+				Header:                 writer.Header(),
+				Body:                   bw.body(),
+				Options:                options,
+			})
+			if err != nil && !unsupportedFormat(err) {
+				zapctx.L(ctx).Error("openapi_response_validation_error", zap.Error(err))
+				writer.Header().Del("Content-Length")
+				writer.Header().Del("Content-Disposition")
+				writeValidationError(writer, request, http.StatusInternalServerError, schemaFieldErrors(err, responseLocation))
+				return
+			}
+
+			bw.flush()
+		})
+	}, nil
+}
+
+// writeValidationError replies the invalid fields, of the request when the status is 422 and of the response when it
+// is 500.
+func writeValidationError(writer http.ResponseWriter, request *http.Request, status int, errs []fieldError) {
+	p := problem.New(status, problem.CodeValidationFailed, "invalid request")
+	if status != http.StatusUnprocessableEntity {
+		p = problem.New(status, problem.CodeInvalidResponse, "invalid response")
+	}
+	p.Errors = errs
+	problem.Write(writer, request, p)
+}
+
+// requestFieldErrors flattens the errors of the request validation into the invalid fields.
+func requestFieldErrors(err error) []fieldError {
+	if me, ok := err.(openapi3.MultiError); ok {
+		errs := make([]fieldError, 0, len(me))
+		for _, e := range me {
+			errs = append(errs, requestFieldErrors(e)...)
+		}
+		return errs
+	}
+
+	var re *openapi3filter.RequestError
+	if errors.As(err, &re) {
+		switch {
+		case re.Parameter != nil:
+			return []fieldError{{Field: re.Parameter.Name, Location: re.Parameter.In, Message: reason(re)}}
+		case re.RequestBody != nil:
+			return schemaFieldErrors(re.Err, "body")
+		}
+	}
+
+	return []fieldError{{Message: err.Error()}}
+}
+
+// schemaFieldErrors flattens the errors of the schemas into the invalid fields, the field is the JSON pointer of the
+// value joined by dots.
+func schemaFieldErrors(err error, location string) []fieldError {
+	if me, ok := err.(openapi3.MultiError); ok {
+		errs := make([]fieldError, 0, len(me))
+		for _, e := range me {
+			errs = append(errs, schemaFieldErrors(e, location)...)
+		}
+		return errs
+	}
+
+	var re *openapi3filter.ResponseError
+	if errors.As(err, &re) && re.Err != nil {
+		return schemaFieldErrors(re.Err, location)
+	}
+
+	var se *openapi3.SchemaError
+	if errors.As(err, &se) {
+		return []fieldError{{Field: strings.Join(se.JSONPointer(), "."), Location: location, Message: se.Reason}}
+	}
+
+	if errors.Is(err, openapi3filter.ErrInvalidRequired) {
+		return []fieldError{{Location: location, Message: "value is required but missing"}}
+	}
+
+	return []fieldError{{Location: location, Message: err.Error()}}
+}
+
+// reason is the reason of the error of the parameter, without the name of the parameter.
+func reason(re *openapi3filter.RequestError) string {
+	var se *openapi3.SchemaError
+	if errors.As(re.Err, &se) {
+		return se.Reason
+	}
+	if re.Err != nil {
+		return re.Err.Error()
+	}
+	return re.Reason
+}
+
+// unsupportedFormat tells if the body could not be validated because the document has no decoder to its content
+// type, e.g. the PDF documents.
+func unsupportedFormat(err error) bool {
+	var pe *openapi3filter.ParseError
+	return errors.As(err, &pe) && pe.Kind == openapi3filter.KindUnsupportedFormat
+}
+
+// bufferResponseWriter holds the response until it is validated, the headers are written to the underlying writer
+// since they are only sent with the status code.
+type bufferResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	buf        bytes.Buffer
+}
+
+func (w *bufferResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+}
+
+func (w *bufferResponseWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+func (w *bufferResponseWriter) body() io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(w.buf.Bytes()))
+}
+
+func (w *bufferResponseWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+}
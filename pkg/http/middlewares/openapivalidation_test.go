@@ -0,0 +1,201 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const openAPITestDocument = `
+openapi: 3.0.3
+info:
+  title: test
+  version: v1
+servers:
+  - url: /
+paths:
+  /holders:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  minLength: 1
+                age:
+                  type: integer
+      responses:
+        "201":
+          description: Created.
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: string
+  /holders/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: size
+          in: query
+          schema:
+            type: integer
+            maximum: 10
+      responses:
+        "200":
+          description: The holder.
+`
+
+func loadOpenAPITestDocument(t *testing.T) *openapi3.T {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(openAPITestDocument))
+	require.NoError(t, err)
+	return doc
+}
+
+func TestNewOpenAPIValidationMiddleware(t *testing.T) {
+	doc := loadOpenAPITestDocument(t)
+
+	created := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}
+
+	t.Run("Valid request", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, true)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/holders", strings.NewReader(`{"name":"john"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{created}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+		assert.JSONEq(t, `{"id":"1"}`, response.Body.String())
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, false)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodPost, "/holders", strings.NewReader(`{"name":"","age":"ten"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{created}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+		var body problem.Problem
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Equal(t, problem.CodeValidationFailed, body.Code)
+		assert.Equal(t, "/holders", body.Instance)
+		assert.ElementsMatch(
+			t,
+			[]string{"name", "age"},
+			[]string{body.Errors[0].Field, body.Errors[1].Field},
+		)
+		for _, fe := range body.Errors {
+			assert.Equal(t, "body", fe.Location)
+			assert.NotEmpty(t, fe.Message)
+		}
+	})
+
+	t.Run("Invalid parameters", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, false)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/holders/abc?size=20", http.NoBody)
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{created}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+		var body problem.Problem
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.ElementsMatch(
+			t,
+			[]fieldError{
+				{Field: "id", Location: "path", Message: body.Errors[0].Message},
+				{Field: "size", Location: "query", Message: body.Errors[1].Message},
+			},
+			body.Errors,
+		)
+	})
+
+	t.Run("Path out of the document", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, true)
+		require.NoError(t, err)
+
+		request := httptest.NewRequest(http.MethodGet, "/debug/pprof/", http.NoBody)
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusTeapot, response.Code)
+	})
+
+	t.Run("Invalid response", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, true)
+		require.NoError(t, err)
+
+		h := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name":"john"}`))
+		}
+
+		request := httptest.NewRequest(http.MethodPost, "/holders", strings.NewReader(`{"name":"john"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+		var body problem.Problem
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		assert.Equal(t, problem.CodeInvalidResponse, body.Code)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "response", body.Errors[0].Location)
+	})
+
+	t.Run("Responses not validated", func(t *testing.T) {
+		middleware, err := NewOpenAPIValidationMiddleware(doc, false)
+		require.NoError(t, err)
+
+		h := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		}
+
+		request := httptest.NewRequest(http.MethodPost, "/holders", strings.NewReader(`{"name":"john"}`))
+		request.Header.Set("Content-Type", "application/json")
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, middleware).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusCreated, response.Code)
+	})
+}
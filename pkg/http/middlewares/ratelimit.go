@@ -0,0 +1,172 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// RateLimitKey is a part of the key the requests of a RateLimitPolicy are counted by.
+type RateLimitKey string
+
+const (
+	// RateLimitByPrincipal counts the requests of each authenticated principal.
+	RateLimitByPrincipal RateLimitKey = "principal"
+	// RateLimitByIP counts the requests of each remote address.
+	RateLimitByIP RateLimitKey = "ip"
+	// RateLimitByRoute counts the requests of each method and route, see RateLimitPolicy.Route.
+	RateLimitByRoute RateLimitKey = "route"
+	// RateLimitByAccount counts the requests of each account, see RateLimitPolicy.AccountID.
+	RateLimitByAccount RateLimitKey = "account"
+)
+
+// RateLimitPolicy is the rate of the requests of a route, counted by the keys.
+type RateLimitPolicy struct {
+	// Name identifies the windows of the policy, the policies of the same name share them.
+	Name string
+	Rate ratelimit.Rate
+	Keys []RateLimitKey
+	// AccountID returns the account of the request for RateLimitByAccount, e.g. AccountFromJSONBody.
+	AccountID func(request *http.Request) string
+	// Route returns the route template of the request for RateLimitByRoute, e.g. /v1/accounts/:id, so the ids of the
+	// path do not open a window each. The path with the ids obfuscated as in the metrics is used when it is nil.
+	Route func(request *http.Request) string
+}
+
+// NewRateLimitMiddleware returns a middleware that replies 429 to the requests over the rate of the policy, with the
+// RateLimit-* headers in all the responses and Retry-After in the refused ones. It goes after the authentication
+// middleware to count by principal, the requests are allowed when the limiter fails and when the rate is zero.
+func NewRateLimitMiddleware(limiter ratelimit.Limiter, policy RateLimitPolicy, ignorePaths ...string) Middleware {
+	return func(handler http.Handler) http.Handler {
+		if policy.Rate.IsZero() {
+			return handler
+		}
+
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := request.Context()
+			for _, ignorePath := range ignorePaths {
+				if request.URL.Path == ignorePath {
+					handler.ServeHTTP(writer, request)
+					return
+				}
+			}
+
+			key := rateLimitKey(request, policy)
+			result, err := limiter.Allow(ctx, key, policy.Rate)
+			if err != nil {
+				zapctx.L(ctx).Error("ratelimit_allow_error", zap.String("key", key), zap.Error(err))
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			reset := int(math.Ceil(result.Reset.Seconds()))
+			writer.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			writer.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			writer.Header().Set("RateLimit-Reset", strconv.Itoa(reset))
+			writer.Header().Set(
+				"RateLimit-Policy",
+				fmt.Sprintf("%d;w=%d", policy.Rate.Limit, int(math.Ceil(policy.Rate.Window.Seconds()))),
+			)
+
+			if !result.Allowed {
+				zapctx.L(ctx).Info("ratelimit_exceeded", zap.String("policy", policy.Name), zap.String("key", key))
+				trace.SpanFromContext(ctx).SetAttributes(attribute.String("ratelimit.policy", policy.Name))
+
+				writer.Header().Set("Retry-After", strconv.Itoa(reset))
+				problem.Write(
+					writer,
+					request,
+					problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "too many requests"),
+				)
+				return
+			}
+
+			handler.ServeHTTP(writer, request)
+		})
+	}
+}
+
+// rateLimitKey returns the key of the window of the request in the policy.
+func rateLimitKey(request *http.Request, policy RateLimitPolicy) string {
+	var key strings.Builder
+	key.WriteString("ratelimit:")
+	key.WriteString(policy.Name)
+
+	for _, part := range policy.Keys {
+		var value string
+		switch part {
+		case RateLimitByPrincipal:
+			if principal, ok := auth.PrincipalFromContext(request.Context()); ok {
+				value = principal.ID
+			}
+		case RateLimitByIP:
+			value = request.RemoteAddr
+			if host, _, err := net.SplitHostPort(request.RemoteAddr); err == nil {
+				value = host
+			}
+		case RateLimitByRoute:
+			route := obfuscateURL(request.URL.Path)
+			if policy.Route != nil {
+				route = policy.Route(request)
+			}
+			value = request.Method + " " + route
+		case RateLimitByAccount:
+			if policy.AccountID != nil {
+				value = policy.AccountID(request)
+			}
+		}
+		_, _ = fmt.Fprintf(&key, ":%s=%s", part, value)
+	}
+
+	return key.String()
+}
+
+// maxAccountBodySize is the size of the JSON body AccountFromJSONBody reads at most, the account of a larger body is
+// not read and the request is counted by the other keys of the policy.
+const maxAccountBodySize = 64 << 10
+
+// AccountFromJSONBody returns a RateLimitPolicy.AccountID reading the account of the field of the JSON body, the body
+// is kept for the handler. It returns empty when the body does not have the field or is larger than
+// maxAccountBodySize.
+func AccountFromJSONBody(field string) func(request *http.Request) string {
+	return func(request *http.Request) string {
+		if request.Body == nil || request.Body == http.NoBody {
+			return ""
+		}
+
+		original := request.Body
+		body, err := io.ReadAll(io.LimitReader(original, maxAccountBodySize+1))
+		request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), original), Closer: original}
+		if err != nil || len(body) > maxAccountBodySize {
+			return ""
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return ""
+		}
+
+		account, _ := fields[field].(string)
+		return account
+	}
+}
+
+// readCloser is a body read from its Reader and closed by its Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
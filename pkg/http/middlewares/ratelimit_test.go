@@ -0,0 +1,155 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type _limiterTest struct {
+	keys   []string
+	result ratelimit.Result
+	err    error
+}
+
+func (l *_limiterTest) Allow(_ context.Context, key string, _ ratelimit.Rate) (ratelimit.Result, error) {
+	l.keys = append(l.keys, key)
+	return l.result, l.err
+}
+
+func TestNewRateLimitMiddleware(t *testing.T) {
+	policy := RateLimitPolicy{
+		Name:      "p2p",
+		Rate:      ratelimit.Rate{Limit: 2, Window: time.Minute},
+		Keys:      []RateLimitKey{RateLimitByPrincipal, RateLimitByIP, RateLimitByRoute, RateLimitByAccount},
+		AccountID: AccountFromJSONBody("from_account_id"),
+	}
+
+	var body string
+	h := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+	}
+
+	newRequest := func() *http.Request {
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{ID: "key-1"})
+		request := httptest.NewRequest(
+			http.MethodPost,
+			"/v1/transactions/p2p",
+			strings.NewReader(`{"from_account_id":"account-1"}`),
+		).WithContext(ctx)
+		request.RemoteAddr = "10.0.0.1:5000"
+		return request
+	}
+
+	t.Run("Allowed request", func(t *testing.T) {
+		limiter := &_limiterTest{result: ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1}}
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, policy)).ServeHTTP(response, newRequest())
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(
+			t,
+			[]string{"ratelimit:p2p:principal=key-1:ip=10.0.0.1:route=POST /v1/transactions/p2p:account=account-1"},
+			limiter.keys,
+		)
+		assert.Equal(t, `{"from_account_id":"account-1"}`, body)
+		assert.Equal(t, "2", response.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", response.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "0", response.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2;w=60", response.Header().Get("RateLimit-Policy"))
+		assert.Empty(t, response.Header().Get("Retry-After"))
+	})
+
+	t.Run("Refused request", func(t *testing.T) {
+		body = ""
+		limiter := &_limiterTest{result: ratelimit.Result{Limit: 2, Reset: 1500 * time.Millisecond}}
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, policy)).ServeHTTP(response, newRequest())
+
+		assert.Equal(t, http.StatusTooManyRequests, response.Code)
+		assert.Empty(t, body)
+		assert.Equal(t, "0", response.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "2", response.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "2", response.Header().Get("Retry-After"))
+		assert.Equal(t, problem.ContentType, response.Header().Get("Content-Type"))
+		assert.Contains(t, response.Body.String(), `"code":"rate_limited"`)
+	})
+
+	t.Run("Limiter failure", func(t *testing.T) {
+		limiter := &_limiterTest{err: errors.New("failure")}
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, policy)).ServeHTTP(response, newRequest())
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, response.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("Route template", func(t *testing.T) {
+		newBlockRequest := func(accountID string) *http.Request {
+			return httptest.NewRequest(http.MethodPut, "/v1/accounts/"+accountID+"/blocks", nil)
+		}
+		routes := map[string]string{
+			"default": "/v1/accounts/{UUID}/blocks",
+			"router":  "/v1/accounts/:id/blocks",
+		}
+
+		for name, route := range routes {
+			limiter := &_limiterTest{result: ratelimit.Result{Allowed: true}}
+			policy := RateLimitPolicy{
+				Name: "v1",
+				Rate: ratelimit.Rate{Limit: 2, Window: time.Minute},
+				Keys: []RateLimitKey{RateLimitByRoute},
+			}
+			if name == "router" {
+				policy.Route = func(*http.Request) string { return route }
+			}
+
+			handler := Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, policy))
+			handler.ServeHTTP(httptest.NewRecorder(), newBlockRequest("5b0b9f1c-58a4-4f6e-9a3c-2b0c9b7d1e01"))
+			handler.ServeHTTP(httptest.NewRecorder(), newBlockRequest("c1d2e3f4-0a1b-4c2d-8e3f-405162738495"))
+
+			assert.Equal(t, []string{"ratelimit:v1:route=PUT " + route, "ratelimit:v1:route=PUT " + route}, limiter.keys)
+		}
+	})
+
+	t.Run("Large body", func(t *testing.T) {
+		large := `{"from_account_id":"account-1","description":"` + strings.Repeat("a", maxAccountBodySize) + `"}`
+		request := newRequest()
+		request.Body = io.NopCloser(strings.NewReader(large))
+
+		limiter := &_limiterTest{result: ratelimit.Result{Allowed: true}}
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, policy)).ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(
+			t,
+			[]string{"ratelimit:p2p:principal=key-1:ip=10.0.0.1:route=POST /v1/transactions/p2p:account="},
+			limiter.keys,
+		)
+		assert.Equal(t, large, body)
+	})
+
+	t.Run("Zero rate", func(t *testing.T) {
+		limiter := &_limiterTest{}
+		response := httptest.NewRecorder()
+		Chain(_handleHTTPTest{h}, NewRateLimitMiddleware(limiter, RateLimitPolicy{Name: "none"})).
+			ServeHTTP(response, newRequest())
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, limiter.keys)
+	})
+}
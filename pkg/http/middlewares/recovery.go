@@ -3,6 +3,7 @@ package middlewares
 import (
 	"net/http"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
 	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
 	"go.uber.org/zap"
 )
@@ -15,7 +16,11 @@ func NewRecoveryHTTPMiddleware() Middleware {
 			defer func() {
 				if r := recover(); r != nil || panicked {
 					zapctx.L(request.Context()).Error("recovery_panic", zap.Reflect("error", r))
-					writer.WriteHeader(http.StatusInternalServerError)
+					problem.Write(
+						writer,
+						request,
+						problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error"),
+					)
 				}
 			}()
 
@@ -6,6 +6,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/stretchr/testify/assert"
 )
 
 type _handleHTTPTest struct {
@@ -27,6 +30,9 @@ func Test_recoveryHTTPMiddleware(t *testing.T) {
 		request := httptest.NewRequest(http.MethodPost, "/health", nil)
 		response := httptest.NewRecorder()
 		chain.ServeHTTP(response, request)
+
+		assert.Equal(t, http.StatusInternalServerError, response.Code)
+		assert.Equal(t, problem.ContentType, response.Header().Get("Content-Type"))
 	})
 
 	t.Run("Handle non panic", func(t *testing.T) {
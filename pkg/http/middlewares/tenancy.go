@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/http/problem"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TenantHeader is the header choosing the tenant of the principals not bound to one.
+const TenantHeader = "X-Tenant-ID"
+
+// NewTenancyMiddleware returns a middleware that resolves the tenant of the requests, the one of the principal or of
+// the X-Tenant-ID header, replying 403 when the principal is bound to another tenant and 400 when it is unknown. It
+// goes after the authentication middleware, the tenant is put in the context of the request.
+func NewTenancyMiddleware(tenants tenancy.Tenants, ignorePaths ...string) Middleware {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			ctx := request.Context()
+			for _, ignorePath := range ignorePaths {
+				if request.URL.Path == ignorePath {
+					handler.ServeHTTP(writer, request)
+					return
+				}
+			}
+
+			tenant, err := tenants.Resolve(ctx, request.Header.Get(TenantHeader))
+			if err != nil {
+				zapctx.L(ctx).Info(
+					"tenancy_resolve_error",
+					zap.String("tenant_id", request.Header.Get(TenantHeader)),
+					zap.Error(err),
+				)
+
+				p := problem.New(http.StatusBadRequest, problem.CodeUnknownTenant, err.Error())
+				if errors.Is(err, tenancy.ErrTenantMismatch) {
+					p = problem.New(http.StatusForbidden, problem.CodeTenantMismatch, err.Error())
+				}
+				problem.Write(writer, request, p)
+				return
+			}
+
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant.id", tenant.ID))
+
+			handler.ServeHTTP(writer, request.WithContext(tenancy.WithTenant(ctx, tenant)))
+		})
+	}
+}
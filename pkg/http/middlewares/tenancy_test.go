@@ -0,0 +1,59 @@
+//go:build unit
+
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTenancyMiddleware(t *testing.T) {
+	brandA := tenancy.Tenant{ID: "brand-a", Agency: "0002", DailyDebitLimit: 100}
+	tenants := tenancy.Tenants{tenancy.Default.ID: tenancy.Default, brandA.ID: brandA}
+
+	var tenant tenancy.Tenant
+	h := func(w http.ResponseWriter, r *http.Request) {
+		tenant, _ = tenancy.FromContext(r.Context())
+	}
+	chain := Chain(_handleHTTPTest{h}, NewTenancyMiddleware(tenants, "/liveness"))
+
+	tests := []struct {
+		name      string
+		path      string
+		principal auth.Principal
+		header    string
+		status    int
+		tenant    tenancy.Tenant
+	}{
+		{name: "Default tenant", path: "/v1/holders", status: http.StatusOK, tenant: tenancy.Default},
+		{name: "Tenant of the header", path: "/v1/holders", header: "brand-a", status: http.StatusOK, tenant: brandA},
+		{name: "Tenant of the principal", path: "/v1/holders", principal: auth.Principal{TenantID: "brand-a"},
+			status: http.StatusOK, tenant: brandA},
+		{name: "Other tenant of the principal", path: "/v1/holders", principal: auth.Principal{TenantID: "brand-a"},
+			header: "default", status: http.StatusForbidden},
+		{name: "Unknown tenant", path: "/v1/holders", header: "brand-c", status: http.StatusBadRequest},
+		{name: "Ignored path", path: "/liveness", header: "brand-c", status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant = tenancy.Tenant{}
+			ctx := auth.WithPrincipal(context.Background(), tt.principal)
+			request := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody).WithContext(ctx)
+			if tt.header != "" {
+				request.Header.Set(TenantHeader, tt.header)
+			}
+			response := httptest.NewRecorder()
+			chain.ServeHTTP(response, request)
+
+			assert.Equal(t, tt.status, response.Code)
+			assert.Equal(t, tt.tenant, tenant)
+		})
+	}
+}
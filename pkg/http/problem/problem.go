@@ -0,0 +1,89 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContentType is the media type of the problem details (RFC 7807).
+const ContentType = "application/problem+json"
+
+// Code is the machine-readable code of a problem, stable across the versions of the API and documented in its
+// catalog, the clients should branch on it instead of the status or the detail.
+type Code string
+
+// The codes of the problems of the HTTP layer, the domain ones are in the catalog of the API.
+const (
+	CodeInvalidRequest       Code = "invalid_request"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeUnauthenticated      Code = "unauthenticated"
+	CodePermissionDenied     Code = "permission_denied"
+	CodeNotFound             Code = "not_found"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeNotAcceptable        Code = "not_acceptable"
+	CodeConflict             Code = "conflict"
+	CodeUnsupportedMediaType Code = "unsupported_media_type"
+	CodeRateLimited          Code = "rate_limited"
+	CodeUnknownTenant        Code = "unknown_tenant"
+	CodeTenantMismatch       Code = "tenant_mismatch"
+	CodeTimeout              Code = "timeout"
+	CodeUnavailable          Code = "unavailable"
+	CodeInternal             Code = "internal"
+	CodeInvalidResponse      Code = "invalid_response"
+)
+
+// FieldError is one invalid field of a request, its location is the path, query, header, cookie, body or response.
+type FieldError struct {
+	Field    string `json:"field"`
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// Problem is the body of the error responses, the problem details of RFC 7807 with the code of the problem, the id
+// of the trace of the request and the invalid fields, if any.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     Code         `json:"code"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// New returns the problem of the status with the code and the detail, the detail is shown to the clients.
+func New(status int, code Code, detail string) Problem {
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Error returns the detail of the problem, so a handler can return it.
+func (p Problem) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// Write replies the problem to the request, with the path of the request as its instance and the trace id of the
+// span of the request.
+func Write(writer http.ResponseWriter, request *http.Request, p Problem) {
+	if p.Instance == "" && request.URL != nil {
+		p.Instance = request.URL.Path
+	}
+	if sc := trace.SpanContextFromContext(request.Context()); sc.HasTraceID() {
+		p.TraceID = sc.TraceID().String()
+	}
+
+	writer.Header().Set("Content-Type", ContentType)
+	writer.WriteHeader(p.Status)
+	_ = json.NewEncoder(writer).Encode(p)
+}
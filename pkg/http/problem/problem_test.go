@@ -0,0 +1,47 @@
+//go:build unit
+
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWrite(t *testing.T) {
+	traceID := trace.TraceID{1, 2, 3}
+	ctx := trace.ContextWithSpanContext(
+		context.Background(),
+		trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: trace.SpanID{1}}),
+	)
+	request := httptest.NewRequest(http.MethodGet, "/v1/holders/1", http.NoBody).WithContext(ctx)
+	response := httptest.NewRecorder()
+
+	Write(response, request, New(http.StatusNotFound, CodeNotFound, "no holders found"))
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, ContentType, response.Header().Get("Content-Type"))
+
+	var body Problem
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, Problem{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "no holders found",
+		Instance: "/v1/holders/1",
+		Code:     CodeNotFound,
+		TraceID:  traceID.String(),
+	}, body)
+}
+
+func TestProblem_Error(t *testing.T) {
+	assert.Equal(t, "no holders found", New(http.StatusNotFound, CodeNotFound, "no holders found").Error())
+	assert.Equal(t, "Not Found", New(http.StatusNotFound, CodeNotFound, "").Error())
+}
@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+)
+
+// exportInterval is the interval the metrics are pushed to the collector, that exposes them to Prometheus.
+const exportInterval = 15 * time.Second
+
+type (
+	Meter = metric.Meter
+)
+
+// Metrics provides the meters of the application, the instruments are created by the packages that record them.
+type Metrics interface {
+	Meter(name string) Meter
+	Stop(ctx context.Context) error
+}
+
+type metrics struct {
+	provider *sdkmetric.MeterProvider
+}
+
+func New(endpoint, service, env, version string) (Metrics, error) {
+	r, err := resource.Merge(
+		resource.Default(),
+		resource.NewSchemaless(
+			semconv.TelemetrySDKLanguageGo,
+			semconv.ServiceNameKey.String(service),
+			semconv.ServiceVersionKey.String(version),
+			semconv.DeploymentEnvironmentKey.String(env),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter, err := otlpmetrichttp.New(
+		context.Background(),
+		otlpmetrichttp.WithEndpoint(endpoint),
+		otlpmetrichttp.WithInsecure(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m := metrics{
+		provider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithResource(r),
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(exportInterval))),
+		),
+	}
+
+	otel.SetMeterProvider(m.provider)
+
+	return m, nil
+}
+
+// NewWithReader returns the metrics collected by the reader, the tests use it with a sdkmetric.ManualReader.
+func NewWithReader(reader sdkmetric.Reader) Metrics {
+	return metrics{provider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))}
+}
+
+func (m metrics) Meter(name string) Meter {
+	return m.provider.Meter(name)
+}
+
+func (m metrics) Stop(ctx context.Context) error {
+	err := m.provider.ForceFlush(ctx)
+	if err != nil {
+		return err
+	}
+	return m.provider.Shutdown(ctx)
+}
@@ -0,0 +1,77 @@
+// Package metricstest collects the metrics recorded in the tests.
+package metricstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// Collector is a metrics.Metrics that keeps the recorded metrics in memory.
+type Collector struct {
+	metrics.Metrics
+	reader *sdkmetric.ManualReader
+}
+
+func New() *Collector {
+	reader := sdkmetric.NewManualReader()
+	return &Collector{Metrics: metrics.NewWithReader(reader), reader: reader}
+}
+
+// Sum returns the sum of the counter, or the count of the histogram, of the data points of the metric with the
+// attributes, the data points can have other attributes.
+func (c *Collector) Sum(t *testing.T, name string, attrs ...attribute.KeyValue) float64 {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	if err := c.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect metrics: %v", err)
+	}
+
+	var sum float64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					if matches(dp.Attributes, attrs) {
+						sum += float64(dp.Value)
+					}
+				}
+			case metricdata.Sum[float64]:
+				for _, dp := range data.DataPoints {
+					if matches(dp.Attributes, attrs) {
+						sum += dp.Value
+					}
+				}
+			case metricdata.Histogram[float64]:
+				for _, dp := range data.DataPoints {
+					if matches(dp.Attributes, attrs) {
+						sum += float64(dp.Count)
+					}
+				}
+			default:
+				t.Fatalf("metric %s: unsupported data %T", name, m.Data)
+			}
+		}
+	}
+
+	return sum
+}
+
+func matches(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if v, ok := set.Value(attr.Key); !ok || v != attr.Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package metrics
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type noopMetrics struct{}
+
+func NewNoop() Metrics {
+	return noopMetrics{}
+}
+
+func (n noopMetrics) Meter(name string) Meter {
+	return noop.NewMeterProvider().Meter(name)
+}
+
+func (n noopMetrics) Stop(_ context.Context) error {
+	return nil
+}
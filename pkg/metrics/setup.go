@@ -0,0 +1,16 @@
+package metrics
+
+import "go.uber.org/fx"
+
+func Setup(lc fx.Lifecycle, endpoint, service, env, version string) (Metrics, error) {
+	m, err := New(endpoint, service, env, version)
+	if err != nil {
+		return m, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: m.Stop,
+	})
+
+	return m, nil
+}
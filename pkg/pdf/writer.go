@@ -0,0 +1,262 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Font is one of the standard Type 1 fonts every PDF reader must provide, so nothing is embedded in the document.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// Size is the page size in points (1/72 inch).
+type Size struct {
+	Width  float64
+	Height float64
+}
+
+var A4 = Size{Width: 595.28, Height: 841.89}
+
+var ErrWriterClosed = errors.New("pdf writer already closed")
+
+const (
+	catalogObject = 1
+	pagesObject   = 2
+	// fontObject is the object number of the first font, the others follow it in the same order of fontNames.
+	fontObject = 3
+)
+
+// Writer writes a text only PDF document page by page. Only the page being drawn is kept in memory,
+// finished pages are written straight to the underlying io.Writer.
+type Writer struct {
+	out     *countingWriter
+	size    Size
+	offsets map[int]int64
+	next    int
+	pages   []int
+	page    *bytes.Buffer
+	closed  bool
+}
+
+// NewWriter writes the PDF header and the fonts objects and returns a Writer ready to receive pages.
+func NewWriter(w io.Writer, size Size) (*Writer, error) {
+	pw := &Writer{
+		out:     &countingWriter{w: w},
+		size:    size,
+		offsets: map[int]int64{},
+		next:    fontObject + len(fontNames),
+	}
+
+	// The binary comment in the second line tells transfer tools the file must be handled as binary.
+	if _, err := io.WriteString(pw.out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"); err != nil {
+		return nil, err
+	}
+
+	for i, name := range fontNames {
+		err := pw.writeObject(
+			fontObject+i,
+			fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pw, nil
+}
+
+// Size returns the page size of the document.
+func (pw *Writer) Size() Size {
+	return pw.size
+}
+
+// AddPage finishes the current page, if any, and starts a new one.
+func (pw *Writer) AddPage() error {
+	if pw.closed {
+		return ErrWriterClosed
+	}
+
+	if err := pw.flushPage(); err != nil {
+		return err
+	}
+
+	pw.page = &bytes.Buffer{}
+	return nil
+}
+
+// Text draws text with its baseline starting at x, y. The origin is the bottom left corner of the page.
+func (pw *Writer) Text(x, y float64, font Font, size float64, text string) error {
+	if err := pw.ensurePage(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(
+		pw.page,
+		"BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		font+1,
+		size,
+		x,
+		y,
+		escape(text),
+	)
+	return err
+}
+
+// Line draws a thin line from x1, y1 to x2, y2.
+func (pw *Writer) Line(x1, y1, x2, y2 float64) error {
+	if err := pw.ensurePage(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(pw.page, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+	return err
+}
+
+// Close finishes the current page and writes the pages tree, the catalog and the cross-reference table.
+func (pw *Writer) Close() error {
+	if pw.closed {
+		return ErrWriterClosed
+	}
+
+	if err := pw.ensurePage(); err != nil {
+		return err
+	}
+
+	if err := pw.flushPage(); err != nil {
+		return err
+	}
+	pw.closed = true
+
+	kids := make([]string, len(pw.pages))
+	for i, page := range pw.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+
+	err := pw.writeObject(
+		pagesObject,
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pw.pages)),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = pw.writeObject(catalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+	if err != nil {
+		return err
+	}
+
+	xref := pw.out.n
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "xref\n0 %d\n0000000000 65535 f \n", pw.next)
+	for i := 1; i < pw.next; i++ {
+		fmt.Fprintf(&sb, "%010d 00000 n \n", pw.offsets[i])
+	}
+	fmt.Fprintf(&sb, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", pw.next, catalogObject, xref)
+
+	_, err = io.WriteString(pw.out, sb.String())
+	return err
+}
+
+func (pw *Writer) ensurePage() error {
+	if pw.closed {
+		return ErrWriterClosed
+	}
+
+	if pw.page == nil {
+		pw.page = &bytes.Buffer{}
+	}
+	return nil
+}
+
+func (pw *Writer) flushPage() error {
+	if pw.page == nil {
+		return nil
+	}
+
+	content := pw.nextObject()
+	err := pw.writeObject(
+		content,
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", pw.page.Len(), pw.page.String()),
+	)
+	if err != nil {
+		return err
+	}
+
+	fonts := make([]string, len(fontNames))
+	for i := range fontNames {
+		fonts[i] = fmt.Sprintf("/F%d %d 0 R", i+1, fontObject+i)
+	}
+
+	page := pw.nextObject()
+	err = pw.writeObject(
+		page,
+		fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pagesObject,
+			pw.size.Width,
+			pw.size.Height,
+			strings.Join(fonts, " "),
+			content,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	pw.pages = append(pw.pages, page)
+	pw.page = nil
+	return nil
+}
+
+func (pw *Writer) nextObject() int {
+	n := pw.next
+	pw.next++
+	return n
+}
+
+func (pw *Writer) writeObject(n int, body string) error {
+	pw.offsets[n] = pw.out.n
+	_, err := fmt.Fprintf(pw.out, "%d 0 obj\n%s\nendobj\n", n, body)
+	return err
+}
+
+// escape converts text to WinAnsiEncoding, replacing what can not be represented, and escapes the characters
+// that have a meaning inside PDF literal strings.
+func escape(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,58 @@
+//go:build unit
+
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	t.Run("Write pages with valid cross-reference table", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, A4)
+		assert.NoError(t, err)
+
+		assert.NoError(t, w.Text(40, 800, HelveticaBold, 14, "Título (1)"))
+		assert.NoError(t, w.Line(40, 790, 555, 790))
+		assert.NoError(t, w.AddPage())
+		assert.NoError(t, w.Text(40, 800, Courier, 8, `back\slash`))
+		assert.NoError(t, w.Close())
+
+		doc := buf.Bytes()
+		assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+		assert.Contains(t, string(doc), "/Count 2")
+		assert.Contains(t, string(doc), `(T\355tulo \(1\)) Tj`)
+		assert.Contains(t, string(doc), `(back\\slash) Tj`)
+
+		startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+		assert.Len(t, startxref, 2)
+		xref, err := strconv.Atoi(string(startxref[1]))
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(doc[xref:], []byte("xref\n")))
+
+		entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(doc[xref:], -1)
+		assert.NotEmpty(t, entries)
+		for i, entry := range entries {
+			offset, err := strconv.Atoi(string(entry[1]))
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(doc[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+		}
+	})
+
+	t.Run("Do not write after close", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := NewWriter(&buf, A4)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+
+		assert.ErrorIs(t, w.Text(0, 0, Helvetica, 8, "text"), ErrWriterClosed)
+		assert.ErrorIs(t, w.Close(), ErrWriterClosed)
+	})
+}
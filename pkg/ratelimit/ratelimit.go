@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/dalmarcogd/ledger-exp/pkg/zapctx"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidRate is returned by ParseRate when the rate is not in the <limit>/<window> format.
+var ErrInvalidRate = errors.New("invalid rate, it must be <limit>/<window>, e.g. 30/1m")
+
+// Rate is the number of requests allowed in a sliding window.
+type Rate struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ParseRate parses a rate in the <limit>/<window> format, e.g. 30/1m, the zero Rate when it is empty.
+func ParseRate(rate string) (Rate, error) {
+	if rate == "" {
+		return Rate{}, nil
+	}
+
+	limit, window, ok := strings.Cut(rate, "/")
+	if !ok {
+		return Rate{}, ErrInvalidRate
+	}
+
+	l, err := strconv.Atoi(limit)
+	if err != nil || l <= 0 {
+		return Rate{}, fmt.Errorf("%w: %s", ErrInvalidRate, rate)
+	}
+
+	w, err := time.ParseDuration(window)
+	if err != nil || w < time.Millisecond {
+		return Rate{}, fmt.Errorf("%w: %s", ErrInvalidRate, rate)
+	}
+
+	return Rate{Limit: l, Window: w}, nil
+}
+
+// IsZero reports whether the rate is unset, the requests are not limited by it.
+func (r Rate) IsZero() bool {
+	return r.Limit == 0
+}
+
+// Result is the outcome of counting a request in a rate.
+type Result struct {
+	Allowed bool
+	Limit   int
+	// Remaining is the number of requests still allowed in the window.
+	Remaining int
+	// Reset is the time until the window allows one more request.
+	Reset time.Duration
+}
+
+// Limiter counts the requests of the keys in their rates.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rate Rate) (Result, error)
+}
+
+// slidingWindow keeps the requests of the window of KEYS[1] in a sorted set scored by their time in microseconds of
+// the redis clock, so all the instances of the application share the window. ARGV are the limit, the window in
+// microseconds and the id of the request. It returns whether the request is allowed, the remaining requests and the
+// microseconds to reset.
+var slidingWindow = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+
+local reset = 0
+if count >= limit then
+	local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, limit - count, reset}
+`)
+
+type limiter struct {
+	store    redis.Client
+	tracer   tracer.Tracer
+	fallback Limiter
+}
+
+// NewLimiter returns a Limiter with sliding windows in redis, shared by all the instances of the application. The
+// requests are counted by a local Limiter of the instance while redis is unavailable.
+func NewLimiter(t tracer.Tracer, store redis.Client) Limiter {
+	return limiter{
+		store:    store,
+		tracer:   t,
+		fallback: NewMemoryLimiter(),
+	}
+}
+
+func (l limiter) Allow(ctx context.Context, key string, rate Rate) (Result, error) {
+	ctx, span := l.tracer.Span(ctx)
+	defer span.End()
+
+	values, err := slidingWindow.Run(
+		ctx,
+		l.store,
+		[]string{key},
+		rate.Limit,
+		rate.Window.Microseconds(),
+		uuid.NewString(),
+	).Int64Slice()
+	if err == nil && len(values) != 3 {
+		err = fmt.Errorf("unexpected reply of the sliding window: %v", values)
+	}
+	if err != nil {
+		span.RecordError(err)
+		zapctx.L(ctx).Warn("ratelimit_redis_fallback", zap.String("key", key), zap.Error(err))
+		return l.fallback.Allow(ctx, key, rate)
+	}
+
+	return Result{
+		Allowed:   values[0] == 1,
+		Limit:     rate.Limit,
+		Remaining: int(values[1]),
+		Reset:     time.Duration(values[2]) * time.Microsecond,
+	}, nil
+}
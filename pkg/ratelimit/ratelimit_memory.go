@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the interval between the removals of the windows without requests.
+const sweepInterval = time.Minute
+
+// memoryWindow are the requests of a key in its window, sorted by their time.
+type memoryWindow struct {
+	requests []time.Time
+	window   time.Duration
+}
+
+type memoryLimiter struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	windows   map[string]memoryWindow
+	lastSweep time.Time
+}
+
+// NewMemoryLimiter returns a Limiter with sliding windows in the memory of the instance.
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{
+		now:     time.Now,
+		windows: map[string]memoryWindow{},
+	}
+}
+
+func (m *memoryLimiter) Allow(_ context.Context, key string, rate Rate) (Result, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		m.sweep(now)
+	}
+
+	requests := inWindow(m.windows[key].requests, now.Add(-rate.Window))
+	allowed := len(requests) < rate.Limit
+	if allowed {
+		requests = append(requests, now)
+	}
+	m.windows[key] = memoryWindow{requests: requests, window: rate.Window}
+
+	result := Result{Allowed: allowed, Limit: rate.Limit, Remaining: rate.Limit - len(requests)}
+	if len(requests) >= rate.Limit {
+		result.Reset = requests[0].Add(rate.Window).Sub(now)
+	}
+
+	return result, nil
+}
+
+// sweep removes the windows without requests, the keys of the requests that are not repeated.
+func (m *memoryLimiter) sweep(now time.Time) {
+	for key, w := range m.windows {
+		if len(inWindow(w.requests, now.Add(-w.window))) == 0 {
+			delete(m.windows, key)
+		}
+	}
+	m.lastSweep = now
+}
+
+// inWindow returns the requests after start, the requests are sorted by their time.
+func inWindow(requests []time.Time, start time.Time) []time.Time {
+	for i, request := range requests {
+		if request.After(start) {
+			return requests[i:]
+		}
+	}
+	return requests[:0]
+}
@@ -0,0 +1,107 @@
+//go:build unit
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/redis"
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	redis2 "github.com/go-redis/redis/v8"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("30/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Rate{Limit: 30, Window: time.Minute}, rate)
+
+	rate, err = ParseRate("")
+	require.NoError(t, err)
+	assert.True(t, rate.IsZero())
+
+	for _, invalid := range []string{"30", "0/1m", "-1/1m", "a/1m", "30/1", "30/0s"} {
+		_, err := ParseRate(invalid)
+		assert.ErrorIs(t, err, ErrInvalidRate, invalid)
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rate := Rate{Limit: 2, Window: time.Second}
+
+	t.Run("Sliding window of redis", func(t *testing.T) {
+		redisMock := redis.NewMockClient(ctrl)
+		redisMock.EXPECT().
+			EvalSha(gomock.Any(), gomock.Any(), []string{"key"}, 2, int64(1000000), gomock.Any()).
+			Return(redis2.NewCmdResult([]interface{}{int64(0), int64(0), int64(250000)}, nil))
+
+		result, err := NewLimiter(tracer.NewNoop(), redisMock).Allow(ctx, "key", rate)
+		require.NoError(t, err)
+		assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 250 * time.Millisecond}, result)
+	})
+
+	t.Run("Fallback while redis is unavailable", func(t *testing.T) {
+		redisMock := redis.NewMockClient(ctrl)
+		redisMock.EXPECT().
+			EvalSha(gomock.Any(), gomock.Any(), []string{"key"}, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(redis2.NewCmdResult(nil, errors.New("connection refused"))).
+			Times(3)
+
+		l := NewLimiter(tracer.NewNoop(), redisMock)
+		for i := 0; i < 2; i++ {
+			result, err := l.Allow(ctx, "key", rate)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+		}
+
+		result, err := l.Allow(ctx, "key", rate)
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+	})
+}
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := &memoryLimiter{now: func() time.Time { return now }, windows: map[string]memoryWindow{}}
+	rate := Rate{Limit: 2, Window: 10 * time.Second}
+
+	result, err := l.Allow(ctx, "key", rate)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1}, result)
+
+	now = now.Add(4 * time.Second)
+	result, err = l.Allow(ctx, "key", rate)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 6 * time.Second}, result)
+
+	result, err = l.Allow(ctx, "other", rate)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	now = now.Add(5 * time.Second)
+	result, err = l.Allow(ctx, "key", rate)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: time.Second}, result)
+
+	// The first request leaves the window.
+	now = now.Add(time.Second)
+	result, err = l.Allow(ctx, "key", rate)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 4 * time.Second}, result)
+
+	// The windows without requests are swept.
+	now = now.Add(sweepInterval)
+	_, err = l.Allow(ctx, "key", rate)
+	require.NoError(t, err)
+	assert.Len(t, l.windows, 1)
+}
@@ -20,12 +20,21 @@ type Client interface {
 	SetArgs(ctx context.Context, key string, value interface{}, a redis.SetArgs) *redis.StatusCmd
 	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd
 	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd
+	EvalSha(ctx context.Context, sha1 string, keys []string, args ...interface{}) *redis.Cmd
+	ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd
+	ScriptLoad(ctx context.Context, script string) *redis.StringCmd
 }
 
 type SetArgs = redis.SetArgs
 
 type Error = redis.Error
 
+// Script is a Lua script run by the Client, see redis.NewScript.
+type Script = redis.Script
+
+var NewScript = redis.NewScript
+
 func NewClient(redisURL, caCert string) (Client, error) {
 	opt, err := redis.ParseURL(redisURL)
 	if err != nil {
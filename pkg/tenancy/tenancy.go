@@ -0,0 +1,130 @@
+package tenancy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+)
+
+var (
+	// ErrUnknownTenant is returned when the tenant is not configured.
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrTenantMismatch is returned when a principal bound to a tenant asks for another one.
+	ErrTenantMismatch = errors.New("the principal is not allowed to access this tenant")
+)
+
+// Tenant is a brand served by the deployment with its configuration.
+type Tenant struct {
+	ID string `json:"id"`
+	// Agency is the agency code of the accounts of the tenant.
+	Agency string `json:"agency"`
+	// BankCode is the code of the institution of the accounts of the tenant, e.g. the BANKID of the OFX statements.
+	// The tenants without it have the one of the Default tenant.
+	BankCode string `json:"bank_code"`
+	// DailyDebitLimit is the amount each account of the tenant can send per day.
+	DailyDebitLimit float64 `json:"daily_debit_limit"`
+}
+
+// Default is the tenant of the requests without one, and the only tenant when none is configured.
+var Default = Tenant{ID: "default", Agency: "0001", BankCode: "000", DailyDebitLimit: 2000}
+
+// Tenants are the configured tenants by id.
+type Tenants map[string]Tenant
+
+// Load reads the tenants of the JSON file, an array of Tenant. Without a file only the Default tenant exists.
+func Load(file string) (Tenants, error) {
+	if file == "" {
+		return Tenants{Default.ID: Default}, nil
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Tenant
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("invalid tenants file %s: %w", file, err)
+	}
+
+	tenants := make(Tenants, len(list))
+	for _, tenant := range list {
+		if tenant.ID == "" || len(tenant.ID) > 36 || tenant.Agency == "" || len(tenant.Agency) > 4 {
+			return nil, fmt.Errorf("invalid tenant %q in %s: id (up to 36) and agency (up to 4) are required", tenant.ID, file)
+		}
+		if tenant.BankCode == "" {
+			tenant.BankCode = Default.BankCode
+		}
+		if len(tenant.BankCode) > 9 {
+			return nil, fmt.Errorf("invalid tenant %q in %s: bank_code must have up to 9 characters", tenant.ID, file)
+		}
+		if tenant.DailyDebitLimit <= 0 {
+			return nil, fmt.Errorf("invalid tenant %q in %s: daily_debit_limit must be positive", tenant.ID, file)
+		}
+		if _, ok := tenants[tenant.ID]; ok {
+			return nil, fmt.Errorf("duplicated tenant %q in %s", tenant.ID, file)
+		}
+		tenants[tenant.ID] = tenant
+	}
+
+	return tenants, nil
+}
+
+// Get returns the tenant with the id, ErrUnknownTenant when it is not configured.
+func (t Tenants) Get(id string) (Tenant, error) {
+	tenant, ok := t[id]
+	if !ok {
+		return Tenant{}, ErrUnknownTenant
+	}
+	return tenant, nil
+}
+
+// Resolve returns the tenant of a request asking for the requested one, the tenant the principal of ctx is bound to
+// or the requested one when it is not bound, the Default tenant when none is asked for.
+func (t Tenants) Resolve(ctx context.Context, requested string) (Tenant, error) {
+	id := requested
+	if principal, ok := auth.PrincipalFromContext(ctx); ok && principal.TenantID != "" {
+		if requested != "" && requested != principal.TenantID {
+			return Tenant{}, ErrTenantMismatch
+		}
+		id = principal.TenantID
+	}
+
+	if id == "" {
+		id = Default.ID
+	}
+
+	return t.Get(id)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant of the request.
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant of ctx, false when there is none.
+func FromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
+
+// Current returns the tenant of ctx, the Default tenant when there is none.
+func Current(ctx context.Context) Tenant {
+	if tenant, ok := FromContext(ctx); ok {
+		return tenant
+	}
+	return Default
+}
+
+// FilterID returns the id of the tenant of ctx to restrict the queries to it, empty when there is none. The calls
+// without a tenant are made by the application itself (jobs and command lines), they reach all the tenants.
+func FilterID(ctx context.Context) string {
+	tenant, _ := FromContext(ctx)
+	return tenant.ID
+}
@@ -0,0 +1,109 @@
+//go:build unit
+
+package tenancy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTenants(t *testing.T, content string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "tenants.json")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Default tenant", func(t *testing.T) {
+		tenants, err := Load("")
+		require.NoError(t, err)
+		assert.Equal(t, Tenants{"default": Default}, tenants)
+	})
+
+	t.Run("Tenants file", func(t *testing.T) {
+		tenants, err := Load(writeTenants(t, `[
+			{"id": "brand-a", "agency": "0001", "bank_code": "260", "daily_debit_limit": 2000},
+			{"id": "brand-b", "agency": "0002", "daily_debit_limit": 500}
+		]`))
+		require.NoError(t, err)
+
+		tenant, err := tenants.Get("brand-a")
+		require.NoError(t, err)
+		assert.Equal(t, Tenant{ID: "brand-a", Agency: "0001", BankCode: "260", DailyDebitLimit: 2000}, tenant)
+
+		tenant, err = tenants.Get("brand-b")
+		require.NoError(t, err)
+		assert.Equal(t, Tenant{ID: "brand-b", Agency: "0002", BankCode: Default.BankCode, DailyDebitLimit: 500}, tenant)
+
+		_, err = tenants.Get("default")
+		assert.ErrorIs(t, err, ErrUnknownTenant)
+	})
+
+	invalid := map[string]string{
+		"malformed":      `{"id": "brand-a"}`,
+		"no id":          `[{"agency": "0001", "daily_debit_limit": 2000}]`,
+		"no agency":      `[{"id": "brand-a", "daily_debit_limit": 2000}]`,
+		"no limit":       `[{"id": "brand-a", "agency": "0001"}]`,
+		"long bank code": `[{"id": "brand-a", "agency": "0001", "bank_code": "1234567890", "daily_debit_limit": 1}]`,
+		"duplicated": `[
+			{"id": "brand-a", "agency": "0001", "daily_debit_limit": 1},
+			{"id": "brand-a", "agency": "0002", "daily_debit_limit": 1}
+		]`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(writeTenants(t, content))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTenants_Resolve(t *testing.T) {
+	brandA := Tenant{ID: "brand-a", Agency: "0002", DailyDebitLimit: 100}
+	tenants := Tenants{Default.ID: Default, brandA.ID: brandA}
+
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{ID: "operator"})
+	partner := auth.WithPrincipal(context.Background(), auth.Principal{ID: "partner", TenantID: brandA.ID})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requested string
+		tenant    Tenant
+		err       error
+	}{
+		{name: "Requested tenant", ctx: operator, requested: "brand-a", tenant: brandA},
+		{name: "Default tenant", ctx: operator, tenant: Default},
+		{name: "Tenant of the principal", ctx: partner, tenant: brandA},
+		{name: "Same tenant of the principal", ctx: partner, requested: "brand-a", tenant: brandA},
+		{name: "Other tenant of the principal", ctx: partner, requested: "default", err: ErrTenantMismatch},
+		{name: "Unknown tenant", ctx: operator, requested: "brand-c", err: ErrUnknownTenant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := tenants.Resolve(tt.ctx, tt.requested)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.tenant, tenant)
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, Default, Current(ctx))
+	assert.Empty(t, FilterID(ctx))
+
+	brandA := Tenant{ID: "brand-a", Agency: "0002", DailyDebitLimit: 100}
+	ctx = WithTenant(ctx, brandA)
+	assert.Equal(t, brandA, Current(ctx))
+	assert.Equal(t, "brand-a", FilterID(ctx))
+}
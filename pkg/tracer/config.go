@@ -0,0 +1,123 @@
+package tracer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The exporters of the spans.
+const (
+	ExporterOTLPHTTP = "otlphttp"
+	ExporterOTLPGRPC = "otlpgrpc"
+	ExporterStdout   = "stdout"
+	ExporterFile     = "file"
+)
+
+var (
+	ErrUnknownExporter = errors.New("unknown exporter of the spans")
+	ErrInvalidSampling = errors.New("the sample ratio must be between 0 and 1")
+	ErrInvalidHeaders  = errors.New("the headers of the exporter must be key=value pairs separated by commas")
+)
+
+// Config is the configuration of the sampling and the exporter of the spans, the applications parse it from the
+// environment variables prefixed with OTEL_.
+type Config struct {
+	// Exporter is otlphttp, otlpgrpc, stdout or file.
+	Exporter string `cfg:"EXPORTER" cfgDefault:"otlphttp"`
+	// TLS secures the connection of the OTLP exporters, verified with CACert (PEM) when given.
+	TLS    bool   `cfg:"EXPORTER_TLS"`
+	CACert string `cfg:"EXPORTER_CA_CERT"`
+	// Headers are sent by the OTLP exporters, e.g. `api-key=secret,tenant=ledger`.
+	Headers string `cfg:"EXPORTER_HEADERS"`
+	// File is the path the file exporter appends the spans to.
+	File string `cfg:"EXPORTER_FILE" cfgDefault:"spans.json"`
+	// SampleRatio is the ratio of the traces sampled.
+	SampleRatio float64 `cfg:"SAMPLE_RATIO" cfgDefault:"1"`
+	// SampleParentBased follows the sampling decision of the parent span, local or remote, when there is one.
+	SampleParentBased bool `cfg:"SAMPLE_PARENT_BASED" cfgDefault:"true"`
+	// SampleErrors exports the spans that recorded an error even when their trace is not sampled.
+	SampleErrors bool `cfg:"SAMPLE_ERRORS" cfgDefault:"true"`
+	// SampleRoutes are the names of the root spans always sampled, separated by commas, e.g.
+	// `POST /v1/transactions/p2p`.
+	SampleRoutes string `cfg:"SAMPLE_ROUTES"`
+}
+
+// DefaultConfig samples all the traces and exports them with OTLP over insecure HTTP, as the defaults of the
+// environment variables.
+func DefaultConfig() Config {
+	return Config{
+		Exporter:          ExporterOTLPHTTP,
+		File:              "spans.json",
+		SampleRatio:       1,
+		SampleParentBased: true,
+		SampleErrors:      true,
+	}
+}
+
+func (c Config) validate() error {
+	switch c.Exporter {
+	case ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterStdout, ExporterFile:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExporter, c.Exporter)
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return ErrInvalidSampling
+	}
+
+	_, err := c.headers()
+	return err
+}
+
+func (c Config) headers() (map[string]string, error) {
+	headers := map[string]string{}
+	if c.Headers == "" {
+		return headers, nil
+	}
+
+	for _, pair := range strings.Split(c.Headers, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, ErrInvalidHeaders
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return headers, nil
+}
+
+func (c Config) routes() []string {
+	var routes []string
+	for _, route := range strings.Split(c.SampleRoutes, ",") {
+		if route = strings.TrimSpace(route); route != "" {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// Option configures the tracer returned by New.
+type Option func(*options)
+
+type options struct {
+	config   Config
+	exporter sdktrace.SpanExporter
+}
+
+// WithConfig configures the sampling and the exporter of the spans.
+func WithConfig(c Config) Option {
+	return func(o *options) {
+		o.config = c
+	}
+}
+
+// WithSyncer exports the spans synchronously to the exporter, when they end, instead of the one of the config. The
+// tests use it with an in-memory exporter.
+func WithSyncer(exporter sdktrace.SpanExporter) Option {
+	return func(o *options) {
+		o.exporter = exporter
+	}
+}
@@ -0,0 +1,114 @@
+package tracer
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exceptionEvent is the name of the event added by span.RecordError.
+const exceptionEvent = semconv.ExceptionEventName
+
+// newSampler returns the sampler of the config: the root spans named as one of the routes are sampled, the other
+// ones by the ratio, and the children follow their parent when it is parent based. The spans not sampled are still
+// recorded when the errors are sampled, so errorSpanProcessor can export the ones that recorded an error.
+func newSampler(c Config) sdktrace.Sampler {
+	notSampled := sdktrace.NeverSample()
+	if c.SampleErrors {
+		notSampled = recordOnlySampler{}
+	}
+
+	root := routeSampler{
+		routes: map[string]bool{},
+		ratio:  sdktrace.TraceIDRatioBased(c.SampleRatio),
+		drop:   notSampled,
+	}
+	for _, route := range c.routes() {
+		root.routes[route] = true
+	}
+
+	if !c.SampleParentBased {
+		return root
+	}
+
+	return sdktrace.ParentBased(
+		root,
+		sdktrace.WithRemoteParentNotSampled(notSampled),
+		sdktrace.WithLocalParentNotSampled(notSampled),
+	)
+}
+
+type routeSampler struct {
+	routes map[string]bool
+	ratio  sdktrace.Sampler
+	drop   sdktrace.Sampler
+}
+
+func (s routeSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if s.routes[p.Name] {
+		return sdktrace.AlwaysSample().ShouldSample(p)
+	}
+
+	result := s.ratio.ShouldSample(p)
+	if result.Decision == sdktrace.Drop {
+		return s.drop.ShouldSample(p)
+	}
+	return result
+}
+
+func (s routeSampler) Description() string {
+	return fmt.Sprintf("RouteSampler{routes:%d,%s}", len(s.routes), s.ratio.Description())
+}
+
+type recordOnlySampler struct{}
+
+func (s recordOnlySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{
+		Decision:   sdktrace.RecordOnly,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s recordOnlySampler) Description() string {
+	return "RecordOnlySampler"
+}
+
+// errorSpanProcessor passes the spans sampled to the next processor, and the ones recorded but not sampled only when
+// they recorded an error, marked as sampled as the processors of the SDK drop the other ones.
+type errorSpanProcessor struct {
+	sdktrace.SpanProcessor
+}
+
+func (p errorSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+
+	if hasError(s) {
+		p.SpanProcessor.OnEnd(sampledSpan{ReadOnlySpan: s})
+	}
+}
+
+func hasError(s sdktrace.ReadOnlySpan) bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+	for _, event := range s.Events() {
+		if event.Name == exceptionEvent {
+			return true
+		}
+	}
+	return false
+}
+
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	return s.ReadOnlySpan.SpanContext().WithTraceFlags(s.ReadOnlySpan.SpanContext().TraceFlags().WithSampled(true))
+}
@@ -0,0 +1,117 @@
+//go:build unit
+
+package tracer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSampling(t *testing.T) {
+	ctx := context.Background()
+	exporter := tracetest.NewInMemoryExporter()
+
+	config := DefaultConfig()
+	config.SampleRatio = 0
+	config.SampleErrors = true
+	config.SampleRoutes = "POST /v1/transactions/p2p, /ledger.v1.TransactionService/CreateP2P"
+	tr, err := New("", "test", "test", "", WithConfig(config), WithSyncer(exporter))
+	require.NoError(t, err)
+
+	names := func() []string {
+		var names []string
+		for _, span := range exporter.GetSpans() {
+			names = append(names, span.Name)
+		}
+		exporter.Reset()
+		return names
+	}
+
+	t.Run("Route always sampled", func(t *testing.T) {
+		rootCtx, root := tr.SpanName(ctx, "POST /v1/transactions/p2p")
+		_, child := tr.SpanName(rootCtx, "child")
+		child.End()
+		root.End()
+
+		assert.Equal(t, []string{"child", "POST /v1/transactions/p2p"}, names())
+	})
+
+	t.Run("Trace not sampled", func(t *testing.T) {
+		rootCtx, root := tr.SpanName(ctx, "GET /v1/accounts")
+		_, child := tr.SpanName(rootCtx, "child")
+		child.End()
+		root.End()
+
+		assert.Empty(t, names())
+	})
+
+	t.Run("Errors of the traces not sampled", func(t *testing.T) {
+		rootCtx, root := tr.SpanName(ctx, "GET /v1/accounts")
+		_, child := tr.SpanName(rootCtx, "child")
+		child.RecordError(errors.New("connection refused"))
+		child.End()
+		root.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "child", spans[0].Name)
+		assert.True(t, spans[0].SpanContext.IsSampled())
+		exporter.Reset()
+	})
+
+	t.Run("Errors not sampled", func(t *testing.T) {
+		config.SampleErrors = false
+		tr, err := New("", "test", "test", "", WithConfig(config), WithSyncer(exporter))
+		require.NoError(t, err)
+
+		_, span := tr.SpanName(ctx, "GET /v1/accounts")
+		span.RecordError(errors.New("connection refused"))
+		span.End()
+
+		assert.Empty(t, names())
+	})
+}
+
+func TestConfig_validate(t *testing.T) {
+	assert.NoError(t, DefaultConfig().validate())
+
+	config := DefaultConfig()
+	config.Exporter = "zipkin"
+	assert.ErrorIs(t, config.validate(), ErrUnknownExporter)
+
+	config = DefaultConfig()
+	config.SampleRatio = 1.5
+	assert.ErrorIs(t, config.validate(), ErrInvalidSampling)
+
+	config = DefaultConfig()
+	config.Headers = "api-key"
+	assert.ErrorIs(t, config.validate(), ErrInvalidHeaders)
+
+	config.Headers = "api-key=secret, tenant = ledger"
+	headers, err := config.headers()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"api-key": "secret", "tenant": "ledger"}, headers)
+}
+
+func TestNew_fileExporter(t *testing.T) {
+	config := DefaultConfig()
+	config.Exporter = ExporterFile
+	config.File = t.TempDir() + "/spans.json"
+
+	tr, err := New("", "test", "test", "", WithConfig(config))
+	require.NoError(t, err)
+
+	_, span := tr.SpanName(context.Background(), "GET /v1/accounts")
+	span.End()
+	require.NoError(t, tr.Stop(context.Background()))
+
+	content, err := os.ReadFile(config.File)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"Name":"GET /v1/accounts"`)
+}
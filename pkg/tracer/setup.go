@@ -2,8 +2,8 @@ package tracer
 
 import "go.uber.org/fx"
 
-func Setup(lc fx.Lifecycle, endpoint, service, env, version string) (Tracer, error) {
-	trace, err := New(endpoint, service, env, version)
+func Setup(lc fx.Lifecycle, endpoint, service, env, version string, opts ...Option) (Tracer, error) {
+	trace, err := New(endpoint, service, env, version, opts...)
 	if err != nil {
 		return trace, err
 	}
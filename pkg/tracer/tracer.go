@@ -2,13 +2,12 @@ package tracer
 
 import (
 	"context"
+	"io"
 	"regexp"
 	"runtime"
 	"strings"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -43,10 +42,22 @@ type (
 		env         string
 		version     string
 		provider    *sdktrace.TracerProvider
+		closer      io.Closer
 	}
 )
 
-func New(endpoint, service, env, version string) (Tracer, error) {
+// New returns a tracer that samples and exports the spans as configured by the options, by default it samples all
+// of them and exports them with OTLP over insecure HTTP to the endpoint.
+func New(endpoint, service, env, version string, opts ...Option) (Tracer, error) {
+	o := options{config: DefaultConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := o.config.validate(); err != nil {
+		return nil, err
+	}
+
 	s := tracing{
 		endpoint:    endpoint,
 		serviceName: service,
@@ -67,19 +78,26 @@ func New(endpoint, service, env, version string) (Tracer, error) {
 		return s, err
 	}
 
-	client := otlptracehttp.NewClient(
-		otlptracehttp.WithEndpoint(endpoint),
-		otlptracehttp.WithInsecure(),
-	)
-	exporter, err := otlptrace.New(context.Background(), client)
-	if err != nil {
-		return nil, err
+	var processor sdktrace.SpanProcessor
+	if o.exporter != nil {
+		processor = sdktrace.NewSimpleSpanProcessor(o.exporter)
+	} else {
+		exporter, closer, err := newExporter(endpoint, o.config)
+		if err != nil {
+			return nil, err
+		}
+		s.closer = closer
+		processor = sdktrace.NewBatchSpanProcessor(exporter)
+	}
+
+	if o.config.SampleErrors {
+		processor = errorSpanProcessor{SpanProcessor: processor}
 	}
 
 	s.provider = sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(newSampler(o.config)),
 		sdktrace.WithResource(r),
-		sdktrace.WithSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter)),
+		sdktrace.WithSpanProcessor(processor),
 	)
 
 	otel.SetTracerProvider(s.provider)
@@ -132,7 +150,7 @@ func (s tracing) SpanName(ctx context.Context, name string, attrs ...Attributes)
 
 	attrs = append(attrs, semconv.CodeLineNumberKey.Int(line))
 
-	ctx, sp := otel.
+	ctx, sp := s.provider.
 		Tracer(fileName).
 		Start(
 			ctx,
@@ -162,5 +180,13 @@ func (s tracing) Stop(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	return s.provider.Shutdown(ctx)
+	err = s.provider.Shutdown(ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.closer != nil {
+		return s.closer.Close()
+	}
+	return nil
 }
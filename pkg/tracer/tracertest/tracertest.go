@@ -0,0 +1,127 @@
+// Package tracertest records the spans of the tests in memory, to verify the trees of spans produced by the services.
+package tracertest
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dalmarcogd/ledger-exp/pkg/tracer"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span is a node of a tree of spans, identified by its name. Error is whether the span recorded an error.
+type Span struct {
+	Name     string
+	Error    bool
+	Children []Span
+}
+
+// Recorder is a tracer.Tracer that keeps the ended spans in memory.
+type Recorder struct {
+	tracer.Tracer
+	exporter *tracetest.InMemoryExporter
+}
+
+func New(t *testing.T) *Recorder {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tr, err := tracer.New("", "test", "test", "", tracer.WithSyncer(exporter))
+	if err != nil {
+		t.Fatalf("new tracer: %v", err)
+	}
+
+	return &Recorder{Tracer: tr, exporter: exporter}
+}
+
+// Spans returns the ended spans, in the order they ended.
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	return r.exporter.GetSpans()
+}
+
+// Find returns the first ended span with the name, failing the test when there is none.
+func (r *Recorder) Find(t *testing.T, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range r.Spans() {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("no span named %q in %v", name, r.names())
+	return tracetest.SpanStub{}
+}
+
+// Reset forgets the ended spans.
+func (r *Recorder) Reset() {
+	r.exporter.Reset()
+}
+
+// Tree returns the trees of the ended spans, the children in the order they started.
+func (r *Recorder) Tree() []Span {
+	spans := r.Spans()
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].StartTime.Before(spans[j].StartTime)
+	})
+
+	ended := map[trace.SpanID]bool{}
+	for _, span := range spans {
+		ended[span.SpanContext.SpanID()] = true
+	}
+
+	children := map[trace.SpanID][]tracetest.SpanStub{}
+	var roots []tracetest.SpanStub
+	for _, span := range spans {
+		parent := span.Parent.SpanID()
+		if !span.Parent.IsValid() || !ended[parent] {
+			roots = append(roots, span)
+			continue
+		}
+		children[parent] = append(children[parent], span)
+	}
+
+	var build func(stubs []tracetest.SpanStub) []Span
+	build = func(stubs []tracetest.SpanStub) []Span {
+		var nodes []Span
+		for _, stub := range stubs {
+			nodes = append(nodes, Span{
+				Name:     stub.Name,
+				Error:    hasError(stub),
+				Children: build(children[stub.SpanContext.SpanID()]),
+			})
+		}
+		return nodes
+	}
+
+	return build(roots)
+}
+
+// AssertTree asserts the trees of the ended spans are the expected ones.
+func (r *Recorder) AssertTree(t *testing.T, expected ...Span) bool {
+	t.Helper()
+	return assert.Equal(t, expected, r.Tree())
+}
+
+func (r *Recorder) names() []string {
+	var names []string
+	for _, span := range r.Spans() {
+		names = append(names, span.Name)
+	}
+	return names
+}
+
+func hasError(stub tracetest.SpanStub) bool {
+	if stub.Status.Code == codes.Error {
+		return true
+	}
+	for _, event := range stub.Events {
+		if event.Name == "exception" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+//go:build unit
+
+package tracertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	recorder := New(t)
+
+	rootCtx, root := recorder.SpanName(ctx, "root")
+	_, first := recorder.SpanName(rootCtx, "first")
+	first.End()
+	secondCtx, second := recorder.SpanName(rootCtx, "second")
+	_, leaf := recorder.SpanName(secondCtx, "leaf")
+	leaf.RecordError(errors.New("failure"))
+	leaf.End()
+	second.End()
+	root.End()
+
+	recorder.AssertTree(t, Span{
+		Name: "root",
+		Children: []Span{
+			{Name: "first"},
+			{Name: "second", Children: []Span{{Name: "leaf", Error: true}}},
+		},
+	})
+	assert.Equal(t, root.SpanContext().SpanID(), recorder.Find(t, "second").Parent.SpanID())
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Tree())
+}
@@ -3,15 +3,28 @@ package zapctx
 import (
 	"context"
 
+	"github.com/dalmarcogd/ledger-exp/pkg/auth"
+	"github.com/dalmarcogd/ledger-exp/pkg/tenancy"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-// L returns the global logger with considering the Go context.
+// L returns the global logger with considering the Go context, its span, authenticated principal and tenant.
 func L(ctx context.Context) *zap.Logger {
 	logger := zap.L()
 
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		logger = logger.With(
+			zap.String("principal_id", principal.ID),
+			zap.String("principal_type", string(principal.Type)),
+		)
+	}
+
+	if tenant, ok := tenancy.FromContext(ctx); ok {
+		logger = logger.With(zap.String("tenant_id", tenant.ID))
+	}
+
 	span := trace.SpanFromContext(ctx)
 	if !span.IsRecording() {
 		return logger